@@ -21,6 +21,9 @@ package main
 
 import (
 	"flag"
+	"os"
+
+	log "github.com/golang/glog"
 	"github.com/sodafoundation/dock/pkg/dock"
 	"github.com/sodafoundation/dock/pkg/model"
 	"github.com/sodafoundation/dock/pkg/utils/constants"
@@ -40,6 +43,7 @@ func init() {
 	flag.StringVar(&CONF.OsdsDock.DockType, "dock-type", CONF.OsdsDock.DockType, "Type of dock service")
 	flag.BoolVar(&CONF.OsdsDock.Daemon, "daemon", CONF.OsdsDock.Daemon, "Run app as a daemon with -daemon=true")
 	flag.DurationVar(&CONF.OsdsDock.LogFlushFrequency, "log-flush-frequency", CONF.OsdsDock.LogFlushFrequency, "Maximum number of seconds between log flushes")
+	flag.StringVar(&CONF.OsdsDock.MetricsEndpoint, "metrics-endpoint", CONF.OsdsDock.MetricsEndpoint, "Listen endpoint of dock prometheus metrics, disabled if empty")
 	flag.Parse()
 
 	daemon.CheckAndRunDaemon(CONF.OsdsDock.Daemon)
@@ -60,8 +64,12 @@ func main() {
 		listenEndpoint = CONF.OsdsDock.ApiEndpoint
 	}
 	// Construct dock module grpc server struct and run dock server process.
+	// Run returns after the graceful shutdown, the exit status is non-zero if
+	// the dock fails to start or to shut down cleanly.
 	ds := dock.NewDockServer(CONF.OsdsDock.DockType, listenEndpoint)
 	if err := ds.Run(); err != nil {
-		panic(err)
+		log.Error("Dock server stopped with error: ", err)
+		logs.FlushLogs()
+		os.Exit(1)
 	}
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/sodafoundation/dock/pkg/utils/pwd"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 )
 
 const (
@@ -151,6 +152,12 @@ func (c *Client) doRequest(method, u string, in interface{}, cb ReqSettingCB) ([
 		req.Header("X-Auth-Token", c.token.ID)
 	}
 
+	span, traceparent := trace.StartHTTPSpan(method, u)
+	defer span.End()
+	if traceparent != "" {
+		req.Header(trace.TraceparentHeader, traceparent)
+	}
+
 	req.SetTimeout(c.timeout, c.timeout)
 	if cb != nil {
 		if err := cb(req); err != nil {
@@ -171,9 +178,11 @@ func (c *Client) doRequest(method, u string, in interface{}, cb ReqSettingCB) ([
 
 	resp, err := req.Response()
 	if err != nil {
+		span.SetError(err)
 		log.Errorf("Do http request failed, method: %s\n url: %s\n error: %v", method, u, err)
 		return nil, nil, err
 	}
+	span.SetStatusCode(resp.StatusCode)
 
 	log.Infof("%s: %s OK\n", method, u)
 	rbody, err := ioutil.ReadAll(resp.Body)
@@ -29,6 +29,24 @@ func ExecCmd(name string, arg ...string) (string, error) {
 	return string(info), err
 }
 
+// ExecCmdWithSecrets works like ExecCmd, but the secrets in the arguments are
+// masked in log.
+func ExecCmdWithSecrets(secrets []string, name string, arg ...string) (string, error) {
+	masked := make([]string, len(arg))
+	for i, a := range arg {
+		masked[i] = a
+		for _, s := range secrets {
+			if s != "" && strings.Contains(a, s) {
+				masked[i] = "******"
+				break
+			}
+		}
+	}
+	log.Printf("Command: %s %s:\n", name, strings.Join(masked, " "))
+	info, err := exec.Command(name, arg...).CombinedOutput()
+	return string(info), err
+}
+
 // GetFSType returns the File System Type of device
 func GetFSType(device string) (string, error) {
 	log.Printf("GetFSType: %s\n", device)
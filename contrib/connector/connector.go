@@ -36,6 +36,8 @@ const (
 	NvmeofDriver = config.NVMEOFProtocol
 	Nqn          = "nqn"
 	NFSDriver    = config.NFSProtocol
+
+	LocalDriver = config.LocalProtocol
 )
 
 // Connector implementation
@@ -34,6 +34,8 @@ type IscsiConnectorInfo struct {
 	AuthUser   string   `mapstructure:"authUserName"`
 	AuthPass   string   `mapstructure:"authPassword"`
 	AuthMethod string   `mapstructure:"authMethod"`
+	MutualUser string   `mapstructure:"mutualAuthUserName"`
+	MutualPass string   `mapstructure:"mutualAuthPassword"`
 	TgtDisco   bool     `mapstructure:"targetDiscovered"`
 	TgtIQN     []string `mapstructure:"targetIQN"`
 	TgtPortal  []string `mapstructure:"targetPortal"`
@@ -130,21 +132,28 @@ func discovery(portal string) (string, error) {
 	return strings.Replace(result, "\n", "", -1), nil
 }
 
-// Login ISCSI Target
-func setAuth(portal string, targetiqn string, name string, passwd string) error {
-	// Set UserName
-	info, err := connector.ExecCmd("iscsiadm", "-m", "node", "-p", portal, "-T", targetiqn,
-		"--op=update", "--name", "node.session.auth.username", "--value", name)
-	if err != nil {
-		log.Printf("Received error on set income username: %v, %v\n", err, info)
-		return err
+// Set CHAP authentication of ISCSI Target, the mutual CHAP is set only if
+// the target provides its credential.
+func setAuth(portal string, targetiqn string, conn *IscsiConnectorInfo) error {
+	settings := [][]string{
+		{"node.session.auth.authmethod", "CHAP"},
+		{"node.session.auth.username", conn.AuthUser},
+		{"node.session.auth.password", conn.AuthPass},
 	}
-	// Set Password
-	info, err = connector.ExecCmd("iscsiadm", "-m", "node", "-p", portal, "-T", targetiqn,
-		"--op=update", "--name", "node.session.auth.password", "--value", passwd)
-	if err != nil {
-		log.Printf("Received error on set income password: %v, %v\n", err, info)
-		return err
+	if conn.MutualUser != "" {
+		settings = append(settings,
+			[]string{"node.session.auth.username_in", conn.MutualUser},
+			[]string{"node.session.auth.password_in", conn.MutualPass})
+	}
+
+	secrets := []string{conn.AuthPass, conn.MutualPass}
+	for _, s := range settings {
+		info, err := connector.ExecCmdWithSecrets(secrets, "iscsiadm", "-m", "node", "-p", portal, "-T", targetiqn,
+			"--op=update", "--name", s[0], "--value", s[1])
+		if err != nil {
+			log.Printf("Received error on set %s: %v, %v\n", s[0], err, info)
+			return err
+		}
 	}
 	return nil
 }
@@ -233,8 +242,6 @@ func connect(connMap map[string]interface{}) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	log.Println("connmap info: ", connMap)
-	log.Println("conn info is: ", conn)
 	portal := conn.TgtPortal[index]
 
 	var targetiqn string
@@ -280,7 +287,9 @@ func connect(connMap map[string]interface{}) (string, error) {
 		return "", err
 	}
 	if len(conn.AuthMethod) != 0 {
-		setAuth(portal, targetiqn, conn.AuthUser, conn.AuthPass)
+		if err := setAuth(portal, targetiqn, conn); err != nil {
+			return "", err
+		}
 	}
 	//Login
 	err = login(portal, targetiqn)
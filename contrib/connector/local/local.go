@@ -0,0 +1,119 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the connector of local access protocol, which is used
+when the volume is provisioned on the same host as it is attached, so the
+block device can be used directly without any target in between.
+*/
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/sodafoundation/dock/contrib/connector"
+)
+
+// These are package variables so that they can be faked in unit tests.
+var (
+	hostname     = os.Hostname
+	evalSymlinks = filepath.EvalSymlinks
+	stat         = os.Stat
+)
+
+// ConnectorInfo is the connection data returned by the drivers which support
+// local access protocol.
+type ConnectorInfo struct {
+	DevicePath string `mapstructure:"devicePath"`
+	HostName   string `mapstructure:"hostName"`
+}
+
+type Local struct{}
+
+var _ connector.Connector = &Local{}
+
+func init() {
+	connector.RegisterConnector(connector.LocalDriver, &Local{})
+}
+
+func parseConnectInfo(conn map[string]interface{}) (*ConnectorInfo, error) {
+	var info ConnectorInfo
+	if err := mapstructure.Decode(conn, &info); err != nil {
+		return nil, err
+	}
+	if info.DevicePath == "" {
+		return nil, errors.New("local connection data is invalid, device path is empty")
+	}
+	if info.HostName != "" {
+		host, err := hostname()
+		if err != nil {
+			return nil, err
+		}
+		if host != info.HostName {
+			return nil, fmt.Errorf("device %s is local to host %s, not %s", info.DevicePath, info.HostName, host)
+		}
+	}
+	return &info, nil
+}
+
+// checkDevice makes sure the path exists and refers to a block device, the
+// symbolic links such as /dev/<vg>/<lv> are followed.
+func checkDevice(devicePath string) error {
+	realPath, err := evalSymlinks(devicePath)
+	if err != nil {
+		return fmt.Errorf("device %s is not available: %v", devicePath, err)
+	}
+	fi, err := stat(realPath)
+	if err != nil {
+		return fmt.Errorf("device %s is not available: %v", devicePath, err)
+	}
+	if fi.Mode()&os.ModeDevice == 0 || fi.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("%s is not a block device", devicePath)
+	}
+	return nil
+}
+
+// Attach validates the local device and returns its path directly.
+func (*Local) Attach(conn map[string]interface{}) (string, error) {
+	info, err := parseConnectInfo(conn)
+	if err != nil {
+		return "", err
+	}
+	if err := checkDevice(info.DevicePath); err != nil {
+		return "", err
+	}
+	return info.DevicePath, nil
+}
+
+// Detach does nothing but validating the connection data, because nothing
+// is set up when attaching.
+func (*Local) Detach(conn map[string]interface{}) error {
+	_, err := parseConnectInfo(conn)
+	return err
+}
+
+// GetInitiatorInfo returns the host name, which is the only thing that
+// identifies a local consumer.
+func (*Local) GetInitiatorInfo() ([]string, error) {
+	host, err := hostname()
+	if err != nil {
+		return nil, err
+	}
+	return []string{host}, nil
+}
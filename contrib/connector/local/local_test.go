@@ -0,0 +1,106 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeFileInfo struct {
+	mode os.FileMode
+}
+
+func (f fakeFileInfo) Name() string       { return "dm-0" }
+func (f fakeFileInfo) Size() int64        { return 0 }
+func (f fakeFileInfo) Mode() os.FileMode  { return f.mode }
+func (f fakeFileInfo) ModTime() time.Time { return time.Time{} }
+func (f fakeFileInfo) IsDir() bool        { return false }
+func (f fakeFileInfo) Sys() interface{}   { return nil }
+
+// fakeHost fakes the host name and the devices of the host, it returns the
+// function to restore the fakes.
+func fakeHost(devices map[string]os.FileMode) func() {
+	origHostname, origEvalSymlinks, origStat := hostname, evalSymlinks, stat
+	hostname = func() (string, error) { return "host1", nil }
+	evalSymlinks = func(path string) (string, error) {
+		if path == "/dev/vg001/volume-1" {
+			return "/dev/dm-0", nil
+		}
+		return path, nil
+	}
+	stat = func(path string) (os.FileInfo, error) {
+		mode, ok := devices[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return fakeFileInfo{mode: mode}, nil
+	}
+	return func() { hostname, evalSymlinks, stat = origHostname, origEvalSymlinks, origStat }
+}
+
+func TestAttachDetach(t *testing.T) {
+	defer fakeHost(map[string]os.FileMode{
+		"/dev/dm-0":  os.ModeDevice,
+		"/dev/tty0":  os.ModeDevice | os.ModeCharDevice,
+		"/tmp/plain": 0644,
+	})()
+	l := &Local{}
+
+	conn := map[string]interface{}{"devicePath": "/dev/vg001/volume-1", "hostName": "host1"}
+	path, err := l.Attach(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/dev/vg001/volume-1" {
+		t.Errorf("Expected device path /dev/vg001/volume-1, got %s", path)
+	}
+	if err = l.Detach(conn); err != nil {
+		t.Error(err)
+	}
+
+	for name, conn := range map[string]map[string]interface{}{
+		"empty device path": {"hostName": "host1"},
+		"other host":        {"devicePath": "/dev/vg001/volume-1", "hostName": "host2"},
+		"missing device":    {"devicePath": "/dev/vg001/volume-2"},
+		"char device":       {"devicePath": "/dev/tty0"},
+		"regular file":      {"devicePath": "/tmp/plain"},
+	} {
+		if _, err := l.Attach(conn); err == nil {
+			t.Errorf("Expected error of attaching %s", name)
+		}
+	}
+	if err = l.Detach(map[string]interface{}{"devicePath": "/dev/vg001/volume-1", "hostName": "host2"}); err == nil {
+		t.Error("Expected error of detaching device of other host")
+	}
+}
+
+func TestGetInitiatorInfo(t *testing.T) {
+	defer fakeHost(nil)()
+	l := &Local{}
+
+	info, err := l.GetInitiatorInfo()
+	if err != nil || !reflect.DeepEqual(info, []string{"host1"}) {
+		t.Errorf("Expected initiator host1, got %v, %v", info, err)
+	}
+
+	hostname = func() (string, error) { return "", errors.New("no host name") }
+	if _, err = l.GetInitiatorInfo(); err == nil {
+		t.Error("Expected error of host name")
+	}
+}
@@ -30,11 +30,13 @@ const (
 
 // ConnectorInfo define
 type ConnectorInfo struct {
-	Nqn       string `mapstructure:"targetNQN"`    //NVMe subsystem name to the volume to be connected
-	TgtPort   string `mapstructure:"targetPort"`   //NVMe target port that hosts the nqn sybsystem
-	TgtPortal string `mapstructure:"targetIP"`     //NVMe target ip that hosts the nqn sybsystem
-	TranType  string `mapstructure:"transporType"` // Nvme transport type
-	HostNqn   string `mapstructure:"hostNqn"`      // host nqn
+	Nqn       string `mapstructure:"targetNQN"`     //NVMe subsystem name to the volume to be connected
+	TgtPort   string `mapstructure:"targetPort"`    //NVMe target port that hosts the nqn sybsystem
+	TgtPortal string `mapstructure:"targetIP"`      //NVMe target ip that hosts the nqn sybsystem
+	TranType  string `mapstructure:"transporType"`  // Nvme transport type
+	HostNqn   string `mapstructure:"hostNqn"`       // host nqn
+	HostKey   string `mapstructure:"dhchapHostKey"` // DH-HMAC-CHAP secret of the host
+	CtrlKey   string `mapstructure:"dhchapCtrlKey"` // DH-HMAC-CHAP secret of the controller
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////
@@ -153,13 +155,23 @@ func Connect(connMap map[string]interface{}) (string, error) {
 	nvmeTransportType := conn.TranType
 	hostName := conn.HostNqn
 
-	cmd := "nvme connect -t " + nvmeTransportType + " -n " + connNqn + " -s " + port + " -a " + targetPortal
+	args := []string{"connect", "-t", nvmeTransportType, "-n", connNqn, "-s", port, "-a", targetPortal}
 	if hostName != "ALL" {
-		cmd += " -q " + hostName
+		args = append(args, "-q", hostName)
+	}
+	// In-band authentication, the controller key is only set for
+	// bidirectional authentication.
+	if conn.HostKey != "" {
+		if hostName == "ALL" {
+			return "", errors.New("host nqn must be specified for nvme in-band authentication")
+		}
+		args = append(args, "--dhchap-secret="+conn.HostKey)
+		if conn.CtrlKey != "" {
+			args = append(args, "--dhchap-ctrl-secret="+conn.CtrlKey)
+		}
 	}
-	//log.Printf("conn information:%s, %s, %s ", connNqn, targetPortal, port)
 
-	_, err := connector.ExecCmd("/bin/bash", "-c", cmd)
+	_, err := connector.ExecCmdWithSecrets([]string{conn.HostKey, conn.CtrlKey}, "nvme", args...)
 
 	if err != nil {
 		log.Println("Failed to connect to NVMe nqn :", connNqn)
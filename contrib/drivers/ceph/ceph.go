@@ -36,6 +36,7 @@ import (
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
 	"github.com/sodafoundation/dock/pkg/utils"
 	"github.com/sodafoundation/dock/pkg/utils/config"
+	"github.com/sodafoundation/dock/pkg/utils/exec"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -147,11 +148,15 @@ func (s *SrcMgr) destroy() {
 
 type Driver struct {
 	conf *CephConfig
+	// cli runs the rbd commands of the consistency groups, which are not
+	// supported by the rbd bindings.
+	cli exec.Executer
 }
 
 func (d *Driver) Setup() error {
 	d.conf = &CephConfig{ConfigFile: "/etc/ceph/ceph.conf"}
-	p := config.CONF.OsdsDock.Backends.Ceph.ConfigPath
+	d.cli = exec.NewRootExecuter()
+	p := config.GetBackends().Ceph.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -625,6 +630,37 @@ type DfInfo struct {
 	Pools []PoolStats `json:"pools,omitempty"`
 }
 
+// provisionedCapacity returns the sum of the sizes of rbd images in the pool
+// in GB.
+func provisionedCapacity(conn *rados.Conn, poolName string) (int64, error) {
+	ioctx, err := conn.OpenIOContext(poolName)
+	if err != nil {
+		return 0, err
+	}
+	defer ioctx.Destroy()
+
+	names, err := rbd.GetImageNames(ioctx)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	for _, name := range names {
+		img := rbd.GetImage(ioctx, name)
+		if err := img.Open(true); err != nil {
+			// The image may be removed after it is listed.
+			log.Warningf("open image %s in pool %s failed: %v", name, poolName, err)
+			continue
+		}
+		size, err := img.GetSize()
+		img.Close()
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return int64(total >> sizeShiftBit), nil
+}
+
 func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 	mgr := NewSrcMgr(d.conf)
 	defer mgr.destroy()
@@ -658,7 +694,14 @@ func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 			Extras:           d.conf.Pool[p.Name].Extras,
 			AvailabilityZone: d.conf.Pool[p.Name].AvailabilityZone,
 			MultiAttach:      d.conf.Pool[p.Name].MultiAttach,
+			// The rbd images are thin provisioned.
+			AllocatedCapacity: p.Stats.BytesUsed >> sizeShiftBit,
 		}
+		if pol.ProvisionedCapacity, err = provisionedCapacity(conn, p.Name); err != nil {
+			log.Errorf("get provisioned capacity of pool %s failed: %v", p.Name, err)
+			return nil, err
+		}
+		d.conf.Pool[p.Name].SetCapacityLimits(pol)
 		if pol.AvailabilityZone == "" {
 			pol.AvailabilityZone = defaultAZ
 		}
@@ -699,15 +742,3 @@ func (d *Driver) InitializeSnapshotConnection(opt *pb.CreateSnapshotAttachmentOp
 func (d *Driver) TerminateSnapshotConnection(opt *pb.DeleteSnapshotAttachmentOpts) error {
 	return nil
 }
-
-func (d *Driver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method CreateVolumeGroup has not been implemented yet"}
-}
-
-func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method UpdateVolumeGroup has not been implemented yet"}
-}
-
-func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
-	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
-}
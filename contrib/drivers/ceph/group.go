@@ -0,0 +1,194 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ceph
+
+import (
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+const (
+	KGroupName     = "CephGroupName"
+	KGroupSnapName = "CephGroupSnapName"
+)
+
+// rbdNotFound reports whether the rbd command failed because the group, the
+// image or the snapshot does not exist.
+func rbdNotFound(out string) bool {
+	return strings.Contains(out, "No such file or directory")
+}
+
+func (d *Driver) rbd(args ...string) (string, error) {
+	return d.cli.Run("rbd", append([]string{"--conf", d.conf.ConfigFile}, args...)...)
+}
+
+// groupPool returns the pool of the group, which is kept in its metadata
+// after it is created.
+func groupPool(poolName string, metadata map[string]string) string {
+	if p, ok := metadata[KPoolName]; ok {
+		return p
+	}
+	return poolName
+}
+
+func (d *Driver) addGroupImages(pool, group string, volIds []string) error {
+	for _, id := range volIds {
+		if _, err := d.rbd("group", "image", "add", pool+"/"+group, pool+"/"+EncodeName(id)); err != nil {
+			log.Errorf("When add image of volume %s to group %s: %v", id, group, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) removeGroupImages(pool, group string, volIds []string) error {
+	for _, id := range volIds {
+		out, err := d.rbd("group", "image", "remove", pool+"/"+group, pool+"/"+EncodeName(id))
+		if err != nil && !rbdNotFound(out) {
+			log.Errorf("When remove image of volume %s from group %s: %v", id, group, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateVolumeGroup creates a rbd group, the snapshots of which are taken
+// consistently across all its images.
+func (d *Driver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
+	pool, group := opt.GetPoolName(), EncodeName(opt.GetId())
+	if _, err := d.rbd("group", "create", pool+"/"+group); err != nil {
+		log.Error("When create group:", err)
+		return nil, err
+	}
+	if err := d.addGroupImages(pool, group, opt.GetAddVolumes()); err != nil {
+		if _, err := d.rbd("group", "remove", pool+"/"+group); err != nil {
+			log.Error("When remove group:", err)
+		}
+		return nil, err
+	}
+
+	log.Infof("Create group (name:%s, id:%s) success", opt.GetName(), opt.GetId())
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:             opt.GetName(),
+		Description:      opt.GetDescription(),
+		AvailabilityZone: opt.GetAvailabilityZone(),
+		PoolId:           opt.GetPoolId(),
+		Status:           model.VolumeGroupAvailable,
+		Metadata: map[string]string{
+			KPoolName:  pool,
+			KGroupName: group,
+		},
+	}, nil
+}
+
+func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
+	pool, group := groupPool(opt.GetPoolName(), opt.GetMetadata()), EncodeName(opt.GetId())
+	if err := d.removeGroupImages(pool, group, opt.GetRemoveVolumes()); err != nil {
+		return nil, err
+	}
+	if err := d.addGroupImages(pool, group, opt.GetAddVolumes()); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Update group (%s) success", opt.GetId())
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		PoolId: opt.GetPoolId(),
+		Status: model.VolumeGroupAvailable,
+	}, nil
+}
+
+// DeleteVolumeGroup removes the rbd group along with its snapshots, the
+// images in it are kept and deleted as the volumes.
+func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
+	pool, group := groupPool(opt.GetPoolName(), opt.GetMetadata()), EncodeName(opt.GetId())
+	out, err := d.rbd("group", "remove", pool+"/"+group)
+	if err != nil && !rbdNotFound(out) {
+		log.Error("When remove group:", err)
+		return err
+	}
+
+	log.Infof("Delete group (%s) success", opt.GetId())
+	return nil
+}
+
+// CreateGroupSnapshot takes a rbd group snapshot, which snapshots all the
+// images in the group atomically.
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	pool, group := groupPool(opt.GetPoolName(), opt.GetGroupMetadata()), EncodeName(opt.GetGroupId())
+	snap := EncodeName(opt.GetId())
+	if _, err := d.rbd("group", "snap", "create", pool+"/"+group+"@"+snap); err != nil {
+		log.Error("When create group snapshot:", err)
+		return nil, err
+	}
+
+	var snps []*model.VolumeSnapshotSpec
+	for _, s := range opt.GetSnapshots() {
+		snps = append(snps, &model.VolumeSnapshotSpec{
+			BaseModel: &model.BaseModel{
+				Id: s.GetId(),
+			},
+			Name:     opt.GetName(),
+			Size:     s.GetSize(),
+			VolumeId: s.GetVolumeId(),
+			Status:   model.VolumeSnapAvailable,
+			Metadata: map[string]string{
+				KPoolName:      pool,
+				KImageName:     EncodeName(s.GetVolumeId()),
+				KGroupName:     group,
+				KGroupSnapName: snap,
+			},
+		})
+	}
+
+	log.Infof("Create group snapshot (name:%s, id:%s, groupID:%s) success",
+		opt.GetName(), opt.GetId(), opt.GetGroupId())
+	return &model.VolumeGroupSnapshotSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:        opt.GetName(),
+		Description: opt.GetDescription(),
+		GroupId:     opt.GetGroupId(),
+		Status:      model.VolumeSnapAvailable,
+		Snapshots:   snps,
+		Metadata: map[string]string{
+			KPoolName:      pool,
+			KGroupName:     group,
+			KGroupSnapName: snap,
+		},
+	}, nil
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	pool, group := groupPool(opt.GetPoolName(), opt.GetGroupMetadata()), EncodeName(opt.GetGroupId())
+	snap := EncodeName(opt.GetId())
+	out, err := d.rbd("group", "snap", "remove", pool+"/"+group+"@"+snap)
+	if err != nil && !rbdNotFound(out) {
+		log.Error("When remove group snapshot:", err)
+		return err
+	}
+
+	log.Infof("Delete group snapshot (%s) success", opt.GetId())
+	return nil
+}
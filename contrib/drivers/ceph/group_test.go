@@ -0,0 +1,137 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ceph
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+type groupFakeResp struct {
+	out string
+	err error
+}
+
+// groupFakeExecuter records the rbd commands without the config file, and
+// replies to the ones in respMap.
+type groupFakeExecuter struct {
+	cmds    []string
+	respMap map[string]*groupFakeResp
+}
+
+func (f *groupFakeExecuter) Run(name string, args ...string) (string, error) {
+	cmd := strings.Join(append([]string{name}, args[2:]...), " ")
+	f.cmds = append(f.cmds, cmd)
+	if r, ok := f.respMap[cmd]; ok {
+		return r.out, r.err
+	}
+	return "", nil
+}
+
+func TestVolumeGroup(t *testing.T) {
+	fe := &groupFakeExecuter{respMap: map[string]*groupFakeResp{
+		"rbd group image add rbd/opensds-group1 rbd/opensds-vol2": {"", errors.New("exit status 2")},
+	}}
+	d := &Driver{conf: &CephConfig{ConfigFile: "/etc/ceph/ceph.conf"}, cli: fe}
+
+	vg, err := d.CreateVolumeGroup(&pb.CreateVolumeGroupOpts{
+		Id: "group1", PoolName: "rbd", AddVolumes: []string{"vol1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.Metadata[KPoolName] != "rbd" || vg.Metadata[KGroupName] != "opensds-group1" {
+		t.Errorf("Unexpected group metadata %v", vg.Metadata)
+	}
+
+	// The group is removed if the images can't be added.
+	fe.cmds = nil
+	if _, err = d.CreateVolumeGroup(&pb.CreateVolumeGroupOpts{
+		Id: "group1", PoolName: "rbd", AddVolumes: []string{"vol2"},
+	}); err == nil {
+		t.Error("Expected error of adding image")
+	}
+	expected := []string{
+		"rbd group create rbd/opensds-group1",
+		"rbd group image add rbd/opensds-group1 rbd/opensds-vol2",
+		"rbd group remove rbd/opensds-group1",
+	}
+	if !reflect.DeepEqual(fe.cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, fe.cmds)
+	}
+
+	// The pool in the group metadata is used.
+	fe.cmds = nil
+	if _, err = d.UpdateVolumeGroup(&pb.UpdateVolumeGroupOpts{
+		Id: "group1", Metadata: vg.Metadata, AddVolumes: []string{"vol3"}, RemoveVolumes: []string{"vol1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"rbd group image remove rbd/opensds-group1 rbd/opensds-vol1",
+		"rbd group image add rbd/opensds-group1 rbd/opensds-vol3",
+	}
+	if !reflect.DeepEqual(fe.cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, fe.cmds)
+	}
+
+	// A missing group is already deleted.
+	fe.respMap["rbd group remove rbd/opensds-group1"] = &groupFakeResp{
+		"rbd: remove error: (2) No such file or directory", errors.New("exit status 2"),
+	}
+	if err = d.DeleteVolumeGroup(&pb.DeleteVolumeGroupOpts{Id: "group1", Metadata: vg.Metadata}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGroupSnapshot(t *testing.T) {
+	fe := &groupFakeExecuter{respMap: map[string]*groupFakeResp{}}
+	d := &Driver{conf: &CephConfig{ConfigFile: "/etc/ceph/ceph.conf"}, cli: fe}
+	groupMetadata := map[string]string{KPoolName: "rbd", KGroupName: "opensds-group1"}
+
+	gs, err := d.CreateGroupSnapshot(&pb.CreateGroupSnapshotOpts{
+		Id:            "gsnap1",
+		GroupId:       "group1",
+		GroupMetadata: groupMetadata,
+		Snapshots: []*pb.GroupSnapshotMember{
+			{Id: "snap1", VolumeId: "vol1"},
+			{Id: "snap2", VolumeId: "vol2"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"rbd group snap create rbd/opensds-group1@opensds-gsnap1"}
+	if !reflect.DeepEqual(fe.cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, fe.cmds)
+	}
+	if len(gs.Snapshots) != 2 || gs.Snapshots[1].Metadata[KImageName] != "opensds-vol2" ||
+		gs.Snapshots[1].Metadata[KGroupSnapName] != "opensds-gsnap1" {
+		t.Errorf("Unexpected group snapshot %+v", gs)
+	}
+
+	fe.respMap["rbd group snap remove rbd/opensds-group1@opensds-gsnap1"] = &groupFakeResp{
+		"", errors.New("exit status 1"),
+	}
+	if err = d.DeleteGroupSnapshot(&pb.DeleteGroupSnapshotOpts{
+		Id: "gsnap1", GroupId: "group1", GroupMetadata: groupMetadata,
+	}); err == nil {
+		t.Error("Expected error of removing group snapshot")
+	}
+}
@@ -20,8 +20,9 @@ import (
 
 // Probably make some of these configurable later
 const (
-	portPostfix  = "-drbd-port"
-	minorPostfix = "-drbd-minor"
+	portPostfix     = "-drbd-port"
+	minorPostfix    = "-drbd-minor"
+	resourcePostfix = "-drbd-resource"
 
 	defaultPortMin = 7000
 	defaultPortMax = 8000
@@ -29,22 +30,32 @@ const (
 	minorMin = 1
 	minorMax = 1000
 
-	// for the time being opensds only has one primary and one secondary,
-	// but reserve slots for 7 peers anyways
-	maxPeers = 7
+	// a resource has one primary and up to 6 secondaries, the last slot of
+	// the peers is reserved for the diskless tie-breaker
+	maxPeers       = 7
+	maxSecondaries = maxPeers - 1
 
-	resDir          = "/etc/drbd.d"
 	defaultConfPath = "/etc/opensds/driver/drbd.yaml"
 )
 
+// The paths are variables so that they can be changed in unit tests.
+var (
+	resDir    = "/etc/drbd.d"
+	statePath = "/var/lib/opensds/drbd/resources.json"
+)
+
 type drbdConf struct {
 	Hosts   []godrbdutils.Host `yaml:"Hosts,omitempty"`
 	PortMin int                `yaml:"PortMin,omitempty"`
 	PortMax int                `yaml:"PortMax,omitempty"`
+	// The hostname of the diskless host which breaks the tie of quorum, it
+	// must be one of the Hosts and never holds any data.
+	TieBreaker string `yaml:"TieBreaker,omitempty"`
 }
 
-func portKey(s string) string  { return s + portPostfix }
-func minorKey(s string) string { return s + minorPostfix }
+func portKey(s string) string     { return s + portPostfix }
+func minorKey(s string) string    { return s + minorPostfix }
+func resourceKey(s string) string { return s + resourcePostfix }
 
 func resFilePath(resName string) string {
 	return filepath.Join(resDir, resName) + ".res"
@@ -17,12 +17,14 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/LINBIT/godrbdutils"
 	log "github.com/golang/glog"
 	"github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/exec"
 )
 
 // ReplicationDriver
@@ -43,149 +45,150 @@ func (r *ReplicationDriver) CreateReplication(opt *pb.CreateReplicationOpts) (*m
 	if err != nil {
 		return nil, err
 	}
-	if len(conf.Hosts) != 2 {
-		return nil, fmt.Errorf("Your configuration does not contain exactly 2 hosts")
-	}
-
-	isPrimary := opt.GetIsPrimary()
-	primaryData := opt.GetPrimaryReplicationDriverData()
-	secondaryData := opt.GetSecondaryReplicationDriverData()
-
-	var myData *map[string]string
-	if isPrimary {
-		myData = &primaryData
-	} else {
-		myData = &secondaryData
-	}
-
-	var myHostName string
-	var myHostIP string
-	var hok bool
-	if myHostName, hok = (*myData)["HostName"]; hok {
-		myHostIP, hok = (*myData)["HostIp"]
-	}
-	if !hok {
-		return nil, fmt.Errorf("Data did not contain 'HostIp' or 'HostName' key")
-	}
+	return createReplication(&conf, opt)
+}
 
-	var myHost, peerHost godrbdutils.Host
-	for _, h := range conf.Hosts {
-		if h.Name == myHostName && h.IP == myHostIP {
-			myHost = h
-		} else {
-			peerHost = h
-		}
+// findHost finds the configured host of a site by the 'HostName' and 'HostIp'
+// of its replication driver data.
+func findHost(hosts map[string]godrbdutils.Host, data map[string]string) (godrbdutils.Host, error) {
+	name, hok := data["HostName"]
+	ip, iok := data["HostIp"]
+	if !hok || !iok {
+		return godrbdutils.Host{}, fmt.Errorf("Data did not contain 'HostIp' or 'HostName' key")
 	}
-
-	if myHost.Name == "" || myHost.IP == "" || peerHost.Name == "" || peerHost.IP == "" ||
-		myHost.Name == peerHost.Name || myHost.ID == peerHost.ID || myHost.IP == peerHost.IP {
-		return nil, fmt.Errorf("Could not find valid hosts")
+	if h, ok := hosts[name]; ok && h.IP == ip {
+		return h, nil
 	}
+	return godrbdutils.Host{}, fmt.Errorf("Could not find host %s (%s) in your configuration", name, ip)
+}
 
-	resName := opt.GetId()
-
-	primaryVolID := opt.GetPrimaryVolumeId()
-	secondaryVolID := opt.GetSecondaryVolumeId()
-	path, _ := filepath.EvalSymlinks(primaryData["Mountpoint"])
-	primaryBackingDevice, _ := filepath.Abs(path)
-	path, _ = filepath.EvalSymlinks(secondaryData["Mountpoint"])
-	secondaryBackingDevice, _ := filepath.Abs(path)
-	log.Info(primaryBackingDevice, secondaryBackingDevice)
-	// as we use the same minors/ports in primary/secondary, make them a set:
-	usedPort := make(map[int]bool)
-	usedMinor := make(map[int]bool)
-	for _, volData := range opt.GetVolumeDataList() {
+// usedNumbers gets the ports and minors which are still used by the volumes.
+func usedNumbers(volDataList []*pb.VolumeData) (ports, minors []int, err error) {
+	for _, volData := range volDataList {
 		data := volData.GetData()
-
-		// check if the current device in the DataList() already has a key for port/minor that belongs to the primary/secondary
-		// That would happen for example if the data was not deleted and/or CreateReplication() was called multiple times.
-		// if val, ok := data[portKey(primaryVolID)]; ok {
-		// 	return nil, fmt.Errorf("Primary Volume ID (%s), already has a port number (%s)", primaryVolID, val)
-		// }
-		// if val, ok := data[portKey(secondaryVolID)]; ok {
-		// 	return nil, fmt.Errorf("Secondary Volume ID (%s), already has a port number (%s)", secondaryVolID, val)
-		// }
-		// if val, ok := data[minorKey(primaryVolID)]; ok {
-		// 	return nil, fmt.Errorf("Primary Volume ID (%s), already has a minor number (%s)", primaryVolID, val)
-		// }
-		// if val, ok := data[minorKey(secondaryVolID)]; ok {
-		// 	return nil, fmt.Errorf("Secondary Volume ID (%s), already has a minor number (%s)", secondaryVolID, val)
-		// }
-
-		// get ports and minors still in use
 		volID := data["VolumeId"]
 		if val, ok := data[portKey(volID)]; ok {
 			p, err := strconv.Atoi(val)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			usedPort[p] = true
+			ports = append(ports, p)
 		}
 		if val, ok := data[minorKey(volID)]; ok {
 			m, err := strconv.Atoi(val)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			usedMinor[m] = true
+			minors = append(minors, m)
 		}
 	}
+	return ports, minors, nil
+}
 
-	portMin := cfgOrDefault(conf.PortMin, defaultPortMin)
-	portMax := cfgOrDefault(conf.PortMax, defaultPortMax)
-	var up []int
-	for k := range usedPort {
-		up = append(up, k)
-	}
-	port, err := godrbdutils.GetNumber(portMin, portMax, up)
+// createReplication adds the secondary site of the replication to the DRBD
+// resource of the primary volume, and the resource file is regenerated. The
+// resource is created if it is the first replication of the primary volume.
+func createReplication(conf *drbdConf, opt *pb.CreateReplicationOpts) (*model.ReplicationSpec, error) {
+	hosts, err := conf.hosts()
 	if err != nil {
 		return nil, err
 	}
 
-	var um []int
-	for k := range usedMinor {
-		um = append(um, k)
+	isPrimary := opt.GetIsPrimary()
+	primaryData := opt.GetPrimaryReplicationDriverData()
+	secondaryData := opt.GetSecondaryReplicationDriverData()
+
+	primaryHost, err := findHost(hosts, primaryData)
+	if err != nil {
+		return nil, err
 	}
-	minor, err := godrbdutils.GetNumber(minorMin, minorMax, um)
+	secondaryHost, err := findHost(hosts, secondaryData)
 	if err != nil {
 		return nil, err
 	}
-
-	res := godrbdutils.NewResource(resName, port)
-
-	res.AddHost(myHost.ID, myHost.Name, myHost.IP)
-	res.AddHost(peerHost.ID, peerHost.Name, peerHost.IP)
-
-	drbdVolID := 0 // currently only one volume per DRBD resource
-	if isPrimary {
-		res.AddVolume(drbdVolID, minor, primaryBackingDevice, myHost.Name)
-		res.AddVolume(drbdVolID, minor, secondaryBackingDevice, peerHost.Name)
-	} else {
-		res.AddVolume(drbdVolID, minor, primaryBackingDevice, peerHost.Name)
-		res.AddVolume(drbdVolID, minor, secondaryBackingDevice, myHost.Name)
+	if primaryHost.Name == secondaryHost.Name ||
+		primaryHost.Name == conf.TieBreaker || secondaryHost.Name == conf.TieBreaker {
+		return nil, fmt.Errorf("Could not find valid hosts")
 	}
 
-	res.WriteConfig(resFilePath(resName))
+	replicationID := opt.GetId()
+	primaryVolID := opt.GetPrimaryVolumeId()
+	secondaryVolID := opt.GetSecondaryVolumeId()
+	path, _ := filepath.EvalSymlinks(primaryData["Mountpoint"])
+	primaryBackingDevice, _ := filepath.Abs(path)
+	path, _ = filepath.EvalSymlinks(secondaryData["Mountpoint"])
+	secondaryBackingDevice, _ := filepath.Abs(path)
+	log.Info(primaryBackingDevice, secondaryBackingDevice)
 
-	// Bring up the resource
-	drbdadm := godrbdutils.NewDrbdAdm([]string{resName})
+	usedPorts, usedMinors, err := usedNumbers(opt.GetVolumeDataList())
+	if err != nil {
+		return nil, err
+	}
+	// The secondary sites use the resource name, port and minor of the primary
+	// site if they are known already, since the peers of a resource only
+	// connect if all of them match.
+	port, _ := strconv.Atoi(primaryData[portKey(primaryVolID)])
+	minor, _ := strconv.Atoi(primaryData[minorKey(primaryVolID)])
+	resName := primaryData[resourceKey(primaryVolID)]
+	if resName == "" {
+		resName = replicationID
+	}
 
-	drbdadm.CreateMetaData(fmt.Sprintf("--max-peers=%d", maxPeers), "--force")
-	drbdadm.Up()
+	var res *resource
+	var created bool
+	err = newResourceStore().update(func(resources map[string]*resource) error {
+		if res = resources[primaryVolID]; res == nil {
+			p, m, err := allocate(resources, usedPorts, usedMinors,
+				cfgOrDefault(conf.PortMin, defaultPortMin), cfgOrDefault(conf.PortMax, defaultPortMax), port, minor)
+			if err != nil {
+				return err
+			}
+			res = &resource{
+				Name:        resName,
+				Port:        p,
+				Minor:       m,
+				Primary:     newPeer(primaryHost, primaryVolID, primaryBackingDevice),
+				Secondaries: make(map[string]*peer),
+			}
+			if conf.TieBreaker != "" {
+				res.TieBreaker = newPeer(hosts[conf.TieBreaker], "", "")
+			}
+			resources[primaryVolID] = res
+			created = true
+		}
+		if err := res.addSecondary(replicationID, newPeer(secondaryHost, secondaryVolID, secondaryBackingDevice)); err != nil {
+			return err
+		}
+		return res.writeConfig()
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if isPrimary {
-		// start initial sync
-		drbdadm.Primary("--force")
-		drbdadm.Secondary() // switch back, rest done by auto promote
+	if created {
+		err = upResource(res.Name, isPrimary)
+	} else {
+		// The new secondary site is synchronized once it is connected.
+		err = drbdAdm("adjust", res.Name)
+	}
+	if err != nil {
+		log.Errorf("Failed to bring up drbd resource %s: %v", res.Name, err)
+		if err := removeReplication(replicationID); err != nil {
+			log.Errorf("Failed to remove replication %s from drbd resource %s: %v", replicationID, res.Name, err)
+		}
+		return nil, err
 	}
 
 	additionalPrimaryData := map[string]string{
-		portKey(primaryVolID):  strconv.Itoa(port),
-		minorKey(primaryVolID): strconv.Itoa(minor),
+		portKey(primaryVolID):     strconv.Itoa(res.Port),
+		minorKey(primaryVolID):    strconv.Itoa(res.Minor),
+		resourceKey(primaryVolID): res.Name,
 	}
 
 	additionalSecondaryData := map[string]string{
-		portKey(secondaryVolID):  strconv.Itoa(port),
-		minorKey(secondaryVolID): strconv.Itoa(minor),
+		portKey(secondaryVolID):     strconv.Itoa(res.Port),
+		minorKey(secondaryVolID):    strconv.Itoa(res.Minor),
+		resourceKey(secondaryVolID): res.Name,
 	}
 
 	return &model.ReplicationSpec{
@@ -195,28 +198,72 @@ func (r *ReplicationDriver) CreateReplication(opt *pb.CreateReplicationOpts) (*m
 	}, nil
 }
 
+// upResource brings up the new resource, and the primary site starts the
+// initial sync to the secondary sites.
+func upResource(resName string, isPrimary bool) error {
+	if err := drbdAdm("create-md", resName, fmt.Sprintf("--max-peers=%d", maxPeers), "--force"); err != nil {
+		return err
+	}
+	if err := drbdAdm("up", resName); err != nil {
+		return err
+	}
+	if isPrimary {
+		// start initial sync
+		if err := drbdAdm("primary", resName, "--force"); err != nil {
+			return err
+		}
+		return drbdAdm("secondary", resName) // switch back, rest done by auto promote
+	}
+	return nil
+}
+
 func (r *ReplicationDriver) DeleteReplication(opt *pb.DeleteReplicationOpts) error {
 	log.Infof("DRBD delete replication ....")
 
-	resName := opt.GetId()
+	return removeReplication(opt.GetId())
+}
 
-	drbdadm := godrbdutils.NewDrbdAdm([]string{resName})
-	if _, err := drbdadm.Down(); err != nil {
+// removeReplication removes the secondary site of the replication from its
+// DRBD resource, and the resource file is regenerated. The resource is taken
+// down if it has no secondary site any more.
+func removeReplication(replicationID string) error {
+	return newResourceStore().update(func(resources map[string]*resource) error {
+		key, res := findResource(resources, replicationID)
+		if res == nil {
+			// The resources which are not persisted are named after their
+			// replications.
+			return downResource(replicationID)
+		}
+		delete(res.Secondaries, replicationID)
+		if len(res.Secondaries) > 0 {
+			if err := res.writeConfig(); err != nil {
+				return err
+			}
+			return drbdAdm("adjust", res.Name)
+		}
+		if err := downResource(res.Name); err != nil {
+			return err
+		}
+		// the port and minor are released along with the resource
+		delete(resources, key)
+		return nil
+	})
+}
+
+func downResource(resName string) error {
+	if err := drbdAdm("down", resName); err != nil {
 		return err
 	}
-	if err := os.Remove(resFilePath(resName)); err != nil {
+	if err := os.Remove(resFilePath(resName)); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-
-	// reserved minor/port are automatically deleted because they are gone from *ReplicationData
-
 	return nil
 }
 
 func (r *ReplicationDriver) EnableReplication(opt *pb.EnableReplicationOpts) error {
 	log.Infof("DRBD enable replication ....")
 
-	drbdadm := godrbdutils.NewDrbdAdm([]string{opt.GetId()})
+	drbdadm := godrbdutils.NewDrbdAdm([]string{resourceName(opt.GetId())})
 	_, err := drbdadm.Adjust()
 	return err
 }
@@ -224,20 +271,99 @@ func (r *ReplicationDriver) EnableReplication(opt *pb.EnableReplicationOpts) err
 func (r *ReplicationDriver) DisableReplication(opt *pb.DisableReplicationOpts) error {
 	log.Infof("DRBD disable replication ....")
 
-	drbdadm := godrbdutils.NewDrbdAdm([]string{opt.GetId()})
+	drbdadm := godrbdutils.NewDrbdAdm([]string{resourceName(opt.GetId())})
 	_, err := drbdadm.Disconnect()
 	return err
 }
 
+// drbdAdm runs the drbdadm action on the resource, it is a variable so that
+// it can be faked in unit tests.
+var drbdAdm = func(action, resName string, args ...string) error {
+	argv := append(append([]string{action}, args...), resName)
+	if _, err := exec.Run("drbdadm", argv...); err != nil {
+		return fmt.Errorf("drbdadm %s failed: %v", strings.Join(argv, " "), err)
+	}
+	return nil
+}
+
+// The driver returns a block device on both nodes (/dev/drbd$minor and a symlink as /dev/drbd/by-res/$resname)
+// When the device is used (open(2) in RW mode, it switches that side to DRBD Primary. That is the "autopromote" feature of DRBD9
+// After the user finished using the device (e.g., umount), the device switches to DRBD Secondary
+// And it can then be used on the other node by just open(2)ing the device again.
+// So the role changes of failover, switchover and reprotect only make sure
+// that the right side can be promoted when it is used.
 func (r *ReplicationDriver) FailoverReplication(opt *pb.FailoverReplicationOpts) error {
 	log.Infof("DRBD failover replication ....")
-	// nothing to do here:
-	// The driver returns a block device on both nodes (/dev/drbd$minor and a symlink as /dev/drbd/by-res/$resname)
-	// And the driver makes sure that it triggeres an initial sync from the primary to the secondary side
-	// Then:
-	// When the device is used (open(2) in RW mode, it switches that side to DRBD Primary. That is the "autopromote" feature of DRBD9
-	// That happens what ever the user does (use it as raw device with 'dd', or put a file system on it and mount it,...)
-	// After the user finished using the device (e.g., umount), the device switches to DRBD Secondary
-	// And it can then be used on the second node by just open(2)ing the device again.
-	return nil
+
+	if opt.GetIsPrimary() {
+		// The primary site is probably unavailable, it is demoted when the
+		// replication is reprotected.
+		return nil
+	}
+	res, err := getResource(resourceName(opt.GetId()))
+	if err != nil {
+		return err
+	}
+	for _, dev := range res.Devices {
+		if dev.DiskState != diskUpToDate && dev.DiskState != diskOutdated && dev.DiskState != diskConsistent {
+			return fmt.Errorf("can not failover drbd resource %s since volume %d disk is %s",
+				res.Name, dev.Volume, dev.DiskState)
+		}
+	}
+	// Force the promotion since the primary site can not be reached, and then
+	// demote it so that it is promoted automatically when it is used.
+	if err := drbdAdm("primary", res.Name, "--force"); err != nil {
+		return err
+	}
+	return drbdAdm("secondary", res.Name)
+}
+
+func (r *ReplicationDriver) SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) error {
+	log.Infof("DRBD switchover replication ....")
+
+	res, err := getResource(resourceName(opt.GetId()))
+	if err != nil {
+		return err
+	}
+	// The peers are written synchronously, so no data is lost as long as
+	// they are all up to date when the primary site is demoted.
+	if st := pairStatus(res); st.RunningStatus != model.ReplicationRunningSynced {
+		return fmt.Errorf("can not switch over drbd resource %s which is %s: %s",
+			res.Name, st.RunningStatus, st.Reason)
+	}
+	if res.Role != rolePrimary {
+		return nil
+	}
+	return drbdAdm("secondary", res.Name)
+}
+
+func (r *ReplicationDriver) ReprotectReplication(opt *pb.ReprotectReplicationOpts) error {
+	log.Infof("DRBD reprotect replication ....")
+
+	res, err := getResource(resourceName(opt.GetId()))
+	if err != nil {
+		return err
+	}
+	var connecting bool
+	for _, conn := range res.Connections {
+		connecting = connecting || conn.ConnectionState != standAlone
+	}
+	if connecting {
+		if err := drbdAdm("disconnect", res.Name); err != nil {
+			return err
+		}
+	}
+	if !opt.GetIsPrimary() {
+		// The failed over secondary site keeps its data and becomes the
+		// sync source once the primary site connects.
+		return drbdAdm("connect", res.Name)
+	}
+	// The data of the primary site is out of date after failover, so it is
+	// discarded and synchronized from the secondary site.
+	if res.Role == rolePrimary {
+		if err := drbdAdm("secondary", res.Name); err != nil {
+			return err
+		}
+	}
+	return drbdAdm("connect", res.Name, "--discard-my-data")
 }
@@ -0,0 +1,230 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LINBIT/godrbdutils"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+// fakeDrbd fakes the drbd commands, it returns the drbdadm commands which are
+// run and the function to restore the fakes.
+func fakeDrbd() (*[]string, func()) {
+	var cmds []string
+	origStatus, origAdm := drbdStatus, drbdAdm
+	drbdStatus = func(resNames ...string) ([]byte, error) {
+		return []byte(sampleStatus), nil
+	}
+	drbdAdm = func(action, resName string, args ...string) error {
+		cmds = append(cmds, strings.Join(append(append([]string{action}, args...), resName), " "))
+		return nil
+	}
+	return &cmds, func() { drbdStatus, drbdAdm = origStatus, origAdm }
+}
+
+func TestSwitchoverReplication(t *testing.T) {
+	cmds, restore := fakeDrbd()
+	defer restore()
+	r := &ReplicationDriver{}
+
+	if err := r.SwitchoverReplication(&pb.SwitchoverReplicationOpts{Id: "synced", IsPrimary: true}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"secondary synced"}; !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+
+	*cmds = nil
+	if err := r.SwitchoverReplication(&pb.SwitchoverReplicationOpts{Id: "syncing", IsPrimary: true}); err == nil {
+		t.Error("Expected switching over the syncing resource to fail")
+	}
+	if len(*cmds) != 0 {
+		t.Errorf("Expected no drbdadm command, got %v", *cmds)
+	}
+}
+
+func TestFailoverReplication(t *testing.T) {
+	cmds, restore := fakeDrbd()
+	defer restore()
+	r := &ReplicationDriver{}
+
+	if err := r.FailoverReplication(&pb.FailoverReplicationOpts{Id: "split", IsPrimary: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.FailoverReplication(&pb.FailoverReplicationOpts{Id: "split"}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"primary --force split", "secondary split"}; !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+}
+
+func TestReprotectReplication(t *testing.T) {
+	cmds, restore := fakeDrbd()
+	defer restore()
+	r := &ReplicationDriver{}
+
+	if err := r.ReprotectReplication(&pb.ReprotectReplicationOpts{Id: "split", IsPrimary: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReprotectReplication(&pb.ReprotectReplicationOpts{Id: "split"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReprotectReplication(&pb.ReprotectReplicationOpts{Id: "synced", IsPrimary: true}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"connect --discard-my-data split",
+		"connect split",
+		"disconnect synced", "secondary synced", "connect --discard-my-data synced",
+	}
+	if !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+}
+
+func TestCreateDeleteReplication(t *testing.T) {
+	cmds, restore := fakeDrbd()
+	defer restore()
+	dir, err := ioutil.TempDir("", "drbd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(r, s string) { resDir, statePath = r, s }(resDir, statePath)
+	resDir, statePath = dir, filepath.Join(dir, "state", "resources.json")
+
+	conf := &drbdConf{
+		Hosts: []godrbdutils.Host{
+			{ID: 0, Name: "a", IP: "10.0.0.1"},
+			{ID: 1, Name: "b", IP: "10.0.0.2"},
+			{ID: 2, Name: "c", IP: "10.0.0.3"},
+			{ID: 3, Name: "t", IP: "10.0.0.4"},
+		},
+		TieBreaker: "t",
+	}
+	siteData := func(host, ip string) map[string]string {
+		return map[string]string{"HostName": host, "HostIp": ip, "Mountpoint": "/dev/" + host}
+	}
+	create := func(id, priVol, secVol, secHost, secIp string) (map[string]string, error) {
+		spec, err := createReplication(conf, &pb.CreateReplicationOpts{
+			Id: id, PrimaryVolumeId: priVol, SecondaryVolumeId: secVol, IsPrimary: true,
+			PrimaryReplicationDriverData:   siteData("a", "10.0.0.1"),
+			SecondaryReplicationDriverData: siteData(secHost, secIp),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return spec.PrimaryReplicationDriverData, nil
+	}
+
+	data, err := create("r1", "v1", "v2", "b", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primaryData := map[string]string{portKey("v1"): "7000", minorKey("v1"): "1", resourceKey("v1"): "r1"}
+	if !reflect.DeepEqual(data, primaryData) {
+		t.Errorf("Expected %v, got %v", primaryData, data)
+	}
+	// The second secondary site of the primary volume joins the resource.
+	if data, err = create("r2", "v1", "v3", "c", "10.0.0.3"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, primaryData) {
+		t.Errorf("Expected %v, got %v", primaryData, data)
+	}
+	if _, err = create("r3", "v1", "v4", "b", "10.0.0.2"); err == nil {
+		t.Error("Expected adding the secondary host twice to fail")
+	}
+	if _, err = create("r4", "v5", "v6", "t", "10.0.0.4"); err == nil {
+		t.Error("Expected replicating to the tie-breaker to fail")
+	}
+	expected := []string{
+		"create-md --max-peers=7 --force r1", "up r1", "primary --force r1", "secondary r1",
+		"adjust r1",
+	}
+	if !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+	cfg, err := ioutil.ReadFile(resFilePath("r1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cfg), "on c {") || !strings.Contains(string(cfg), "quorum majority;") {
+		t.Errorf("Unexpected resource file:\n%s", cfg)
+	}
+	if name := resourceName("r2"); name != "r1" {
+		t.Errorf("Expected resource r1 of replication r2, got %s", name)
+	}
+
+	*cmds = nil
+	if err = removeReplication("r1"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ = ioutil.ReadFile(resFilePath("r1")); strings.Contains(string(cfg), "on b {") {
+		t.Errorf("Expected host b to be removed from resource file:\n%s", cfg)
+	}
+	if err = removeReplication("r2"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"adjust r1", "down r1"}; !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+	if _, err = os.Stat(resFilePath("r1")); !os.IsNotExist(err) {
+		t.Errorf("Expected the resource file to be removed, got %v", err)
+	}
+	if resources, _ := newResourceStore().load(); len(resources) != 0 {
+		t.Errorf("Expected the port and minor to be released, got %v", resources)
+	}
+
+	// The store of the secondary host c has no resource of the primary volume,
+	// and the resource of the primary site is brought up there.
+	*cmds = nil
+	resDir, statePath = filepath.Join(dir, "c"), filepath.Join(dir, "c", "state", "resources.json")
+	if err = os.MkdirAll(resDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	priData := siteData("a", "10.0.0.1")
+	for k, v := range primaryData {
+		priData[k] = v
+	}
+	spec, err := createReplication(conf, &pb.CreateReplicationOpts{
+		Id: "r2", PrimaryVolumeId: "v1", SecondaryVolumeId: "v3", IsPrimary: false,
+		PrimaryReplicationDriverData:   priData,
+		SecondaryReplicationDriverData: siteData("c", "10.0.0.3"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{portKey("v3"): "7000", minorKey("v3"): "1", resourceKey("v3"): "r1"}; !reflect.DeepEqual(spec.SecondaryReplicationDriverData, expected) {
+		t.Errorf("Expected %v, got %v", expected, spec.SecondaryReplicationDriverData)
+	}
+	if expected := []string{"create-md --max-peers=7 --force r1", "up r1"}; !reflect.DeepEqual(*cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, *cmds)
+	}
+	if _, err = os.Stat(resFilePath("r1")); err != nil {
+		t.Errorf("Expected the resource file r1 on the secondary host, got %v", err)
+	}
+	if name := resourceName("r2"); name != "r1" {
+		t.Errorf("Expected resource r1 of replication r2, got %s", name)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/LINBIT/godrbdutils"
+)
+
+// peer is a site of a DRBD resource.
+type peer struct {
+	Host    string `json:"host"`
+	NodeId  int    `json:"nodeId"`
+	Address string `json:"address"`
+	// The volume of the site, it is empty for the tie-breaker.
+	VolumeId string `json:"volumeId,omitempty"`
+	// The backing device of the site, it is empty for the tie-breaker.
+	Disk string `json:"disk,omitempty"`
+}
+
+// resource is a DRBD resource which replicates a primary volume to the
+// secondary volumes of all its replications. It is named after the first
+// replication of the primary volume, since a backing device can't be used by
+// several resources.
+type resource struct {
+	Name  string `json:"name"`
+	Port  int    `json:"port"`
+	Minor int    `json:"minor"`
+	// The primary site of the resource.
+	Primary *peer `json:"primary"`
+	// The secondary sites of the resource, the key is the replication id.
+	Secondaries map[string]*peer `json:"secondaries"`
+	// The diskless site which breaks the tie of quorum, optional.
+	TieBreaker *peer `json:"tieBreaker,omitempty"`
+}
+
+// newPeer creates the site on the configured host.
+func newPeer(h godrbdutils.Host, volId, disk string) *peer {
+	return &peer{Host: h.Name, NodeId: h.ID, Address: h.IP, VolumeId: volId, Disk: disk}
+}
+
+// hosts checks the configured hosts and returns them by hostname.
+func (c *drbdConf) hosts() (map[string]godrbdutils.Host, error) {
+	if len(c.Hosts) < 2 || len(c.Hosts) > maxPeers+1 {
+		return nil, fmt.Errorf("Your configuration should contain 2 to %d hosts", maxPeers+1)
+	}
+	hosts := make(map[string]godrbdutils.Host)
+	ids := make(map[int]bool)
+	ips := make(map[string]bool)
+	for _, h := range c.Hosts {
+		if h.Name == "" || h.IP == "" {
+			return nil, fmt.Errorf("Host %q of your configuration has no name or IP", h.Name)
+		}
+		if _, ok := hosts[h.Name]; ok || ids[h.ID] || ips[h.IP] {
+			return nil, fmt.Errorf("Host %q of your configuration has duplicated name, Node-ID or IP", h.Name)
+		}
+		hosts[h.Name], ids[h.ID], ips[h.IP] = h, true, true
+	}
+	if _, ok := hosts[c.TieBreaker]; c.TieBreaker != "" && !ok {
+		return nil, fmt.Errorf("Tie-breaker %q is not one of the hosts", c.TieBreaker)
+	}
+	return hosts, nil
+}
+
+// addSecondary adds the secondary site of the replication to the resource,
+// it is updated if the replication is added already.
+func (r *resource) addSecondary(replicationId string, p *peer) error {
+	if old, ok := r.Secondaries[replicationId]; ok && old.Host == p.Host {
+		r.Secondaries[replicationId] = p
+		return nil
+	}
+	if p.Host == r.Primary.Host {
+		return fmt.Errorf("host %s is the primary site of drbd resource %s already", p.Host, r.Name)
+	}
+	for id, s := range r.Secondaries {
+		if s.Host == p.Host {
+			return fmt.Errorf("host %s is a secondary site of drbd resource %s already for replication %s",
+				p.Host, r.Name, id)
+		}
+	}
+	if len(r.Secondaries) >= maxSecondaries {
+		return fmt.Errorf("drbd resource %s has %d secondary sites already", r.Name, maxSecondaries)
+	}
+	r.Secondaries[replicationId] = p
+	return nil
+}
+
+// peers returns all the sites of the resource ordered by node id.
+func (r *resource) peers() []*peer {
+	peers := []*peer{r.Primary}
+	for _, s := range r.Secondaries {
+		peers = append(peers, s)
+	}
+	if r.TieBreaker != nil {
+		peers = append(peers, r.TieBreaker)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].NodeId < peers[j].NodeId })
+	return peers
+}
+
+// config renders the configuration of the resource. The primary site connects
+// to all the secondary sites, and each secondary site connects to the primary
+// site only, so a secondary host only needs to know its own replication. The
+// tie-breaker connects to all the sites, which makes the majority quorum work
+// when the primary site is lost.
+func (r *resource) config() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "resource %s {\n", r.Name)
+	if r.TieBreaker != nil {
+		b.WriteString(indent(1, "options {\n"))
+		b.WriteString(indent(2, "quorum majority;\n"))
+		b.WriteString(indent(2, "on-no-quorum io-error;\n"))
+		b.WriteString(indent(1, "}\n\n"))
+	}
+
+	peers := r.peers()
+	for _, p := range peers {
+		disk := p.Disk
+		if disk == "" {
+			disk = "none"
+		}
+		b.WriteString(indent(1, "on %s {\n", p.Host))
+		b.WriteString(indent(2, "node-id %d;\n", p.NodeId))
+		b.WriteString(indent(2, "address %s:%d;\n", p.Address, r.Port))
+		b.WriteString(indent(2, "volume 0 {\n")) // currently only one volume per DRBD resource
+		b.WriteString(indent(3, "device minor %d;\n", r.Minor))
+		b.WriteString(indent(3, "disk %s;\n", disk))
+		b.WriteString(indent(3, "meta-disk internal;\n"))
+		b.WriteString(indent(2, "}\n"))
+		b.WriteString(indent(1, "}\n\n"))
+	}
+
+	for i, p := range peers {
+		for _, q := range peers[i+1:] {
+			if p != r.Primary && q != r.Primary && p != r.TieBreaker && q != r.TieBreaker {
+				// The secondary sites are not connected to each other.
+				continue
+			}
+			b.WriteString(indent(1, "connection {\n"))
+			b.WriteString(indent(2, "host %s;\n", p.Host))
+			b.WriteString(indent(2, "host %s;\n", q.Host))
+			b.WriteString(indent(1, "}\n"))
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func indent(level int, format string, a ...interface{}) string {
+	return fmt.Sprintf(fmt.Sprintf("%*s", level*3, "")+format, a...)
+}
+
+// writeConfig regenerates the resource file, it is written to a temporary
+// file first so that drbdadm never reads a partial configuration.
+func (r *resource) writeConfig() error {
+	if err := os.MkdirAll(resDir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(resDir, ".opensds-drbd-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(r.config()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), resFilePath(r.Name))
+}
+
+// findResource finds the resource of the replication, the key of the
+// resource is returned as well.
+func findResource(resources map[string]*resource, replicationId string) (string, *resource) {
+	for key, res := range resources {
+		if _, ok := res.Secondaries[replicationId]; ok {
+			return key, res
+		}
+	}
+	return "", nil
+}
+
+// allocate allocates the port and minor of a new resource, which are not used
+// by the other resources on this host. The ones allocated by the primary site
+// are used if given, so that all the sites of the resource agree on them.
+func allocate(resources map[string]*resource, usedPorts, usedMinors []int, portMin, portMax, port, minor int) (int, int, error) {
+	ports := make(map[int]string)
+	minors := make(map[int]string)
+	for _, res := range resources {
+		ports[res.Port], minors[res.Minor] = res.Name, res.Name
+		usedPorts = append(usedPorts, res.Port)
+		usedMinors = append(usedMinors, res.Minor)
+	}
+
+	var err error
+	if port > 0 {
+		if name, ok := ports[port]; ok {
+			return -1, -1, fmt.Errorf("port %d is used by drbd resource %s on this host", port, name)
+		}
+	} else if port, err = godrbdutils.GetNumber(portMin, portMax, usedPorts); err != nil {
+		return -1, -1, err
+	}
+	if minor > 0 {
+		if name, ok := minors[minor]; ok {
+			return -1, -1, fmt.Errorf("minor %d is used by drbd resource %s on this host", minor, name)
+		}
+	} else if minor, err = godrbdutils.GetNumber(minorMin, minorMax, usedMinors); err != nil {
+		return -1, -1, err
+	}
+	return port, minor, nil
+}
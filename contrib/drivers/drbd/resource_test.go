@@ -0,0 +1,126 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"testing"
+)
+
+func TestResourceConfig(t *testing.T) {
+	res := &resource{
+		Name:    "r1",
+		Port:    7000,
+		Minor:   1,
+		Primary: &peer{Host: "a", NodeId: 0, Address: "10.0.0.1", VolumeId: "v1", Disk: "/dev/sda"},
+		Secondaries: map[string]*peer{
+			"r1": {Host: "b", NodeId: 1, Address: "10.0.0.2", VolumeId: "v2", Disk: "/dev/sdb"},
+			"r2": {Host: "c", NodeId: 2, Address: "10.0.0.3", VolumeId: "v3", Disk: "/dev/sdc"},
+		},
+		TieBreaker: &peer{Host: "t", NodeId: 3, Address: "10.0.0.4"},
+	}
+	expected := `resource r1 {
+   options {
+      quorum majority;
+      on-no-quorum io-error;
+   }
+
+   on a {
+      node-id 0;
+      address 10.0.0.1:7000;
+      volume 0 {
+         device minor 1;
+         disk /dev/sda;
+         meta-disk internal;
+      }
+   }
+
+   on b {
+      node-id 1;
+      address 10.0.0.2:7000;
+      volume 0 {
+         device minor 1;
+         disk /dev/sdb;
+         meta-disk internal;
+      }
+   }
+
+   on c {
+      node-id 2;
+      address 10.0.0.3:7000;
+      volume 0 {
+         device minor 1;
+         disk /dev/sdc;
+         meta-disk internal;
+      }
+   }
+
+   on t {
+      node-id 3;
+      address 10.0.0.4:7000;
+      volume 0 {
+         device minor 1;
+         disk none;
+         meta-disk internal;
+      }
+   }
+
+   connection {
+      host a;
+      host b;
+   }
+   connection {
+      host a;
+      host c;
+   }
+   connection {
+      host a;
+      host t;
+   }
+   connection {
+      host b;
+      host t;
+   }
+   connection {
+      host c;
+      host t;
+   }
+}
+`
+	if cfg := string(res.config()); cfg != expected {
+		t.Errorf("Expected config:\n%s\ngot:\n%s", expected, cfg)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	resources := map[string]*resource{"v1": {Name: "r1", Port: 7000, Minor: 1}}
+	port, minor, err := allocate(resources, []int{7001}, nil, 7000, 8000, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port != 7002 || minor != 2 {
+		t.Errorf("Expected port 7002 and minor 2, got %d and %d", port, minor)
+	}
+
+	if port, minor, err = allocate(resources, nil, nil, 7000, 8000, 7005, 5); err != nil {
+		t.Fatal(err)
+	}
+	if port != 7005 || minor != 5 {
+		t.Errorf("Expected the given port 7005 and minor 5, got %d and %d", port, minor)
+	}
+
+	if _, _, err = allocate(resources, nil, nil, 7000, 8000, 7000, 5); err == nil {
+		t.Error("Expected allocating the port used by another resource to fail")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/exec"
+)
+
+const (
+	diskUpToDate   = "UpToDate"
+	diskOutdated   = "Outdated"
+	diskConsistent = "Consistent"
+	connected      = "Connected"
+	standAlone     = "StandAlone"
+	established    = "Established"
+	rolePrimary    = "Primary"
+)
+
+// The following types are the parts of `drbdsetup status --json` output which
+// are used to tell the health of resources.
+type peerDeviceStatus struct {
+	Volume           int     `json:"volume"`
+	ReplicationState string  `json:"replication-state"`
+	PeerDiskState    string  `json:"peer-disk-state"`
+	PercentInSync    float64 `json:"percent-in-sync"`
+}
+
+type connectionStatus struct {
+	Name            string              `json:"name"`
+	ConnectionState string              `json:"connection-state"`
+	PeerDevices     []*peerDeviceStatus `json:"peer_devices"`
+}
+
+type deviceStatus struct {
+	Volume    int    `json:"volume"`
+	DiskState string `json:"disk-state"`
+}
+
+type resourceStatus struct {
+	Name        string              `json:"name"`
+	Role        string              `json:"role"`
+	Devices     []*deviceStatus     `json:"devices"`
+	Connections []*connectionStatus `json:"connections"`
+}
+
+// drbdStatus gets the status of the DRBD resources, all of them if none is
+// given. It is a variable so that it can be faked in unit tests.
+var drbdStatus = func(resNames ...string) ([]byte, error) {
+	args := append([]string{"status"}, resNames...)
+	out, err := exec.Run("drbdsetup", append(args, "--json")...)
+	return []byte(out), err
+}
+
+func listResources(resNames ...string) ([]*resourceStatus, error) {
+	out, err := drbdStatus(resNames...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drbd status: %v", err)
+	}
+	var resources []*resourceStatus
+	if err := json.Unmarshal(out, &resources); err != nil {
+		return nil, fmt.Errorf("failed to parse drbd status: %v", err)
+	}
+	return resources, nil
+}
+
+// getResource gets the status of the DRBD resource of the replication.
+func getResource(resName string) (*resourceStatus, error) {
+	resources, err := listResources(resName)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if res.Name == resName {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("drbd resource %s is not found", resName)
+}
+
+// pairStatus tells the state of a DRBD resource from its local disks and the
+// connections to its peers. The peers are written synchronously, so they are
+// never behind the local site while they are connected and up to date.
+func pairStatus(res *resourceStatus) *model.ReplicationPairStatus {
+	var reasons []string
+	for _, dev := range res.Devices {
+		if dev.DiskState != diskUpToDate {
+			reasons = append(reasons, fmt.Sprintf("volume %d disk is %s", dev.Volume, dev.DiskState))
+		}
+	}
+	if len(res.Connections) == 0 {
+		reasons = append(reasons, "no peer is configured")
+	}
+
+	running, progress := model.ReplicationRunningSynced, 100.0
+	for _, conn := range res.Connections {
+		if conn.ConnectionState != connected {
+			reasons = append(reasons, fmt.Sprintf("connection to %s is %s", conn.Name, conn.ConnectionState))
+			if conn.ConnectionState == standAlone {
+				running = model.ReplicationRunningSplit
+			} else if running != model.ReplicationRunningSplit {
+				running = model.ReplicationRunningInterrupted
+			}
+			continue
+		}
+		for _, pd := range conn.PeerDevices {
+			if pd.PeerDiskState == diskUpToDate {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("volume %d disk of %s is %s (%s)",
+				pd.Volume, conn.Name, pd.PeerDiskState, pd.ReplicationState))
+			if pd.ReplicationState != established && running == model.ReplicationRunningSynced {
+				running = model.ReplicationRunningSyncing
+			}
+			if pd.PercentInSync < progress {
+				progress = pd.PercentInSync
+			}
+		}
+	}
+	if len(res.Connections) == 0 {
+		running = model.ReplicationRunningUnknown
+	}
+
+	lag := int64(0)
+	if running != model.ReplicationRunningSynced {
+		lag = -1
+	}
+	if running == model.ReplicationRunningSplit || running == model.ReplicationRunningInterrupted ||
+		running == model.ReplicationRunningUnknown {
+		// The peers which can't be reached are not synchronized any more.
+		progress = 0
+	}
+	return &model.ReplicationPairStatus{
+		Id:            res.Name,
+		Role:          strings.ToLower(res.Role),
+		Healthy:       len(reasons) == 0,
+		Reason:        strings.Join(reasons, "; "),
+		RunningStatus: running,
+		SyncProgress:  progress,
+		RpoLag:        lag,
+	}
+}
+
+// ListReplicationPairs reports the health of the DRBD resources, which are
+// named after the replication ids.
+func (r *ReplicationDriver) ListReplicationPairs() ([]*model.ReplicationPairStatus, error) {
+	resources, err := listResources()
+	if err != nil {
+		return nil, err
+	}
+	var pairs []*model.ReplicationPairStatus
+	for _, res := range resources {
+		pairs = append(pairs, pairStatus(res))
+	}
+	return pairs, nil
+}
+
+// GetReplicationStatus reports the state of the DRBD resource of the
+// replication. The resource may be shared by several replications of the
+// primary volume, so only the connection between the sites of the replication
+// is taken into account.
+func (r *ReplicationDriver) GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	stored := lookupResource(opt.GetId())
+	resName := opt.GetId()
+	if stored != nil {
+		resName = stored.Name
+	}
+	res, err := getResource(resName)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		sites := map[string]bool{stored.Primary.Host: true, stored.Secondaries[opt.GetId()].Host: true}
+		var conns []*connectionStatus
+		for _, conn := range res.Connections {
+			if sites[conn.Name] {
+				conns = append(conns, conn)
+			}
+		}
+		res.Connections = conns
+	}
+	st := pairStatus(res)
+	st.Id = opt.GetId()
+	return st, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+const sampleStatus = `[
+{"name": "synced", "role": "Primary",
+ "devices": [{"volume": 0, "disk-state": "UpToDate"}],
+ "connections": [{"name": "peer", "connection-state": "Connected",
+   "peer_devices": [{"volume": 0, "replication-state": "Established", "peer-disk-state": "UpToDate", "percent-in-sync": 100}]}]},
+{"name": "syncing", "role": "Primary",
+ "devices": [{"volume": 0, "disk-state": "UpToDate"}],
+ "connections": [{"name": "peer", "connection-state": "Connected",
+   "peer_devices": [{"volume": 0, "replication-state": "SyncSource", "peer-disk-state": "Inconsistent", "percent-in-sync": 42.5}]}]},
+{"name": "split", "role": "Secondary",
+ "devices": [{"volume": 0, "disk-state": "UpToDate"}],
+ "connections": [{"name": "peer", "connection-state": "StandAlone"}]}
+]`
+
+func TestReplicationStatus(t *testing.T) {
+	defer func(f func(...string) ([]byte, error)) { drbdStatus = f }(drbdStatus)
+	var args []string
+	drbdStatus = func(resNames ...string) ([]byte, error) {
+		args = resNames
+		return []byte(sampleStatus), nil
+	}
+	r := &ReplicationDriver{}
+
+	pairs, err := r.ListReplicationPairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*model.ReplicationPairStatus{
+		{Id: "synced", Role: "primary", Healthy: true, RunningStatus: model.ReplicationRunningSynced, SyncProgress: 100},
+		{Id: "syncing", Role: "primary", Reason: "volume 0 disk of peer is Inconsistent (SyncSource)",
+			RunningStatus: model.ReplicationRunningSyncing, SyncProgress: 42.5, RpoLag: -1},
+		{Id: "split", Role: "secondary", Reason: "connection to peer is StandAlone",
+			RunningStatus: model.ReplicationRunningSplit, RpoLag: -1},
+	}
+	if !reflect.DeepEqual(pairs, expected) {
+		for i := range pairs {
+			t.Errorf("Expected %+v, got %+v", expected[i], pairs[i])
+		}
+	}
+
+	st, err := r.GetReplicationStatus(&pb.GetReplicationStatusOpts{Id: "syncing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(args, []string{"syncing"}) || !reflect.DeepEqual(st, expected[1]) {
+		t.Errorf("Expected %+v of resource syncing, got %+v of %v", expected[1], st, args)
+	}
+	if _, err = r.GetReplicationStatus(&pb.GetReplicationStatusOpts{Id: "missing"}); err == nil {
+		t.Error("Expected error of missing resource")
+	}
+
+	drbdStatus = func(resNames ...string) ([]byte, error) {
+		return nil, errors.New("exit status 10")
+	}
+	if _, err = r.GetReplicationStatus(&pb.GetReplicationStatusOpts{Id: "synced"}); err == nil {
+		t.Error("Expected error of drbdsetup")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drbd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	log "github.com/golang/glog"
+)
+
+// resourceStore persists the DRBD resources of this host in a json file, the
+// key of the resources is the id of the primary volume. The file is locked
+// while it is updated, so that the concurrent requests, even of several
+// processes, allocate the ports and minors atomically.
+type resourceStore struct {
+	path string
+}
+
+func newResourceStore() *resourceStore {
+	return &resourceStore{path: statePath}
+}
+
+func (s *resourceStore) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+func (s *resourceStore) load() (map[string]*resource, error) {
+	resources := make(map[string]*resource)
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return resources, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func (s *resourceStore) save(resources map[string]*resource) error {
+	data, err := json.MarshalIndent(resources, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), ".resources-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// update runs fn on the resources with the file locked, and saves them if fn
+// succeeds.
+func (s *resourceStore) update(fn func(resources map[string]*resource) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	resources, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(resources); err != nil {
+		return err
+	}
+	return s.save(resources)
+}
+
+// lookupResource gets the persisted DRBD resource of the replication, nil is
+// returned if it is not persisted.
+func lookupResource(replicationId string) *resource {
+	// The file is replaced atomically, so it is read without the lock.
+	resources, err := newResourceStore().load()
+	if err != nil {
+		log.Warningf("Failed to load drbd resources: %v", err)
+		return nil
+	}
+	_, res := findResource(resources, replicationId)
+	return res
+}
+
+// resourceName gets the name of the DRBD resource of the replication. The
+// resources which are not persisted are named after their replications.
+func resourceName(replicationId string) string {
+	if res := lookupResource(replicationId); res != nil {
+		return res.Name
+	}
+	return replicationId
+}
@@ -81,6 +81,12 @@ type VolumeDriver interface {
 	// their status.
 	DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error
 
+	// CreateGroupSnapshot snapshots all the volumes in the group at the same
+	// point in time, so that the snapshots are crash-consistent.
+	CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error)
+
+	DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error
+
 	ListPools() ([]*model.StoragePoolSpec, error)
 }
 
@@ -118,7 +124,9 @@ func Init(resourceType string) VolumeDriver {
 		d = &sample.Driver{}
 		break
 	}
+	d = &tracedDriver{VolumeDriver: d, name: resourceType}
 	d.Setup()
+	trackVolumeDriver(d, true)
 	return d
 }
 
@@ -147,7 +155,11 @@ func Clean(d VolumeDriver) VolumeDriver {
 	default:
 		break
 	}
-	d.Unset()
+	// The drivers left by the rpcs which are stopped on shutdown are unset by
+	// CleanAll, so they are not unset again once the rpcs return.
+	if trackVolumeDriver(d, false) {
+		d.Unset()
+	}
 	d = nil
 
 	return d
@@ -161,7 +173,9 @@ func CleanMetricDriver(d MetricDriver) MetricDriver {
 	default:
 		break
 	}
-	_ = d.Teardown()
+	if trackMetricDriver(d, false) {
+		_ = d.Teardown()
+	}
 	d = nil
 
 	return d
@@ -193,6 +207,11 @@ func InitMetricDriver(resourceType string) MetricDriver {
 		//d = &sample.Driver{}
 		break
 	}
+	if d == nil {
+		return nil
+	}
+	d = &tracedMetricDriver{MetricDriver: d, name: resourceType}
 	d.Setup()
+	trackMetricDriver(d, true)
 	return d
 }
@@ -21,12 +21,14 @@ import (
 	"github.com/sodafoundation/dock/contrib/drivers/ceph"
 	"github.com/sodafoundation/dock/contrib/drivers/lvm"
 	"github.com/sodafoundation/dock/contrib/drivers/openstack/cinder"
+	"github.com/sodafoundation/dock/pkg/dock/metrics"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
 	sample "github.com/sodafoundation/dock/testutils/driver"
 )
 
 func TestInit(t *testing.T) {
 	var rsList = []string{"others"}
-	var expectedVd = []VolumeDriver{&sample.Driver{}}
+	var expectedVd = []VolumeDriver{&tracedDriver{VolumeDriver: &sample.Driver{}, name: "others"}}
 
 	for i, rs := range rsList {
 		if vp := Init(rs); !reflect.DeepEqual(vp, expectedVd[i]) {
@@ -35,6 +37,32 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestObserveDriverOperation(t *testing.T) {
+	d := Init("others")
+	defer Clean(d)
+	d.CreateVolume(&pb.CreateVolumeOpts{})
+
+	mfs, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != "opensds_dock_driver_operation_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["driver"] == "others" && labels["operation"] == "CreateVolume" && m.GetHistogram().GetSampleCount() != 0 {
+				return
+			}
+		}
+	}
+	t.Error("Expected duration of CreateVolume to be observed")
+}
+
 func TestClean(t *testing.T) {
 	var driverList = []VolumeDriver{
 		&ceph.Driver{},
@@ -49,3 +77,19 @@ func TestClean(t *testing.T) {
 		}
 	}
 }
+
+func TestCleanAll(t *testing.T) {
+	CleanAll()
+	d := Init("others")
+	Clean(Init("others"))
+	if n := CleanAll(); n != 1 {
+		t.Errorf("Expected 1 live driver, got %d", n)
+	}
+	if n := CleanAll(); n != 0 {
+		t.Errorf("Expected no live driver, got %d", n)
+	}
+	// The driver unset by CleanAll is not unset again when it is cleaned.
+	if trackVolumeDriver(d, false) {
+		t.Error("Expected the driver not to be live after CleanAll")
+	}
+}
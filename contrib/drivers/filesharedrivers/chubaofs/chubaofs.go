@@ -88,7 +88,7 @@ type Driver struct {
 
 func (d *Driver) Setup() error {
 	conf := &Config{}
-	path := config.CONF.OsdsDock.Backends.Chubaofs.ConfigPath
+	path := config.GetBackends().Chubaofs.ConfigPath
 	if "" == path {
 		path = DefaultConfPath
 	}
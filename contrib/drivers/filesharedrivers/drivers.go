@@ -76,7 +76,9 @@ func Init(resourceType string) FileShareDriver {
 		f = &sample.Driver{}
 		break
 	}
+	f = &tracedDriver{FileShareDriver: f, name: resourceType}
 	f.Setup()
+	track(f, true)
 	return f
 }
 
@@ -95,7 +97,10 @@ func Clean(f FileShareDriver) FileShareDriver {
 	default:
 		break
 	}
-	_ = f.Unset()
+	// The drivers unset by CleanAll on shutdown are not unset again.
+	if track(f, false) {
+		_ = f.Unset()
+	}
 	f = nil
 
 	return f
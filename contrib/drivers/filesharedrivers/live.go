@@ -0,0 +1,66 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesharedrivers
+
+import (
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+// live records the drivers which are set up by Init and not cleaned yet, so
+// that they can still be unset if the dock shuts down before the operations
+// using them finish. The drivers are counted since the ones of zero size,
+// such as the sample driver, may share the same address.
+var live = struct {
+	sync.Mutex
+	drivers map[FileShareDriver]int
+}{drivers: make(map[FileShareDriver]int)}
+
+// track records that f is set up or cleaned. When f is cleaned, it returns
+// false if f is not live any more, that is, it is unset by CleanAll already.
+func track(f FileShareDriver, set bool) bool {
+	live.Lock()
+	defer live.Unlock()
+	if set {
+		live.drivers[f]++
+		return true
+	}
+	count, ok := live.drivers[f]
+	if count > 1 {
+		live.drivers[f]--
+	} else {
+		delete(live.drivers, f)
+	}
+	return ok
+}
+
+// CleanAll unsets the fileshare drivers which are not cleaned yet, it returns
+// the number of them.
+func CleanAll() int {
+	live.Lock()
+	drivers := live.drivers
+	live.drivers = make(map[FileShareDriver]int)
+	live.Unlock()
+
+	n := 0
+	for f, count := range drivers {
+		n += count
+		if err := f.Unset(); err != nil {
+			log.Error("when unsetting fileshare driver:", err)
+		}
+	}
+	return n
+}
@@ -22,6 +22,7 @@ package manila
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack"
 	sharesv2 "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
 	snapshotsv2 "github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/snapshots"
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 	driverConfig "github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
@@ -77,7 +79,7 @@ type Config struct {
 func (d *Driver) Setup() error {
 	// Read manila config file
 	d.conf = &Config{}
-	p := config.CONF.OsdsDock.Backends.Manila.ConfigPath
+	p := config.GetBackends().Manila.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -268,35 +270,35 @@ func (d *Driver) PullFileShare(ID string) (*model.FileShareSpec, error) {
 
 // CreateFileShareAcl implementation
 func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (fshare *model.FileShareAclSpec, err error) {
-	var accessLevel string
-	accessCapability := opt.GetAccessCapability()
-	canRead, canWrite, canExecute := false, false, false
-	for _, v := range accessCapability {
-		switch strings.ToLower(v) {
-		case "read":
-			canRead = true
-		case "write":
-			canWrite = true
-		case "execute":
-			canExecute = true
-		default:
-			return nil, errors.New("accessCapability can only be read, write or execute")
-		}
+	accessType, accessTo := strings.ToLower(opt.Type), opt.GetAccessTo()
+	accessLevel, err := odu.ParseAccessLevel(opt.GetAccessCapability())
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
 
-	switch {
-	case canRead && !canWrite && !canExecute:
-		accessLevel = "ro"
-	case canRead && canWrite && !canExecute:
-		accessLevel = "rw"
-	default:
-		return nil, errors.New("only read only and read write access level are supported")
+	switch accessType {
+	case odu.AccessTypeIp, odu.AccessTypeCidr, odu.AccessTypeNetgroup, odu.AccessTypeHostname:
+		rule, err := odu.ParseNfsExportRule(opt)
+		if err != nil {
+			log.Error("invalid nfs acl:", err)
+			return nil, err
+		}
+		// Manila only accepts ip rules, which can also be a cidr, and has
+		// no per-rule export options.
+		if accessType == odu.AccessTypeNetgroup || accessType == odu.AccessTypeHostname {
+			return nil, fmt.Errorf("access type %s is not supported by manila", accessType)
+		}
+		if !rule.IsDefault() {
+			return nil, errors.New("manila does not support squash, security flavor or sync mode in acl")
+		}
+		accessType, accessTo = odu.AccessTypeIp, rule.AccessTo
 	}
 
 	// Configure request body.
 	opts := &sharesv2.GrantAccessOpts{
-		AccessType:  opt.Type,
-		AccessTo:    opt.GetAccessTo(),
+		AccessType:  accessType,
+		AccessTo:    accessTo,
 		AccessLevel: accessLevel,
 	}
 
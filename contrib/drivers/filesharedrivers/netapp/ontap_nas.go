@@ -16,6 +16,8 @@ package netapp
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -27,8 +29,11 @@ import (
 	sa "github.com/netapp/trident/storage_attribute"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/ontap"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
 	"github.com/netapp/trident/utils"
 
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 	. "github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
@@ -54,7 +59,7 @@ func (d *NASDriver) Setup() error {
 	// Read NetApp ONTAP config file
 	d.conf = &ONTAPConfig{}
 
-	p := config.CONF.OsdsDock.Backends.NetappOntapNas.ConfigPath
+	p := config.GetBackends().NetappOntapNas.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -286,10 +291,162 @@ func (d *NASDriver) DeleteFileShareSnapshot(opts *pb.DeleteFileShareSnapshotOpts
 	return nil
 }
 
+// exportRuleDestroyRequest is the export-rule-destroy zapi, which is not
+// provided by the vendored azgo package.
+type exportRuleDestroyRequest struct {
+	XMLName    xml.Name `xml:"export-rule-destroy"`
+	PolicyName string   `xml:"policy-name"`
+	RuleIndex  int      `xml:"rule-index"`
+}
+
+func (o *exportRuleDestroyRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	return string(output), err
+}
+
+type exportRuleDestroyResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+	Result  struct {
+		ResultStatusAttr string `xml:"status,attr"`
+		ResultReasonAttr string `xml:"reason,attr"`
+		ResultErrnoAttr  string `xml:"errno,attr"`
+	} `xml:"results"`
+}
+
+func (d *NASDriver) zapiRunner() *azgo.ZapiRunner {
+	zr := d.nasStorageDriver.API.GetNontunneledZapiRunner()
+	zr.SVM = d.conf.Svm
+	return zr
+}
+
+// getExportRuleIndex returns the index of the rule which matches the client
+// in the export policy, or 0 if there is no such rule.
+func (d *NASDriver) getExportRuleIndex(policy, clientMatch string) (int, error) {
+	resp, err := d.nasStorageDriver.API.ExportRuleGetIterRequest(policy)
+	if err = api.GetError(resp, err); err != nil {
+		return 0, err
+	}
+	if resp.Result.AttributesListPtr == nil {
+		return 0, nil
+	}
+	for _, rule := range resp.Result.AttributesListPtr.ExportRuleInfo() {
+		if rule.ClientMatchPtr != nil && rule.ClientMatch() == clientMatch && rule.RuleIndexPtr != nil {
+			return rule.RuleIndex(), nil
+		}
+	}
+	return 0, nil
+}
+
+func toSecurityFlavors(flavors ...string) []azgo.SecurityFlavorType {
+	var result []azgo.SecurityFlavorType
+	for _, f := range flavors {
+		result = append(result, azgo.SecurityFlavorType(f))
+	}
+	return result
+}
+
+// CreateFileShareAcl adds a rule for the client to the export policy of the
+// backend. ONTAP expresses the squash mode with the security flavors of the
+// superuser and the anonymous user, and always exports synchronously.
 func (d *NASDriver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.FileShareAclSpec, error) {
-	return nil, &model.NotImplementError{"method is not implemented"}
+	rule, err := odu.ParseNfsExportRule(opt)
+	if err != nil {
+		log.Error("invalid nfs acl:", err)
+		return nil, err
+	}
+	if rule.SyncMode == odu.SyncModeAsync {
+		return nil, errors.New("async export is not supported by netapp ontap nas")
+	}
+	if rule.AnonGid != 0 {
+		return nil, errors.New("anonymous gid is not supported by netapp ontap nas")
+	}
+
+	policy := d.nasStorageDriver.Config.ExportPolicy
+	index, err := d.getExportRuleIndex(policy, rule.ClientMatch())
+	if err != nil {
+		log.Errorf("get export rule of %s failed: %v", rule.ClientMatch(), err)
+		return nil, err
+	}
+	if index != 0 {
+		return nil, fmt.Errorf("export rule of %s already exists in policy %s", rule.ClientMatch(), policy)
+	}
+
+	roFlavors, rwFlavors, suFlavors := rule.SecurityFlavors, rule.SecurityFlavors, []string{"none"}
+	if rule.AccessLevel == odu.AccessLevelRo {
+		rwFlavors = []string{"never"}
+	}
+	switch rule.Squash {
+	case odu.NoRootSquash:
+		suFlavors = rule.SecurityFlavors
+	case odu.AllSquash:
+		roFlavors = []string{"none"}
+		if rule.AccessLevel == odu.AccessLevelRw {
+			rwFlavors = []string{"none"}
+		}
+	}
+
+	protocol := azgo.ExportRuleCreateRequestProtocol{
+		AccessProtocolPtr: []azgo.AccessProtocolType{azgo.AccessProtocolType(NFSProtocol)},
+	}
+	roRule := azgo.ExportRuleCreateRequestRoRule{SecurityFlavorPtr: toSecurityFlavors(roFlavors...)}
+	rwRule := azgo.ExportRuleCreateRequestRwRule{SecurityFlavorPtr: toSecurityFlavors(rwFlavors...)}
+	suRule := azgo.ExportRuleCreateRequestSuperUserSecurity{SecurityFlavorPtr: toSecurityFlavors(suFlavors...)}
+	req := azgo.NewExportRuleCreateRequest().
+		SetPolicyName(azgo.ExportPolicyNameType(policy)).
+		SetClientMatch(rule.ClientMatch()).
+		SetProtocol(protocol).
+		SetRoRule(roRule).
+		SetRwRule(rwRule).
+		SetSuperUserSecurity(suRule)
+	if rule.AnonUid != 0 {
+		req.SetAnonymousUserId(strconv.FormatInt(rule.AnonUid, 10))
+	}
+	resp, err := req.ExecuteUsing(d.zapiRunner())
+	if err = api.GetError(resp, err); err != nil {
+		log.Errorf("create export rule of %s failed: %v", rule.ClientMatch(), err)
+		return nil, err
+	}
+
+	return &model.FileShareAclSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.Id,
+		},
+		FileShareId:      opt.FileshareId,
+		Type:             rule.AccessType,
+		AccessCapability: opt.GetAccessCapability(),
+		AccessTo:         rule.AccessTo,
+		Description:      opt.Description,
+		Squash:           rule.Squash,
+		AnonUid:          rule.AnonUid,
+		SecurityFlavors:  rule.SecurityFlavors,
+		SyncMode:         rule.SyncMode,
+		Metadata:         map[string]string{},
+	}, nil
 }
 
 func (d *NASDriver) DeleteFileShareAcl(opt *pb.DeleteFileShareAclOpts) error {
-	return &model.NotImplementError{"method is not implemented"}
+	clientMatch, err := odu.NfsClientMatch(opt.GetType(), opt.GetAccessTo())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	policy := d.nasStorageDriver.Config.ExportPolicy
+	index, err := d.getExportRuleIndex(policy, clientMatch)
+	if err != nil {
+		log.Errorf("get export rule of %s failed: %v", clientMatch, err)
+		return err
+	}
+	if index == 0 {
+		log.Warningf("export rule of %s does not exist in policy %s", clientMatch, policy)
+		return nil
+	}
+
+	req := &exportRuleDestroyRequest{PolicyName: policy, RuleIndex: index}
+	resp, err := d.zapiRunner().ExecuteUsing(req, "ExportRuleDestroyRequest", &exportRuleDestroyResponse{})
+	if err = api.GetError(resp, err); err != nil {
+		log.Errorf("delete export rule of %s failed: %v", clientMatch, err)
+		return err
+	}
+	return nil
 }
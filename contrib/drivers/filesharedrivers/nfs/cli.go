@@ -59,7 +59,30 @@ func (c *Cli) GetExportLocation(share_name, ip string) string {
 	return exportLocation
 }
 
-func (c *Cli) CreateAccess(accessto, accesscapability, fname string) error {
+// GetCifsExportLocation returns the UNC path of the samba share.
+func (c *Cli) GetCifsExportLocation(shareName, ip string) string {
+	server := net.ParseIP(ip)
+	if server == nil {
+		glog.Errorf("this is not a valid ip:")
+		return ""
+	}
+	return fmt.Sprintf("\\\\%s\\%s", server, shareName)
+}
+
+// ReloadSamba asks smbd to re-read its configuration, so that the changes
+// of the managed include file take effect without interrupting sessions.
+func (c *Cli) ReloadSamba() error {
+	cmd := []string{
+		"env", "LC_ALL=C",
+		"smbcontrol",
+		"smbd",
+		"reload-config",
+	}
+	_, err := c.execute(cmd...)
+	return err
+}
+
+func (c *Cli) CreateAccess(accessto, options, fname string) error {
 	var accesstoAndMount string
 	sharePath := path.Join(MountPath, fname)
 	accesstoAndMount = fmt.Sprintf("%s:%s", accessto, strings.Replace(sharePath, "-", "_", -1))
@@ -67,7 +90,7 @@ func (c *Cli) CreateAccess(accessto, accesscapability, fname string) error {
 		"env", "LC_ALL=C",
 		"exportfs",
 		"-o",
-		accesscapability,
+		options,
 		accesstoAndMount,
 	}
 	_, err := c.execute(cmd...)
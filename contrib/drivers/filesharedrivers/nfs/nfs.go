@@ -16,14 +16,15 @@ package nfs
 
 import (
 	"errors"
+	"fmt"
 	"path"
 	"strings"
 
 	log "github.com/golang/glog"
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 	. "github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
-	"github.com/sodafoundation/dock/pkg/utils"
 	"github.com/sodafoundation/dock/pkg/utils/config"
 	uuid "github.com/satori/go.uuid"
 )
@@ -32,6 +33,7 @@ const (
 	defaultTgtConfDir = "/etc/tgt/conf.d"
 	defaultTgtBindIp  = "127.0.0.1"
 	defaultConfPath   = "/etc/opensds/driver/nfs.yaml"
+	defaultSambaConf  = "/etc/samba/opensds.conf"
 	FileSharePrefix   = "fileshare-"
 	snapshotPrefix    = "_snapshot-"
 	blocksize         = 4096
@@ -48,6 +50,7 @@ const (
 	KFileshareID       = "nfsFileshareID"
 	KFileshareSnapName = "snapshotName"
 	KFileshareSnapID   = "snapshotID"
+	KFileshareProtocol = "fileshareProtocol"
 	AccessLevelRo      = "ro"
 	AccessLevelRw      = "rw"
 	MountPath          = "/mnt"
@@ -57,18 +60,24 @@ type NFSConfig struct {
 	TgtBindIp      string                    `yaml:"tgtBindIp"`
 	TgtConfDir     string                    `yaml:"tgtConfDir"`
 	EnableChapAuth bool                      `yaml:"enableChapAuth"`
+	SambaConfPath  string                    `yaml:"sambaConfPath"`
 	Pool           map[string]PoolProperties `yaml:"pool,flow"`
 }
 
 type Driver struct {
-	conf *NFSConfig
-	cli  *Cli
+	conf  *NFSConfig
+	cli   *Cli
+	samba *SambaConf
 }
 
 func (d *Driver) Setup() error {
 	// Read nfs config file
-	d.conf = &NFSConfig{TgtBindIp: defaultTgtBindIp, TgtConfDir: defaultTgtConfDir}
-	p := config.CONF.OsdsDock.Backends.NFS.ConfigPath
+	d.conf = &NFSConfig{
+		TgtBindIp:     defaultTgtBindIp,
+		TgtConfDir:    defaultTgtConfDir,
+		SambaConfPath: defaultSambaConf,
+	}
+	p := config.GetBackends().NFS.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -80,14 +89,54 @@ func (d *Driver) Setup() error {
 		return err
 	}
 	d.cli = cli
+	d.samba = NewSambaConf(d.conf.SambaConfPath)
 
 	return nil
 }
 
 func (*Driver) Unset() error { return nil }
 
+// getProtocol returns the share protocol in lower case, nfs is used if the
+// protocol is not specified for backward compatibility.
+func getProtocol(proto string, meta map[string]string) (string, error) {
+	if proto == "" {
+		proto = meta[KFileshareProtocol]
+	}
+	proto = strings.ToLower(proto)
+	switch proto {
+	case "":
+		return NFSProtocol, nil
+	case NFSProtocol, CIFSProtocol:
+		return proto, nil
+	default:
+		return "", fmt.Errorf("%s protocol is not supported, support is %s and %s", proto, NFSProtocol, CIFSProtocol)
+	}
+}
+
+// sambaShareName converts the fileshare name in the same way as the nfs
+// export path does.
+func sambaShareName(name string) string {
+	return strings.Replace(name, "-", "_", -1)
+}
+
+func (d *Driver) createCifsAcl(opt *pb.CreateFileShareAclOpts, access string) error {
+	if strings.ToLower(opt.GetType()) != odu.AccessTypeUser {
+		return errors.New("only user access type is allowed for cifs shares")
+	}
+	if err := d.samba.GrantAccess(sambaShareName(opt.GetName()), opt.GetAccessTo(), access); err != nil {
+		return err
+	}
+	return d.cli.ReloadSamba()
+}
+
+func (d *Driver) deleteCifsAcl(opt *pb.DeleteFileShareAclOpts) error {
+	if err := d.samba.RevokeAccess(sambaShareName(opt.GetName()), opt.GetAccessTo()); err != nil {
+		return err
+	}
+	return d.cli.ReloadSamba()
+}
+
 func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.FileShareAclSpec, error) {
-	var access string
 	// Get accessto list
 	accessTo := opt.GetAccessTo()
 	// get accessCapability list
@@ -95,27 +144,9 @@ func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.File
 	// get fileshare name
 	fname := opt.Name
 
-	permissions := []string{"write"}
-	WriteAccess := false
-
-	for _, value := range accessCapability {
-		value = strings.ToLower(value)
-		if utils.Contains(permissions, value) {
-			WriteAccess = true
-		}
-		if value == "Execute" {
-			log.Error("invalid permission:", value)
-			return nil, nil
-		}
-	}
-	if WriteAccess {
-		access = AccessLevelRw
-	} else {
-		access = AccessLevelRo
-	}
-
-	if err := d.cli.CreateAccess(accessTo, access, fname); err != nil {
-		log.Errorf("grant access %s to %s failed %v", accessTo, fname, err)
+	proto, err := getProtocol(opt.GetAccessProtocol(), opt.GetMetadata())
+	if err != nil {
+		log.Error(err)
 		return nil, err
 	}
 
@@ -129,6 +160,37 @@ func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.File
 		AccessTo:         accessTo,
 		Metadata:         map[string]string{},
 	}
+
+	if proto == CIFSProtocol {
+		access, err := odu.ParseAccessLevel(accessCapability)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		if err := d.createCifsAcl(opt, access); err != nil {
+			log.Errorf("grant access %s to %s failed %v", accessTo, fname, err)
+			return nil, err
+		}
+		return shareAccess, nil
+	}
+
+	rule, err := odu.ParseNfsExportRule(opt)
+	if err != nil {
+		log.Error("invalid nfs acl:", err)
+		return nil, err
+	}
+	if err := d.cli.CreateAccess(rule.ClientMatch(), rule.ExportOptions(), fname); err != nil {
+		log.Errorf("grant access %s to %s failed %v", accessTo, fname, err)
+		return nil, err
+	}
+
+	shareAccess.Type = rule.AccessType
+	shareAccess.AccessTo = rule.AccessTo
+	shareAccess.Squash = rule.Squash
+	shareAccess.AnonUid = rule.AnonUid
+	shareAccess.AnonGid = rule.AnonGid
+	shareAccess.SecurityFlavors = rule.SecurityFlavors
+	shareAccess.SyncMode = rule.SyncMode
 	return shareAccess, nil
 }
 
@@ -138,8 +200,21 @@ func (d *Driver) DeleteFileShareAcl(opt *pb.DeleteFileShareAclOpts) error {
 	// get fileshare name
 	fname := opt.Name
 
-	if err := d.cli.DeleteAccess(accessTo, fname); err != nil {
-		log.Error("cannot revoke access:", err)
+	proto, err := getProtocol(opt.GetAccessProtocol(), opt.GetMetadata())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if proto == CIFSProtocol {
+		err = d.deleteCifsAcl(opt)
+	} else {
+		var client string
+		if client, err = odu.NfsClientMatch(opt.GetType(), accessTo); err == nil {
+			err = d.cli.DeleteAccess(client, fname)
+		}
+	}
+	if err != nil {
+		log.Errorf("cannot revoke access %s from %s: %v", accessTo, fname, err)
 		return err
 	}
 
@@ -160,6 +235,12 @@ func (d *Driver) CreateFileShare(opt *pb.CreateFileShareOpts) (*model.FileShareS
 	// create a fileshare path
 	var lvPath = path.Join("/dev", vg, name)
 
+	proto, err := getProtocol(opt.GetAccessProtocol(), opt.GetMetadata())
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
 	if err := d.cli.CreateDirectory(dirName); err != nil {
 		log.Error("failed to create a directory:", err)
 		return nil, err
@@ -226,8 +307,21 @@ func (d *Driver) CreateFileShare(opt *pb.CreateFileShareOpts) (*model.FileShareS
 	}
 	// get export location of fileshare
 	var location []string
-	location = []string{d.cli.GetExportLocation(name, server)}
-	if len(location) == 0 {
+	if proto == CIFSProtocol {
+		if err := d.samba.AddShare(sambaShareName(name), dirName); err != nil {
+			log.Error("failed to add samba share:", err)
+			return nil, err
+		}
+		if err := d.cli.ReloadSamba(); err != nil {
+			log.Error("failed to reload samba configuration:", err)
+			d.samba.RemoveShare(sambaShareName(name))
+			return nil, err
+		}
+		location = []string{d.cli.GetCifsExportLocation(sambaShareName(name), server)}
+	} else {
+		location = []string{d.cli.GetExportLocation(name, server)}
+	}
+	if len(location) == 0 || location[0] == "" {
 		errMsg := errors.New("failed to get exportlocation: export location is empty!")
 		log.Error(errMsg)
 		return nil, errMsg
@@ -242,13 +336,14 @@ func (d *Driver) CreateFileShare(opt *pb.CreateFileShareOpts) (*model.FileShareS
 		Description:      opt.GetDescription(),
 		AvailabilityZone: opt.GetAvailabilityZone(),
 		PoolId:           opt.GetPoolId(),
-		Protocols:        []string{NFSProtocol},
+		Protocols:        []string{proto},
 		ExportLocations:  location,
 		Metadata: map[string]string{
 			KFileshareName:     name,
 			KFileshareSnapName: "",
 			KFileshareID:       opt.GetId(),
 			KLvPath:            lvPath,
+			KFileshareProtocol: proto,
 		},
 	}
 	return fshare, nil
@@ -298,6 +393,23 @@ func (d *Driver) DeleteFileShare(opt *pb.DeleteFileShareOpts) error {
 	// get directory where fileshare mounted
 	var dirName = path.Join(MountPath, fname)
 
+	proto, err := getProtocol(opt.GetAccessProtocol(), opt.GetMetadata())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	// stop serving the share before unmounting it
+	if proto == CIFSProtocol {
+		if err := d.samba.RemoveShare(sambaShareName(fname)); err != nil {
+			log.Error("failed to remove samba share:", err)
+			return err
+		}
+		if err := d.cli.ReloadSamba(); err != nil {
+			log.Error("failed to reload samba configuration:", err)
+			return err
+		}
+	}
+
 	// umount the volume to directory
 	if err := d.cli.UnMount(dirName); err != nil {
 		log.Error("failed to unmount the directory:", err)
@@ -16,6 +16,9 @@ package nfs
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -64,6 +67,7 @@ func TestSetup(t *testing.T) {
 			TgtBindIp:      "11.242.178.20",
 			TgtConfDir:     "/etc/tgt/conf.d",
 			EnableChapAuth: false,
+			SambaConfPath:  "/etc/samba/opensds.conf",
 		},
 	}
 
@@ -131,10 +135,11 @@ func TestCreateFileShare(t *testing.T) {
 		Protocols:       []string{"nfs"},
 		ExportLocations: []string{"11.242.178.20:/mnt/test001"},
 		Metadata: map[string]string{
-			"lvPath":           "/dev/vg001/test001",
-			"nfsFileshareID":   "e1bb066c-5ce7-46eb-9336-25508cee9f71",
-			"nfsFileshareName": "test001",
-			"snapshotName":     "",
+			"lvPath":            "/dev/vg001/test001",
+			"nfsFileshareID":    "e1bb066c-5ce7-46eb-9336-25508cee9f71",
+			"nfsFileshareName":  "test001",
+			"snapshotName":      "",
+			"fileshareProtocol": "nfs",
 		},
 	}
 	fileshare, err := fd.CreateFileShare(opt)
@@ -146,6 +151,193 @@ func TestCreateFileShare(t *testing.T) {
 	}
 }
 
+func TestCifsFileShare(t *testing.T) {
+	var fd = &Driver{}
+	config.CONF.OsdsDock.Backends.NFS.ConfigPath = "testdata/nfs.yaml"
+	fd.Setup()
+
+	dir, err := ioutil.TempDir("", "opensds-samba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fd.samba = NewSambaConf(filepath.Join(dir, "opensds.conf"))
+
+	respMap := map[string]*FakeResp{
+		"mkdir":      {"", nil},
+		"mke2fs":     {"", nil},
+		"mount":      {"", nil},
+		"umount":     {"", nil},
+		"chmod":      {"", nil},
+		"lvcreate":   {"", nil},
+		"lvremove":   {"", nil},
+		"lvs":        {"test-cifs", nil},
+		"rm":         {"", nil},
+		"smbcontrol": {"", nil},
+	}
+	fd.cli.RootExecuter = NewFakeExecuter(respMap)
+	fd.cli.BaseExecuter = NewFakeExecuter(respMap)
+
+	fileshare, err := fd.CreateFileShare(&pb.CreateFileShareOpts{
+		Id:             "e1bb066c-5ce7-46eb-9336-25508cee9f72",
+		Name:           "test-cifs",
+		Size:           int64(1),
+		PoolName:       "vg001",
+		AccessProtocol: "CIFS",
+	})
+	if err != nil {
+		t.Fatal("Failed to create cifs fileshare:", err)
+	}
+	if !reflect.DeepEqual(fileshare.Protocols, []string{"cifs"}) {
+		t.Errorf("Expected protocols %v, got %v\n", []string{"cifs"}, fileshare.Protocols)
+	}
+	if expected := `\\11.242.178.20\test_cifs`; fileshare.ExportLocations[0] != expected {
+		t.Errorf("Expected export location %s, got %s\n", expected, fileshare.ExportLocations[0])
+	}
+
+	for _, acl := range []*pb.CreateFileShareAclOpts{
+		{Type: "user", AccessTo: "alice", AccessCapability: []string{"Read"}, Name: "test-cifs", Metadata: fileshare.Metadata},
+		{Type: "user", AccessTo: "bob", AccessCapability: []string{"Read", "Write"}, Name: "test-cifs", Metadata: fileshare.Metadata},
+	} {
+		if _, err := fd.CreateFileShareAcl(acl); err != nil {
+			t.Fatal("Failed to create cifs fileshare acl:", err)
+		}
+	}
+	var expected = &SambaShare{
+		Name:       "test_cifs",
+		Path:       "/mnt/test-cifs",
+		ValidUsers: []string{"alice", "bob"},
+		ReadList:   []string{"alice"},
+		WriteList:  []string{"bob"},
+	}
+	share, _ := fd.samba.GetShare("test_cifs")
+	if !reflect.DeepEqual(share, expected) {
+		t.Errorf("Expected %+v, got %+v\n", expected, share)
+	}
+
+	if _, err := fd.CreateFileShareAcl(&pb.CreateFileShareAclOpts{
+		Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Read"}, Name: "test-cifs", Metadata: fileshare.Metadata,
+	}); err == nil {
+		t.Error("Expected an error when granting ip access to a cifs fileshare")
+	}
+	for _, user := range []string{"eve\n[evil]", "eve = root", "eve[", ""} {
+		if _, err := fd.CreateFileShareAcl(&pb.CreateFileShareAclOpts{
+			Type: "user", AccessTo: user, AccessCapability: []string{"Read"}, Name: "test-cifs", Metadata: fileshare.Metadata,
+		}); err == nil {
+			t.Errorf("Expected an error when granting access to invalid user %q", user)
+		}
+	}
+	if share, _ = fd.samba.GetShare("test_cifs"); !reflect.DeepEqual(share, expected) {
+		t.Errorf("Expected %+v, got %+v\n", expected, share)
+	}
+
+	if err := fd.DeleteFileShareAcl(&pb.DeleteFileShareAclOpts{
+		Type: "user", AccessTo: "alice", Name: "test-cifs", Metadata: fileshare.Metadata,
+	}); err != nil {
+		t.Fatal("Failed to delete cifs fileshare acl:", err)
+	}
+	share, _ = fd.samba.GetShare("test_cifs")
+	if !reflect.DeepEqual(share.ValidUsers, []string{"bob"}) || len(share.ReadList) != 0 {
+		t.Errorf("Expected alice to be revoked, got %+v\n", share)
+	}
+
+	if err := fd.DeleteFileShare(&pb.DeleteFileShareOpts{Metadata: fileshare.Metadata}); err != nil {
+		t.Fatal("Failed to delete cifs fileshare:", err)
+	}
+	if share, _ = fd.samba.GetShare("test_cifs"); share != nil {
+		t.Errorf("Expected samba share to be removed, got %+v\n", share)
+	}
+}
+
+type recordExecuter struct {
+	cmds [][]string
+}
+
+func (r *recordExecuter) Run(name string, args ...string) (string, error) {
+	r.cmds = append(r.cmds, append([]string{name}, args...))
+	return "", nil
+}
+
+func TestNfsFileShareAcl(t *testing.T) {
+	var fd = &Driver{}
+	config.CONF.OsdsDock.Backends.NFS.ConfigPath = "testdata/nfs.yaml"
+	fd.Setup()
+
+	var metadata = map[string]string{KFileshareProtocol: NFSProtocol}
+	testCases := []struct {
+		opt      *pb.CreateFileShareAclOpts
+		expected []string
+	}{
+		{
+			opt: &pb.CreateFileShareAclOpts{
+				Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Read", "Execute"},
+			},
+			expected: []string{"ro,sync,sec=sys,root_squash", "10.0.0.1:/mnt/test_nfs"},
+		},
+		{
+			opt: &pb.CreateFileShareAclOpts{
+				Type: "cidr", AccessTo: "10.0.0.1/24", AccessCapability: []string{"Read", "Write"},
+				Squash: "all_squash", AnonUid: 1000, AnonGid: 1000, SyncMode: "async",
+			},
+			expected: []string{"rw,async,sec=sys,all_squash,anonuid=1000,anongid=1000", "10.0.0.0/24:/mnt/test_nfs"},
+		},
+		{
+			opt: &pb.CreateFileShareAclOpts{
+				Type: "netgroup", AccessTo: "trusted", AccessCapability: []string{"Write"},
+				Squash: "no_root_squash", SecurityFlavors: []string{"krb5p", "krb5"},
+			},
+			expected: []string{"rw,sync,sec=krb5p:krb5,no_root_squash", "@trusted:/mnt/test_nfs"},
+		},
+		{
+			opt: &pb.CreateFileShareAclOpts{
+				Type: "hostname", AccessTo: "*.example.com", AccessCapability: []string{"Read"},
+			},
+			expected: []string{"ro,sync,sec=sys,root_squash", "*.example.com:/mnt/test_nfs"},
+		},
+	}
+	for _, c := range testCases {
+		r := &recordExecuter{}
+		fd.cli.RootExecuter, fd.cli.BaseExecuter = r, r
+		c.opt.Name, c.opt.Metadata = "test-nfs", metadata
+		if _, err := fd.CreateFileShareAcl(c.opt); err != nil {
+			t.Fatal("Failed to create nfs fileshare acl:", err)
+		}
+		if len(r.cmds) != 1 || !reflect.DeepEqual(r.cmds[0][4:], c.expected) {
+			t.Errorf("Expected exportfs -o %v, got %v\n", c.expected, r.cmds)
+		}
+	}
+
+	for _, opt := range []*pb.CreateFileShareAclOpts{
+		{Type: "ip", AccessTo: "10.0.0.256", AccessCapability: []string{"Read"}},
+		{Type: "user", AccessTo: "alice", AccessCapability: []string{"Read"}},
+		{Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Delete"}},
+		{Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Read"}, Squash: "some_squash"},
+		{Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Read"}, SecurityFlavors: []string{"ntlm"}},
+		{Type: "ip", AccessTo: "10.0.0.1", AccessCapability: []string{"Read"}, AnonUid: -1},
+	} {
+		r := &recordExecuter{}
+		fd.cli.RootExecuter, fd.cli.BaseExecuter = r, r
+		opt.Name, opt.Metadata = "test-nfs", metadata
+		if _, err := fd.CreateFileShareAcl(opt); err == nil {
+			t.Errorf("Expected an error when creating nfs fileshare acl %+v", opt)
+		}
+		if len(r.cmds) != 0 {
+			t.Errorf("Expected no command to be executed, got %v\n", r.cmds)
+		}
+	}
+
+	r := &recordExecuter{}
+	fd.cli.RootExecuter, fd.cli.BaseExecuter = r, r
+	if err := fd.DeleteFileShareAcl(&pb.DeleteFileShareAclOpts{
+		Type: "netgroup", AccessTo: "@trusted", Name: "test-nfs", Metadata: metadata,
+	}); err != nil {
+		t.Fatal("Failed to delete nfs fileshare acl:", err)
+	}
+	if len(r.cmds) != 1 || r.cmds[0][len(r.cmds[0])-1] != "@trusted:/mnt/test_nfs" {
+		t.Errorf("Expected exportfs -u @trusted:/mnt/test_nfs, got %v\n", r.cmds)
+	}
+}
+
 func TestListPools(t *testing.T) {
 	var fd = &Driver{}
 	config.CONF.OsdsDock.Backends.NFS.ConfigPath = "testdata/nfs.yaml"
@@ -0,0 +1,284 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package nfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// The samba include file is shared by all driver instances in the dock, so
+// every read-modify-write cycle on it must be serialized.
+var sambaConfLock sync.Mutex
+
+// The share and user names are written into the include file as they are, so
+// they are restricted to the characters which can neither end a value nor
+// start a new section or parameter. User names may be qualified with their
+// domain like DOMAIN\user or user@domain.
+var (
+	sambaShareRegexp = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+	sambaUserRegexp  = regexp.MustCompile(`^[A-Za-z0-9_.$@\\-]+$`)
+)
+
+func checkSambaShare(name string) error {
+	if !sambaShareRegexp.MatchString(name) {
+		return fmt.Errorf("%q is not a valid samba share name", name)
+	}
+	return nil
+}
+
+func checkSambaUser(user string) error {
+	if !sambaUserRegexp.MatchString(user) {
+		return fmt.Errorf("%q is not a valid samba user name", user)
+	}
+	return nil
+}
+
+// SambaShare describes one share section in the managed samba include file.
+type SambaShare struct {
+	Name       string
+	Path       string
+	ValidUsers []string
+	ReadList   []string
+	WriteList  []string
+}
+
+// SambaConf manages the share definitions which are owned by opensds. The
+// file is expected to be referenced from smb.conf with an include directive,
+// e.g. "include = /etc/samba/opensds.conf".
+type SambaConf struct {
+	Path string
+}
+
+func NewSambaConf(path string) *SambaConf {
+	return &SambaConf{Path: path}
+}
+
+func splitUsers(value string) []string {
+	return strings.Fields(strings.Replace(value, ",", " ", -1))
+}
+
+func (s *SambaConf) load() ([]*SambaShare, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var shares []*SambaShare
+	var cur *SambaShare
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			cur = &SambaShare{Name: strings.TrimSpace(line[1 : len(line)-1])}
+			shares = append(shares, cur)
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if cur == nil || len(kv) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "path":
+			cur.Path = value
+		case "valid users":
+			cur.ValidUsers = splitUsers(value)
+		case "read list":
+			cur.ReadList = splitUsers(value)
+		case "write list":
+			cur.WriteList = splitUsers(value)
+		}
+	}
+	return shares, scanner.Err()
+}
+
+func (s *SambaConf) save(shares []*SambaShare) error {
+	var buf bytes.Buffer
+	buf.WriteString("# This file is managed by opensds, do not edit it manually.\n")
+	for _, share := range shares {
+		fmt.Fprintf(&buf, "\n[%s]\n", share.Name)
+		fmt.Fprintf(&buf, "    path = %s\n", share.Path)
+		buf.WriteString("    browseable = yes\n")
+		buf.WriteString("    guest ok = no\n")
+		buf.WriteString("    read only = yes\n")
+		if len(share.ValidUsers) > 0 {
+			fmt.Fprintf(&buf, "    valid users = %s\n", strings.Join(share.ValidUsers, " "))
+		} else {
+			// Keep the share disabled until an acl is granted, otherwise
+			// an empty user list would let every user in.
+			buf.WriteString("    available = no\n")
+		}
+		if len(share.ReadList) > 0 {
+			fmt.Fprintf(&buf, "    read list = %s\n", strings.Join(share.ReadList, " "))
+		}
+		if len(share.WriteList) > 0 {
+			fmt.Fprintf(&buf, "    write list = %s\n", strings.Join(share.WriteList, " "))
+		}
+	}
+
+	// Write to a temporary file first so that smbd never reads a partial
+	// configuration.
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, ".opensds-samba-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+func (s *SambaConf) update(fn func([]*SambaShare) ([]*SambaShare, error)) error {
+	sambaConfLock.Lock()
+	defer sambaConfLock.Unlock()
+
+	shares, err := s.load()
+	if err != nil {
+		return err
+	}
+	if shares, err = fn(shares); err != nil {
+		return err
+	}
+	return s.save(shares)
+}
+
+// GetShare returns the share section with the specified name, or nil if
+// it does not exist.
+func (s *SambaConf) GetShare(name string) (*SambaShare, error) {
+	sambaConfLock.Lock()
+	defer sambaConfLock.Unlock()
+
+	shares, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range shares {
+		if share.Name == name {
+			return share, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddShare adds a share section, replacing any previous definition with the
+// same name.
+func (s *SambaConf) AddShare(name, sharePath string) error {
+	if err := checkSambaShare(name); err != nil {
+		return err
+	}
+	return s.update(func(shares []*SambaShare) ([]*SambaShare, error) {
+		for _, share := range shares {
+			if share.Name == name {
+				share.Path = sharePath
+				return shares, nil
+			}
+		}
+		return append(shares, &SambaShare{Name: name, Path: sharePath}), nil
+	})
+}
+
+// RemoveShare removes the share section, it is not an error if the share
+// does not exist.
+func (s *SambaConf) RemoveShare(name string) error {
+	return s.update(func(shares []*SambaShare) ([]*SambaShare, error) {
+		var result []*SambaShare
+		for _, share := range shares {
+			if share.Name != name {
+				result = append(result, share)
+			}
+		}
+		return result, nil
+	})
+}
+
+func removeUser(users []string, user string) []string {
+	var result []string
+	for _, u := range users {
+		if u != user {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// GrantAccess allows the user to access the share with the specified access
+// level, any previous grant of the user is replaced.
+func (s *SambaConf) GrantAccess(name, user, accessLevel string) error {
+	if err := checkSambaShare(name); err != nil {
+		return err
+	}
+	if err := checkSambaUser(user); err != nil {
+		return err
+	}
+	return s.update(func(shares []*SambaShare) ([]*SambaShare, error) {
+		for _, share := range shares {
+			if share.Name != name {
+				continue
+			}
+			share.ValidUsers = append(removeUser(share.ValidUsers, user), user)
+			share.ReadList = removeUser(share.ReadList, user)
+			share.WriteList = removeUser(share.WriteList, user)
+			if accessLevel == AccessLevelRw {
+				share.WriteList = append(share.WriteList, user)
+			} else {
+				share.ReadList = append(share.ReadList, user)
+			}
+			return shares, nil
+		}
+		return nil, fmt.Errorf("samba share %s does not exist", name)
+	})
+}
+
+// RevokeAccess removes the user from all access lists of the share.
+func (s *SambaConf) RevokeAccess(name, user string) error {
+	return s.update(func(shares []*SambaShare) ([]*SambaShare, error) {
+		for _, share := range shares {
+			if share.Name != name {
+				continue
+			}
+			share.ValidUsers = removeUser(share.ValidUsers, user)
+			share.ReadList = removeUser(share.ReadList, user)
+			share.WriteList = removeUser(share.WriteList, user)
+			return shares, nil
+		}
+		return nil, fmt.Errorf("samba share %s does not exist", name)
+	})
+}
@@ -17,6 +17,8 @@ package oceanstor
 import (
 	"fmt"
 	"strings"
+
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 )
 
 type CIFS struct {
@@ -124,7 +126,9 @@ func (c *CIFS) deleteShare(shareID string) error {
 	return nil
 }
 
-func (c *CIFS) allowAccess(shareID, accessTo, accessLevel string) (interface{}, error) {
+// allowAccess grants the user with the access level, the nfs export rule is
+// ignored for cifs shares.
+func (c *CIFS) allowAccess(shareID, accessTo, accessLevel string, rule *odu.NfsExportRule) (interface{}, error) {
 
 	domainType := map[string]string{"local": "2", "ad": "0"}
 
@@ -38,6 +38,9 @@ const (
 	FileShareSnapshotID   = "fileshareSnapId"
 	AccessTypeUser        = "user"
 	AccessTypeIp          = "ip"
+	AccessTypeCidr        = "cidr"
+	AccessTypeNetgroup    = "netgroup"
+	AccessTypeHostname    = "hostname"
 	AccessLevelRead       = "read"
 	AccessLevelWrite      = "write"
 	AccessLevelExecute    = "execute"
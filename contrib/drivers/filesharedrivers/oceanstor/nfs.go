@@ -17,6 +17,8 @@ package oceanstor
 import (
 	"fmt"
 	"strings"
+
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 )
 
 type NFS struct {
@@ -103,7 +105,7 @@ func (c *NFS) getShareByID(shareID string) (interface{}, error) {
 	return &nfsShare.Data, nil
 }
 
-func (c *NFS) allowAccess(shareID, accessTo, accessLevel string) (interface{}, error) {
+func (c *NFS) allowAccess(shareID, accessTo, accessLevel string, rule *odu.NfsExportRule) (interface{}, error) {
 	url := "/NFS_SHARE_AUTH_CLIENT"
 	data := map[string]string{
 		"TYPE":       "16409",
@@ -114,6 +116,19 @@ func (c *NFS) allowAccess(shareID, accessTo, accessLevel string) (interface{}, e
 		"ALLSQUASH":  "1",
 		"ROOTSQUASH": "0",
 	}
+	// For the squash options of the array, 0 means enabled and 1 disabled,
+	// while for the sync option 0 means synchronous.
+	if rule != nil {
+		if rule.SyncMode == odu.SyncModeAsync {
+			data["SYNC"] = "1"
+		}
+		if rule.Squash == odu.AllSquash {
+			data["ALLSQUASH"] = "0"
+		}
+		if rule.Squash == odu.NoRootSquash {
+			data["ROOTSQUASH"] = "1"
+		}
+	}
 
 	resp, err := c.request(url, "Post", data)
 	if err != nil {
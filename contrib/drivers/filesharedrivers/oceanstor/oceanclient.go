@@ -27,7 +27,9 @@ import (
 	"strings"
 
 	log "github.com/golang/glog"
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 	"github.com/sodafoundation/dock/pkg/utils/pwd"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 )
 
 func newRestCommon(conf *Config) (*Client, error) {
@@ -124,7 +126,7 @@ type Protocol interface {
 	deleteShare(shareID string) error
 	getShareByID(shareID string) (interface{}, error)
 	getLocation(sharePath, ipAddr string) string
-	allowAccess(shareID, accessTo, accessLevel string) (interface{}, error)
+	allowAccess(shareID, accessTo, accessLevel string, rule *odu.NfsExportRule) (interface{}, error)
 	getAccessLevel(accessLevel string) string
 }
 
@@ -452,12 +454,20 @@ func (c *Client) request(url, method string, reqParams interface{}) ([]byte, err
 		req.Header.Set(k, v)
 	}
 
+	span, traceparent := trace.StartHTTPSpan(req.Method, callUrl)
+	defer span.End()
+	if traceparent != "" {
+		req.Header.Set(trace.TraceparentHeader, traceparent)
+	}
+
 	// do the request
 	resp, err := client.Do(req)
 	if err != nil {
+		span.SetError(err)
 		return nil, fmt.Errorf("process request failed: %v, url is %s", err, callUrl)
 	}
 	defer resp.Body.Close()
+	span.SetStatusCode(resp.StatusCode)
 
 	respContent, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
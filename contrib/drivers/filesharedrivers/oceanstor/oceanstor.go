@@ -22,6 +22,7 @@ import (
 	"time"
 
 	log "github.com/golang/glog"
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 	. "github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	model "github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
@@ -52,7 +53,7 @@ func (d *Driver) Setup() error {
 }
 
 func (d *Driver) InitConf() {
-	path := config.CONF.OsdsDock.Backends.HuaweiOceanStorFile.ConfigPath
+	path := config.GetBackends().HuaweiOceanStorFile.ConfigPath
 	if path == "" {
 		path = DefaultConfPath
 	}
@@ -463,61 +464,73 @@ func (d *Driver) getAccessLevel(accessLevels []string, shareProto string) (strin
 	return shareDriver.getAccessLevel(accessLevel), nil
 }
 
-func (d *Driver) CreateFileShareAclParamCheck(opt *pb.CreateFileShareAclOpts) (string, string, string, string, error) {
+func (d *Driver) CreateFileShareAclParamCheck(opt *pb.CreateFileShareAclOpts) (string, string, string, string, *odu.NfsExportRule, error) {
 	log.V(5).Infof("create file share access client parameters %#v", opt)
 	meta := opt.GetMetadata()
 
 	if meta == nil || (meta != nil && meta[FileShareName] == "" && meta[FileShareID] == "") {
 		msg := "cannot get file share name and id"
 		log.Error(msg)
-		return "", "", "", "", errors.New(msg)
+		return "", "", "", "", nil, errors.New(msg)
 	}
 
 	fsName := meta[FileShareName]
 	if fsName == "" {
-		return "", "", "", "", errors.New("fileshare name cannot be empty")
+		return "", "", "", "", nil, errors.New("fileshare name cannot be empty")
 	}
 
 	shareProto := opt.GetAccessProtocol()
 
 	if !checkProtocol(shareProto) {
-		return "", "", "", "", fmt.Errorf("%s protocol is not supported, support is NFS and CIFS", shareProto)
+		return "", "", "", "", nil, fmt.Errorf("%s protocol is not supported, support is NFS and CIFS", shareProto)
 	}
 
 	accessLevels := opt.GetAccessCapability()
 
 	accessLevel, err := d.getAccessLevel(accessLevels, shareProto)
 	if err != nil {
-		return "", "", "", "", err
+		return "", "", "", "", nil, err
 	}
 
 	accessType := opt.Type
 	if !checkAccessType(accessType) {
-		return "", "", "", "", fmt.Errorf("only access type %s and %s are supported", AccessTypeUser, AccessTypeIp)
+		return "", "", "", "", nil, fmt.Errorf("only access type %s, %s, %s, %s and %s are supported",
+			AccessTypeUser, AccessTypeIp, AccessTypeCidr, AccessTypeNetgroup, AccessTypeHostname)
 	}
 	if shareProto == CIFSProto && accessType != AccessTypeUser {
-		return "", "", "", "", errors.New("only USER access type is allowed for CIFS shares")
+		return "", "", "", "", nil, errors.New("only USER access type is allowed for CIFS shares")
 	}
 
 	accessTo := opt.GetAccessTo()
 	if accessTo == "" {
-		return "", "", "", "", errors.New("access client cannot be empty")
+		return "", "", "", "", nil, errors.New("access client cannot be empty")
 	}
 
-	if shareProto == NFSProto {
-		if accessType == AccessTypeUser {
-			accessTo += "@"
-		} else {
-			accessTo = "*"
+	var rule *odu.NfsExportRule
+	if shareProto == NFSProto && accessType == AccessTypeUser {
+		// User clients keep the form the array has always been given, and
+		// the export options only apply to host clients.
+		if opt.GetSquash() != "" || opt.GetSyncMode() != "" || len(opt.GetSecurityFlavors()) != 0 ||
+			opt.GetAnonUid() != 0 || opt.GetAnonGid() != 0 {
+			return "", "", "", "", nil, fmt.Errorf("nfs export options are not supported for %s access type", AccessTypeUser)
 		}
+		accessTo = nfsUserClient(accessTo)
+	} else if shareProto == NFSProto {
+		if rule, err = odu.ParseNfsExportRule(opt); err != nil {
+			return "", "", "", "", nil, err
+		}
+		if err = checkNfsExportRule(rule); err != nil {
+			return "", "", "", "", nil, err
+		}
+		accessTo = rule.ClientMatch()
 	}
 
-	return fsName, shareProto, accessLevel, accessTo, nil
+	return fsName, shareProto, accessLevel, accessTo, rule, nil
 }
 
 // AllowAccess allow access to the share
 func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.FileShareAclSpec, error) {
-	shareName, shareProto, accessLevel, accessTo, err := d.CreateFileShareAclParamCheck(opt)
+	shareName, shareProto, accessLevel, accessTo, rule, err := d.CreateFileShareAclParamCheck(opt)
 	if err != nil {
 		msg := fmt.Sprintf("create fileshare access client failed: %v", err)
 		log.Error(msg)
@@ -538,7 +551,7 @@ func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.File
 
 	shareID := shareDriver.getShareID(share)
 
-	err = d.createAccessIfNotExist(shareID, accessTo, shareProto, accessLevel, shareDriver)
+	err = d.createAccessIfNotExist(shareID, accessTo, shareProto, accessLevel, rule, shareDriver)
 	if err != nil {
 		msg := fmt.Sprintf("allow access %s to %s failed %v", accessTo, shareName, err)
 		log.Error(msg)
@@ -552,11 +565,16 @@ func (d *Driver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (*model.File
 		AccessTo: accessTo,
 		Metadata: map[string]string{FileShareName: shareName},
 	}
+	if rule != nil {
+		shareAccess.Squash = rule.Squash
+		shareAccess.SecurityFlavors = rule.SecurityFlavors
+		shareAccess.SyncMode = rule.SyncMode
+	}
 
 	return shareAccess, nil
 }
 
-func (d *Driver) createAccessIfNotExist(shareID, accessTo, shareProto, accessLevel string, shareDriver Protocol) error {
+func (d *Driver) createAccessIfNotExist(shareID, accessTo, shareProto, accessLevel string, rule *odu.NfsExportRule, shareDriver Protocol) error {
 	// Check if access already exists
 	accessID, err := d.getAccessFromShare(shareID, accessTo, shareProto)
 	if err != nil {
@@ -568,7 +586,7 @@ func (d *Driver) createAccessIfNotExist(shareID, accessTo, shareProto, accessLev
 		return nil
 	}
 
-	if _, err := shareDriver.allowAccess(shareID, accessTo, accessLevel); err != nil {
+	if _, err := shareDriver.allowAccess(shareID, accessTo, accessLevel, rule); err != nil {
 		return err
 	}
 
@@ -584,8 +602,6 @@ func (d *Driver) DeleteFileShareAcl(opt *pb.DeleteFileShareAclOpts) error {
 		return err
 	}
 
-	accessTo = "*"
-
 	shareDriver := NewProtocol(shareProto, d.Client)
 
 	share, err := shareDriver.getShare(shareName)
@@ -609,6 +625,17 @@ func (d *Driver) DeleteFileShareAcl(opt *pb.DeleteFileShareAclOpts) error {
 		return errors.New(msg)
 	}
 
+	// Acls created before the client was passed to the array were all
+	// created for any host, so look them up as well.
+	if accessID == "" && shareProto == NFSProto && accessTo != legacyNfsClient {
+		log.Infof("access %s not found in share %s, try the legacy client %s", accessTo, shareName, legacyNfsClient)
+		if accessID, err = d.getAccessFromShare(shareID, legacyNfsClient, shareProto); err != nil {
+			msg := fmt.Sprintf("get access from share failed: %v", err)
+			log.Error(msg)
+			return errors.New(msg)
+		}
+	}
+
 	if accessID == "" {
 		msg := fmt.Sprintf("can not get access id from share %s", shareName)
 		log.Error(msg)
@@ -639,7 +666,8 @@ func (d *Driver) DeleteFileShareAclParamCheck(opt *pb.DeleteFileShareAclOpts) (s
 
 	accessType := opt.Type
 	if !checkAccessType(accessType) {
-		return "", "", "", fmt.Errorf("only access type %s and %s are supported", AccessTypeUser, AccessTypeIp)
+		return "", "", "", fmt.Errorf("only access type %s, %s, %s, %s and %s are supported",
+			AccessTypeUser, AccessTypeIp, AccessTypeCidr, AccessTypeNetgroup, AccessTypeHostname)
 	}
 	if shareProto == CIFSProto && accessType != AccessTypeUser {
 		return "", "", "", fmt.Errorf("only USER access type is allowed for CIFS shares")
@@ -650,5 +678,15 @@ func (d *Driver) DeleteFileShareAclParamCheck(opt *pb.DeleteFileShareAclOpts) (s
 		return "", "", "", errors.New("cannot find access client")
 	}
 
+	if shareProto == NFSProto && accessType == AccessTypeUser {
+		accessTo = nfsUserClient(accessTo)
+	} else if shareProto == NFSProto {
+		client, err := odu.NfsClientMatch(accessType, accessTo)
+		if err != nil {
+			return "", "", "", err
+		}
+		accessTo = client
+	}
+
 	return fsName, shareProto, accessTo, nil
 }
@@ -0,0 +1,73 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oceanstor
+
+import (
+	"testing"
+
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+func TestFileShareAclParamCheck(t *testing.T) {
+	d := &Driver{}
+	meta := map[string]string{FileShareName: "share"}
+
+	t.Run("nfs user client", func(t *testing.T) {
+		_, _, _, accessTo, rule, err := d.CreateFileShareAclParamCheck(&pb.CreateFileShareAclOpts{
+			Type:             AccessTypeUser,
+			AccessTo:         "alice",
+			AccessProtocol:   NFSProto,
+			AccessCapability: []string{AccessLevelRead},
+			Metadata:         meta,
+		})
+		assertTestResult(t, err, nil)
+		assertTestResult(t, accessTo, "alice@")
+		assertTestResult(t, rule == nil, true)
+
+		_, _, accessTo, err = d.DeleteFileShareAclParamCheck(&pb.DeleteFileShareAclOpts{
+			Type:           AccessTypeUser,
+			AccessTo:       "alice",
+			AccessProtocol: NFSProto,
+			Metadata:       meta,
+		})
+		assertTestResult(t, err, nil)
+		assertTestResult(t, accessTo, "alice@")
+	})
+
+	t.Run("nfs user client with export options", func(t *testing.T) {
+		_, _, _, _, _, err := d.CreateFileShareAclParamCheck(&pb.CreateFileShareAclOpts{
+			Type:             AccessTypeUser,
+			AccessTo:         "alice",
+			AccessProtocol:   NFSProto,
+			AccessCapability: []string{AccessLevelRead},
+			Squash:           "all_squash",
+			Metadata:         meta,
+		})
+		assertTestResult(t, err != nil, true)
+	})
+
+	t.Run("nfs ip client", func(t *testing.T) {
+		_, _, _, accessTo, rule, err := d.CreateFileShareAclParamCheck(&pb.CreateFileShareAclOpts{
+			Type:             AccessTypeIp,
+			AccessTo:         "10.0.0.1",
+			AccessProtocol:   NFSProto,
+			AccessCapability: []string{AccessLevelRead},
+			Metadata:         meta,
+		})
+		assertTestResult(t, err, nil)
+		assertTestResult(t, accessTo, "10.0.0.1")
+		assertTestResult(t, rule.Squash, "root_squash")
+	})
+}
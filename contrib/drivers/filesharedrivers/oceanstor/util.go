@@ -24,6 +24,7 @@ import (
 	"time"
 
 	log "github.com/golang/glog"
+	odu "github.com/sodafoundation/dock/contrib/drivers/utils"
 )
 
 func handleReponse(respContent []byte, out interface{}) error {
@@ -109,7 +110,7 @@ func checkAccessLevel(accessLevel string) bool {
 }
 
 func checkAccessType(accessType string) bool {
-	accessTypes := []string{AccessTypeUser, AccessTypeIp}
+	accessTypes := []string{AccessTypeUser, AccessTypeIp, AccessTypeCidr, AccessTypeNetgroup, AccessTypeHostname}
 	for _, v := range accessTypes {
 		if v == accessType {
 			return true
@@ -119,6 +120,27 @@ func checkAccessType(accessType string) bool {
 	return false
 }
 
+// legacyNfsClient is the client which nfs acls of host access types were
+// created for before the real client was passed to the array.
+const legacyNfsClient = "*"
+
+// nfsUserClient returns the client of an nfs acl of the user access type.
+func nfsUserClient(user string) string {
+	return user + "@"
+}
+
+// checkNfsExportRule checks if the nfs export rule can be expressed by the
+// array, which supports neither kerberos nor the anonymous user mapping.
+func checkNfsExportRule(rule *odu.NfsExportRule) error {
+	if rule.AnonUid != 0 || rule.AnonGid != 0 {
+		return errors.New("anonymous uid and gid are not supported")
+	}
+	if len(rule.SecurityFlavors) != 1 || rule.SecurityFlavors[0] != odu.SecSys {
+		return fmt.Errorf("only security flavor %s is supported", odu.SecSys)
+	}
+	return nil
+}
+
 func tryTimes(f func() error) error {
 	var err error
 
@@ -0,0 +1,82 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filesharedrivers
+
+import (
+	"time"
+
+	"github.com/sodafoundation/dock/pkg/dock/metrics"
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
+)
+
+// startDriverSpan starts the span of a driver method, the returned function
+// ends it and records the duration of the method in the metrics.
+func startDriverSpan(driver, method string) func(err *error) {
+	start := time.Now()
+	end := trace.StartBoundSpan("driver "+method, "driver.name", driver, "driver.method", method)
+	return func(err *error) {
+		end(err)
+		metrics.ObserveDriverOperation(driver, method, start)
+	}
+}
+
+// tracedDriver starts a span around each method of the fileshare driver and
+// observes its duration, the span is a no-op when tracing is disabled.
+type tracedDriver struct {
+	FileShareDriver
+	name string
+}
+
+func (d *tracedDriver) Setup() (err error) {
+	defer startDriverSpan(d.name, "Setup")(&err)
+	return d.FileShareDriver.Setup()
+}
+
+func (d *tracedDriver) CreateFileShare(opt *pb.CreateFileShareOpts) (fs *model.FileShareSpec, err error) {
+	defer startDriverSpan(d.name, "CreateFileShare")(&err)
+	return d.FileShareDriver.CreateFileShare(opt)
+}
+
+func (d *tracedDriver) DeleteFileShare(opt *pb.DeleteFileShareOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteFileShare")(&err)
+	return d.FileShareDriver.DeleteFileShare(opt)
+}
+
+func (d *tracedDriver) CreateFileShareSnapshot(opt *pb.CreateFileShareSnapshotOpts) (snp *model.FileShareSnapshotSpec, err error) {
+	defer startDriverSpan(d.name, "CreateFileShareSnapshot")(&err)
+	return d.FileShareDriver.CreateFileShareSnapshot(opt)
+}
+
+func (d *tracedDriver) DeleteFileShareSnapshot(opt *pb.DeleteFileShareSnapshotOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteFileShareSnapshot")(&err)
+	return d.FileShareDriver.DeleteFileShareSnapshot(opt)
+}
+
+func (d *tracedDriver) CreateFileShareAcl(opt *pb.CreateFileShareAclOpts) (acl *model.FileShareAclSpec, err error) {
+	defer startDriverSpan(d.name, "CreateFileShareAcl")(&err)
+	return d.FileShareDriver.CreateFileShareAcl(opt)
+}
+
+func (d *tracedDriver) DeleteFileShareAcl(opt *pb.DeleteFileShareAclOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteFileShareAcl")(&err)
+	return d.FileShareDriver.DeleteFileShareAcl(opt)
+}
+
+func (d *tracedDriver) ListPools() (pols []*model.StoragePoolSpec, err error) {
+	defer startDriverSpan(d.name, "ListPools")(&err)
+	return d.FileShareDriver.ListPools()
+}
@@ -16,6 +16,7 @@ package eternus
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ import (
 
 	log "github.com/golang/glog"
 	"github.com/sodafoundation/dock/pkg/utils/pwd"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 	"golang.org/x/crypto/ssh"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -133,6 +135,12 @@ func (c *EternusClient) doRequest(cmd string, param map[string]string) (bytes.Bu
 			cmdOption += fmt.Sprintf(" -%s %s ", k, v)
 		}
 	}
+	// The options are not traced since they may contain credentials.
+	_, span := trace.StartSpan(context.Background(), "CLI "+cmd, trace.KindClient)
+	defer span.End()
+	span.SetAttribute("eternus.command", cmd)
+	span.SetAttribute("net.peer.name", c.endpoint)
+
 	// execute command
 	log.Infof("execute cli. cmd = %s, option = %s", cmd, cmdOption)
 	c.stdin.Write([]byte(cmd + cmdOption + "\n"))
@@ -143,6 +151,7 @@ func (c *EternusClient) doRequest(cmd string, param map[string]string) (bytes.Bu
 	for {
 		n, err := c.stdout.Read(buff)
 		if err != io.EOF && err != nil {
+			span.SetError(err)
 			return result, err
 		}
 		n, err = result.Write(buff[:n])
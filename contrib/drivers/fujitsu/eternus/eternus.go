@@ -41,7 +41,7 @@ func (d *Driver) Setup() (err error) {
 	// Read fujitsu eternus config file
 	conf := &EternusConfig{}
 	d.conf = conf
-	path := config.CONF.OsdsDock.Backends.FujitsuEternus.ConfigPath
+	path := config.GetBackends().FujitsuEternus.ConfigPath
 
 	if "" == path {
 		path = defaultConfPath
@@ -633,3 +633,11 @@ func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.Volume
 func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
+
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	return nil, &model.NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &model.NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
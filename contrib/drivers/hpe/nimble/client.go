@@ -26,6 +26,7 @@ import (
 	"github.com/sodafoundation/dock/contrib/drivers/utils"
 	. "github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 )
 
 const (
@@ -143,6 +144,12 @@ func (c *NimbleClient) doRequest(method, url string, in interface{}, token strin
 	req.Header("Content-Type", "application/json;charset=utf-8")
 	req.Header("X-Auth-Token", token) // Set token
 
+	span, traceparent := trace.StartHTTPSpan(method, url)
+	defer span.End()
+	if traceparent != "" {
+		req.Header(trace.TraceparentHeader, traceparent)
+	}
+
 	if in != nil {
 		body, _ := json.Marshal(in)
 		req.Body(body)
@@ -151,9 +158,11 @@ func (c *NimbleClient) doRequest(method, url string, in interface{}, token strin
 
 	resp, err := req.Response()
 	if err != nil {
+		span.SetError(err)
 		log.Errorf("%v: http request failed, method: %s url: %s error: %v", DriverName, method, url, err)
 		return nil, nil, err
 	}
+	span.SetStatusCode(resp.StatusCode)
 
 	b, err := req.Bytes()
 	if err != nil {
@@ -164,6 +173,7 @@ func (c *NimbleClient) doRequest(method, url string, in interface{}, token strin
 	inErr := &ArrayInnerErrorBody{}
 	json.Unmarshal(b, inErr)
 	if len(inErr.Errs) != 0 {
+		span.SetError(inErr)
 		log.Errorf("%v: get error Infof from response failed, method: %s url: %s error: %v", DriverName, method, url, inErr)
 		return nil, nil, inErr
 	}
@@ -330,6 +340,21 @@ func (c *NimbleClient) ListVolume(poolId string) (*AllVolumeRespBody, error) {
 	return respBody, err
 }
 
+// ListPoolVolumes lists the volumes in the storage pool.
+func (c *NimbleClient) ListPoolVolumes(pool StoragePoolRespData) ([]VolumeRespData, error) {
+	respBody := &AllVolumeRespBody{}
+	if err := c.request("GET", pool.Endpoint+volumeUrlPath+"/detail", nil, respBody, pool.Token); err != nil {
+		return nil, err
+	}
+	var vols []VolumeRespData
+	for _, v := range respBody.Data {
+		if v.PoolName == pool.Name {
+			vols = append(vols, v)
+		}
+	}
+	return vols, nil
+}
+
 func (c *NimbleClient) GetStorageVolumeId(poolId string, volName string) (string, error) {
 	storageVolumeId := ""
 
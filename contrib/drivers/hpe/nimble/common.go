@@ -46,3 +46,7 @@ func Byte2Gib(byteSize int64) int64 {
 func Gib2Mebi(gigabyte int64) int64 {
 	return gigabyte * UnitMebi
 }
+
+func Mebi2Gib(mebibyte int64) int64 {
+	return mebibyte / UnitMebi
+}
@@ -55,6 +55,7 @@ type StoragePoolRespData struct {
 	Description   string      `json:"description"`
 	TotalCapacity int64       `json:"capacity"`
 	FreeCapacity  int64       `json:"free_space"`
+	Usage         int64       `json:"usage"`
 	ArrayList     []ArrayList `json:"array_list"`
 	Endpoint      string
 	Token         string
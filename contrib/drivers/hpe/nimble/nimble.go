@@ -45,7 +45,7 @@ func (d *Driver) Setup() (err error) {
 
 	conf := &Config{}
 	d.conf = conf
-	path := config.CONF.OsdsDock.Backends.HpeNimble.ConfigPath
+	path := config.GetBackends().HpeNimble.ConfigPath
 	if "" == path {
 		path = DefaultConfPath
 	}
@@ -187,7 +187,19 @@ func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 					StorageType:      "block",
 					AvailabilityZone: pool.ArrayList[0].ArrayName + "/" + pool.Name,
 					Extras:           c.Pool[grpName].Extras,
+					// The volumes are thin provisioned.
+					AllocatedCapacity: Byte2Gib(pool.Usage),
 				}
+				vols, err := d.client.ListPoolVolumes(pool)
+				if err != nil {
+					return nil, err
+				}
+				for _, vol := range vols {
+					// The size of volume is in mebibytes.
+					pol.ProvisionedCapacity += vol.Size
+				}
+				pol.ProvisionedCapacity = Mebi2Gib(pol.ProvisionedCapacity)
+				c.Pool[grpName].SetCapacityLimits(pol)
 				pols = append(pols, pol)
 				break
 			}
@@ -346,3 +358,11 @@ func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.Volume
 func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method deleteVolumeGroup has not been implemented yet"}
 }
+
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	return nil, &model.NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &model.NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
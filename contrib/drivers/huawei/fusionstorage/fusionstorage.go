@@ -35,7 +35,7 @@ func (d *Driver) Setup() error {
 
 	d.Conf = conf
 
-	path := config.CONF.OsdsDock.Backends.HuaweiFusionStorage.ConfigPath
+	path := config.GetBackends().HuaweiFusionStorage.ConfigPath
 	if path == "" {
 		path = DefaultConfPath
 	}
@@ -521,3 +521,11 @@ func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*VolumeGroupS
 func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
+
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*VolumeGroupSnapshotSpec, error) {
+	return nil, &NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
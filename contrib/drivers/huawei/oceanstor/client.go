@@ -28,6 +28,7 @@ import (
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
 	"github.com/sodafoundation/dock/pkg/utils"
 	"github.com/sodafoundation/dock/pkg/utils/pwd"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 )
 
 type ArrayInnerError struct {
@@ -107,6 +108,12 @@ func (c *OceanStorClient) doRequest(method, url string, in interface{}) ([]byte,
 	req.Header("iBaseToken", c.iBaseToken)
 	req.Header("Cookie", c.cookie)
 
+	span, traceparent := trace.StartHTTPSpan(method, url)
+	defer span.End()
+	if traceparent != "" {
+		req.Header(trace.TraceparentHeader, traceparent)
+	}
+
 	if in != nil {
 		body, _ := json.Marshal(in)
 		req.Body(body)
@@ -114,9 +121,11 @@ func (c *OceanStorClient) doRequest(method, url string, in interface{}) ([]byte,
 
 	resp, err := req.Response()
 	if err != nil {
+		span.SetError(err)
 		log.Errorf("Do http request failed, method: %s\n url: %s\n error: %v", method, url, err)
 		return nil, nil, err
 	}
+	span.SetStatusCode(resp.StatusCode)
 
 	b, err := req.Bytes()
 	if err != nil {
@@ -127,6 +136,7 @@ func (c *OceanStorClient) doRequest(method, url string, in interface{}) ([]byte,
 	inErr := &ArrayInnerError{}
 	json.Unmarshal(b, inErr)
 	if inErr.Err.Code != 0 {
+		span.SetError(inErr)
 		log.Errorf("Get error info from response failed, method: %s\n url: %s\n error: %v", method, url, inErr)
 		return nil, nil, inErr
 	}
@@ -367,6 +377,22 @@ func (c *OceanStorClient) DeleteSnapshot(id string) error {
 	return c.request("DELETE", "/snapshot/"+id, nil, nil)
 }
 
+// ActivateSnapshots activates the snapshots at the same point in time, so
+// that they are consistent with each other.
+func (c *OceanStorClient) ActivateSnapshots(ids []string) error {
+	data := map[string]interface{}{
+		"SNAPSHOTLIST": ids,
+	}
+	return c.request("POST", "/snapshot/activate", data, nil)
+}
+
+func (c *OceanStorClient) StopSnapshot(id string) error {
+	data := map[string]interface{}{
+		"ID": id,
+	}
+	return c.request("PUT", "/snapshot/stop", data, nil)
+}
+
 func (c *OceanStorClient) ListStoragePools() ([]StoragePool, error) {
 	pools := &StoragePoolsResp{}
 	err := c.request("GET", "/storagepool?range=[0-100]", nil, pools)
@@ -847,6 +873,15 @@ func (c *OceanStorClient) IsLunGroupContainLun(lunGrpId, lunId string) bool {
 	return false
 }
 
+func (c *OceanStorClient) ListLunGroupLuns(lunGrpId string) ([]Lun, error) {
+	lunsResp := &LunsResp{}
+	if err := c.request("GET", "/lun/associate?ASSOCIATEOBJTYPE=256&ASSOCIATEOBJID="+lunGrpId, nil, lunsResp); err != nil {
+		log.Errorf("List luns failed by lun group id: %s, error: %v", lunGrpId, err)
+		return nil, err
+	}
+	return lunsResp.Data, nil
+}
+
 func (c *OceanStorClient) AssociateLunToLunGroup(lunGrpId, lunId string) error {
 	reqBody := map[string]interface{}{
 		"ID":               lunGrpId,
@@ -26,6 +26,8 @@ const (
 	KLunId  = "huaweiLunId"
 	KSnapId = "huaweiSnapId"
 	KPairId = "huaweiReplicaPairId" // replication pair
+
+	KLunGroupId = "huaweiLunGroupId" // consistency group
 )
 
 // name prefix
@@ -0,0 +1,210 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oceanstor
+
+import (
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+// lunGroupId returns the id of the lun group of the volume group, which is
+// kept in its metadata after it is created.
+func (d *Driver) lunGroupId(groupId string, metadata map[string]string) (string, error) {
+	if id, ok := metadata[KLunGroupId]; ok {
+		return id, nil
+	}
+	return d.client.FindLunGroup(EncodeName(groupId))
+}
+
+func (d *Driver) addLunsToLunGroup(lunGrpId string, volIds []string) error {
+	for _, id := range volIds {
+		lun, err := d.client.GetVolumeByName(EncodeName(id))
+		if err != nil {
+			log.Errorf("Get lun of volume %s failed, %v", id, err)
+			return err
+		}
+		if err := d.client.AssociateLunToLunGroup(lunGrpId, lun.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Driver) removeLunsFromLunGroup(lunGrpId string, volIds []string) error {
+	for _, id := range volIds {
+		lun, err := d.client.GetVolumeByName(EncodeName(id))
+		if err != nil {
+			log.Warningf("Get lun of volume %s failed, skip removing it from lun group: %v", id, err)
+			continue
+		}
+		if err := d.client.RemoveLunFromLunGroup(lunGrpId, lun.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateVolumeGroup creates a lun group of the volumes in the group.
+func (d *Driver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
+	lunGrpId, err := d.client.CreateLunGroup(EncodeName(opt.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	if err := d.addLunsToLunGroup(lunGrpId, opt.GetAddVolumes()); err != nil {
+		if err := d.client.DeleteLunGroup(lunGrpId); err != nil {
+			log.Errorf("Delete lun group %s failed, %v", lunGrpId, err)
+		}
+		return nil, err
+	}
+
+	log.Infof("Create volume group (%s) success, lun group id: %s", opt.GetId(), lunGrpId)
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:             opt.GetName(),
+		Description:      opt.GetDescription(),
+		AvailabilityZone: opt.GetAvailabilityZone(),
+		PoolId:           opt.GetPoolId(),
+		Status:           model.VolumeGroupAvailable,
+		Metadata: map[string]string{
+			KLunGroupId: lunGrpId,
+		},
+	}, nil
+}
+
+func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
+	lunGrpId, err := d.lunGroupId(opt.GetId(), opt.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+	if err := d.removeLunsFromLunGroup(lunGrpId, opt.GetRemoveVolumes()); err != nil {
+		return nil, err
+	}
+	if err := d.addLunsToLunGroup(lunGrpId, opt.GetAddVolumes()); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Update volume group (%s) success", opt.GetId())
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		PoolId: opt.GetPoolId(),
+		Status: model.VolumeGroupAvailable,
+	}, nil
+}
+
+// DeleteVolumeGroup removes the luns from the lun group and deletes it, the
+// luns are kept and deleted as the volumes.
+func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
+	lunGrpId, err := d.lunGroupId(opt.GetId(), opt.GetMetadata())
+	if IsNotFoundError(err) {
+		log.Warningf("Lun group of volume group %s does not exist, ignore it", opt.GetId())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	luns, err := d.client.ListLunGroupLuns(lunGrpId)
+	if err != nil {
+		return err
+	}
+	for _, lun := range luns {
+		if err := d.client.RemoveLunFromLunGroup(lunGrpId, lun.Id); err != nil {
+			return err
+		}
+	}
+	if err := d.client.DeleteLunGroup(lunGrpId); err != nil {
+		log.Errorf("Delete lun group %s failed, %v", lunGrpId, err)
+		return err
+	}
+
+	log.Infof("Delete volume group (%s) success", opt.GetId())
+	return nil
+}
+
+// CreateGroupSnapshot creates the snapshots of all the luns first, and then
+// activates them at the same time, so that they are consistent.
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	var snps []*model.VolumeSnapshotSpec
+	var ids []string
+	rollback := func() {
+		for _, id := range ids {
+			if err := d.client.DeleteSnapshot(id); err != nil {
+				log.Errorf("Delete snapshot %s failed, %v", id, err)
+			}
+		}
+	}
+	desc := TruncateDescription(opt.GetDescription())
+	for _, s := range opt.GetSnapshots() {
+		snap, err := d.client.CreateSnapshot(s.GetMetadata()[KLunId], EncodeName(s.GetId()), desc)
+		if err != nil {
+			log.Errorf("Create snapshot of volume %s failed, %v", s.GetVolumeId(), err)
+			rollback()
+			return nil, err
+		}
+		ids = append(ids, snap.Id)
+		snps = append(snps, &model.VolumeSnapshotSpec{
+			BaseModel: &model.BaseModel{
+				Id: s.GetId(),
+			},
+			Name:     opt.GetName(),
+			VolumeId: s.GetVolumeId(),
+			Status:   model.VolumeSnapAvailable,
+			Metadata: map[string]string{
+				KSnapId: snap.Id,
+			},
+		})
+	}
+	if err := d.client.ActivateSnapshots(ids); err != nil {
+		log.Errorf("Activate snapshots of group %s failed, %v", opt.GetGroupId(), err)
+		rollback()
+		return nil, err
+	}
+
+	log.Infof("Create group snapshot (%s) success", opt.GetId())
+	return &model.VolumeGroupSnapshotSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:        opt.GetName(),
+		Description: opt.GetDescription(),
+		GroupId:     opt.GetGroupId(),
+		Status:      model.VolumeSnapAvailable,
+		Snapshots:   snps,
+	}, nil
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	for _, s := range opt.GetSnapshots() {
+		id := s.GetMetadata()[KSnapId]
+		if id == "" {
+			log.Warningf("Can't find the snapshot id of volume snapshot %s, ignore it", s.GetId())
+			continue
+		}
+		if err := d.client.StopSnapshot(id); err != nil {
+			log.Warningf("Stop snapshot %s failed, %v", id, err)
+		}
+		if err := d.client.DeleteSnapshot(id); err != nil {
+			log.Errorf("Delete snapshot %s failed, %v", id, err)
+			return err
+		}
+	}
+
+	log.Infof("Delete group snapshot (%s) success", opt.GetId())
+	return nil
+}
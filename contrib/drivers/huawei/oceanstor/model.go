@@ -43,11 +43,13 @@ type AuthResp struct {
 }
 
 type StoragePool struct {
-	Description       string `json:"DESCRIPTION"`
-	Id                string `json:"ID"`
-	Name              string `json:"NAME"`
-	UserFreeCapacity  string `json:"USERFREECAPACITY"`
-	UserTotalCapacity string `json:"USERTOTALCAPACITY"`
+	Description          string `json:"DESCRIPTION"`
+	Id                   string `json:"ID"`
+	Name                 string `json:"NAME"`
+	UserFreeCapacity     string `json:"USERFREECAPACITY"`
+	UserTotalCapacity    string `json:"USERTOTALCAPACITY"`
+	UserConsumedCapacity string `json:"USERCONSUMEDCAPACITY"`
+	LunConfigedCapacity  string `json:"LUNCONFIGEDCAPACITY"`
 }
 type StoragePoolsResp struct {
 	Data  []StoragePool `json:"data"`
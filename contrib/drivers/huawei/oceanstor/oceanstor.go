@@ -39,7 +39,7 @@ func (d *Driver) Setup() (err error) {
 	// Read huawei oceanstor config file
 	conf := &OceanStorConfig{}
 	d.conf = conf
-	path := config.CONF.OsdsDock.Backends.HuaweiOceanStorBlock.ConfigPath
+	path := config.GetBackends().HuaweiOceanStorBlock.ConfigPath
 
 	if "" == path {
 		path = defaultConfPath
@@ -522,7 +522,11 @@ func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 			Extras:           c.Pool[p.Name].Extras,
 			AvailabilityZone: c.Pool[p.Name].AvailabilityZone,
 			MultiAttach:      c.Pool[p.Name].MultiAttach,
+			// The capacity configured to all the luns in the pool.
+			ProvisionedCapacity: Sector2Gb(p.LunConfigedCapacity),
+			AllocatedCapacity:   Sector2Gb(p.UserConsumedCapacity),
 		}
+		c.Pool[p.Name].SetCapacityLimits(pol)
 		if pol.AvailabilityZone == "" {
 			pol.AvailabilityZone = defaultAZ
 		}
@@ -828,15 +832,3 @@ func (d *Driver) InitializeSnapshotConnection(opt *pb.CreateSnapshotAttachmentOp
 func (d *Driver) TerminateSnapshotConnection(opt *pb.DeleteSnapshotAttachmentOpts) error {
 	return &model.NotImplementError{S: "method TerminateSnapshotConnection has not been implemented yet."}
 }
-
-func (d *Driver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method CreateVolumeGroup has not been implemented yet"}
-}
-
-func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method UpdateVolumeGroup has not been implemented yet"}
-}
-
-func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
-	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
-}
@@ -39,7 +39,7 @@ func (r *ReplicationDriver) Setup() (err error) {
 	// Read huawei oceanstor config file
 	conf := &OceanStorConfig{}
 	r.conf = conf
-	path := config.CONF.OsdsDock.Backends.HuaweiOceanStorBlock.ConfigPath
+	path := config.GetBackends().HuaweiOceanStorBlock.ConfigPath
 
 	if "" == path {
 		path = defaultConfPath
@@ -137,10 +137,95 @@ func (r *ReplicationDriver) FailoverReplication(opt *pb.FailoverReplicationOpts)
 		log.Errorf(msg)
 		return fmt.Errorf(msg)
 	}
-	if opt.SecondaryBackendId == model.ReplicationDefaultBackendId {
-		return r.mgr.Failover(pairId)
+	return r.mgr.Failover(pairId)
+}
+
+func (r *ReplicationDriver) SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) error {
+	if !opt.GetIsPrimary() {
+		return nil
+	}
+	pairId, ok := opt.GetMetadata()[KPairId]
+	if !ok {
+		msg := fmt.Sprintf("Can find pair id in metadata")
+		log.Errorf(msg)
+		return fmt.Errorf(msg)
+	}
+	return r.mgr.Switchover(pairId)
+}
+
+func (r *ReplicationDriver) ReprotectReplication(opt *pb.ReprotectReplicationOpts) error {
+	if !opt.GetIsPrimary() {
+		return nil
+	}
+	pairId, ok := opt.GetMetadata()[KPairId]
+	if !ok {
+		msg := fmt.Sprintf("Can find pair id in metadata")
+		log.Errorf(msg)
+		return fmt.Errorf(msg)
+	}
+	return r.mgr.Reprotect(pairId)
+}
+
+func (r *ReplicationDriver) GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	pairId, ok := opt.GetMetadata()[KPairId]
+	if !ok {
+		msg := fmt.Sprintf("Can find pair id in metadata")
+		log.Errorf(msg)
+		return nil, fmt.Errorf(msg)
+	}
+	pair, err := r.mgr.localOp.GetReplicationInfo(pairId)
+	if err != nil {
+		log.Errorf("Get replication pair %s failed, %v", pairId, err)
+		return nil, err
 	}
-	return r.mgr.Failback(pairId)
+	return pairStatus(opt.GetId(), pair), nil
+}
+
+// pairStatus converts the replication pair on the array to the state of the
+// replication. The RPO lag of async pairs is the time difference between the
+// primary and secondary luns, the sync ones have no lag once synchronized.
+func pairStatus(id string, pair *ReplicationPair) *model.ReplicationPairStatus {
+	st := &model.ReplicationPairStatus{Id: id, Role: "secondary", RpoLag: -1}
+	if strings.ToLower(pair.IsPrimary) == "true" {
+		st.Role = "primary"
+	}
+
+	switch pair.RunningStatus {
+	case RunningStatusNormal, RunningStatusSynced:
+		st.RunningStatus = model.ReplicationRunningSynced
+	case RunningStatusInitialSync, RunningStatusSync:
+		st.RunningStatus = model.ReplicationRunningSyncing
+	case RunningStatusSplit, RunningStatusDisabled:
+		st.RunningStatus = model.ReplicationRunningSplit
+	case RunningStatusInterrupted, RunningStatusLinkDown, RunningStatusConnecting:
+		st.RunningStatus = model.ReplicationRunningInterrupted
+	default:
+		st.RunningStatus = model.ReplicationRunningUnknown
+	}
+
+	if progress, err := strconv.ParseFloat(pair.ReplicationProgress, 64); err == nil {
+		st.SyncProgress = progress
+	} else if st.RunningStatus == model.ReplicationRunningSynced {
+		st.SyncProgress = 100
+	}
+	if pair.ReplicationMode == ReplicaSyncMode {
+		if st.RunningStatus == model.ReplicationRunningSynced {
+			st.RpoLag = 0
+		}
+	} else if lag, err := strconv.ParseInt(pair.TimeDifference, 10, 64); err == nil {
+		st.RpoLag = lag
+	}
+
+	var reasons []string
+	if pair.HealthStatus != HealthStatusNormal {
+		reasons = append(reasons, fmt.Sprintf("health status is %s", pair.HealthStatus))
+	}
+	if st.RunningStatus != model.ReplicationRunningSynced && st.RunningStatus != model.ReplicationRunningSyncing {
+		reasons = append(reasons, fmt.Sprintf("running status is %s (%s)", st.RunningStatus, pair.RunningStatus))
+	}
+	st.Healthy = len(reasons) == 0
+	st.Reason = strings.Join(reasons, "; ")
+	return st
 }
 
 func NewReplicaPairMgr(conf *OceanStorConfig) (r *ReplicaPairMgr, err error) {
@@ -294,19 +379,50 @@ func (r *ReplicaPairMgr) DeleteReplication(pairId, rmtLunId string) error {
 	return nil
 }
 
-// Failover volumes back to primary backend.
+// Switch over the roles of the replication pair without data loss, it works
+// in both directions, so it also fails back the reprotected pair.
 // The main steps:
-// 1. Switch the role of replication pairs.
-// 2. Copy the second LUN data back to primary LUN.
-// 3. Split replication pairs.
-// 4. Switch the role of replication pairs.
-// 5. Enable replications.
-
-func (r *ReplicaPairMgr) Failback(pairId string) error {
-	r.remoteDriver.Enable(pairId, true)
-	r.remoteDriver.WaitReplicaReady(pairId)
-	r.localDriver.Enable(pairId, false)
-	return nil
+// 1. Synchronize the secondary LUN and wait for the completion.
+// 2. Switch the role of replication pair on the secondary array.
+// 3. Protect the new secondary LUN and synchronize it from the new primary.
+func (r *ReplicaPairMgr) Switchover(pairId string) error {
+	pair, err := r.localOp.GetReplicationInfo(pairId)
+	if err != nil {
+		return err
+	}
+	primary, secondary := r.localDriver, r.remoteDriver
+	if !r.localOp.isPrimary(pair) {
+		primary, secondary = r.remoteDriver, r.localDriver
+	}
+	if err := primary.Sync(pairId, true); err != nil {
+		log.Errorf("Synchronize replication pair %s before switchover failed, %v", pairId, err)
+		return err
+	}
+	if err := secondary.Switch(pairId); err != nil {
+		log.Errorf("Switch replication pair %s failed, %v", pairId, err)
+		return err
+	}
+	return secondary.Sync(pairId, false)
+}
+
+// Reprotect the failed over replication pair, the data of the secondary LUN
+// is copied back to the primary LUN, after that the secondary LUN is the
+// primary one of the pair until it is switched over again.
+// The main steps:
+// 1. Switch the role of replication pair on the secondary array.
+// 2. Protect the primary LUN and synchronize it from the secondary LUN.
+func (r *ReplicaPairMgr) Reprotect(pairId string) error {
+	pair, err := r.remoteOp.GetReplicationInfo(pairId)
+	if err != nil {
+		return err
+	}
+	if !r.remoteOp.isPrimary(pair) {
+		if err := r.remoteDriver.Switch(pairId); err != nil {
+			log.Errorf("Switch replication pair %s failed, %v", pairId, err)
+			return err
+		}
+	}
+	return r.remoteDriver.Sync(pairId, false)
 }
 
 func (r *ReplicaPairMgr) Failover(pairId string) error {
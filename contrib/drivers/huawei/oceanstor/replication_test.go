@@ -15,7 +15,10 @@
 package oceanstor
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/sodafoundation/dock/pkg/model"
 )
 
 func TestLoadConf(t *testing.T) {
@@ -38,3 +41,35 @@ func TestFailoverReplication(t *testing.T) {
 func TestDeleteReplication(t *testing.T) {
 
 }
+
+func TestPairStatus(t *testing.T) {
+	tests := []struct {
+		pair     *ReplicationPair
+		expected *model.ReplicationPairStatus
+	}{
+		{
+			&ReplicationPair{IsPrimary: "true", RunningStatus: RunningStatusNormal, HealthStatus: HealthStatusNormal,
+				ReplicationMode: ReplicaAsyncMode, ReplicationProgress: "100", TimeDifference: "30"},
+			&model.ReplicationPairStatus{Id: "r1", Role: "primary", Healthy: true,
+				RunningStatus: model.ReplicationRunningSynced, SyncProgress: 100, RpoLag: 30},
+		},
+		{
+			&ReplicationPair{IsPrimary: "true", RunningStatus: RunningStatusSync, HealthStatus: HealthStatusNormal,
+				ReplicationMode: ReplicaSyncMode, ReplicationProgress: "45"},
+			&model.ReplicationPairStatus{Id: "r1", Role: "primary", Healthy: true,
+				RunningStatus: model.ReplicationRunningSyncing, SyncProgress: 45, RpoLag: -1},
+		},
+		{
+			&ReplicationPair{IsPrimary: "false", RunningStatus: RunningStatusInterrupted, HealthStatus: HealthStatusFault,
+				ReplicationMode: ReplicaSyncMode},
+			&model.ReplicationPairStatus{Id: "r1", Role: "secondary",
+				Reason:        "health status is 2; running status is interrupted (34)",
+				RunningStatus: model.ReplicationRunningInterrupted, RpoLag: -1},
+		},
+	}
+	for _, tt := range tests {
+		if st := pairStatus("r1", tt.pair); !reflect.DeepEqual(st, tt.expected) {
+			t.Errorf("Expected %+v, got %+v", tt.expected, st)
+		}
+	}
+}
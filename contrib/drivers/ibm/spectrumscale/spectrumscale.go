@@ -74,7 +74,7 @@ func (d *Driver) Setup() error {
 		Port:       port,
 		Password:   password,
 	}
-	p := config.CONF.OsdsDock.Backends.IBMSpectrumScale.ConfigPath
+	p := config.GetBackends().IBMSpectrumScale.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -255,6 +255,14 @@ func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
 
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	return nil, &model.NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &model.NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
+
 func (d *Driver) PullVolume(volIdentifier string) (*model.VolumeSpec, error) {
 	return nil, &model.NotImplementError{"method CreateVolumeGroup has not been implemented yet"}
 }
@@ -0,0 +1,95 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drivers
+
+import (
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+// live records the drivers which are set up by Init and not cleaned yet, so
+// that they can still be unset if the dock shuts down before the operations
+// using them finish. The drivers are counted since the ones of zero size,
+// such as the sample driver, may share the same address.
+var live = struct {
+	sync.Mutex
+	volumeDrivers map[VolumeDriver]int
+	metricDrivers map[MetricDriver]int
+}{
+	volumeDrivers: make(map[VolumeDriver]int),
+	metricDrivers: make(map[MetricDriver]int),
+}
+
+// trackVolumeDriver records that d is set up or cleaned. When d is cleaned, it
+// returns false if d is not live any more, that is, it is unset by CleanAll
+// already.
+func trackVolumeDriver(d VolumeDriver, set bool) bool {
+	live.Lock()
+	defer live.Unlock()
+	if set {
+		live.volumeDrivers[d]++
+		return true
+	}
+	count, ok := live.volumeDrivers[d]
+	if count > 1 {
+		live.volumeDrivers[d]--
+	} else {
+		delete(live.volumeDrivers, d)
+	}
+	return ok
+}
+
+// trackMetricDriver is the same as trackVolumeDriver for metric drivers.
+func trackMetricDriver(d MetricDriver, set bool) bool {
+	live.Lock()
+	defer live.Unlock()
+	if set {
+		live.metricDrivers[d]++
+		return true
+	}
+	count, ok := live.metricDrivers[d]
+	if count > 1 {
+		live.metricDrivers[d]--
+	} else {
+		delete(live.metricDrivers, d)
+	}
+	return ok
+}
+
+// CleanAll unsets the volume and metric drivers which are not cleaned yet,
+// it returns the number of them.
+func CleanAll() int {
+	live.Lock()
+	volumeDrivers, metricDrivers := live.volumeDrivers, live.metricDrivers
+	live.volumeDrivers = make(map[VolumeDriver]int)
+	live.metricDrivers = make(map[MetricDriver]int)
+	live.Unlock()
+
+	n := 0
+	for d, count := range volumeDrivers {
+		n += count
+		if err := d.Unset(); err != nil {
+			log.Error("when unsetting volume driver:", err)
+		}
+	}
+	for d, count := range metricDrivers {
+		n += count
+		if err := d.Teardown(); err != nil {
+			log.Error("when tearing down metric driver:", err)
+		}
+	}
+	return n
+}
@@ -219,6 +219,36 @@ func (c *Cli) CreateLvSnapshot(name, sourceLvName, vg string, size int64) error
 	return nil
 }
 
+// dmName returns the device mapper name of the logic volume, in which the
+// dashes in the names are doubled.
+func dmName(name, vg string) string {
+	return strings.Replace(vg, "-", "--", -1) + "-" + strings.Replace(name, "-", "--", -1)
+}
+
+// SuspendLv blocks the I/O of the logic volume after flushing the pending
+// one, until ResumeLv is called.
+func (c *Cli) SuspendLv(name, vg string) error {
+	cmd := []string{
+		"env", "LC_ALL=C",
+		"dmsetup",
+		"suspend",
+		dmName(name, vg),
+	}
+	_, err := c.execute(cmd...)
+	return err
+}
+
+func (c *Cli) ResumeLv(name, vg string) error {
+	cmd := []string{
+		"env", "LC_ALL=C",
+		"dmsetup",
+		"resume",
+		dmName(name, vg),
+	}
+	_, err := c.execute(cmd...)
+	return err
+}
+
 type VolumeGroup struct {
 	Name          string
 	TotalCapacity int64
@@ -259,6 +289,37 @@ func (c *Cli) ListVgs() (*[]VolumeGroup, error) {
 	return &vgs, nil
 }
 
+// ListLvSizes returns the sum of the sizes of logical volumes in each volume
+// group in GB.
+func (c *Cli) ListLvSizes() (map[string]int64, error) {
+	cmd := []string{
+		"env", "LC_ALL=C",
+		"lvs",
+		"--noheadings",
+		"--nosuffix",
+		"--unit=g",
+		"-o", "vg_name,lv_size",
+	}
+	out, err := c.execute(cmd...)
+	if err != nil {
+		return nil, err
+	}
+	sizes := map[string]float64{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, _ := strconv.ParseFloat(fields[1], 64)
+		sizes[fields[0]] += size
+	}
+	lvSizes := map[string]int64{}
+	for vg, size := range sizes {
+		lvSizes[vg] = int64(size)
+	}
+	return lvSizes, nil
+}
+
 func (c *Cli) CopyVolume(src, dest string, size int64) error {
 	var count = (size << sizeShiftBit) / blocksize
 	_, err := c.execute("dd",
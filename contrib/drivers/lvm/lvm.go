@@ -17,6 +17,7 @@ package lvm
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path"
 	"runtime"
@@ -52,13 +53,27 @@ const (
 	KLvPath     = "lvPath"
 	KLvsPath    = "lvsPath"
 	KLvIdFormat = "NAA"
+	// KLocalAttach is advertised in the advanced pool capabilities when the
+	// volumes can be attached to the dock host with local access protocol.
+	KLocalAttach = "localAttach"
+)
+
+// These are package variables so that they can be faked in unit tests.
+var (
+	hostname       = os.Hostname
+	interfaceAddrs = net.InterfaceAddrs
 )
 
 type LVMConfig struct {
-	TgtBindIp      string                    `yaml:"tgtBindIp"`
-	TgtConfDir     string                    `yaml:"tgtConfDir"`
-	EnableChapAuth bool                      `yaml:"enableChapAuth"`
-	Pool           map[string]PoolProperties `yaml:"pool,flow"`
+	TgtBindIp            string                    `yaml:"tgtBindIp"`
+	TgtConfDir           string                    `yaml:"tgtConfDir"`
+	EnableChapAuth       bool                      `yaml:"enableChapAuth"`
+	EnableMutualChapAuth bool                      `yaml:"enableMutualChapAuth"`
+	EnableDhchapAuth     bool                      `yaml:"enableDhchapAuth"`
+	EnableLocalAttach    bool                      `yaml:"enableLocalAttach"`
+	IscsiTarget          string                    `yaml:"iscsiTarget"`
+	PoolIscsiTarget      map[string]string         `yaml:"poolIscsiTarget"`
+	Pool                 map[string]PoolProperties `yaml:"pool,flow"`
 }
 
 type Driver struct {
@@ -69,7 +84,7 @@ type Driver struct {
 func (d *Driver) Setup() error {
 	// Read lvm config file
 	d.conf = &LVMConfig{TgtBindIp: defaultTgtBindIp, TgtConfDir: defaultTgtConfDir}
-	p := config.CONF.OsdsDock.Backends.LVM.ConfigPath
+	p := config.GetBackends().LVM.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -82,11 +97,55 @@ func (d *Driver) Setup() error {
 	}
 	d.cli = cli
 
-	return nil
+	if len(d.lioPools()) == 0 {
+		return nil
+	}
+	return targets.RestoreLioTargets(d.conf.TgtBindIp, d.conf.TgtConfDir)
+}
+
+func (d *Driver) lioPools() []string {
+	var pools []string
+	for pool := range d.conf.Pool {
+		if d.iscsiTargetType(pool) == targets.LioTargetType {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// MigrateTgtToLio moves the tgt exports of the lio pools in the lvm config
+// file to lio. It is run once by the lioMigrator tool after the pools are
+// switched to lio, rather than by the driver.
+func MigrateTgtToLio(confPath string) error {
+	if confPath == "" {
+		confPath = defaultConfPath
+	}
+	d := &Driver{conf: &LVMConfig{TgtBindIp: defaultTgtBindIp, TgtConfDir: defaultTgtConfDir}}
+	if _, err := Parse(d.conf, confPath); err != nil {
+		return err
+	}
+	pools := d.lioPools()
+	if len(pools) == 0 {
+		return errors.New("no pool uses lio target")
+	}
+	return targets.MigrateTgtToLio(d.conf.TgtBindIp, d.conf.TgtConfDir, pools)
 }
 
 func (*Driver) Unset() error { return nil }
 
+// iscsiTargetType returns the iscsi target implementation of the pool, which
+// is either tgt or lio. The default one of all pools can be overridden by the
+// pool specific config.
+func (d *Driver) iscsiTargetType(pool string) string {
+	if t, ok := d.conf.PoolIscsiTarget[pool]; ok {
+		return t
+	}
+	if d.conf.IscsiTarget == "" {
+		return targets.TgtTargetType
+	}
+	return d.conf.IscsiTarget
+}
+
 func (d *Driver) downloadSnapshot(bucket, backupId, dest string) error {
 	mc, err := backup.NewBackup("multi-cloud")
 	if err != nil {
@@ -252,22 +311,29 @@ func (d *Driver) InitializeConnection(opt *pb.CreateVolumeAttachmentOpts) (*mode
 		log.Error(err)
 		return nil, err
 	}
-	var chapAuth []string
-	if d.conf.EnableChapAuth {
-		chapAuth = []string{utils.RandSeqWithAlnum(20), utils.RandSeqWithAlnum(16)}
+
+	// The device can be used directly if it is consumed on this host, so no
+	// target needs to be created.
+	if opt.AccessProtocol == LocalProtocol {
+		return d.initializeLocalConnection(opt.GetHostInfo(), lvPath)
 	}
 
 	// create target according to the pool's access protocol
 	accPro := opt.AccessProtocol
 	log.Info("accpro:", accPro)
-	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro)
-	expt, err := t.CreateExport(opt.GetVolumeId(), lvPath, hostIP, initiator, chapAuth)
+	auth, err := d.newAuthOptions(accPro)
+	if err != nil {
+		log.Error("Failed to generate authentication secrets:", err)
+		return nil, err
+	}
+	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro, d.iscsiTargetType(path.Base(path.Dir(lvPath))))
+	expt, err := t.CreateExport(opt.GetVolumeId(), lvPath, hostIP, initiator, auth)
 	if err != nil {
 		log.Error("Failed to initialize connection of logic volume:", err)
 		return nil, err
 	}
 
-	log.V(8).Infof("lvm ConnectionData: %v", expt)
+	log.V(8).Infof("lvm ConnectionData: %v", model.RedactConnectionData(expt))
 
 	return &model.ConnectionInfo{
 		DriverVolumeType: accPro,
@@ -275,10 +341,107 @@ func (d *Driver) InitializeConnection(opt *pb.CreateVolumeAttachmentOpts) (*mode
 	}, nil
 }
 
+// newAuthOptions generates the secrets of an attachment according to the
+// access protocol, so that every attachment has its own credential.
+func (d *Driver) newAuthOptions(accPro string) (*targets.AuthOptions, error) {
+	var auth = &targets.AuthOptions{}
+	var err error
+	switch accPro {
+	case iscsiAccess:
+		// Mutual chap requires the initiator to be authenticated too.
+		if d.conf.EnableChapAuth || d.conf.EnableMutualChapAuth {
+			if auth.ChapUser, err = utils.SecureRandSeqWithAlnum(20); err != nil {
+				return nil, err
+			}
+			if auth.ChapPassword, err = utils.SecureRandSeqWithAlnum(16); err != nil {
+				return nil, err
+			}
+		}
+		if d.conf.EnableMutualChapAuth {
+			if auth.MutualChapUser, err = utils.SecureRandSeqWithAlnum(20); err != nil {
+				return nil, err
+			}
+			if auth.MutualChapPassword, err = utils.SecureRandSeqWithAlnum(16); err != nil {
+				return nil, err
+			}
+		}
+	case nvmeofAccess:
+		if d.conf.EnableDhchapAuth {
+			if auth.DhchapHostKey, err = targets.GenerateDhchapKey(); err != nil {
+				return nil, err
+			}
+			if auth.DhchapCtrlKey, err = targets.GenerateDhchapKey(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return auth, nil
+}
+
+func (d *Driver) initializeLocalConnection(hostInfo *pb.HostInfo, lvPath string) (*model.ConnectionInfo, error) {
+	if !d.conf.EnableLocalAttach {
+		err := errors.New("local attach is not enabled in lvm driver")
+		log.Error(err)
+		return nil, err
+	}
+	host, err := d.isLocalHost(hostInfo)
+	if err != nil {
+		log.Error("failed to check host of attachment:", err)
+		return nil, err
+	}
+	if !host {
+		err := fmt.Errorf("host %s(%s) is not the host of lvm driver, can not attach locally",
+			hostInfo.GetHost(), hostInfo.GetIp())
+		log.Error(err)
+		return nil, err
+	}
+
+	name, _ := hostname()
+	return &model.ConnectionInfo{
+		DriverVolumeType: LocalProtocol,
+		ConnectionData: map[string]interface{}{
+			"devicePath": lvPath,
+			"hostName":   name,
+		},
+	}, nil
+}
+
+// isLocalHost checks if the host of attachment is the one the driver runs
+// on, by either its host name or one of its ip addresses.
+func (d *Driver) isLocalHost(hostInfo *pb.HostInfo) (bool, error) {
+	if hostInfo.GetHost() != "" {
+		name, err := hostname()
+		if err != nil {
+			return false, err
+		}
+		if hostInfo.GetHost() == name {
+			return true, nil
+		}
+	}
+
+	ip := net.ParseIP(hostInfo.GetIp())
+	if ip == nil {
+		return false, nil
+	}
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return false, err
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (d *Driver) TerminateConnection(opt *pb.DeleteVolumeAttachmentOpts) error {
 	log.V(8).Infof("TerminateConnection: opt info is %v", opt)
 	accPro := opt.AccessProtocol
-	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro)
+	if accPro == LocalProtocol {
+		return nil
+	}
+	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro, d.iscsiTargetType(""))
 	if err := t.RemoveExport(opt.GetVolumeId(), opt.GetHostInfo().GetIp()); err != nil {
 		log.Error("failed to terminate connection of logic volume:", err)
 		return err
@@ -495,6 +658,10 @@ func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 	if err != nil {
 		return nil, err
 	}
+	lvSizes, err := d.cli.ListLvSizes()
+	if err != nil {
+		return nil, err
+	}
 
 	var pols []*model.StoragePoolSpec
 	for _, vg := range *vgs {
@@ -513,10 +680,22 @@ func (d *Driver) ListPools() ([]*model.StoragePoolSpec, error) {
 			Extras:           d.conf.Pool[vg.Name].Extras,
 			AvailabilityZone: d.conf.Pool[vg.Name].AvailabilityZone,
 			MultiAttach:      d.conf.Pool[vg.Name].MultiAttach,
+			// The logical volumes are thick provisioned.
+			ProvisionedCapacity: lvSizes[vg.Name],
+			AllocatedCapacity:   vg.TotalCapacity - vg.FreeCapacity,
 		}
+		d.conf.Pool[vg.Name].SetCapacityLimits(pol)
 		if pol.AvailabilityZone == "" {
 			pol.AvailabilityZone = "default"
 		}
+		if d.conf.EnableLocalAttach {
+			// Copy the advanced capabilities to keep the config untouched.
+			advanced := map[string]interface{}{KLocalAttach: true}
+			for k, v := range pol.Extras.Advanced {
+				advanced[k] = v
+			}
+			pol.Extras.Advanced = advanced
+		}
 		pols = append(pols, pol)
 	}
 	return pols, nil
@@ -538,10 +717,6 @@ func (d *Driver) InitializeSnapshotConnection(opt *pb.CreateSnapshotAttachmentOp
 		log.Error(err)
 		return nil, err
 	}
-	var chapAuth []string
-	if d.conf.EnableChapAuth {
-		chapAuth = []string{utils.RandSeqWithAlnum(20), utils.RandSeqWithAlnum(16)}
-	}
 	accPro := opt.AccessProtocol
 	if accPro == nvmeofAccess {
 		log.Infof("nvmet right now can not support snap volume serve as nvme target")
@@ -549,8 +724,13 @@ func (d *Driver) InitializeSnapshotConnection(opt *pb.CreateSnapshotAttachmentOp
 		accPro = iscsiAccess
 	}
 	accPro = iscsiAccess
-	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro)
-	data, err := t.CreateExport(opt.GetSnapshotId(), lvsPath, hostIP, initiator, chapAuth)
+	auth, err := d.newAuthOptions(accPro)
+	if err != nil {
+		log.Error("Failed to generate authentication secrets:", err)
+		return nil, err
+	}
+	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro, d.iscsiTargetType(path.Base(path.Dir(lvsPath))))
+	data, err := t.CreateExport(opt.GetSnapshotId(), lvsPath, hostIP, initiator, auth)
 	if err != nil {
 		log.Error("Failed to initialize snapshot connection of logic volume:", err)
 		return nil, err
@@ -570,7 +750,7 @@ func (d *Driver) TerminateSnapshotConnection(opt *pb.DeleteSnapshotAttachmentOpt
 		accPro = iscsiAccess
 	}
 	log.Info("terminate snapshot conn")
-	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro)
+	t := targets.NewTarget(d.conf.TgtBindIp, d.conf.TgtConfDir, accPro, d.iscsiTargetType(""))
 	if err := t.RemoveExport(opt.GetSnapshotId(), opt.GetHostInfo().GetIp()); err != nil {
 		log.Error("Failed to terminate snapshot connection of logic volume:", err)
 		return err
@@ -579,14 +759,119 @@ func (d *Driver) TerminateSnapshotConnection(opt *pb.DeleteSnapshotAttachmentOpt
 
 }
 
+// CreateVolumeGroup does nothing on the storage, since the members of a
+// group are only known by the group snapshots, which freeze them together.
 func (d *Driver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method CreateVolumeGroup has not been implemented yet"}
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:             opt.GetName(),
+		Description:      opt.GetDescription(),
+		AvailabilityZone: opt.GetAvailabilityZone(),
+		PoolId:           opt.GetPoolId(),
+		Status:           model.VolumeGroupAvailable,
+	}, nil
 }
 
 func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
-	return nil, &model.NotImplementError{"method UpdateVolumeGroup has not been implemented yet"}
+	return &model.VolumeGroupSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		PoolId: opt.GetPoolId(),
+		Status: model.VolumeGroupAvailable,
+	}, nil
 }
 
 func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
-	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
+	return nil
+}
+
+// CreateGroupSnapshot suspends the I/O of all the logic volumes in the group
+// before snapshotting them. Each volume is resumed by lvcreate once it is
+// snapshotted while the others stay suspended, so no write completes on any
+// volume between the freeze and its snapshot.
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	type member struct {
+		vg, lv, snapName string
+		snap             *pb.GroupSnapshotMember
+	}
+	var members []member
+	for _, s := range opt.GetSnapshots() {
+		lvPath := s.GetMetadata()[KLvPath]
+		fields := strings.Split(lvPath, "/")
+		if len(fields) != 4 {
+			err := fmt.Errorf("invalid 'lvPath' %q in metadata of volume %s", lvPath, s.GetVolumeId())
+			log.Error(err)
+			return nil, err
+		}
+		members = append(members, member{vg: fields[2], lv: fields[3], snapName: snapshotPrefix + s.GetId(), snap: s})
+	}
+
+	var frozen []member
+	defer func() {
+		for _, m := range frozen {
+			if err := d.cli.ResumeLv(m.lv, m.vg); err != nil {
+				log.Errorf("Failed to resume logic volume %s: %v", m.lv, err)
+			}
+		}
+	}()
+	for _, m := range members {
+		if err := d.cli.SuspendLv(m.lv, m.vg); err != nil {
+			log.Errorf("Failed to suspend logic volume %s: %v", m.lv, err)
+			return nil, err
+		}
+		frozen = append(frozen, m)
+	}
+
+	var snps []*model.VolumeSnapshotSpec
+	for i, m := range members {
+		if err := d.cli.CreateLvSnapshot(m.snapName, m.lv, m.vg, m.snap.GetSize()); err != nil {
+			log.Error("Failed to create logic volume snapshot:", err)
+			for _, c := range members[:i] {
+				if err := d.cli.Delete(c.snapName, c.vg); err != nil {
+					log.Error("Failed to remove logic volume snapshot:", err)
+				}
+			}
+			return nil, err
+		}
+		snps = append(snps, &model.VolumeSnapshotSpec{
+			BaseModel: &model.BaseModel{
+				Id: m.snap.GetId(),
+			},
+			Name:     opt.GetName(),
+			Size:     m.snap.GetSize(),
+			VolumeId: m.snap.GetVolumeId(),
+			Status:   model.VolumeSnapAvailable,
+			Metadata: map[string]string{KLvsPath: path.Join("/dev", m.vg, m.snapName)},
+		})
+	}
+
+	return &model.VolumeGroupSnapshotSpec{
+		BaseModel: &model.BaseModel{
+			Id: opt.GetId(),
+		},
+		Name:        opt.GetName(),
+		Description: opt.GetDescription(),
+		GroupId:     opt.GetGroupId(),
+		Status:      model.VolumeSnapAvailable,
+		Snapshots:   snps,
+	}, nil
+}
+
+// DeleteGroupSnapshot removes the snapshots of all the logic volumes, the
+// ones already removed are skipped, so it can be retried after a failure.
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	var lastErr error
+	for _, s := range opt.GetSnapshots() {
+		if err := d.DeleteSnapshot(&pb.DeleteVolumeSnapshotOpts{
+			Id:       s.GetId(),
+			VolumeId: s.GetVolumeId(),
+			Metadata: s.GetMetadata(),
+		}); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
 }
@@ -16,7 +16,10 @@ package lvm
 
 import (
 	"fmt"
+	"net"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/sodafoundation/dock/contrib/drivers/utils/config"
@@ -28,9 +31,10 @@ import (
 
 var fp = map[string]PoolProperties{
 	"vg001": {
-		StorageType:      "block",
-		AvailabilityZone: "default",
-		MultiAttach:      true,
+		StorageType:        "block",
+		AvailabilityZone:   "default",
+		MultiAttach:        true,
+		ReservedPercentage: 10,
 		Extras: model.StoragePoolExtraSpec{
 			DataStorage: model.DataStorageLoS{
 				ProvisioningPolicy: "Thin",
@@ -302,6 +306,162 @@ func TestDeleteSnapshot(t *testing.T) {
 	}
 }
 
+// recordExecuter records the commands run through env, and fails the ones
+// listed in fail.
+type recordExecuter struct {
+	cmds []string
+	fail map[string]bool
+}
+
+func (r *recordExecuter) Run(name string, args ...string) (string, error) {
+	cmd := strings.Join(args[1:3], " ")
+	r.cmds = append(r.cmds, cmd)
+	if r.fail[cmd] {
+		return "", fmt.Errorf("%s failed", cmd)
+	}
+	return "", nil
+}
+
+func TestCreateGroupSnapshot(t *testing.T) {
+	var fd = &Driver{}
+	config.CONF.OsdsDock.Backends.LVM.ConfigPath = "testdata/lvm.yaml"
+	fd.Setup()
+
+	opt := &pb.CreateGroupSnapshotOpts{
+		Id:      "3769855c-a102-11e7-b772-17b880d2f537",
+		Name:    "gsnap001",
+		GroupId: "3fb3dfd4-a102-11e7-b772-17b880d2f537",
+		Snapshots: []*pb.GroupSnapshotMember{
+			{Id: "snap1", VolumeId: "vol1", Size: 1, Metadata: map[string]string{KLvPath: "/dev/vg001/volume-vol1"}},
+			{Id: "snap2", VolumeId: "vol2", Size: 1, Metadata: map[string]string{KLvPath: "/dev/vg001/volume-vol2"}},
+		},
+	}
+	rec := &recordExecuter{}
+	fd.cli.RootExecuter = rec
+	gs, err := fd.CreateGroupSnapshot(opt)
+	if err != nil {
+		t.Fatal("Failed to create group snapshot:", err)
+	}
+	// All the volumes are suspended before any snapshot is taken.
+	expectedCmds := []string{
+		"dmsetup suspend", "dmsetup suspend",
+		"lvcreate -n", "lvcreate -n",
+		"dmsetup resume", "dmsetup resume",
+	}
+	if !reflect.DeepEqual(rec.cmds, expectedCmds) {
+		t.Errorf("Expected %v, got %v\n", expectedCmds, rec.cmds)
+	}
+	if len(gs.Snapshots) != 2 || gs.Snapshots[1].Metadata[KLvsPath] != "/dev/vg001/_snapshot-snap2" {
+		t.Errorf("Unexpected group snapshot %+v\n", gs)
+	}
+
+	// The created snapshots are removed and the volumes are resumed when
+	// one of the snapshots fails.
+	rec = &recordExecuter{fail: map[string]bool{"lvcreate -n": true}}
+	fd.cli.RootExecuter = rec
+	if _, err = fd.CreateGroupSnapshot(opt); err == nil {
+		t.Error("Expected error of failed snapshot")
+	}
+	expectedCmds = []string{"dmsetup suspend", "dmsetup suspend", "lvcreate -n", "dmsetup resume", "dmsetup resume"}
+	if !reflect.DeepEqual(rec.cmds, expectedCmds) {
+		t.Errorf("Expected %v, got %v\n", expectedCmds, rec.cmds)
+	}
+
+	opt.Snapshots[0].Metadata = nil
+	if _, err = fd.CreateGroupSnapshot(opt); err == nil {
+		t.Error("Expected error of missing lvPath")
+	}
+}
+
+func TestNewAuthOptions(t *testing.T) {
+	var fd = &Driver{conf: &LVMConfig{}}
+
+	auth, err := fd.newAuthOptions(iscsiAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.ChapUser != "" || auth.MutualChapUser != "" {
+		t.Errorf("Expected no chap secrets, got %+v", auth)
+	}
+
+	fd.conf.EnableMutualChapAuth = true
+	auth, err = fd.newAuthOptions(iscsiAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.ChapUser == "" || auth.ChapPassword == "" || auth.MutualChapUser == "" || auth.MutualChapPassword == "" {
+		t.Errorf("Expected mutual chap secrets, got %+v", auth)
+	}
+	if auth.ChapPassword == auth.MutualChapPassword {
+		t.Error("Expected different passwords for both directions")
+	}
+	if auth.DhchapHostKey != "" {
+		t.Errorf("Expected no dhchap secrets for iscsi, got %+v", auth)
+	}
+
+	fd.conf.EnableDhchapAuth = true
+	auth, err = fd.newAuthOptions(nvmeofAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.DhchapHostKey == "" || auth.DhchapCtrlKey == "" || auth.DhchapHostKey == auth.DhchapCtrlKey {
+		t.Errorf("Expected different dhchap host and controller keys, got %+v", auth)
+	}
+	if auth.ChapUser != "" {
+		t.Errorf("Expected no chap secrets for nvmeof, got %+v", auth)
+	}
+}
+
+func TestInitializeLocalConnection(t *testing.T) {
+	var fd = &Driver{}
+	config.CONF.OsdsDock.Backends.LVM.ConfigPath = "testdata/lvm.yaml"
+	fd.Setup()
+
+	hostname = func() (string, error) { return "node1", nil }
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return []net.Addr{&net.IPNet{IP: net.ParseIP("192.168.56.105"), Mask: net.CIDRMask(24, 32)}}, nil
+	}
+	defer func() { hostname, interfaceAddrs = os.Hostname, net.InterfaceAddrs }()
+
+	opt := &pb.CreateVolumeAttachmentOpts{
+		VolumeId:       "e1bb066c-5ce7-46eb-9336-25508cee9f71",
+		AccessProtocol: LocalProtocol,
+		HostInfo:       &pb.HostInfo{Host: "node1"},
+		Metadata:       map[string]string{KLvPath: "/dev/vg001/volume-e1bb066c"},
+	}
+	if _, err := fd.InitializeConnection(opt); err == nil {
+		t.Error("Expected an error when local attach is disabled")
+	}
+
+	fd.conf.EnableLocalAttach = true
+	var expected = &model.ConnectionInfo{
+		DriverVolumeType: LocalProtocol,
+		ConnectionData: map[string]interface{}{
+			"devicePath": "/dev/vg001/volume-e1bb066c",
+			"hostName":   "node1",
+		},
+	}
+	for _, hostInfo := range []*pb.HostInfo{{Host: "node1"}, {Host: "node2", Ip: "192.168.56.105"}} {
+		opt.HostInfo = hostInfo
+		info, err := fd.InitializeConnection(opt)
+		if err != nil {
+			t.Error("Failed to initialize local connection:", err)
+		}
+		if !reflect.DeepEqual(info, expected) {
+			t.Errorf("Expected %+v, got %+v\n", expected, info)
+		}
+	}
+
+	opt.HostInfo = &pb.HostInfo{Host: "node2", Ip: "192.168.56.106"}
+	if _, err := fd.InitializeConnection(opt); err == nil {
+		t.Error("Expected an error when attaching locally to another host")
+	}
+
+	if err := fd.TerminateConnection(&pb.DeleteVolumeAttachmentOpts{AccessProtocol: LocalProtocol}); err != nil {
+		t.Error("Failed to terminate local connection:", err)
+	}
+}
+
 func TestListPools(t *testing.T) {
 	var fd = &Driver{}
 	config.CONF.OsdsDock.Backends.LVM.ConfigPath = "testdata/lvm.yaml"
@@ -309,22 +469,30 @@ func TestListPools(t *testing.T) {
 
 	var vgsResp = `  vg001  18.00 18.00 ahF6kS-QNOH-X63K-avat-6Kag-XLTo-c9ghQ6
   ubuntu-vg               127.52  0.03 fQbqtg-3vDQ-vk3U-gfsT-50kJ-30pq-OZVSJH
+`
+	var lvsResp = `  vg001      4.00
+  vg001      2.50
+  ubuntu-vg 126.52
 `
 	respMap := map[string]*FakeResp{
 		"vgs": {vgsResp, nil},
+		"lvs": {lvsResp, nil},
 	}
 	fd.cli.RootExecuter = NewFakeExecuter(respMap)
 	fd.cli.BaseExecuter = NewFakeExecuter(respMap)
 
 	var expected = []*model.StoragePoolSpec{
 		{
-			BaseModel:        &model.BaseModel{},
-			Name:             "vg001",
-			TotalCapacity:    int64(18),
-			FreeCapacity:     int64(18),
-			AvailabilityZone: "default",
-			StorageType:      "block",
-			MultiAttach:      true,
+			BaseModel:                &model.BaseModel{},
+			Name:                     "vg001",
+			TotalCapacity:            int64(18),
+			FreeCapacity:             int64(18),
+			ProvisionedCapacity:      int64(6),
+			MaxOverSubscriptionRatio: 1,
+			ReservedPercentage:       10,
+			AvailabilityZone:         "default",
+			StorageType:              "block",
+			MultiAttach:              true,
 			Extras: model.StoragePoolExtraSpec{
 				DataStorage: model.DataStorageLoS{
 					ProvisioningPolicy: "Thin",
@@ -357,4 +525,16 @@ func TestListPools(t *testing.T) {
 	if !reflect.DeepEqual(pols, expected) {
 		t.Errorf("Expected %+v, got %+v\n", expected[0], pols[0])
 	}
+
+	fd.conf.EnableLocalAttach = true
+	pols, err = fd.ListPools()
+	if err != nil {
+		t.Error("Failed to list pools:", err)
+	}
+	if pols[0].Extras.Advanced[KLocalAttach] != true {
+		t.Errorf("Expected pool to advertise local attach, got %+v\n", pols[0].Extras.Advanced)
+	}
+	if _, ok := fd.conf.Pool["vg001"].Extras.Advanced[KLocalAttach]; ok {
+		t.Error("Expected pool config not to be modified")
+	}
 }
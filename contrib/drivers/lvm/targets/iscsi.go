@@ -34,7 +34,7 @@ const (
 )
 
 type ISCSITarget interface {
-	CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator string, chapAuth []string) error
+	CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator string, auth *AuthOptions) error
 	GetISCSITarget(iqn string) int
 	RemoveISCSITarget(volId, iqn, hostIp string) error
 	GetLun(path string) int
@@ -94,7 +94,11 @@ func CreateScsiIDFromVolID(volID string) string {
 	out[0] = '6'
 	return string(out)
 }
-func (t *tgtTarget) CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator string, chapAuth []string) error {
+
+// CreateISCSITarget creates or updates the target of the volume. Since a target
+// can only have one outgoing user, the mutual CHAP credential in auth will be
+// replaced with the existing one when the volume is attached more than once.
+func (t *tgtTarget) CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator string, auth *AuthOptions) error {
 	// Multi-attach require a specific ip
 	if hostIp == "" || hostIp == "ALL" {
 		msg := fmt.Sprintf("create ISCSI target failed: host ip %s cannot be empty or ALL, iscsi only allows specific ip access, not all", hostIp)
@@ -126,12 +130,24 @@ func (t *tgtTarget) CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator str
 		config.parse(string(data))
 	}
 
-	var charStr string
-	if len(chapAuth) != 0 {
-		charStr = fmt.Sprintf("%s %s", chapAuth[0], chapAuth[1])
-		config.updateConfigmap("incominguser", charStr)
+	// The credentials of a former attachment of the host are replaced.
+	config.removeHost(hostIp)
+	host := tgtHost{Ip: hostIp, Initiator: initiator}
+	if auth != nil && auth.ChapUser != "" {
+		config.updateConfigmap("incominguser", fmt.Sprintf("%s %s", auth.ChapUser, auth.ChapPassword))
+		host.ChapUser = auth.ChapUser
+		if auth.MutualChapUser != "" {
+			if outgoing := config["outgoinguser"]; len(outgoing) != 0 {
+				if fields := strings.Fields(outgoing[0]); len(fields) == 2 {
+					auth.MutualChapUser, auth.MutualChapPassword = fields[0], fields[1]
+				}
+			}
+			config["outgoinguser"] = []string{fmt.Sprintf("%s %s", auth.MutualChapUser, auth.MutualChapPassword)}
+			host.MutualChapUser = auth.MutualChapUser
+		}
 	}
 
+	config.setHost(host)
 	config.updateConfigmap("initiator-address", hostIp)
 	config.updateConfigmap("driver", "iscsi")
 	config.updateConfigmap("backing-store", path)
@@ -202,15 +218,8 @@ func (t *tgtTarget) RemoveISCSITarget(volId, iqn, hostIp string) error {
 
 	config.parse(string(data))
 
-	ips := config["initiator-address"]
-	for i, v := range ips {
-		if v == hostIp {
-			ips = append(ips[:i], ips[i+1:]...)
-			break
-		}
-	}
-	config["initiator-address"] = ips
-	if len(ips) == 0 {
+	config.removeHost(hostIp)
+	if len(config["initiator-address"]) == 0 {
 		if info, err := t.execCmd(tgtAdminCmd, "--force", "--delete", iqn); err != nil {
 			log.Errorf("Fail to exec '%s' to forcely remove iscsi target, %s, %v",
 				tgtAdminCmd, string(info), err)
@@ -258,14 +267,15 @@ func (m *configMap) parse(data string) {
 	var lines = strings.Split(data, "\n")
 
 	for _, line := range lines {
-		for _, key := range []string{"backing-store", "driver", "initiator-address", "write-cache"} {
-			if strings.Contains(line, key) {
-				s := strings.TrimSpace(line)
-				if (*m)[key] == nil {
-					(*m)[key] = []string{strings.Split(s, " ")[1]}
-				} else {
-					(*m)[key] = append((*m)[key], strings.Split(s, " ")[1])
-				}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, key := range []string{"backing-store", "driver", "initiator-address", "write-cache",
+			"incominguser", "outgoinguser", hostKey} {
+			if fields[0] == key {
+				// The value of chap users contains both name and password.
+				(*m)[key] = append((*m)[key], strings.Join(fields[1:], " "))
 			}
 		}
 	}
@@ -283,12 +293,102 @@ func (m *configMap) updateConfigmap(key, value string) {
 	}
 }
 
+// hostKey records the initiator and chap users of the attachment of a host, so
+// that its credentials can be revoked together with its address. tgt-admin
+// takes the record as a comment.
+const hostKey = "#opensds-host"
+
+// tgtHost is the record of the attachment of a host in the target config, the
+// empty fields are written as "-".
+type tgtHost struct {
+	Ip             string
+	Initiator      string
+	ChapUser       string
+	MutualChapUser string
+}
+
+func (h tgtHost) String() string {
+	fields := []string{h.Ip, h.Initiator, h.ChapUser, h.MutualChapUser}
+	for i := range fields {
+		if fields[i] == "" {
+			fields[i] = "-"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// hosts returns the host records of the target.
+func (m configMap) hosts() []tgtHost {
+	var hosts []tgtHost
+	for _, v := range m[hostKey] {
+		fields := strings.Fields(v)
+		if len(fields) != 4 {
+			continue
+		}
+		for i := range fields {
+			if fields[i] == "-" {
+				fields[i] = ""
+			}
+		}
+		hosts = append(hosts, tgtHost{Ip: fields[0], Initiator: fields[1], ChapUser: fields[2], MutualChapUser: fields[3]})
+	}
+	return hosts
+}
+
+func (m configMap) setHost(h tgtHost) {
+	m.removeValue(hostKey, func(v string) bool { return strings.Fields(v)[0] == h.Ip })
+	m[hostKey] = append(m[hostKey], h.String())
+}
+
+// removeHost removes the address of the host along with its record and its
+// incoming user. The outgoing user is shared by the hosts of the target, so it
+// is only removed when no other host uses mutual chap.
+func (m configMap) removeHost(hostIp string) {
+	m.removeValue("initiator-address", func(v string) bool { return v == hostIp })
+	for _, h := range m.hosts() {
+		if h.Ip != hostIp {
+			continue
+		}
+		if h.ChapUser != "" {
+			m.removeValue("incominguser", func(v string) bool { return strings.Fields(v)[0] == h.ChapUser })
+		}
+		m.removeValue(hostKey, func(v string) bool { return strings.Fields(v)[0] == hostIp })
+	}
+
+	for _, h := range m.hosts() {
+		if h.MutualChapUser != "" {
+			return
+		}
+	}
+	if len(m[hostKey]) != 0 || len(m["initiator-address"]) == 0 {
+		delete(m, "outgoinguser")
+	}
+}
+
+func (m configMap) removeValue(key string, match func(string) bool) {
+	var values []string
+	for _, v := range m[key] {
+		if !match(v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		delete(m, key)
+		return
+	}
+	m[key] = values
+}
+
 func (m configMap) writeConfig(file, tgtIqn string) error {
-	f, err := os.Create(file)
+	// The config may contain chap secrets, so it is only readable by owner.
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
 
 	f.WriteString(fmt.Sprintf("<target %s>\n", tgtIqn))
 	for k, v := range m {
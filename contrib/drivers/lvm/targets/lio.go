@@ -0,0 +1,680 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/utils"
+)
+
+const (
+	// TgtTargetType and LioTargetType are the supported iscsi target
+	// implementations of lvm driver.
+	TgtTargetType = "tgt"
+	LioTargetType = "lio"
+
+	lioPortalPort = "3260"
+	lioTpg        = "tpgt_1"
+	// The lun number is the same as tgt, so an initiator still finds the
+	// volume on the same lun after the export is migrated.
+	lioLun        = "lun_1"
+	lioLunId      = 1
+	lioAllAccess  = "ALL"
+	lioStateExt   = ".lio.json"
+	lioBackstores = "core/iblock_0"
+)
+
+var (
+	// LioConfigfsDir is the root of LIO in configfs, it is a variable so that
+	// unit tests can run against a fake tree.
+	LioConfigfsDir = "/sys/kernel/config/target"
+	// removeConfigfsItem removes a symlink or an empty directory of configfs.
+	removeConfigfsItem = os.Remove
+	// showTgtTargets returns the targets of tgtd along with their sessions.
+	showTgtTargets = func() (string, error) {
+		return (&tgtTarget{}).execCmd(tgtAdminCmd, "--show")
+	}
+
+	lioMutex     sync.Mutex
+	migrateMutex sync.Mutex
+)
+
+// lioHost records the access of one attached host, which is granted by the
+// node acl of its initiator.
+type lioHost struct {
+	Initiator          string `json:"initiator"`
+	ChapUser           string `json:"chapUser,omitempty"`
+	ChapPassword       string `json:"chapPassword,omitempty"`
+	MutualChapUser     string `json:"mutualChapUser,omitempty"`
+	MutualChapPassword string `json:"mutualChapPassword,omitempty"`
+}
+
+// lioExport is persisted in the target config directory, which is used to
+// remove the access of a host and to restore the exports after reboot since
+// configfs is not persistent.
+type lioExport struct {
+	Iqn   string              `json:"iqn"`
+	Path  string              `json:"path"`
+	Hosts map[string]*lioHost `json:"hosts"`
+}
+
+// NewLioTarget creates an ISCSITarget which manages the kernel LIO target
+// through configfs directly.
+func NewLioTarget(bip, tgtConfDir string) ISCSITarget {
+	return &lioTarget{
+		BindIp:     bip,
+		TgtConfDir: tgtConfDir,
+	}
+}
+
+type lioTarget struct {
+	BindIp     string
+	TgtConfDir string
+}
+
+func (t *lioTarget) getStatePath(volId string) string {
+	return filepath.Join(t.TgtConfDir, opensdsPrefix+volId+lioStateExt)
+}
+
+func (t *lioTarget) loadState(volId string) (*lioExport, error) {
+	data, err := ioutil.ReadFile(t.getStatePath(volId))
+	if err != nil {
+		return nil, err
+	}
+	exp := &lioExport{}
+	if err := json.Unmarshal(data, exp); err != nil {
+		return nil, err
+	}
+	if exp.Hosts == nil {
+		exp.Hosts = map[string]*lioHost{}
+	}
+	return exp, nil
+}
+
+func (t *lioTarget) saveState(volId string, exp *lioExport) error {
+	if exist, _ := utils.PathExists(t.TgtConfDir); !exist {
+		os.MkdirAll(t.TgtConfDir, 0755)
+	}
+	data, err := json.MarshalIndent(exp, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The state may contain chap secrets, so it is only readable by owner.
+	return ioutil.WriteFile(t.getStatePath(volId), data, 0600)
+}
+
+// CreateISCSITarget creates or updates the LIO target of the volume. Each
+// host has its own node acl and chap credentials. Since LIO can not restrict
+// the access by ip address, the initiator of the host is required.
+func (t *lioTarget) CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator string, auth *AuthOptions) error {
+	if hostIp == "" || hostIp == lioAllAccess {
+		msg := fmt.Sprintf("create ISCSI target failed: host ip %s cannot be empty or ALL, iscsi only allows specific ip access, not all", hostIp)
+		log.Error(msg)
+		return errors.New(msg)
+	}
+	if net.ParseIP(hostIp) == nil {
+		msg := fmt.Sprintf("%s is not a valid ip, please give the proper ip", hostIp)
+		log.Error(msg)
+		return errors.New(msg)
+	}
+	if initiator == "" || initiator == lioAllAccess {
+		msg := fmt.Sprintf("create ISCSI target failed: initiator of host %s is required by lio target", hostIp)
+		log.Error(msg)
+		return errors.New(msg)
+	}
+
+	lioMutex.Lock()
+	defer lioMutex.Unlock()
+
+	exp, err := t.loadState(volId)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		exp = &lioExport{Hosts: map[string]*lioHost{}}
+	}
+	exp.Iqn, exp.Path = tgtIqn, path
+
+	host := &lioHost{Initiator: initiator}
+	if auth != nil && auth.ChapUser != "" {
+		host.ChapUser, host.ChapPassword = auth.ChapUser, auth.ChapPassword
+		host.MutualChapUser, host.MutualChapPassword = auth.MutualChapUser, auth.MutualChapPassword
+	}
+	exp.Hosts[hostIp] = host
+
+	if err := t.saveState(volId, exp); err != nil {
+		log.Errorf("failed to update lio state file %s %v", t.getStatePath(volId), err)
+		return err
+	}
+	if err := t.apply(volId, exp); err != nil {
+		log.Errorf("failed to configure lio target %s: %v", tgtIqn, err)
+		return err
+	}
+	return nil
+}
+
+// GetISCSITarget returns the target portal group tag of the target, or -1
+// if the target does not exist.
+func (t *lioTarget) GetISCSITarget(iqn string) int {
+	if IsExist(filepath.Join(LioConfigfsDir, "iscsi", iqn, lioTpg)) {
+		return 1
+	}
+	return -1
+}
+
+// GetLun returns the lun id of the backing device, or -1 if the device is not
+// exported.
+func (t *lioTarget) GetLun(path string) int {
+	dirs, _ := filepath.Glob(filepath.Join(LioConfigfsDir, lioBackstores, opensdsPrefix+"*"))
+	for _, dir := range dirs {
+		if readConfigfsAttr(filepath.Join(dir, "udev_path")) == path {
+			return lioLunId
+		}
+	}
+	return -1
+}
+
+// RemoveISCSITarget removes the access of the host, the target and its
+// backstore are deleted along with the last host.
+func (t *lioTarget) RemoveISCSITarget(volId, iqn, hostIp string) error {
+	if hostIp == "" {
+		return errors.New("remove ISCSI target failed, host ip cannot be empty")
+	}
+
+	lioMutex.Lock()
+	defer lioMutex.Unlock()
+
+	exp, err := t.loadState(volId)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warningf("Lio state %s does not exist, nothing to remove.", t.getStatePath(volId))
+			return nil
+		}
+		return err
+	}
+	delete(exp.Hosts, hostIp)
+
+	if len(exp.Hosts) != 0 {
+		if err := t.saveState(volId, exp); err != nil {
+			return err
+		}
+		return t.apply(volId, exp)
+	}
+	if err := t.destroy(volId, iqn); err != nil {
+		log.Errorf("failed to remove lio target %s: %v", iqn, err)
+		return err
+	}
+	os.Remove(t.getStatePath(volId))
+	return nil
+}
+
+// RestoreLioTargets re-creates the missing LIO targets from the state files,
+// it is used to recover the exports after the configfs is cleared by reboot.
+func RestoreLioTargets(bip, tgtConfDir string) error {
+	lioMutex.Lock()
+	defer lioMutex.Unlock()
+
+	t := &lioTarget{BindIp: bip, TgtConfDir: tgtConfDir}
+	files, _ := filepath.Glob(filepath.Join(tgtConfDir, opensdsPrefix+"*"+lioStateExt))
+	for _, file := range files {
+		volId := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), opensdsPrefix), lioStateExt)
+		exp, err := t.loadState(volId)
+		if err != nil {
+			return err
+		}
+		if t.GetISCSITarget(exp.Iqn) != -1 {
+			continue
+		}
+		if err := t.apply(volId, exp); err != nil {
+			return err
+		}
+		log.Infof("Restored lio target %s", exp.Iqn)
+	}
+	return nil
+}
+
+// apply makes the configfs tree of the target consistent with the export,
+// all the steps are idempotent so it can be called repeatedly.
+func (t *lioTarget) apply(volId string, exp *lioExport) error {
+	name := opensdsPrefix + volId
+	backstore := filepath.Join(LioConfigfsDir, lioBackstores, name)
+	if !IsExist(filepath.Join(backstore, "udev_path")) ||
+		readConfigfsAttr(filepath.Join(backstore, "enable")) != "1" {
+		if err := os.MkdirAll(backstore, 0755); err != nil {
+			return err
+		}
+		if err := writeConfigfsAttr(filepath.Join(backstore, "control"), "udev_path="+exp.Path); err != nil {
+			return err
+		}
+		if err := writeConfigfsAttr(filepath.Join(backstore, "udev_path"), exp.Path); err != nil {
+			return err
+		}
+		if err := writeConfigfsAttr(filepath.Join(backstore, "wwn", "vpd_unit_serial"), CreateScsiIDFromVolID(volId)); err != nil {
+			return err
+		}
+		if err := writeConfigfsAttr(filepath.Join(backstore, "enable"), "1"); err != nil {
+			return err
+		}
+	}
+
+	tpg := filepath.Join(LioConfigfsDir, "iscsi", exp.Iqn, lioTpg)
+	lun := filepath.Join(tpg, "lun", lioLun)
+	if err := os.MkdirAll(lun, 0755); err != nil {
+		return err
+	}
+	if err := linkConfigfsItem(backstore, filepath.Join(lun, name)); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(tpg, "np", net.JoinHostPort(t.BindIp, lioPortalPort)), 0755); err != nil {
+		return err
+	}
+
+	var chap bool
+	acls := map[string]*lioHost{}
+	for ip, h := range exp.Hosts {
+		// The hosts allowed by the generated acls of the former releases
+		// have to be attached again with their initiators.
+		if h.Initiator == lioAllAccess {
+			log.Warningf("Host %s of lio target %s has no initiator, its access is revoked", ip, exp.Iqn)
+			continue
+		}
+		acls[h.Initiator] = h
+		chap = chap || h.ChapUser != ""
+	}
+
+	// Remove the acls of the detached hosts.
+	existing, _ := ioutil.ReadDir(filepath.Join(tpg, "acls"))
+	for _, fi := range existing {
+		if _, ok := acls[fi.Name()]; !ok {
+			if err := removeLioAcl(filepath.Join(tpg, "acls", fi.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	for initiator, h := range acls {
+		acl := filepath.Join(tpg, "acls", initiator)
+		if err := os.MkdirAll(filepath.Join(acl, lioLun), 0755); err != nil {
+			return err
+		}
+		if err := linkConfigfsItem(lun, filepath.Join(acl, lioLun, lioLun)); err != nil {
+			return err
+		}
+		if err := writeLioAuth(filepath.Join(acl, "auth"), h); err != nil {
+			return err
+		}
+	}
+
+	attrs := map[string]string{
+		"authentication":          "0",
+		"generate_node_acls":      "0",
+		"cache_dynamic_acls":      "0",
+		"demo_mode_write_protect": "0",
+	}
+	if chap {
+		attrs["authentication"] = "1"
+	}
+	for k, v := range attrs {
+		if err := writeConfigfsAttr(filepath.Join(tpg, "attrib", k), v); err != nil {
+			return err
+		}
+	}
+	return writeConfigfsAttr(filepath.Join(tpg, "enable"), "1")
+}
+
+// destroy removes the configfs items of the target in reverse order of
+// creation.
+func (t *lioTarget) destroy(volId, iqn string) error {
+	target := filepath.Join(LioConfigfsDir, "iscsi", iqn)
+	tpg := filepath.Join(target, lioTpg)
+	if IsExist(tpg) {
+		writeConfigfsAttr(filepath.Join(tpg, "enable"), "0")
+
+		acls, _ := ioutil.ReadDir(filepath.Join(tpg, "acls"))
+		for _, fi := range acls {
+			if err := removeLioAcl(filepath.Join(tpg, "acls", fi.Name())); err != nil {
+				return err
+			}
+		}
+		portals, _ := ioutil.ReadDir(filepath.Join(tpg, "np"))
+		for _, fi := range portals {
+			if err := removeConfigfsItem(filepath.Join(tpg, "np", fi.Name())); err != nil {
+				return err
+			}
+		}
+		lun := filepath.Join(tpg, "lun", lioLun)
+		if err := removeConfigfsLinks(lun); err != nil {
+			return err
+		}
+		for _, dir := range []string{lun, tpg} {
+			if err := removeConfigfsItem(dir); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	if err := removeConfigfsItem(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	backstore := filepath.Join(LioConfigfsDir, lioBackstores, opensdsPrefix+volId)
+	if err := removeConfigfsItem(backstore); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func removeLioAcl(acl string) error {
+	lun := filepath.Join(acl, lioLun)
+	if err := removeConfigfsLinks(lun); err != nil {
+		return err
+	}
+	if err := removeConfigfsItem(lun); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return removeConfigfsItem(acl)
+}
+
+// removeConfigfsLinks removes all the symlinks in the directory.
+func removeConfigfsLinks(dir string) error {
+	items, _ := ioutil.ReadDir(dir)
+	for _, fi := range items {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if err := removeConfigfsItem(filepath.Join(dir, fi.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLioAuth(dir string, h *lioHost) error {
+	attrs := map[string]string{
+		"userid":          h.ChapUser,
+		"password":        h.ChapPassword,
+		"userid_mutual":   h.MutualChapUser,
+		"password_mutual": h.MutualChapPassword,
+	}
+	for k, v := range attrs {
+		if err := writeConfigfsAttr(filepath.Join(dir, k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func linkConfigfsItem(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return nil
+	}
+	return os.Symlink(src, dst)
+}
+
+// writeConfigfsAttr writes an attribute of configfs. The attribute is created
+// if it does not exist, which only happens in a fake tree since the kernel
+// creates all the attributes along with the directory.
+func writeConfigfsAttr(file, value string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(value)
+	return err
+}
+
+func readConfigfsAttr(file string) string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// MigrateTgtToLio moves the tgt exports of the given volume groups to LIO,
+// exports of all volume groups are migrated if vgs is empty. Every host gets
+// the node acl of its initiator with its own chap credentials, which are
+// taken from the host records of the tgt config, or rebuilt from the addresses
+// and the sessions of the hosts if the config has none. The tgt target is deleted
+// only after LIO serves the lun, so tgtd must listen on another portal than
+// LIO. It is a one-time step run by the lioMigrator tool while osdsdock is
+// stopped.
+func MigrateTgtToLio(bip, tgtConfDir string, vgs []string) error {
+	migrateMutex.Lock()
+	defer migrateMutex.Unlock()
+
+	files, _ := filepath.Glob(filepath.Join(tgtConfDir, opensdsPrefix+"*.conf"))
+	// The initiators of the configs without host records are only known from
+	// the sessions of the hosts which are logged in.
+	targets, err := showTgtTargets()
+	if err != nil {
+		log.Warningf("Failed to get the sessions of tgt targets: %v", err)
+	}
+	for _, file := range files {
+		volId := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), opensdsPrefix), ".conf")
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		config := make(configMap)
+		config.parse(string(data))
+		iqn := parseTgtIqn(string(data))
+		if iqn == "" || len(config["backing-store"]) == 0 {
+			log.Warningf("Skip migrating invalid tgt config %s", file)
+			continue
+		}
+		path := config["backing-store"][0]
+		if len(vgs) != 0 && !utils.Contains(vgs, filepath.Base(filepath.Dir(path))) {
+			continue
+		}
+
+		exp, err := newLioExport(config, iqn, path, tgtSessions(targets, iqn))
+		if err != nil {
+			log.Warningf("Skip migrating tgt target %s: %v", iqn, err)
+			continue
+		}
+		if err := migrateTgtTarget(bip, tgtConfDir, volId, exp); err != nil {
+			return err
+		}
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+		log.Infof("Migrated iscsi target %s from tgt to lio", iqn)
+	}
+	return nil
+}
+
+// tgtSessions returns the initiators of the sessions of the target by their
+// addresses, parsed from the output of tgt-admin --show.
+func tgtSessions(targets, iqn string) map[string]string {
+	sessions := map[string]string{}
+	var inTarget bool
+	var initiator string
+	for _, line := range strings.Split(targets, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Target ") {
+			fields := strings.Fields(line)
+			inTarget = fields[len(fields)-1] == iqn
+			continue
+		}
+		if !inTarget {
+			continue
+		}
+		if strings.HasPrefix(line, "Initiator:") {
+			if fields := strings.Fields(line); len(fields) > 1 {
+				initiator = fields[1]
+			}
+		} else if strings.HasPrefix(line, "IP Address:") && initiator != "" {
+			sessions[strings.TrimSpace(strings.TrimPrefix(line, "IP Address:"))] = initiator
+		}
+	}
+	return sessions
+}
+
+// legacyTgtHosts rebuilds the hosts of a tgt config written before the host
+// records were kept. The initiator names and the incoming users are paired
+// with the addresses in the order they were added, and a single one is
+// shared by all the hosts, the same as tgt does. The initiators which are not
+// in the config are taken from the sessions.
+func legacyTgtHosts(config configMap, sessions map[string]string) ([]tgtHost, error) {
+	ips, names := config["initiator-address"], config["initiator-name"]
+	var users, mutualUsers []string
+	for _, v := range config["incominguser"] {
+		if fields := strings.Fields(v); len(fields) != 0 {
+			users = append(users, fields[0])
+		}
+	}
+	for _, v := range config["outgoinguser"] {
+		if fields := strings.Fields(v); len(fields) != 0 {
+			mutualUsers = append(mutualUsers, fields[0])
+		}
+	}
+	pick := func(values []string, i int) (string, bool) {
+		switch len(values) {
+		case 0:
+			return "", true
+		case 1:
+			return values[0], true
+		case len(ips):
+			return values[i], true
+		}
+		return "", false
+	}
+
+	var hosts []tgtHost
+	for i, ip := range ips {
+		initiator, ok := pick(names, i)
+		if len(names) == 0 {
+			initiator = sessions[ip]
+		}
+		if !ok || initiator == "" {
+			return nil, fmt.Errorf("initiator of host %s is unknown", ip)
+		}
+		user, ok := pick(users, i)
+		if !ok {
+			return nil, fmt.Errorf("chap credential of host %s is unknown", ip)
+		}
+		h := tgtHost{Ip: ip, Initiator: initiator, ChapUser: user}
+		if user != "" {
+			if h.MutualChapUser, ok = pick(mutualUsers, i); !ok {
+				return nil, fmt.Errorf("chap credential of host %s is unknown", ip)
+			}
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
+// newLioExport converts the tgt config to a LIO export, it fails if the
+// initiator of any host is unknown since its access can not be kept.
+func newLioExport(config configMap, iqn, path string, sessions map[string]string) (*lioExport, error) {
+	ips := config["initiator-address"]
+	if len(ips) == 0 {
+		return nil, errors.New("no host is attached")
+	}
+	secrets := map[string]string{}
+	for _, users := range [][]string{config["incominguser"], config["outgoinguser"]} {
+		for _, v := range users {
+			if fields := strings.Fields(v); len(fields) == 2 {
+				secrets[fields[0]] = fields[1]
+			}
+		}
+	}
+
+	hosts := config.hosts()
+	if len(hosts) == 0 {
+		var err error
+		if hosts, err = legacyTgtHosts(config, sessions); err != nil {
+			return nil, err
+		}
+	}
+	exp := &lioExport{Iqn: iqn, Path: path, Hosts: map[string]*lioHost{}}
+	for _, h := range hosts {
+		if h.Initiator == "" || h.Initiator == lioAllAccess {
+			return nil, fmt.Errorf("initiator of host %s is unknown", h.Ip)
+		}
+		host := &lioHost{Initiator: h.Initiator}
+		if (h.ChapUser != "" && secrets[h.ChapUser] == "") ||
+			(h.MutualChapUser != "" && secrets[h.MutualChapUser] == "") {
+			return nil, fmt.Errorf("chap credential of host %s is unknown", h.Ip)
+		}
+		if h.ChapUser != "" {
+			host.ChapUser, host.ChapPassword = h.ChapUser, secrets[h.ChapUser]
+			if h.MutualChapUser != "" {
+				host.MutualChapUser, host.MutualChapPassword = h.MutualChapUser, secrets[h.MutualChapUser]
+			}
+		}
+		exp.Hosts[h.Ip] = host
+	}
+	for _, ip := range ips {
+		if _, ok := exp.Hosts[ip]; !ok {
+			return nil, fmt.Errorf("initiator of host %s is unknown", ip)
+		}
+	}
+	return exp, nil
+}
+
+// migrateTgtTarget serves the export by LIO before the tgt target is deleted,
+// the LIO target is rolled back if it fails to serve the lun.
+func migrateTgtTarget(bip, tgtConfDir, volId string, exp *lioExport) error {
+	lioMutex.Lock()
+	defer lioMutex.Unlock()
+
+	lio := &lioTarget{BindIp: bip, TgtConfDir: tgtConfDir}
+	err := lio.saveState(volId, exp)
+	if err == nil {
+		err = lio.apply(volId, exp)
+	}
+	if err == nil && (lio.GetISCSITarget(exp.Iqn) == -1 || lio.GetLun(exp.Path) == -1) {
+		err = fmt.Errorf("lio does not serve the lun of %s", exp.Iqn)
+	}
+	if err != nil {
+		log.Errorf("failed to migrate tgt target %s to lio: %v", exp.Iqn, err)
+		if err := lio.destroy(volId, exp.Iqn); err != nil {
+			log.Errorf("failed to roll back lio target %s: %v", exp.Iqn, err)
+		}
+		os.Remove(lio.getStatePath(volId))
+		return err
+	}
+
+	// The failure is ignored since tgtd may have been stopped.
+	tgt := &tgtTarget{BindIp: bip, TgtConfDir: tgtConfDir}
+	if info, err := tgt.execCmd(tgtAdminCmd, "--force", "--delete", exp.Iqn); err != nil {
+		log.Warningf("Fail to exec '%s' to remove tgt target %s, %s, %v", tgtAdminCmd, exp.Iqn, info, err)
+	}
+	return nil
+}
+
+func parseTgtIqn(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "<target ") {
+			return strings.TrimSuffix(strings.TrimPrefix(line, "<target "), ">")
+		}
+	}
+	return ""
+}
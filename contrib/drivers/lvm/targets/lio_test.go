@@ -0,0 +1,253 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sodafoundation/dock/contrib/drivers/utils/config"
+)
+
+// fakeConfigfs points LIO to a temp dir, the directories of the fake tree
+// contain regular files so they are removed recursively.
+func fakeConfigfs(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "opensds-lio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldDir, oldRemove := LioConfigfsDir, removeConfigfsItem
+	LioConfigfsDir = filepath.Join(dir, "configfs")
+	removeConfigfsItem = os.RemoveAll
+	return dir, func() {
+		LioConfigfsDir, removeConfigfsItem = oldDir, oldRemove
+		os.RemoveAll(dir)
+	}
+}
+
+func expectAttr(t *testing.T, file, expected string) {
+	t.Helper()
+	if got := readConfigfsAttr(file); got != expected {
+		t.Errorf("Expected %s of %s, got %s", expected, file, got)
+	}
+}
+
+func TestLioTarget(t *testing.T) {
+	dir, cleanup := fakeConfigfs(t)
+	defer cleanup()
+
+	const (
+		volId     = "bd5b12a8-a101-11e7-941e-d77981b584d8"
+		path      = "/dev/vg001/volume-bd5b12a8-a101-11e7-941e-d77981b584d8"
+		initiator = "iqn.1993-08.org.debian:01:host1"
+	)
+	iqn := iscsiTgtPrefix + volId
+	lio := NewLioTarget("192.168.0.10", filepath.Join(dir, "conf")).(*lioTarget)
+	auth := &AuthOptions{ChapUser: "user1", ChapPassword: "password1",
+		MutualChapUser: "mutual1", MutualChapPassword: "password2"}
+
+	if err := lio.CreateISCSITarget(volId, iqn, path, "ALL", initiator, auth); err == nil {
+		t.Error("Expected error when host ip is ALL")
+	}
+	if err := lio.CreateISCSITarget(volId, iqn, path, "192.168.0.1", initiator, auth); err != nil {
+		t.Fatal(err)
+	}
+
+	backstore := filepath.Join(LioConfigfsDir, lioBackstores, opensdsPrefix+volId)
+	tpg := filepath.Join(LioConfigfsDir, "iscsi", iqn, lioTpg)
+	acl := filepath.Join(tpg, "acls", initiator)
+	expectAttr(t, filepath.Join(backstore, "control"), "udev_path="+path)
+	expectAttr(t, filepath.Join(backstore, "enable"), "1")
+	expectAttr(t, filepath.Join(acl, "auth", "userid"), "user1")
+	expectAttr(t, filepath.Join(acl, "auth", "password_mutual"), "password2")
+	expectAttr(t, filepath.Join(tpg, "attrib", "authentication"), "1")
+	expectAttr(t, filepath.Join(tpg, "attrib", "generate_node_acls"), "0")
+	expectAttr(t, filepath.Join(tpg, "enable"), "1")
+	if link, err := os.Readlink(filepath.Join(tpg, "lun", lioLun, opensdsPrefix+volId)); err != nil || link != backstore {
+		t.Errorf("Expected lun linked to %s, got %s, %v", backstore, link, err)
+	}
+	if !IsExist(filepath.Join(tpg, "np", "192.168.0.10:3260")) {
+		t.Error("Expected network portal to be created")
+	}
+	if fi, err := os.Stat(lio.getStatePath(volId)); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected state file with mode 0600, got %v, %v", fi, err)
+	}
+	if lio.GetISCSITarget(iqn) != 1 || lio.GetLun(path) != lioLunId {
+		t.Error("Expected target and lun to be found")
+	}
+
+	// LIO can not restrict the access of a host without initiator.
+	if err := lio.CreateISCSITarget(volId, iqn, path, "192.168.0.2", "", nil); err == nil {
+		t.Error("Expected error when initiator is empty")
+	}
+	if err := lio.CreateISCSITarget(volId, iqn, path, "192.168.0.2", "iqn.1993-08.org.debian:01:host2",
+		&AuthOptions{ChapUser: "user2", ChapPassword: "password3"}); err != nil {
+		t.Fatal(err)
+	}
+	expectAttr(t, filepath.Join(tpg, "attrib", "generate_node_acls"), "0")
+	expectAttr(t, filepath.Join(tpg, "acls", "iqn.1993-08.org.debian:01:host2", "auth", "userid"), "user2")
+	expectAttr(t, filepath.Join(acl, "auth", "userid"), "user1")
+
+	if err := lio.RemoveISCSITarget(volId, iqn, "192.168.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if IsExist(acl) {
+		t.Error("Expected acl of the detached host to be removed")
+	}
+	if err := lio.RemoveISCSITarget(volId, iqn, "192.168.0.2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{backstore, filepath.Join(LioConfigfsDir, "iscsi", iqn), lio.getStatePath(volId)} {
+		if IsExist(f) {
+			t.Errorf("Expected %s to be removed", f)
+		}
+	}
+	if err := lio.RemoveISCSITarget(volId, iqn, "192.168.0.2"); err != nil {
+		t.Error("Expected nothing to remove, got", err)
+	}
+}
+
+func TestRestoreLioTargets(t *testing.T) {
+	dir, cleanup := fakeConfigfs(t)
+	defer cleanup()
+
+	confDir := filepath.Join(dir, "conf")
+	lio := NewLioTarget("192.168.0.10", confDir)
+	iqn := iscsiTgtPrefix + "volume-1"
+	if err := lio.CreateISCSITarget("volume-1", iqn, "/dev/vg001/volume-1", "192.168.0.1", "iqn.1993-08.org.debian:01:host1", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Reboot clears the configfs.
+	os.RemoveAll(LioConfigfsDir)
+	if err := RestoreLioTargets("192.168.0.10", confDir); err != nil {
+		t.Fatal(err)
+	}
+	if lio.GetISCSITarget(iqn) != 1 || lio.GetLun("/dev/vg001/volume-1") != lioLunId {
+		t.Error("Expected lio target to be restored")
+	}
+}
+
+func TestMigrateTgtToLio(t *testing.T) {
+	dir, cleanup := fakeConfigfs(t)
+	defer cleanup()
+
+	confDir := filepath.Join(dir, "conf")
+	os.MkdirAll(confDir, 0755)
+	tgt := &tgtTarget{BindIp: "192.168.0.10", TgtConfDir: confDir}
+	hosts := []tgtHost{
+		{Ip: "192.168.0.1", Initiator: "iqn.1993-08.org.debian:01:host1", ChapUser: "user1", MutualChapUser: "mutual"},
+		{Ip: "192.168.0.2", Initiator: "iqn.1993-08.org.debian:01:host2", ChapUser: "user2"},
+	}
+	for volId, path := range map[string]string{
+		"volume-1": "/dev/vg001/volume-1",
+		"volume-2": "/dev/vg002/volume-2",
+		"volume-3": "/dev/vg001/volume-3",
+	} {
+		conf := make(configMap)
+		conf.updateConfigmap("incominguser", "user1 password1")
+		conf.updateConfigmap("incominguser", "user2 password2")
+		conf["outgoinguser"] = []string{"mutual password3"}
+		conf.updateConfigmap("backing-store", path)
+		for _, h := range hosts {
+			// The initiators of volume-3 were not recorded.
+			if volId != "volume-3" {
+				conf.setHost(h)
+			}
+			conf.updateConfigmap("initiator-address", h.Ip)
+		}
+		if err := conf.writeConfig(tgt.getTgtConfPath(volId), iscsiTgtPrefix+volId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// volume-4 was attached before the host records were kept, so its
+	// initiators are taken from the sessions of tgtd.
+	legacy := configMap{
+		"initiator-address": {"192.168.0.1", "192.168.0.2"},
+		"incominguser":      {"user1 password1", "user2 password2"},
+		"backing-store":     {"/dev/vg001/volume-4"},
+	}
+	if err := legacy.writeConfig(tgt.getTgtConfPath("volume-4"), iscsiTgtPrefix+"volume-4"); err != nil {
+		t.Fatal(err)
+	}
+	defer func(f func() (string, error)) { showTgtTargets = f }(showTgtTargets)
+	showTgtTargets = func() (string, error) {
+		return `Target 1: ` + iscsiTgtPrefix + `volume-3
+    I_T nexus information:
+Target 2: ` + iscsiTgtPrefix + `volume-4
+    System information:
+        Driver: iscsi
+    I_T nexus information:
+        I_T nexus: 1
+            Initiator: iqn.1993-08.org.debian:01:host1 alias: host1
+            Connection: 0
+                IP Address: 192.168.0.1
+        I_T nexus: 2
+            Initiator: iqn.1993-08.org.debian:01:host2 alias: host2
+            Connection: 0
+                IP Address: 192.168.0.2
+    LUN information:
+`, nil
+	}
+
+	if err := MigrateTgtToLio("192.168.0.10", confDir, []string{"vg001"}); err != nil {
+		t.Fatal(err)
+	}
+	if IsExist(tgt.getTgtConfPath("volume-1")) || !IsExist(tgt.getTgtConfPath("volume-2")) {
+		t.Error("Expected only the tgt export of vg001 to be migrated")
+	}
+	if !IsExist(tgt.getTgtConfPath("volume-3")) || IsExist(filepath.Join(LioConfigfsDir, "iscsi", iscsiTgtPrefix+"volume-3")) {
+		t.Error("Expected the tgt export without initiators to be kept")
+	}
+	lio := &lioTarget{BindIp: "192.168.0.10", TgtConfDir: confDir}
+	exp, err := lio.loadState("volume-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]*lioHost{
+		"192.168.0.1": {Initiator: "iqn.1993-08.org.debian:01:host1", ChapUser: "user1", ChapPassword: "password1",
+			MutualChapUser: "mutual", MutualChapPassword: "password3"},
+		"192.168.0.2": {Initiator: "iqn.1993-08.org.debian:01:host2", ChapUser: "user2", ChapPassword: "password2"},
+	}
+	if !reflect.DeepEqual(exp.Hosts, expected) {
+		t.Errorf("Expected migrated hosts %v, got %v", expected, exp.Hosts)
+	}
+	if exp, err = lio.loadState("volume-4"); err != nil {
+		t.Fatal(err)
+	}
+	expected["192.168.0.1"].MutualChapUser, expected["192.168.0.1"].MutualChapPassword = "", ""
+	if !reflect.DeepEqual(exp.Hosts, expected) {
+		t.Errorf("Expected migrated hosts %v, got %v", expected, exp.Hosts)
+	}
+	if IsExist(tgt.getTgtConfPath("volume-4")) {
+		t.Error("Expected the tgt export without host records to be migrated")
+	}
+	tpg := filepath.Join(LioConfigfsDir, "iscsi", iscsiTgtPrefix+"volume-1", lioTpg)
+	expectAttr(t, filepath.Join(tpg, "acls", "iqn.1993-08.org.debian:01:host1", "auth", "password"), "password1")
+	expectAttr(t, filepath.Join(tpg, "acls", "iqn.1993-08.org.debian:01:host2", "auth", "password"), "password2")
+	expectAttr(t, filepath.Join(tpg, "attrib", "generate_node_acls"), "0")
+	expectAttr(t, filepath.Join(tpg, "attrib", "authentication"), "1")
+
+	// The migrated volume is removed by lio even if the pool uses tgt.
+	target := NewTarget("192.168.0.10", confDir, config.ISCSIProtocol, TgtTargetType).(*iscsiTarget)
+	if _, ok := target.exportedBy("volume-1").(*lioTarget); !ok {
+		t.Error("Expected volume-1 to be exported by lio")
+	}
+	if _, ok := target.exportedBy("volume-2").(*tgtTarget); !ok {
+		t.Error("Expected volume-2 to be exported by tgt")
+	}
+}
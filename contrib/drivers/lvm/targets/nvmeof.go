@@ -29,13 +29,14 @@ import (
 const (
 	opensdsNvmeofPrefix = "opensds-Nvmeof"
 	NvmetDir            = "/sys/kernel/config/nvmet"
+	dhchapHash          = "hmac(sha256)"
 )
 
 type NvmeofTarget interface {
 	AddNvmeofSubsystem(volId, tgtNqn, path, initiator string) (string, error)
 	RemoveNvmeofSubsystem(volId, nqn string) error
 	GetNvmeofSubsystem(nqn string) (string, error)
-	CreateNvmeofTarget(volId, tgtIqn, path, initiator, transtype string) error
+	CreateNvmeofTarget(volId, tgtIqn, path, initiator, transtype string, auth *AuthOptions) error
 	GetNvmeofTarget(nqn, transtype string) (bool, error)
 	RemoveNvmeofTarget(volId, nqn, transtype string) error
 }
@@ -77,6 +78,37 @@ func (t *NvmeoftgtTarget) convertTranstype(transtype string) string {
 	return portid
 }
 
+// setHostAuth sets the DH-HMAC-CHAP secrets of the host, the secrets are
+// replaced every time the host attaches a volume.
+func (t *NvmeoftgtTarget) setHostAuth(initiator string, auth *AuthOptions) error {
+	if auth == nil || auth.DhchapHostKey == "" {
+		return nil
+	}
+	if initiator == "" || initiator == "ALL" {
+		return errors.New("nvme in-band authentication requires a specific host nqn")
+	}
+
+	hostDir := NvmetDir + "/hosts/" + initiator
+	if exist, _ := utils.PathExists(hostDir); !exist {
+		os.MkdirAll(hostDir, 0755)
+	}
+	if err := t.WriteWithIo(hostDir+"/dhchap_hash", dhchapHash); err != nil {
+		log.Errorf("Fail to set dhchap hash of host %s", initiator)
+		return err
+	}
+	if err := t.WriteWithIo(hostDir+"/dhchap_key", auth.DhchapHostKey); err != nil {
+		log.Errorf("Fail to set dhchap key of host %s", initiator)
+		return err
+	}
+	if auth.DhchapCtrlKey != "" {
+		if err := t.WriteWithIo(hostDir+"/dhchap_ctrl_key", auth.DhchapCtrlKey); err != nil {
+			log.Errorf("Fail to set dhchap controller key of host %s", initiator)
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *NvmeoftgtTarget) AddNvmeofSubsystem(volId, tgtNqn, path, initiator string) (string, error) {
 	if exist, _ := utils.PathExists(NvmetDir); !exist {
 		os.MkdirAll(NvmetDir, 0755)
@@ -165,7 +197,12 @@ func (t *NvmeoftgtTarget) GetNvmeofSubsystem(nqn string) (string, error) {
 
 }
 
-func (t *NvmeoftgtTarget) CreateNvmeofTarget(volId, tgtNqn, path, initiator, transtype string) error {
+func (t *NvmeoftgtTarget) CreateNvmeofTarget(volId, tgtNqn, path, initiator, transtype string, auth *AuthOptions) error {
+	// The secrets are bound to the host rather than the subsystem, so they
+	// are set even if the target exists already.
+	if err := t.setHostAuth(initiator, auth); err != nil {
+		return err
+	}
 
 	if tgtexisted, err := t.GetNvmeofTarget(tgtNqn, transtype); tgtexisted == true && err == nil {
 		log.Infof("Nvmeof target %s with transtype %s has existed", tgtNqn, transtype)
@@ -402,8 +439,10 @@ func (*NvmeoftgtTarget) WriteWithIo(name, content string) error {
 		log.Errorf("Failed to open the file %v", err)
 		return err
 	}
+	defer fileObj.Close()
+	// The content is not logged since it may be a secret.
 	if _, err := io.WriteString(fileObj, content); err == nil {
-		log.Infof("Successful appending to the file with os.OpenFile and io.WriteString.%s", content)
+		log.Infof("Successful appending to the file %s with os.OpenFile and io.WriteString.", name)
 		return nil
 	}
 	return err
@@ -14,28 +14,75 @@
 
 package targets
 
-import "github.com/sodafoundation/dock/contrib/drivers/utils/config"
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/sodafoundation/dock/contrib/drivers/utils/config"
+)
 
 const (
 	iscsiTgtPrefix  = "iqn.2017-10.io.opensds:"
 	nvmeofTgtPrefix = "nqn.2019-01.com.opensds:nvme:"
+
+	dhchapKeyLen = 32
 )
 
+// AuthOptions contains the secrets used to authenticate an attachment, the
+// authentication is disabled if the corresponding fields are empty.
+type AuthOptions struct {
+	// ChapUser and ChapPassword are used by the target to authenticate
+	// the iscsi initiator.
+	ChapUser     string
+	ChapPassword string
+	// MutualChapUser and MutualChapPassword are used by the iscsi initiator
+	// to authenticate the target.
+	MutualChapUser     string
+	MutualChapPassword string
+	// DhchapHostKey and DhchapCtrlKey are the DH-HMAC-CHAP secrets of nvme
+	// host and controller, the controller key is only for bidirectional
+	// authentication.
+	DhchapHostKey string
+	DhchapCtrlKey string
+}
+
+// GenerateDhchapKey generates a random DH-HMAC-CHAP secret in the format of
+// nvme-cli "gen-dhchap-key", which is not transformed by any hash.
+func GenerateDhchapKey() (string, error) {
+	key := make([]byte, dhchapKeyLen, dhchapKeyLen+4)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	crc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(key))
+	return fmt.Sprintf("DHHC-1:00:%s:", base64.StdEncoding.EncodeToString(append(key, crc...))), nil
+}
+
 // Target is an interface for exposing some operations of different targets,
 // currently support iscsiTarget.
 type Target interface {
-	CreateExport(volId, path, hostIp, initiator string, chapAuth []string) (map[string]interface{}, error)
+	CreateExport(volId, path, hostIp, initiator string, auth *AuthOptions) (map[string]interface{}, error)
 
 	RemoveExport(volId, hostIp string) error
 }
 
-// NewTarget method creates a new target based on its type.
-func NewTarget(bip string, tgtConfDir string, access string) Target {
+// NewTarget method creates a new target based on its type, iscsiType selects
+// the iscsi target implementation which is tgt by default.
+func NewTarget(bip string, tgtConfDir string, access string, iscsiType string) Target {
 	switch access {
 	case config.ISCSIProtocol:
-		return &iscsiTarget{
+		t := &iscsiTarget{
 			ISCSITarget: NewISCSITarget(bip, tgtConfDir),
+			BindIp:      bip,
+			TgtConfDir:  tgtConfDir,
 		}
+		if iscsiType == LioTargetType {
+			t.ISCSITarget = NewLioTarget(bip, tgtConfDir)
+		}
+		return t
 	case config.NVMEOFProtocol:
 		return &nvmeofTarget{
 			NvmeofTarget: NewNvmeofTarget(bip, tgtConfDir),
@@ -47,39 +94,61 @@ func NewTarget(bip string, tgtConfDir string, access string) Target {
 
 type iscsiTarget struct {
 	ISCSITarget
+	BindIp     string
+	TgtConfDir string
+}
+
+// exportedBy returns the iscsi target which has exported the volume, so that
+// the volumes exported before the target type is changed keep working until
+// they are migrated. The configured one is returned for a new export.
+func (t *iscsiTarget) exportedBy(volId string) ISCSITarget {
+	tgt := &tgtTarget{BindIp: t.BindIp, TgtConfDir: t.TgtConfDir}
+	if IsExist(tgt.getTgtConfPath(volId)) {
+		return tgt
+	}
+	lio := &lioTarget{BindIp: t.BindIp, TgtConfDir: t.TgtConfDir}
+	if IsExist(lio.getStatePath(volId)) {
+		return lio
+	}
+	return t.ISCSITarget
 }
 
-func (t *iscsiTarget) CreateExport(volId, path, hostIp, initiator string, chapAuth []string) (map[string]interface{}, error) {
+func (t *iscsiTarget) CreateExport(volId, path, hostIp, initiator string, auth *AuthOptions) (map[string]interface{}, error) {
 	tgtIqn := iscsiTgtPrefix + volId
-	if err := t.CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator, chapAuth); err != nil {
+	target := t.exportedBy(volId)
+	if err := target.CreateISCSITarget(volId, tgtIqn, path, hostIp, initiator, auth); err != nil {
 		return nil, err
 	}
-	lunId := t.GetLun(path)
+	lunId := target.GetLun(path)
 	conn := map[string]interface{}{
 		"targetDiscovered": true,
 		"targetIQN":        []string{tgtIqn},
-		"targetPortal":     []string{t.ISCSITarget.(*tgtTarget).BindIp + ":3260"},
+		"targetPortal":     []string{t.BindIp + ":3260"},
 		"discard":          false,
 		"targetLun":        lunId,
 	}
-	if len(chapAuth) == 2 {
+	if auth != nil && auth.ChapUser != "" {
 		conn["authMethod"] = "chap"
-		conn["authUserName"] = chapAuth[0]
-		conn["authPassword"] = chapAuth[1]
+		conn["authUserName"] = auth.ChapUser
+		conn["authPassword"] = auth.ChapPassword
+		if auth.MutualChapUser != "" {
+			conn["mutualAuthUserName"] = auth.MutualChapUser
+			conn["mutualAuthPassword"] = auth.MutualChapPassword
+		}
 	}
 	return conn, nil
 }
 
 func (t *iscsiTarget) RemoveExport(volId, hostIp string) error {
 	tgtIqn := iscsiTgtPrefix + volId
-	return t.RemoveISCSITarget(volId, tgtIqn, hostIp)
+	return t.exportedBy(volId).RemoveISCSITarget(volId, tgtIqn, hostIp)
 }
 
 type nvmeofTarget struct {
 	NvmeofTarget
 }
 
-func (t *nvmeofTarget) CreateExport(volId, path, hostIp, initiator string, chapAuth []string) (map[string]interface{}, error) {
+func (t *nvmeofTarget) CreateExport(volId, path, hostIp, initiator string, auth *AuthOptions) (map[string]interface{}, error) {
 	tgtNqn := nvmeofTgtPrefix + volId
 	// So far nvmeof transtport type is defaultly set as tcp because of its widely use, but it can also be rdma/fc.
 	// The difference of transport type leads to different performance of volume attachment latency and iops.
@@ -91,7 +160,7 @@ func (t *nvmeofTarget) CreateExport(volId, path, hostIp, initiator string, chapA
 	//to take the decision in the future.
 	var transtype string
 	transtype = "tcp"
-	if err := t.CreateNvmeofTarget(volId, tgtNqn, path, initiator, transtype); err != nil {
+	if err := t.CreateNvmeofTarget(volId, tgtNqn, path, initiator, transtype, auth); err != nil {
 		return nil, err
 	}
 	conn := map[string]interface{}{
@@ -103,6 +172,13 @@ func (t *nvmeofTarget) CreateExport(volId, path, hostIp, initiator string, chapA
 		"discard":          false,
 		"transporType":     transtype,
 	}
+	if auth != nil && auth.DhchapHostKey != "" {
+		conn["authMethod"] = "dhchap"
+		conn["dhchapHostKey"] = auth.DhchapHostKey
+		if auth.DhchapCtrlKey != "" {
+			conn["dhchapCtrlKey"] = auth.DhchapCtrlKey
+		}
+	}
 
 	return conn, nil
 }
@@ -0,0 +1,115 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package targets
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDhchapKey(t *testing.T) {
+	key, err := GenerateDhchapKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, "DHHC-1:00:") || !strings.HasSuffix(key, ":") {
+		t.Fatalf("Unexpected dhchap key format %s", key)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(key, "DHHC-1:00:"), ":"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != dhchapKeyLen+4 {
+		t.Fatalf("Expected %d bytes, got %d", dhchapKeyLen+4, len(data))
+	}
+	if crc := binary.LittleEndian.Uint32(data[dhchapKeyLen:]); crc != crc32.ChecksumIEEE(data[:dhchapKeyLen]) {
+		t.Error("Checksum of dhchap key does not match")
+	}
+
+	another, _ := GenerateDhchapKey()
+	if key == another {
+		t.Error("Expected different dhchap keys")
+	}
+}
+
+func TestTgtConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensds-tgt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "opensds-volume.conf")
+
+	config := make(configMap)
+	config.updateConfigmap("incominguser", "user1 password1")
+	config.updateConfigmap("incominguser", "user2 password2")
+	config["outgoinguser"] = []string{"mutual password3"}
+	config.updateConfigmap("initiator-address", "192.168.0.1")
+	config.updateConfigmap("backing-store", "/dev/vg001/volume-1")
+	if err := config.writeConfig(file, "iqn.2017-10.io.opensds:volume-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected config file mode 0600, got %v", fi.Mode().Perm())
+	}
+
+	data, _ := ioutil.ReadFile(file)
+	parsed := make(configMap)
+	parsed.parse(string(data))
+	if !reflect.DeepEqual(parsed, config) {
+		t.Errorf("Expected %v, got %v", config, parsed)
+	}
+}
+
+func TestTgtConfigRemoveHost(t *testing.T) {
+	config := make(configMap)
+	for _, h := range []tgtHost{
+		{Ip: "192.168.0.1", Initiator: "iqn.1993-08.org.debian:01:a", ChapUser: "user1", MutualChapUser: "mutual"},
+		{Ip: "192.168.0.2", Initiator: "iqn.1993-08.org.debian:01:b", ChapUser: "user2"},
+	} {
+		config.setHost(h)
+		config.updateConfigmap("initiator-address", h.Ip)
+	}
+	config.updateConfigmap("incominguser", "user1 password1")
+	config.updateConfigmap("incominguser", "user2 password2")
+	config["outgoinguser"] = []string{"mutual password3"}
+
+	config.removeHost("192.168.0.1")
+	expected := configMap{
+		"initiator-address": {"192.168.0.2"},
+		"incominguser":      {"user2 password2"},
+		hostKey:             {"192.168.0.2 iqn.1993-08.org.debian:01:b user2 -"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected %v, got %v", expected, config)
+	}
+
+	config.removeHost("192.168.0.2")
+	if len(config) != 0 {
+		t.Errorf("Expected empty config, got %v", config)
+	}
+}
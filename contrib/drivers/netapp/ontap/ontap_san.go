@@ -93,7 +93,7 @@ func (d *SANDriver) Setup() error {
 	// Read NetApp ONTAP config file
 	d.conf = &ONTAPConfig{}
 
-	p := config.CONF.OsdsDock.Backends.NetappOntapSan.ConfigPath
+	p := config.GetBackends().NetappOntapSan.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -416,6 +416,21 @@ func (d *SANDriver) DeleteSnapshot(opt *pb.DeleteVolumeSnapshotOpts) error {
 	return nil
 }
 
+// aggregateUsedCapacity returns the physical space used in the aggregate in
+// GB, including the snapshot reserve.
+func (d *SANDriver) aggregateUsedCapacity(aggr string) (int64, error) {
+	resp, err := d.sanStorageDriver.API.AggrSpaceGetIterRequest(aggr)
+	if err = api.GetError(resp, err); err != nil {
+		return 0, err
+	}
+	if resp.Result.AttributesListPtr != nil {
+		for _, space := range resp.Result.AttributesListPtr.SpaceInformationPtr {
+			return int64(float64(space.UsedIncludingSnapshotReserve()) / bytesGiB), nil
+		}
+	}
+	return 0, fmt.Errorf("space information of aggregate %s is not found", aggr)
+}
+
 func (d *SANDriver) ListPools() ([]*model.StoragePoolSpec, error) {
 
 	var pools []*model.StoragePoolSpec
@@ -433,7 +448,11 @@ func (d *SANDriver) ListPools() ([]*model.StoragePoolSpec, error) {
 		if _, ok := c.Pool[aggr]; !ok {
 			continue
 		}
-		aggregate, _ := d.sanStorageDriver.API.AggregateCommitment(aggr)
+		aggregate, err := d.sanStorageDriver.API.AggregateCommitment(aggr)
+		if err != nil {
+			log.Errorf("get commitment of aggregate %s failed: %v", aggr, err)
+			return nil, err
+		}
 		aggregateCapacity := aggregate.AggregateSize / bytesGiB
 		aggregateAllocatedCapacity := aggregate.TotalAllocated / bytesGiB
 
@@ -448,7 +467,14 @@ func (d *SANDriver) ListPools() ([]*model.StoragePoolSpec, error) {
 			StorageType:      c.Pool[aggr].StorageType,
 			Extras:           c.Pool[aggr].Extras,
 			AvailabilityZone: c.Pool[aggr].AvailabilityZone,
+			// The sizes committed to the volumes in the aggregate.
+			ProvisionedCapacity: int64(aggregateAllocatedCapacity),
 		}
+		if pool.AllocatedCapacity, err = d.aggregateUsedCapacity(aggr); err != nil {
+			log.Errorf("get used capacity of aggregate %s failed: %v", aggr, err)
+			return nil, err
+		}
+		c.Pool[aggr].SetCapacityLimits(pool)
 		if pool.AvailabilityZone == "" {
 			pool.AvailabilityZone = DefaultAZ
 		}
@@ -481,3 +507,11 @@ func (d *SANDriver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.Vol
 func (d *SANDriver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
+
+func (d *SANDriver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	return nil, &model.NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *SANDriver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &model.NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
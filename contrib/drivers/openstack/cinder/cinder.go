@@ -113,7 +113,7 @@ func (opts ListPoolOpts) ToStoragePoolsListQuery() (string, error) {
 func (d *Driver) Setup() error {
 	// Read cinder config file
 	d.conf = &CinderConfig{}
-	p := config.CONF.OsdsDock.Backends.Cinder.ConfigPath
+	p := config.GetBackends().Cinder.ConfigPath
 	if "" == p {
 		p = defaultConfPath
 	}
@@ -490,3 +490,11 @@ func (d *Driver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (*model.Volume
 func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
+
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	return nil, &model.NotImplementError{"method CreateGroupSnapshot has not been implemented yet"}
+}
+
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return &model.NotImplementError{"method DeleteGroupSnapshot has not been implemented yet"}
+}
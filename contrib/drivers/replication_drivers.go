@@ -22,6 +22,7 @@ plugin, just modify Init() and Clean() method.
 package drivers
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/sodafoundation/dock/contrib/drivers/drbd"
@@ -46,12 +47,43 @@ type ReplicationDriver interface {
 	DeleteReplication(opt *pb.DeleteReplicationOpts) error
 	EnableReplication(opt *pb.EnableReplicationOpts) error
 	DisableReplication(opt *pb.DisableReplicationOpts) error
+	// FailoverReplication makes the secondary volume writable when the primary
+	// site is unavailable, the data not replicated yet may be lost.
 	FailoverReplication(opt *pb.FailoverReplicationOpts) error
+	// SwitchoverReplication synchronizes the secondary volume and then swaps
+	// the roles of the volumes, so that no data is lost.
+	SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) error
+	// ReprotectReplication replicates the data of the failed over secondary
+	// volume back to the primary volume, which protects the data again.
+	ReprotectReplication(opt *pb.ReprotectReplicationOpts) error
+	// GetReplicationStatus reports the role, health, sync progress and RPO
+	// lag of the replication pair on the backend.
+	GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error)
+}
+
+// ReplicationPairLister is implemented by the replication drivers which can
+// report the health of replication pairs on the backend.
+type ReplicationPairLister interface {
+	ListReplicationPairs() ([]*model.ReplicationPairStatus, error)
+}
+
+// ListReplicationPairs lists the replication pairs if the driver supports it.
+func ListReplicationPairs(d ReplicationDriver) (pairs []*model.ReplicationPairStatus, err error) {
+	if t, ok := d.(*tracedReplicationDriver); ok {
+		defer startDriverSpan(t.name, "ListReplicationPairs")(&err)
+		d = t.ReplicationDriver
+	}
+	l, ok := d.(ReplicationPairLister)
+	if !ok {
+		return nil, fmt.Errorf("replication driver %T does not support listing replication pairs", d)
+	}
+	return l.ListReplicationPairs()
 }
 
 func IsSupportArrayBasedReplication(resourceType string) bool {
-	v := reflect.ValueOf(config.CONF.Backends)
-	t := reflect.TypeOf(config.CONF.Backends)
+	backends := config.GetBackends()
+	v := reflect.ValueOf(backends)
+	t := reflect.TypeOf(backends)
 	for i := 0; i < t.NumField(); i++ {
 		field := v.Field(i)
 		tag := t.Field(i).Tag.Get("conf")
@@ -78,6 +110,7 @@ func InitReplicationDriver(resourceType string) (ReplicationDriver, error) {
 		d = &replication_sample.ReplicationDriver{}
 		break
 	}
+	d = &tracedReplicationDriver{ReplicationDriver: d, name: resourceType}
 	err := d.Setup()
 	return d, err
 }
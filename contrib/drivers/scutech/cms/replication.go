@@ -15,7 +15,10 @@
 package scms
 
 import (
+	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/golang/glog"
 	"github.com/sodafoundation/dock/pkg/model"
@@ -47,22 +50,11 @@ func (r *ReplicationDriver) CreateReplication(opt *pb.CreateReplicationOpts) (*m
 		return replica, nil
 	}
 
-	bandwidth := opt.GetReplicationBandwidth()
-	primaryData := opt.GetPrimaryReplicationDriverData()
-	secondaryData := opt.GetSecondaryReplicationDriverData()
-	primaryVolId := opt.GetPrimaryVolumeId()
-	secondaryVolId := opt.GetSecondaryVolumeId()
-	path, _ := filepath.EvalSymlinks(primaryData["Mountpoint"])
-	primaryBackingDevice, _ := filepath.Abs(path)
-
-	path, _ = secondaryData["Mountpoint"]
-	secondaryBackingDevice, _ := filepath.Abs(path)
-	glog.Infof("%s:%s\n", primaryBackingDevice, secondaryBackingDevice)
-	sourceVol := CmsVolume{VolumeId: primaryVolId, VolumeName: primaryBackingDevice}
-	targetVol := CmsVolume{VolumeId: secondaryVolId, VolumeName: secondaryBackingDevice}
+	primaryVol, secondaryVol := taskVolumes(opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId(),
+		opt.GetPrimaryReplicationDriverData(), opt.GetSecondaryReplicationDriverData())
 
-	task := NewCmsTask(bandwidth, false)
-	if err := task.AddVolume(sourceVol, targetVol); err != nil {
+	task := NewCmsTask(opt.GetReplicationBandwidth(), false)
+	if err := task.AddVolume(primaryVol, secondaryVol); err != nil {
 		return nil, err
 	}
 
@@ -78,6 +70,44 @@ func (r *ReplicationDriver) CreateReplication(opt *pb.CreateReplicationOpts) (*m
 	return replica, nil
 }
 
+// taskVolumes gets the CMS volumes of the primary and secondary volumes.
+func taskVolumes(primaryVolId, secondaryVolId string, primaryData, secondaryData map[string]string) (CmsVolume, CmsVolume) {
+	path, _ := filepath.EvalSymlinks(primaryData["Mountpoint"])
+	primaryBackingDevice, _ := filepath.Abs(path)
+
+	path, _ = secondaryData["Mountpoint"]
+	secondaryBackingDevice, _ := filepath.Abs(path)
+	glog.Infof("%s:%s\n", primaryBackingDevice, secondaryBackingDevice)
+	return CmsVolume{VolumeId: primaryVolId, VolumeName: primaryBackingDevice},
+		CmsVolume{VolumeId: secondaryVolId, VolumeName: secondaryBackingDevice}
+}
+
+// reverseTask replaces the migration task with the one which migrates the
+// data of the secondary volume to the primary volume. The roles are swapped
+// by the caller afterwards, so reversing the task again migrates the data of
+// the new secondary volume, which is the original primary one.
+func reverseTask(bandwidth int64, primaryVolId, secondaryVolId string, primaryData, secondaryData map[string]string) error {
+	primaryVol, secondaryVol := taskVolumes(primaryVolId, secondaryVolId, primaryData, secondaryData)
+
+	task := NewCmsTask(bandwidth, false)
+	if err := task.AddVolume(secondaryVol, primaryVol); err != nil {
+		return err
+	}
+
+	cmsadm := NewCmsAdm()
+	if _, err := cmsadm.Down(); err != nil {
+		return err
+	}
+	if _, err := cmsadm.DeleteTask(); err != nil {
+		return err
+	}
+	if _, err := cmsadm.CreateTask(task); err != nil {
+		return err
+	}
+	_, err := cmsadm.Up()
+	return err
+}
+
 // Delete replication
 func (r *ReplicationDriver) DeleteReplication(opt *pb.DeleteReplicationOpts) error {
 	glog.Infoln("CMS delete migration task ...")
@@ -127,3 +157,95 @@ func (r *ReplicationDriver) FailoverReplication(opt *pb.FailoverReplicationOpts)
 	// Nothing to do here. Failover is executed automatically by CMS plugin.
 	return nil
 }
+
+// Switchover Replication
+func (r *ReplicationDriver) SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) error {
+	glog.Infoln("CMS switchover ....")
+
+	isPrimary := opt.GetIsPrimary()
+	if !isPrimary {
+		return nil
+	}
+
+	out, err := queryCms()
+	if err != nil {
+		return err
+	}
+	// The task is stopped before it is reversed, so all the data must be
+	// migrated already, otherwise it is lost.
+	if st := taskStatus(ParseQuery(out)); st.RunningStatus != model.ReplicationRunningSynced {
+		return fmt.Errorf("can not switch over the migration task which is %s: %s", st.RunningStatus, st.Reason)
+	}
+	return reverseTask(opt.GetReplicationBandwidth(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId(),
+		opt.GetPrimaryReplicationDriverData(), opt.GetSecondaryReplicationDriverData())
+}
+
+// Reprotect Replication
+func (r *ReplicationDriver) ReprotectReplication(opt *pb.ReprotectReplicationOpts) error {
+	glog.Infoln("CMS reprotect ....")
+
+	isPrimary := opt.GetIsPrimary()
+	if !isPrimary {
+		return nil
+	}
+
+	return reverseTask(opt.GetReplicationBandwidth(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId(),
+		opt.GetPrimaryReplicationDriverData(), opt.GetSecondaryReplicationDriverData())
+}
+
+// queryCms runs the query of CMS, it is a variable so that it can be faked in
+// unit tests.
+var queryCms = func() ([]byte, error) {
+	return NewCmsAdm().Query()
+}
+
+// Get replication status
+func (r *ReplicationDriver) GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	glog.Infoln("CMS query migration task ...")
+
+	out, err := queryCms()
+	if err != nil {
+		return nil, err
+	}
+	st := taskStatus(ParseQuery(out))
+	st.Id = opt.GetId()
+	st.Role = "secondary"
+	if opt.GetIsPrimary() {
+		st.Role = "primary"
+	}
+	return st, nil
+}
+
+// taskStatus converts the queried task of CMS to the state of replication.
+// CMS replicates the changes continuously, so a running task has no lag
+// unless it reports one.
+func taskStatus(task map[string]string) *model.ReplicationPairStatus {
+	st := &model.ReplicationPairStatus{RpoLag: -1}
+	state := strings.ToLower(task["status"])
+	switch state {
+	case "running", "up", "normal", "synced":
+		st.RunningStatus = model.ReplicationRunningSynced
+		st.SyncProgress, st.RpoLag = 100, 0
+	case "syncing", "initializing", "init":
+		st.RunningStatus = model.ReplicationRunningSyncing
+	case "stopped", "down":
+		st.RunningStatus = model.ReplicationRunningSplit
+	case "error", "failed", "disconnected":
+		st.RunningStatus = model.ReplicationRunningInterrupted
+	default:
+		st.RunningStatus = model.ReplicationRunningUnknown
+	}
+
+	if p, err := strconv.ParseFloat(strings.TrimSuffix(task["progress"], "%"), 64); err == nil {
+		st.SyncProgress = p
+	}
+	if lag, err := strconv.ParseInt(strings.TrimSuffix(task["lag"], "s"), 10, 64); err == nil {
+		st.RpoLag = lag
+	}
+	st.Healthy = st.RunningStatus == model.ReplicationRunningSynced ||
+		st.RunningStatus == model.ReplicationRunningSyncing
+	if !st.Healthy {
+		st.Reason = fmt.Sprintf("task status is %q", task["status"])
+	}
+	return st
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package scms
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+func TestGetReplicationStatus(t *testing.T) {
+	defer func(f func() ([]byte, error)) { queryCms = f }(queryCms)
+	r := &ReplicationDriver{}
+
+	queryCms = func() ([]byte, error) {
+		return []byte("CMS task\nStatus: Syncing\nProgress: 37.5%\nLag: 12s\n"), nil
+	}
+	st, err := r.GetReplicationStatus(&pb.GetReplicationStatusOpts{Id: "r1", IsPrimary: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &model.ReplicationPairStatus{
+		Id: "r1", Role: "primary", Healthy: true,
+		RunningStatus: model.ReplicationRunningSyncing, SyncProgress: 37.5, RpoLag: 12,
+	}
+	if !reflect.DeepEqual(st, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, st)
+	}
+
+	queryCms = func() ([]byte, error) {
+		return []byte("Status: Stopped\n"), nil
+	}
+	if st, err = r.GetReplicationStatus(&pb.GetReplicationStatusOpts{Id: "r1"}); err != nil {
+		t.Fatal(err)
+	}
+	if st.Healthy || st.RunningStatus != model.ReplicationRunningSplit || st.Role != "secondary" || st.RpoLag != -1 {
+		t.Errorf("Unexpected status of stopped task %+v", st)
+	}
+}
+
+func TestSwitchoverReplication(t *testing.T) {
+	defer func(f func(string, []string) ([]byte, error)) { cmdExec = f }(cmdExec)
+	var cmds []string
+	status := "Status: Running\n"
+	cmdExec = func(cmd string, argv []string) ([]byte, error) {
+		cmds = append(cmds, strings.Join(argv, " "))
+		if argv[0] == CMS_QUERY {
+			return []byte(status), nil
+		}
+		return nil, nil
+	}
+
+	f, err := ioutil.TempFile("", "cms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	primary, _ := filepath.EvalSymlinks(f.Name())
+
+	r := &ReplicationDriver{}
+	opt := &pb.SwitchoverReplicationOpts{
+		Id: "r1", PrimaryVolumeId: "v1", SecondaryVolumeId: "v2", IsPrimary: true, ReplicationBandwidth: 10,
+		PrimaryReplicationDriverData:   map[string]string{"Mountpoint": f.Name()},
+		SecondaryReplicationDriverData: map[string]string{"Mountpoint": "/dev/sdb"},
+	}
+	if err := r.SwitchoverReplication(opt); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{CMS_QUERY, CMS_STOP, CMS_DELETE, CMS_CREATE + " -b 10 -D /dev/sdb," + primary, CMS_START}
+	if !reflect.DeepEqual(cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, cmds)
+	}
+
+	cmds, status = nil, "Status: Syncing\n"
+	if err := r.SwitchoverReplication(opt); err == nil {
+		t.Error("Expected switching over the syncing task to fail")
+	}
+	if expected := []string{CMS_QUERY}; !reflect.DeepEqual(cmds, expected) {
+		t.Errorf("Expected %v, got %v", expected, cmds)
+	}
+}
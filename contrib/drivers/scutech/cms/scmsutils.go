@@ -18,6 +18,7 @@ import (
 	"errors"
 	"os/exec"
 	"strconv"
+	"strings"
 )
 
 const CMS_ADM = "/opt/cmsagent/cmsadm"
@@ -81,7 +82,27 @@ func (c *CmsAdm) Query() ([]byte, error) {
 	return cmdExec(CMS_ADM, argv)
 }
 
-func cmdExec(cmd string, argv []string) ([]byte, error) {
+// ParseQuery parses the output of query, which prints the task as lines of
+// "key: value". The keys are lower cased, and the lines without ":" are
+// skipped.
+func ParseQuery(out []byte) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if key != "" {
+			fields[key] = strings.TrimSpace(kv[1])
+		}
+	}
+	return fields
+}
+
+// cmdExec runs the command of CMS, it is a variable so that it can be faked
+// in unit tests.
+var cmdExec = func(cmd string, argv []string) ([]byte, error) {
 	out, err := exec.Command(cmd, argv[0:]...).Output()
 	if err != nil {
 		err = errors.New(string(out))
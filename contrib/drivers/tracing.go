@@ -0,0 +1,201 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drivers
+
+import (
+	"time"
+
+	"github.com/sodafoundation/dock/pkg/dock/metrics"
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
+)
+
+// startDriverSpan starts the span of a driver method, the returned function
+// ends it and records the duration of the method in the metrics.
+func startDriverSpan(driver, method string) func(err *error) {
+	start := time.Now()
+	end := trace.StartBoundSpan("driver "+method, "driver.name", driver, "driver.method", method)
+	return func(err *error) {
+		end(err)
+		metrics.ObserveDriverOperation(driver, method, start)
+	}
+}
+
+// tracedDriver starts a span around each method of the volume driver and
+// observes its duration, the span is a no-op when tracing is disabled.
+type tracedDriver struct {
+	VolumeDriver
+	name string
+}
+
+func (d *tracedDriver) Setup() (err error) {
+	defer startDriverSpan(d.name, "Setup")(&err)
+	return d.VolumeDriver.Setup()
+}
+
+func (d *tracedDriver) CreateVolume(opt *pb.CreateVolumeOpts) (vol *model.VolumeSpec, err error) {
+	defer startDriverSpan(d.name, "CreateVolume")(&err)
+	return d.VolumeDriver.CreateVolume(opt)
+}
+
+func (d *tracedDriver) PullVolume(volIdentifier string) (vol *model.VolumeSpec, err error) {
+	defer startDriverSpan(d.name, "PullVolume")(&err)
+	return d.VolumeDriver.PullVolume(volIdentifier)
+}
+
+func (d *tracedDriver) DeleteVolume(opt *pb.DeleteVolumeOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteVolume")(&err)
+	return d.VolumeDriver.DeleteVolume(opt)
+}
+
+func (d *tracedDriver) ExtendVolume(opt *pb.ExtendVolumeOpts) (vol *model.VolumeSpec, err error) {
+	defer startDriverSpan(d.name, "ExtendVolume")(&err)
+	return d.VolumeDriver.ExtendVolume(opt)
+}
+
+func (d *tracedDriver) InitializeConnection(opt *pb.CreateVolumeAttachmentOpts) (info *model.ConnectionInfo, err error) {
+	defer startDriverSpan(d.name, "InitializeConnection")(&err)
+	return d.VolumeDriver.InitializeConnection(opt)
+}
+
+func (d *tracedDriver) TerminateConnection(opt *pb.DeleteVolumeAttachmentOpts) (err error) {
+	defer startDriverSpan(d.name, "TerminateConnection")(&err)
+	return d.VolumeDriver.TerminateConnection(opt)
+}
+
+func (d *tracedDriver) CreateSnapshot(opt *pb.CreateVolumeSnapshotOpts) (snp *model.VolumeSnapshotSpec, err error) {
+	defer startDriverSpan(d.name, "CreateSnapshot")(&err)
+	return d.VolumeDriver.CreateSnapshot(opt)
+}
+
+func (d *tracedDriver) PullSnapshot(snapIdentifier string) (snp *model.VolumeSnapshotSpec, err error) {
+	defer startDriverSpan(d.name, "PullSnapshot")(&err)
+	return d.VolumeDriver.PullSnapshot(snapIdentifier)
+}
+
+func (d *tracedDriver) DeleteSnapshot(opt *pb.DeleteVolumeSnapshotOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteSnapshot")(&err)
+	return d.VolumeDriver.DeleteSnapshot(opt)
+}
+
+func (d *tracedDriver) InitializeSnapshotConnection(opt *pb.CreateSnapshotAttachmentOpts) (info *model.ConnectionInfo, err error) {
+	defer startDriverSpan(d.name, "InitializeSnapshotConnection")(&err)
+	return d.VolumeDriver.InitializeSnapshotConnection(opt)
+}
+
+func (d *tracedDriver) TerminateSnapshotConnection(opt *pb.DeleteSnapshotAttachmentOpts) (err error) {
+	defer startDriverSpan(d.name, "TerminateSnapshotConnection")(&err)
+	return d.VolumeDriver.TerminateSnapshotConnection(opt)
+}
+
+func (d *tracedDriver) CreateVolumeGroup(opt *pb.CreateVolumeGroupOpts) (vg *model.VolumeGroupSpec, err error) {
+	defer startDriverSpan(d.name, "CreateVolumeGroup")(&err)
+	return d.VolumeDriver.CreateVolumeGroup(opt)
+}
+
+func (d *tracedDriver) UpdateVolumeGroup(opt *pb.UpdateVolumeGroupOpts) (vg *model.VolumeGroupSpec, err error) {
+	defer startDriverSpan(d.name, "UpdateVolumeGroup")(&err)
+	return d.VolumeDriver.UpdateVolumeGroup(opt)
+}
+
+func (d *tracedDriver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteVolumeGroup")(&err)
+	return d.VolumeDriver.DeleteVolumeGroup(opt)
+}
+
+func (d *tracedDriver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (gs *model.VolumeGroupSnapshotSpec, err error) {
+	defer startDriverSpan(d.name, "CreateGroupSnapshot")(&err)
+	return d.VolumeDriver.CreateGroupSnapshot(opt)
+}
+
+func (d *tracedDriver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteGroupSnapshot")(&err)
+	return d.VolumeDriver.DeleteGroupSnapshot(opt)
+}
+
+func (d *tracedDriver) ListPools() (pols []*model.StoragePoolSpec, err error) {
+	defer startDriverSpan(d.name, "ListPools")(&err)
+	return d.VolumeDriver.ListPools()
+}
+
+// tracedReplicationDriver starts a span around each method of the replication
+// driver and observes its duration.
+type tracedReplicationDriver struct {
+	ReplicationDriver
+	name string
+}
+
+func (d *tracedReplicationDriver) Setup() (err error) {
+	defer startDriverSpan(d.name, "Setup")(&err)
+	return d.ReplicationDriver.Setup()
+}
+
+func (d *tracedReplicationDriver) CreateReplication(opt *pb.CreateReplicationOpts) (r *model.ReplicationSpec, err error) {
+	defer startDriverSpan(d.name, "CreateReplication")(&err)
+	return d.ReplicationDriver.CreateReplication(opt)
+}
+
+func (d *tracedReplicationDriver) DeleteReplication(opt *pb.DeleteReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "DeleteReplication")(&err)
+	return d.ReplicationDriver.DeleteReplication(opt)
+}
+
+func (d *tracedReplicationDriver) EnableReplication(opt *pb.EnableReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "EnableReplication")(&err)
+	return d.ReplicationDriver.EnableReplication(opt)
+}
+
+func (d *tracedReplicationDriver) DisableReplication(opt *pb.DisableReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "DisableReplication")(&err)
+	return d.ReplicationDriver.DisableReplication(opt)
+}
+
+func (d *tracedReplicationDriver) FailoverReplication(opt *pb.FailoverReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "FailoverReplication")(&err)
+	return d.ReplicationDriver.FailoverReplication(opt)
+}
+
+func (d *tracedReplicationDriver) SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "SwitchoverReplication")(&err)
+	return d.ReplicationDriver.SwitchoverReplication(opt)
+}
+
+func (d *tracedReplicationDriver) ReprotectReplication(opt *pb.ReprotectReplicationOpts) (err error) {
+	defer startDriverSpan(d.name, "ReprotectReplication")(&err)
+	return d.ReplicationDriver.ReprotectReplication(opt)
+}
+
+func (d *tracedReplicationDriver) GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (s *model.ReplicationPairStatus, err error) {
+	defer startDriverSpan(d.name, "GetReplicationStatus")(&err)
+	return d.ReplicationDriver.GetReplicationStatus(opt)
+}
+
+// tracedMetricDriver starts a span around the collection of metric driver and
+// observes its duration.
+type tracedMetricDriver struct {
+	MetricDriver
+	name string
+}
+
+func (d *tracedMetricDriver) Setup() (err error) {
+	defer startDriverSpan(d.name, "Setup")(&err)
+	return d.MetricDriver.Setup()
+}
+
+func (d *tracedMetricDriver) CollectMetrics() (metrics []*model.MetricSpec, err error) {
+	defer startDriverSpan(d.name, "CollectMetrics")(&err)
+	return d.MetricDriver.CollectMetrics()
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+// These constants below represent the access types of fileshare acl.
+const (
+	AccessTypeIp       = "ip"
+	AccessTypeCidr     = "cidr"
+	AccessTypeNetgroup = "netgroup"
+	AccessTypeHostname = "hostname"
+	AccessTypeUser     = "user"
+)
+
+// These constants below represent the access levels of fileshare acl.
+const (
+	AccessLevelRo = "ro"
+	AccessLevelRw = "rw"
+)
+
+// These constants below represent the options of nfs export.
+const (
+	RootSquash   = "root_squash"
+	NoRootSquash = "no_root_squash"
+	AllSquash    = "all_squash"
+
+	SecSys   = "sys"
+	SecKrb5  = "krb5"
+	SecKrb5i = "krb5i"
+	SecKrb5p = "krb5p"
+
+	SyncModeSync  = "sync"
+	SyncModeAsync = "async"
+
+	maxAnonId = 1<<32 - 2
+)
+
+var (
+	hostnameRegexp = regexp.MustCompile(`^(\*\.)?[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*$`)
+	netgroupRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// NfsExportRule is the validated form of a fileshare acl for nfs protocol,
+// drivers should map it onto the export rule of their backends.
+type NfsExportRule struct {
+	AccessType      string
+	AccessTo        string
+	AccessLevel     string
+	Squash          string
+	AnonUid         int64
+	AnonGid         int64
+	SecurityFlavors []string
+	SyncMode        string
+}
+
+// ParseAccessLevel converts access capabilities into access level. Execute
+// is accepted but has no effect, because an export can not grant it.
+func ParseAccessLevel(accessCapability []string) (string, error) {
+	if len(accessCapability) == 0 {
+		return "", errors.New("access capability cannot be empty")
+	}
+	level := AccessLevelRo
+	for _, c := range accessCapability {
+		switch strings.ToLower(c) {
+		case "read", "execute":
+		case "write":
+			level = AccessLevelRw
+		default:
+			return "", fmt.Errorf("invalid access capability %s, only read, write and execute are supported", c)
+		}
+	}
+	return level, nil
+}
+
+// ParseNfsClient validates the access type and access client of nfs acl,
+// it returns the normalized access type and client.
+func ParseNfsClient(accessType, accessTo string) (string, string, error) {
+	accessType = strings.ToLower(accessType)
+	if accessTo == "" {
+		return "", "", errors.New("access client cannot be empty")
+	}
+	switch accessType {
+	case AccessTypeIp:
+		if net.ParseIP(accessTo) == nil {
+			return "", "", fmt.Errorf("%s is not a valid ip address", accessTo)
+		}
+	case AccessTypeCidr:
+		_, ipNet, err := net.ParseCIDR(accessTo)
+		if err != nil {
+			return "", "", fmt.Errorf("%s is not a valid cidr", accessTo)
+		}
+		accessTo = ipNet.String()
+	case AccessTypeNetgroup:
+		accessTo = strings.TrimPrefix(accessTo, "@")
+		if !netgroupRegexp.MatchString(accessTo) {
+			return "", "", fmt.Errorf("%s is not a valid netgroup", accessTo)
+		}
+	case AccessTypeHostname:
+		if len(accessTo) > 253 || !hostnameRegexp.MatchString(accessTo) {
+			return "", "", fmt.Errorf("%s is not a valid hostname", accessTo)
+		}
+	default:
+		return "", "", fmt.Errorf("access type %s is not supported by nfs, support is %s, %s, %s and %s",
+			accessType, AccessTypeIp, AccessTypeCidr, AccessTypeNetgroup, AccessTypeHostname)
+	}
+	return accessType, accessTo, nil
+}
+
+// NfsClientMatch validates the access client of nfs acl and returns it in
+// exports(5) format, which is what the backends use to identify an export.
+func NfsClientMatch(accessType, accessTo string) (string, error) {
+	accessType, accessTo, err := ParseNfsClient(accessType, accessTo)
+	if err != nil {
+		return "", err
+	}
+	rule := &NfsExportRule{AccessType: accessType, AccessTo: accessTo}
+	return rule.ClientMatch(), nil
+}
+
+// ParseNfsExportRule validates all nfs export options of the acl request and
+// fills in the defaults, which are the same as exportfs ones.
+func ParseNfsExportRule(opt *pb.CreateFileShareAclOpts) (*NfsExportRule, error) {
+	accessType, accessTo, err := ParseNfsClient(opt.GetType(), opt.GetAccessTo())
+	if err != nil {
+		return nil, err
+	}
+	level, err := ParseAccessLevel(opt.GetAccessCapability())
+	if err != nil {
+		return nil, err
+	}
+	rule := &NfsExportRule{
+		AccessType:  accessType,
+		AccessTo:    accessTo,
+		AccessLevel: level,
+		Squash:      strings.ToLower(opt.GetSquash()),
+		AnonUid:     opt.GetAnonUid(),
+		AnonGid:     opt.GetAnonGid(),
+		SyncMode:    strings.ToLower(opt.GetSyncMode()),
+	}
+
+	switch rule.Squash {
+	case "":
+		rule.Squash = RootSquash
+	case RootSquash, NoRootSquash, AllSquash:
+	default:
+		return nil, fmt.Errorf("invalid squash mode %s, support is %s, %s and %s",
+			rule.Squash, RootSquash, NoRootSquash, AllSquash)
+	}
+	if rule.AnonUid < 0 || rule.AnonUid > maxAnonId || rule.AnonGid < 0 || rule.AnonGid > maxAnonId {
+		return nil, errors.New("anonymous uid and gid must be between 0 and 4294967294")
+	}
+
+	for _, sec := range opt.GetSecurityFlavors() {
+		sec = strings.ToLower(sec)
+		switch sec {
+		case SecSys, SecKrb5, SecKrb5i, SecKrb5p:
+		default:
+			return nil, fmt.Errorf("invalid security flavor %s, support is %s, %s, %s and %s",
+				sec, SecSys, SecKrb5, SecKrb5i, SecKrb5p)
+		}
+		if !containsString(rule.SecurityFlavors, sec) {
+			rule.SecurityFlavors = append(rule.SecurityFlavors, sec)
+		}
+	}
+	if len(rule.SecurityFlavors) == 0 {
+		rule.SecurityFlavors = []string{SecSys}
+	}
+
+	switch rule.SyncMode {
+	case "":
+		rule.SyncMode = SyncModeSync
+	case SyncModeSync, SyncModeAsync:
+	default:
+		return nil, fmt.Errorf("invalid sync mode %s, support is %s and %s",
+			rule.SyncMode, SyncModeSync, SyncModeAsync)
+	}
+	return rule, nil
+}
+
+// IsDefault returns true if the rule only uses the default nfs export options,
+// which can be expressed by every backend.
+func (r *NfsExportRule) IsDefault() bool {
+	return r.Squash == RootSquash && r.AnonUid == 0 && r.AnonGid == 0 &&
+		len(r.SecurityFlavors) == 1 && r.SecurityFlavors[0] == SecSys &&
+		r.SyncMode == SyncModeSync
+}
+
+// ClientMatch returns the client specification in exports(5) format.
+func (r *NfsExportRule) ClientMatch() string {
+	if r.AccessType == AccessTypeNetgroup {
+		return "@" + r.AccessTo
+	}
+	return r.AccessTo
+}
+
+// ExportOptions returns the export options in exports(5) format, such as
+// "rw,sync,sec=krb5:krb5p,root_squash,anonuid=1000".
+func (r *NfsExportRule) ExportOptions() string {
+	opts := []string{
+		r.AccessLevel,
+		r.SyncMode,
+		"sec=" + strings.Join(r.SecurityFlavors, ":"),
+		r.Squash,
+	}
+	if r.AnonUid != 0 {
+		opts = append(opts, "anonuid="+strconv.FormatInt(r.AnonUid, 10))
+	}
+	if r.AnonGid != 0 {
+		opts = append(opts, "anongid="+strconv.FormatInt(r.AnonGid, 10))
+	}
+	return strings.Join(opts, ",")
+}
+
+func containsString(a []string, x string) bool {
+	for _, n := range a {
+		if n == x {
+			return true
+		}
+	}
+	return false
+}
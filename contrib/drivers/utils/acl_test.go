@@ -0,0 +1,107 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+)
+
+func TestNfsClientMatch(t *testing.T) {
+	testCases := []struct {
+		accessType string
+		accessTo   string
+		expected   string
+		isErr      bool
+	}{
+		{"ip", "192.168.0.1", "192.168.0.1", false},
+		{"IP", "fe80::1", "fe80::1", false},
+		{"ip", "192.168.0.1/24", "", true},
+		{"cidr", "192.168.0.1/24", "192.168.0.0/24", false},
+		{"cidr", "192.168.0.1", "", true},
+		{"netgroup", "@trusted", "@trusted", false},
+		{"netgroup", "trusted hosts", "", true},
+		{"hostname", "node-1.example.com", "node-1.example.com", false},
+		{"hostname", "*.example.com", "*.example.com", false},
+		{"hostname", "-node.example.com", "", true},
+		{"user", "alice", "", true},
+		{"ip", "", "", true},
+	}
+
+	for _, c := range testCases {
+		actual, err := NfsClientMatch(c.accessType, c.accessTo)
+		if (err != nil) != c.isErr {
+			t.Errorf("Expected error %v for %s %s, got %v", c.isErr, c.accessType, c.accessTo, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("Expected %s, got %s", c.expected, actual)
+		}
+	}
+}
+
+func TestParseNfsExportRule(t *testing.T) {
+	rule, err := ParseNfsExportRule(&pb.CreateFileShareAclOpts{
+		Type:             "ip",
+		AccessTo:         "192.168.0.1",
+		AccessCapability: []string{"Read"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rule.IsDefault() {
+		t.Errorf("Expected default export rule, got %+v", rule)
+	}
+
+	rule, err = ParseNfsExportRule(&pb.CreateFileShareAclOpts{
+		Type:             "ip",
+		AccessTo:         "192.168.0.1",
+		AccessCapability: []string{"Read", "Write"},
+		Squash:           "ALL_SQUASH",
+		AnonUid:          65534,
+		SecurityFlavors:  []string{"krb5i", "KRB5I", "sys"},
+		SyncMode:         "Async",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected = &NfsExportRule{
+		AccessType:      AccessTypeIp,
+		AccessTo:        "192.168.0.1",
+		AccessLevel:     AccessLevelRw,
+		Squash:          AllSquash,
+		AnonUid:         65534,
+		SecurityFlavors: []string{SecKrb5i, SecSys},
+		SyncMode:        SyncModeAsync,
+	}
+	if !reflect.DeepEqual(rule, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rule)
+	}
+	if opts := rule.ExportOptions(); opts != "rw,async,sec=krb5i:sys,all_squash,anonuid=65534" {
+		t.Errorf("Unexpected export options %s", opts)
+	}
+
+	for _, opt := range []*pb.CreateFileShareAclOpts{
+		{Type: "ip", AccessTo: "192.168.0.1"},
+		{Type: "ip", AccessTo: "192.168.0.1", AccessCapability: []string{"Read"}, AnonGid: 1 << 32},
+		{Type: "ip", AccessTo: "192.168.0.1", AccessCapability: []string{"Read"}, SyncMode: "lazy"},
+	} {
+		if _, err := ParseNfsExportRule(opt); err == nil {
+			t.Errorf("Expected an error for %+v", opt)
+		}
+	}
+}
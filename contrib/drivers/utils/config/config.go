@@ -42,6 +42,28 @@ type PoolProperties struct {
 
 	// The volumes belong to the pool can be attached more than once.
 	MultiAttach bool `yaml:"multiAttach,omitempty"`
+
+	// The ratio of provisioned capacity to usable capacity allowed in the
+	// pool, the pool is not over-subscribed if it is not greater than 1.
+	MaxOverSubscriptionRatio float64 `yaml:"maxOverSubscriptionRatio,omitempty"`
+
+	// The percentage of total capacity which is never used by new volumes.
+	ReservedPercentage int64 `yaml:"reservedPercentage,omitempty"`
+}
+
+// SetCapacityLimits sets the over-subscription ratio and reserved percentage
+// of the pool from its properties.
+func (p PoolProperties) SetCapacityLimits(pol *model.StoragePoolSpec) {
+	pol.MaxOverSubscriptionRatio = p.MaxOverSubscriptionRatio
+	if pol.MaxOverSubscriptionRatio < 1 {
+		pol.MaxOverSubscriptionRatio = 1
+	}
+	pol.ReservedPercentage = p.ReservedPercentage
+	if pol.ReservedPercentage < 0 {
+		pol.ReservedPercentage = 0
+	} else if pol.ReservedPercentage > 100 {
+		pol.ReservedPercentage = 100
+	}
 }
 
 func Parse(conf interface{}, p string) (interface{}, error) {
@@ -52,6 +52,8 @@ const (
 	FCProtocol     = "fibre_channel"
 	NVMEOFProtocol = "nvmeof"
 	NFSProtocol    = "nfs"
+	CIFSProtocol   = "cifs"
+	LocalProtocol  = "local"
 )
 
 // Telemetry metric resource type
@@ -0,0 +1,224 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sodafoundation/dock/contrib/drivers"
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+const (
+	namespace = "opensds"
+
+	backendLabel      = "backend"
+	instanceIdLabel   = "instance_id"
+	instanceNameLabel = "instance_name"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// sanitizeName converts a string to a valid prometheus metric or label name.
+func sanitizeName(s string) string {
+	s = strings.ToLower(invalidNameChars.ReplaceAllString(s, "_"))
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	return s
+}
+
+// metricName forms the metric name as Job_Component_Name_Unit_AggrType
+// described by model.MetricSpec, the empty parts are omitted.
+func metricName(m *model.MetricSpec) string {
+	parts := []string{namespace}
+	for _, p := range []string{m.Job, m.Component, m.Name, m.Unit, m.AggrType} {
+		if p = strings.Trim(sanitizeName(p), "_"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// collectFunc collects the metrics of a backend, it is a variable so that it
+// can be faked in unit tests.
+var collectFunc = func(backend string) ([]*model.MetricSpec, error) {
+	d := drivers.InitMetricDriver(backend)
+	if d == nil {
+		return nil, fmt.Errorf("metric driver of backend %s is not supported", backend)
+	}
+	defer drivers.CleanMetricDriver(d)
+	return d.CollectMetrics()
+}
+
+type backendMetrics struct {
+	metrics  []*model.MetricSpec
+	duration time.Duration
+	err      error
+}
+
+// storageCollector converts the output of metric drivers to prometheus
+// metrics. The metrics are cached for the interval so that the scrapes do not
+// query the storage backends every time.
+type storageCollector struct {
+	mu          sync.Mutex
+	backends    []string
+	interval    time.Duration
+	collectedAt time.Time
+	cache       map[string]*backendMetrics
+
+	scrapeSuccess  *prometheus.Desc
+	scrapeDuration *prometheus.Desc
+}
+
+func newStorageCollector(backends []string, interval time.Duration) *storageCollector {
+	return &storageCollector{
+		backends: backends,
+		interval: interval,
+		cache:    map[string]*backendMetrics{},
+		scrapeSuccess: prometheus.NewDesc(namespace+"_exporter_collect_success",
+			"Whether the last collection of the backend succeeded.",
+			[]string{backendLabel}, nil,
+		),
+		scrapeDuration: prometheus.NewDesc(namespace+"_exporter_collect_duration_seconds",
+			"Duration of the last collection of the backend.",
+			[]string{backendLabel}, nil,
+		),
+	}
+}
+
+// Describe sends only the exporter metrics, since the metrics of backends are
+// not known until they are collected, which makes it an unchecked collector.
+func (c *storageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.scrapeSuccess
+	ch <- c.scrapeDuration
+}
+
+// refresh collects the metrics of all the backends if the cache is expired.
+func (c *storageCollector) refresh() {
+	if !c.collectedAt.IsZero() && time.Since(c.collectedAt) < c.interval {
+		return
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, b := range c.backends {
+		wg.Add(1)
+		go func(backend string) {
+			defer wg.Done()
+			start := time.Now()
+			metrics, err := collectFunc(backend)
+			if err != nil {
+				log.Errorf("Failed to collect metrics of backend %s: %v", backend, err)
+			}
+			mu.Lock()
+			c.cache[backend] = &backendMetrics{metrics: metrics, duration: time.Since(start), err: err}
+			mu.Unlock()
+		}(b)
+	}
+	wg.Wait()
+	c.collectedAt = time.Now()
+}
+
+type series struct {
+	name   string
+	help   string
+	labels map[string]string
+	value  float64
+}
+
+// Collect implements prometheus.Collector.
+func (c *storageCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	// The series of the same name must have the same label names, so the
+	// label names are merged and the missing labels are left empty.
+	var all []*series
+	labelNames := map[string]map[string]bool{}
+	for _, backend := range c.backends {
+		bm := c.cache[backend]
+		if bm == nil {
+			continue
+		}
+		success := 1.0
+		if bm.err != nil {
+			success = 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.scrapeSuccess, prometheus.GaugeValue, success, backend)
+		ch <- prometheus.MustNewConstMetric(c.scrapeDuration, prometheus.GaugeValue, bm.duration.Seconds(), backend)
+
+		for _, m := range bm.metrics {
+			if m == nil || len(m.MetricValues) == 0 || m.MetricValues[len(m.MetricValues)-1] == nil {
+				continue
+			}
+			s := &series{
+				name: metricName(m),
+				help: fmt.Sprintf("%s of %s collected from %s.", m.Name, m.Component, m.Job),
+				labels: map[string]string{
+					backendLabel:      backend,
+					instanceIdLabel:   m.InstanceID,
+					instanceNameLabel: m.InstanceName,
+				},
+				// The latest value is exported.
+				value: m.MetricValues[len(m.MetricValues)-1].Value,
+			}
+			for k, v := range m.Labels {
+				if k = sanitizeName(k); k != "" {
+					s.labels[k] = v
+				}
+			}
+			if labelNames[s.name] == nil {
+				labelNames[s.name] = map[string]bool{}
+			}
+			for k := range s.labels {
+				labelNames[s.name][k] = true
+			}
+			all = append(all, s)
+		}
+	}
+
+	descs := map[string]*prometheus.Desc{}
+	keys := map[string][]string{}
+	seen := map[string]bool{}
+	for _, s := range all {
+		if descs[s.name] == nil {
+			for k := range labelNames[s.name] {
+				keys[s.name] = append(keys[s.name], k)
+			}
+			sort.Strings(keys[s.name])
+			descs[s.name] = prometheus.NewDesc(s.name, s.help, keys[s.name], nil)
+		}
+		values := make([]string, len(keys[s.name]))
+		for i, k := range keys[s.name] {
+			values[i] = s.labels[k]
+		}
+		// Duplicated series are rejected by prometheus, only the first one
+		// is exported.
+		id := s.name + "\xff" + strings.Join(values, "\xff")
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ch <- prometheus.MustNewConstMetric(descs[s.name], prometheus.GaugeValue, s.value, values...)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+func gather(t *testing.T, c prometheus.Collector) string {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	for _, mf := range mfs {
+		expfmt.MetricFamilyToText(&buf, mf)
+	}
+	return buf.String()
+}
+
+func TestMetricName(t *testing.T) {
+	m := &model.MetricSpec{Job: "HuaweiOceanStor", Component: "pool", Name: "iops", Unit: "tps", AggrType: "Total"}
+	if name := metricName(m); name != "opensds_huaweioceanstor_pool_iops_tps_total" {
+		t.Errorf("Unexpected metric name %s", name)
+	}
+	m = &model.MetricSpec{Job: "lvm", Component: "volume", Name: "utilization_prcnt", Unit: "%"}
+	if name := metricName(m); name != "opensds_lvm_volume_utilization_prcnt" {
+		t.Errorf("Unexpected metric name %s", name)
+	}
+}
+
+func TestParseBackends(t *testing.T) {
+	backends, err := parseBackends("lvm, ceph,lvm")
+	if err != nil || len(backends) != 2 {
+		t.Errorf("Unexpected backends %v, %v", backends, err)
+	}
+	if _, err := parseBackends("unknown"); err == nil {
+		t.Error("Expected error of unsupported backend")
+	}
+	if _, err := parseBackends(""); err == nil {
+		t.Error("Expected error of empty backends")
+	}
+}
+
+func TestStorageCollector(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	collectFunc = func(backend string) ([]*model.MetricSpec, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		if backend == "ceph" {
+			return nil, errors.New("ceph is down")
+		}
+		return []*model.MetricSpec{
+			{
+				InstanceID: "vol-1", InstanceName: "volume-1", Job: "lvm",
+				Labels:    map[string]string{"device": "dm-0"},
+				Component: "volume", Name: "iops", Unit: "tps",
+				MetricValues: []*model.Metric{{Value: 1}, {Value: 2}},
+			},
+			{
+				InstanceID: "vol-2", InstanceName: "volume-2", Job: "lvm",
+				Labels:    map[string]string{"pool.name": "vg001"},
+				Component: "volume", Name: "iops", Unit: "tps",
+				MetricValues: []*model.Metric{{Value: 3}},
+			},
+			{
+				InstanceID: "vol-3", Job: "lvm", Component: "volume", Name: "iops",
+			},
+		}, nil
+	}
+
+	c := newStorageCollector([]string{"lvm", "ceph"}, time.Minute)
+	out := gather(t, c)
+	for _, expected := range []string{
+		`opensds_lvm_volume_iops_tps{backend="lvm",device="dm-0",instance_id="vol-1",instance_name="volume-1",pool_name=""} 2`,
+		`opensds_lvm_volume_iops_tps{backend="lvm",device="",instance_id="vol-2",instance_name="volume-2",pool_name="vg001"} 3`,
+		`opensds_exporter_collect_success{backend="lvm"} 1`,
+		`opensds_exporter_collect_success{backend="ceph"} 0`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %s in metrics:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "vol-3") {
+		t.Error("Expected metric without values to be skipped")
+	}
+
+	// The backends are not collected again within the interval.
+	gather(t, c)
+	if calls != 2 {
+		t.Errorf("Expected backends to be collected once, got %d calls", calls)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements a prometheus exporter of the storage backends which
+have metric drivers, such as lvm, ceph and huawei oceanstor.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sodafoundation/dock/contrib/drivers/utils/config"
+	"github.com/sodafoundation/dock/pkg/utils"
+	conf "github.com/sodafoundation/dock/pkg/utils/config"
+)
+
+var supportedBackends = []string{
+	config.LVMDriverType,
+	config.CephDriverType,
+	config.HuaweiOceanStorBlockDriverType,
+}
+
+func parseBackends(s string) ([]string, error) {
+	var backends []string
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b == "" {
+			continue
+		}
+		if !utils.Contains(supportedBackends, b) {
+			return nil, fmt.Errorf("backend %s is not supported, supported backends are %s",
+				b, strings.Join(supportedBackends, ","))
+		}
+		if !utils.Contains(backends, b) {
+			backends = append(backends, b)
+		}
+	}
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no backend is specified")
+	}
+	return backends, nil
+}
+
+// main function for storage exporter
+// storage exporter is a independent process which user can start if required
+func main() {
+	var listen, backendList string
+	var interval time.Duration
+	// The backend config paths of metric drivers are loaded from the config
+	// file of opensds.
+	conf.CONF.Load()
+	flag.StringVar(&listen, "listen", ":8080", "Listen endpoint of the exporter")
+	flag.StringVar(&backendList, "backends", config.LVMDriverType, "Comma separated backends to collect metrics from")
+	flag.DurationVar(&interval, "interval", 60*time.Second, "Minimum interval between two collections of backends")
+	flag.Parse()
+
+	backends, err := parseBackends(backendList)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	prometheus.MustRegister(newStorageCollector(backends, interval))
+
+	http.Handle("/metrics", promhttp.Handler())
+	log.Infof("storage exporter of %v begining to serve on %s", backends, listen)
+	log.Fatal(http.ListenAndServe(listen, nil))
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sodafoundation/dock/pkg/db/drivers/etcd"
+	"github.com/sodafoundation/dock/pkg/db/drivers/local"
+	. "github.com/sodafoundation/dock/pkg/utils/config"
+	"github.com/spf13/cobra"
+)
+
+var migratorCommand = &cobra.Command{
+	Use:   "dbMigrator",
+	Short: "tool to move the resources of the local database onto etcd",
+}
+
+var exportCommand = &cobra.Command{
+	Use:   "export",
+	Short: "export the resources of the local database",
+	Run:   export,
+}
+
+var importCommand = &cobra.Command{
+	Use:   "import",
+	Short: "import the exported resources into the database of the config file",
+	Run:   importResources,
+}
+
+var (
+	dbFile string
+	file   string
+)
+
+func init() {
+	// The config file is read by the config module from the command line.
+	var configFile string
+	migratorCommand.PersistentFlags().StringVar(&configFile, "config-file", GetConfigPath(), "OpenSDS config file path")
+	CONF.Load()
+
+	exportCommand.Flags().StringVar(&dbFile, "db-file", CONF.Database.Path, "database file of the local driver")
+	exportCommand.Flags().StringVar(&file, "file", "", "file to export to, the standard output if empty")
+	importCommand.Flags().StringVar(&file, "file", "", "file to import from, the standard input if empty")
+	migratorCommand.AddCommand(exportCommand, importCommand)
+}
+
+func export(cmd *cobra.Command, args []string) {
+	s, err := local.Open(dbFile)
+	if err != nil {
+		fmt.Println("Open local database error (is osdsdock stopped?):", err)
+		os.Exit(1)
+	}
+	defer s.Close()
+
+	var w io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			fmt.Println("Create export file error:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := s.Export(w)
+	if err != nil {
+		fmt.Println("Export error:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "%d resources are exported\n", n)
+}
+
+func importResources(cmd *cobra.Command, args []string) {
+	var s etcd.Store
+	switch CONF.Database.Driver {
+	case "etcd":
+		s = etcd.Init(&CONF.Database)
+	case "local":
+		ls, err := local.Open(CONF.Database.Path)
+		if err != nil {
+			fmt.Println("Open local database error:", err)
+			os.Exit(1)
+		}
+		defer ls.Close()
+		s = ls
+	default:
+		fmt.Printf("Importing into database driver %s is not supported\n", CONF.Database.Driver)
+		os.Exit(1)
+	}
+
+	var r io.Reader = os.Stdin
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Println("Open import file error:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		r = f
+	}
+	n, err := local.Import(s, r)
+	if err != nil {
+		fmt.Printf("Import error after %d resources: %v\n", n, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d resources are imported into %s\n", n, CONF.Database.Driver)
+}
+
+func main() {
+	if err := migratorCommand.Execute(); err != nil {
+		fmt.Println("Database migration error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sodafoundation/dock/contrib/drivers/lvm"
+)
+
+func main() {
+	confPath := flag.String("config-file", "/etc/opensds/driver/lvm.yaml", "lvm driver config file path")
+	flag.Parse()
+
+	if err := lvm.MigrateTgtToLio(*confPath); err != nil {
+		fmt.Println("Migrate iscsi targets from tgt to lio error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("The iscsi targets of the lio pools are migrated, see the log for the skipped ones")
+}
@@ -39,7 +39,30 @@ var encrypterCommand = &cobra.Command{
 	Run:   encrypter,
 }
 
+var (
+	generateKey bool
+	keyFile     string
+	migrate     bool
+)
+
+func init() {
+	flags := encrypterCommand.Flags()
+	flags.BoolVar(&generateKey, "generate-key", false, "generate the per-install key of aes into the key file")
+	flags.StringVar(&keyFile, "key-file", pwd.KeyPath(), "key file of aes")
+	flags.BoolVar(&migrate, "migrate", false, "re-encrypt the ciphertext of the deprecated built-in key of aes")
+}
+
 func encrypter(cmd *cobra.Command, args []string) {
+	if generateKey {
+		if err := pwd.GenerateKey(keyFile); err != nil {
+			fmt.Println("Generate key error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Key is generated in", keyFile)
+		os.Exit(0)
+	}
+	os.Setenv(pwd.KeyFileEnv, keyFile)
+
 	if len(args) == 0 {
 		cmd.Usage()
 		os.Exit(0)
@@ -58,6 +81,16 @@ func encrypter(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if migrate {
+		code, err := pwd.NewAES().Migrate(args[0])
+		if err != nil {
+			fmt.Println("Migrate password error:", err)
+			os.Exit(1)
+		}
+		fmt.Println(code)
+		return
+	}
+
 	// Encrypt the password
 	encrypterTool := pwd.NewPwdEncrypter(pwdEncrypter.PwdEncrypter)
 	plaintext, err := encrypterTool.Encrypter(args[0])
@@ -21,10 +21,14 @@ defined in api module.
 package db
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	c "github.com/sodafoundation/dock/pkg/context"
 	"github.com/sodafoundation/dock/pkg/db/drivers/etcd"
+	"github.com/sodafoundation/dock/pkg/db/drivers/local"
+	"github.com/sodafoundation/dock/pkg/db/drivers/sqldb"
 	"github.com/sodafoundation/dock/pkg/model"
 	. "github.com/sodafoundation/dock/pkg/utils/config"
 	fakedb "github.com/sodafoundation/dock/testutils/db"
@@ -36,13 +40,15 @@ var C Client
 // Init function can perform some initialization work of different databases.
 func Init(db *Database) {
 	switch db.Driver {
-	case "mysql":
-		// C = mysql.Init(db.Driver, db.Crendential)
-		fmt.Printf("mysql is not implemented right now!")
+	case "mysql", "postgres":
+		C = sqldb.NewClient(db)
 		return
 	case "etcd":
 		C = etcd.NewClient(db)
 		return
+	case "local":
+		C = local.NewClient(db)
+		return
 	case "fake":
 		C = fakedb.NewFakeDbClient()
 		return
@@ -51,8 +57,50 @@ func Init(db *Database) {
 	}
 }
 
+// IsConflict reports whether err is returned because the resource is changed
+// by others during an update, in which case the update can be retried with
+// the latest resource.
+func IsConflict(err error) bool {
+	_, ok := err.(*model.ConflictError)
+	return ok
+}
+
+// ErrLeaseNotSupported is returned by Liveness if the database has no leases.
+var ErrLeaseNotSupported = etcd.ErrLeaseNotSupported
+
+// Liveness is implemented by the database clients which can tell whether the
+// docks are alive by leases, which expire if the docks stop keeping them
+// alive.
+type Liveness interface {
+	// KeepDockAlive puts the liveness key of the dock under the lease of this
+	// process, which is granted with ttl and kept alive in the background.
+	KeepDockAlive(dckID string, ttl time.Duration) error
+
+	// RevokeDockLease removes the liveness keys of the docks of this process.
+	RevokeDockLease() error
+
+	IsDockAlive(dckID string) (bool, error)
+
+	// WatchDockLeases calls expired with the id of each dock whose liveness
+	// key is removed until stop is closed.
+	WatchDockLeases(stop <-chan struct{}, expired func(dckID string)) error
+}
+
+// ErrLockNotSupported is returned by Locker if the database can't be shared by
+// several docks.
+var ErrLockNotSupported = etcd.ErrLockNotSupported
+
+// Locker is implemented by the database clients which can lock resources
+// across the docks sharing the database.
+type Locker interface {
+	// Lock waits until the lock of key is acquired or ctx is done. The lock
+	// is released when unlock is called, or ttl after this process dies.
+	Lock(ctx context.Context, key string, ttl time.Duration) (unlock func() error, err error)
+}
+
 // Client is an interface for exposing some operations of managing database
-// client.
+// client. The updates are applied to the latest resources, and return a
+// *model.ConflictError if they keep conflicting with the concurrent ones.
 type Client interface {
 	CreateDock(ctx *c.Context, dck *model.DockSpec) (*model.DockSpec, error)
 
@@ -64,6 +112,11 @@ type Client interface {
 
 	UpdateDock(ctx *c.Context, dckID, name, desp string) (*model.DockSpec, error)
 
+	// UpdateDockStatus sets the status and its reason on the latest dock if
+	// check passes on it, so that a dock changed concurrently is checked
+	// again. It returns whether the dock is updated.
+	UpdateDockStatus(ctx *c.Context, dckID, status, reason string, check func(*model.DockSpec) (bool, error)) (bool, error)
+
 	DeleteDock(ctx *c.Context, dckID string) error
 
 	GetDockByPoolId(ctx *c.Context, poolId string) (*model.DockSpec, error)
@@ -78,6 +131,9 @@ type Client interface {
 
 	UpdatePool(ctx *c.Context, polID, name, desp string, usedCapacity int64, used bool) (*model.StoragePoolSpec, error)
 
+	// UpdatePoolStatus works like UpdateDockStatus on the pool.
+	UpdatePoolStatus(ctx *c.Context, polID, status, reason string, check func(*model.StoragePoolSpec) (bool, error)) (bool, error)
+
 	DeletePool(ctx *c.Context, polID string) error
 
 	GetVolume(ctx *c.Context, volID string) (*model.VolumeSpec, error)
@@ -90,6 +146,16 @@ type Client interface {
 
 	ListVolumesByGroupId(ctx *c.Context, vgId string) ([]*model.VolumeSpec, error)
 
+	// UpdateGroupSnapshots adds and removes the ids of the group snapshots of
+	// the volume group in a single update.
+	UpdateGroupSnapshots(ctx *c.Context, vgId string, add, remove []string) (*model.VolumeGroupSpec, error)
+
+	ListReplication(ctx *c.Context) ([]*model.ReplicationSpec, error)
+
+	// UpdateReplicationPairStatus replaces the state of the replication pair
+	// on the backend, the other fields of the replication are kept.
+	UpdateReplicationPairStatus(ctx *c.Context, replicationId string, status *model.ReplicationPairStatus) error
+
 }
 
 func UpdateVolumeStatus(ctx *c.Context, client Client, volID, status string) error {
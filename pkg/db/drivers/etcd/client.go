@@ -16,6 +16,8 @@ package etcd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -26,13 +28,16 @@ import (
 
 	"github.com/coreos/etcd/clientv3"
 	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/model"
 	"github.com/sodafoundation/dock/pkg/utils"
 )
 
 var (
-	timeOut      = 3 * time.Second
-	retryNum     = 3
-	pwdEncrypter = "aes"
+	timeOut  = 3 * time.Second
+	retryNum = 3
+	// conflictRetryNum is the number of attempts of Modify when the content
+	// is changed by others concurrently.
+	conflictRetryNum = 5
 )
 
 // Request
@@ -49,7 +54,9 @@ type Response struct {
 	Error   string   `json:"error"`
 }
 
-type clientInterface interface {
+// Store is the key-value store of the resources, which are kept under their
+// etcd urls.
+type Store interface {
 	Create(req *Request) *Response
 
 	Get(req *Request) *Response
@@ -59,6 +66,12 @@ type clientInterface interface {
 	Update(req *Request) *Response
 
 	Delete(req *Request) *Response
+
+	// Modify replaces the content of url with the result of modify, which is
+	// given the current content. The content is only replaced if it is not
+	// changed since it is read, otherwise modify is applied to the new one.
+	// A model.ConflictError is returned if the conflicts persist.
+	Modify(url string, modify func(content string) (string, error)) (string, error)
 }
 
 // Init
@@ -89,7 +102,7 @@ func Init(etcd *config.Database) *client {
 	if etcd.Username != "" && etcd.Password != "" {
 		clientV3Config.Username = etcd.Username
 
-		pwdTool := pwd.NewPwdEncrypter(pwdEncrypter)
+		pwdTool := pwd.NewPwdEncrypter(etcd.PwdEncrypter)
 		password, err := pwdTool.Decrypter(etcd.Password)
 		if err != nil {
 			panic(err)
@@ -105,21 +118,26 @@ func Init(etcd *config.Database) *client {
 	if err != nil {
 		panic(err)
 	}
-	return &client{cli: cliv3}
+	return &client{cli: cliv3, lease: cliv3, watcher: cliv3}
 }
 
+// client accesses etcd without locking, the concurrent updates are guarded by
+// the revisions of keys in Modify.
 type client struct {
-	cli  *clientv3.Client
-	lock sync.Mutex
+	cli     clientv3.KV
+	lease   clientv3.Lease
+	watcher clientv3.Watcher
+
+	// The lease of the docks of this process, see lease.go.
+	leaseMu     sync.Mutex
+	leaseID     clientv3.LeaseID
+	leaseCancel context.CancelFunc
 }
 
 func (c *client) Create(req *Request) *Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	err := utils.Retry(retryNum, "Etcd put", false, func(retryIdx int, lastErr error) error {
 		_, err := c.cli.Put(ctx, req.Url, req.Content)
 		return err
@@ -143,9 +161,6 @@ func (c *client) Get(req *Request) *Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	resp, err := c.cli.Get(ctx, req.Url)
 	if err != nil {
 		log.Error("When get db request:", err)
@@ -171,9 +186,6 @@ func (c *client) List(req *Request) *Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	resp, err := c.cli.Get(ctx, req.Url, clientv3.WithPrefix())
 	if err != nil {
 		log.Error("When get db request:", err)
@@ -197,9 +209,6 @@ func (c *client) Update(req *Request) *Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	_, err := c.cli.Put(ctx, req.Url, req.NewContent)
 	if err != nil {
 		log.Error("When update db request:", err)
@@ -219,9 +228,6 @@ func (c *client) Delete(req *Request) *Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
 	defer cancel()
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	_, err := c.cli.Delete(ctx, req.Url)
 	if err != nil {
 		log.Error("When delete db request:", err)
@@ -235,3 +241,45 @@ func (c *client) Delete(req *Request) *Response {
 		Status: "Success",
 	}
 }
+
+func (c *client) Modify(url string, modify func(content string) (string, error)) (string, error) {
+	for i := 0; i < conflictRetryNum; i++ {
+		newContent, ok, err := c.modify(url, modify)
+		if err != nil || ok {
+			return newContent, err
+		}
+		log.V(5).Infof("Etcd key %s is changed concurrently, retrying (%d)", url, i+1)
+	}
+	return "", model.NewConflictError(fmt.Sprintf("resource %s is changed concurrently, please retry", url))
+}
+
+// modify applies modify once, ok is false if the content is changed by others
+// in the meantime.
+func (c *client) modify(url string, modify func(content string) (string, error)) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+	defer cancel()
+
+	resp, err := c.cli.Get(ctx, url)
+	if err != nil {
+		log.Error("When get db request:", err)
+		return "", false, err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", false, errors.New("Wrong resource uuid provided!")
+	}
+
+	kv := resp.Kvs[0]
+	newContent, err := modify(string(kv.Value))
+	if err != nil {
+		return "", false, err
+	}
+	txn, err := c.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(url), "=", kv.ModRevision)).
+		Then(clientv3.OpPut(url, newContent)).
+		Commit()
+	if err != nil {
+		log.Error("When update db request:", err)
+		return "", false, err
+	}
+	return newContent, txn.Succeeded, nil
+}
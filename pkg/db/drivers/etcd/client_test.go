@@ -0,0 +1,300 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/embed"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc"
+	"github.com/coreos/etcd/proxy/grpcproxy/adapter"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils"
+	"github.com/sodafoundation/dock/pkg/utils/urls"
+)
+
+// startEtcd starts an embedded etcd server, and returns a client of it along
+// with the function to stop it. The client calls the kv server in process,
+// since the request logging of the grpc server of this etcd version does not
+// work with the protobuf runtime in use.
+func startEtcd(t *testing.T) (*client, func()) {
+	dir, err := ioutil.TempDir("", "etcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	lcurl, _ := url.Parse("http://127.0.0.1:0")
+	lpurl, _ := url.Parse("http://127.0.0.1:0")
+	cfg.LCUrls, cfg.ACUrls = []url.URL{*lcurl}, []url.URL{*lcurl}
+	cfg.LPUrls, cfg.APUrls = []url.URL{*lpurl}, []url.URL{*lpurl}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		e.Close()
+		os.RemoveAll(dir)
+		t.Fatal("embedded etcd is not ready")
+	}
+
+	kv := clientv3.NewKVFromKVClient(adapter.KvServerToKvClient(v3rpc.NewQuotaKVServer(e.Server)), nil)
+	lease := clientv3.NewLeaseFromLeaseClient(adapter.LeaseServerToLeaseClient(v3rpc.NewQuotaLeaseServer(e.Server)), nil, timeOut)
+	watcher := clientv3.NewWatchFromWatchClient(adapter.WatchServerToWatchClient(v3rpc.NewWatchServer(e.Server)), nil)
+	return &client{cli: kv, lease: lease, watcher: watcher}, func() {
+		lease.Close()
+		watcher.Close()
+		e.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestModify(t *testing.T) {
+	cli, stop := startEtcd(t)
+	defer stop()
+	key := "/test/modify"
+
+	if _, err := cli.Modify(key, func(content string) (string, error) { return content, nil }); err == nil {
+		t.Error("Expected error of missing key")
+	}
+	if res := cli.Create(&Request{Url: key, Content: "a"}); res.Status != "Success" {
+		t.Fatal(res.Error)
+	}
+
+	// The change of others between the read and the write is not lost, the
+	// modify function is applied to it again.
+	calls := 0
+	content, err := cli.Modify(key, func(content string) (string, error) {
+		calls++
+		if calls == 1 {
+			cli.Update(&Request{Url: key, NewContent: content + "b"})
+		}
+		return content + "c", nil
+	})
+	if err != nil || content != "abc" || calls != 2 {
+		t.Errorf("Expected abc after 2 calls, got %s, %v after %d calls", content, err, calls)
+	}
+	if res := cli.Get(&Request{Url: key}); res.Message[0] != "abc" {
+		t.Errorf("Expected abc in etcd, got %v", res.Message)
+	}
+
+	// The conflicts which keep happening are returned.
+	calls = 0
+	_, err = cli.Modify(key, func(content string) (string, error) {
+		calls++
+		cli.Update(&Request{Url: key, NewContent: fmt.Sprint(calls)})
+		return "lost", nil
+	})
+	if _, ok := err.(*model.ConflictError); !ok || calls != conflictRetryNum {
+		t.Errorf("Expected conflict error after %d calls, got %v after %d calls", conflictRetryNum, err, calls)
+	}
+
+	// The errors of modify function are returned without writing.
+	if _, err := cli.Modify(key, func(string) (string, error) { return "", fmt.Errorf("invalid") }); err == nil {
+		t.Error("Expected error of modify function")
+	}
+	if res := cli.Get(&Request{Url: key}); res.Message[0] != fmt.Sprint(conflictRetryNum) {
+		t.Errorf("Expected content unchanged, got %v", res.Message)
+	}
+}
+
+func TestConcurrentUpdateVolume(t *testing.T) {
+	cli, stop := startEtcd(t)
+	defer stop()
+	client := &Client{Store: cli}
+	ctx := c.NewAdminContext()
+	vol := &model.VolumeSpec{BaseModel: &model.BaseModel{Id: "vol-1"}, TenantId: ctx.TenantId,
+		Name: "vol", Status: "creating"}
+	if res := cli.Create(&Request{
+		Url:     urls.GenerateVolumeURL(urls.Etcd, vol.TenantId, vol.Id),
+		Content: `{"id":"vol-1","tenantId":"` + vol.TenantId + `","name":"vol","status":"creating"}`,
+	}); res.Status != "Success" {
+		t.Fatal(res.Error)
+	}
+
+	// The updates of different fields from discovery and rpcs are all kept.
+	var wg sync.WaitGroup
+	var updated int32
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := client.UpdateStatus(ctx, &model.VolumeSpec{BaseModel: &model.BaseModel{Id: "vol-1"}},
+				"available"); err != nil {
+				errs <- err
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, err := client.UpdateVolume(ctx, &model.VolumeSpec{BaseModel: &model.BaseModel{Id: "vol-1"},
+				Metadata: map[string]string{fmt.Sprint("key", i): "value"}})
+			if err != nil {
+				errs <- err
+				return
+			}
+			atomic.AddInt32(&updated, 1)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		// The conflicts are rare but possible with so many writers, they
+		// are the only errors expected.
+		if _, ok := err.(*model.ConflictError); !ok {
+			t.Errorf("Unexpected error %v", err)
+		}
+	}
+
+	result, err := client.GetVolume(ctx, "vol-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != "available" || result.Name != "vol" {
+		t.Errorf("Expected available volume vol, got %+v", result)
+	}
+	if n := len(result.Metadata); n != int(updated) {
+		t.Errorf("Expected %d metadata kept, got %d", updated, n)
+	}
+}
+
+func TestDockLease(t *testing.T) {
+	cli, stop := startEtcd(t)
+	defer stop()
+	client := &Client{Store: cli}
+
+	expired := make(chan string, 10)
+	watchStop := make(chan struct{})
+	watchDone := make(chan error, 1)
+	go func() { watchDone <- client.WatchDockLeases(watchStop, func(id string) { expired <- id }) }()
+
+	for _, id := range []string{"dock-1", "dock-2"} {
+		if err := client.KeepDockAlive(id, 5*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if alive, err := client.IsDockAlive("dock-1"); err != nil || !alive {
+		t.Errorf("Expected dock-1 to be alive, got %v, %v", alive, err)
+	}
+	if alive, err := client.IsDockAlive("dock-3"); err != nil || alive {
+		t.Errorf("Expected dock-3 not to be alive, got %v, %v", alive, err)
+	}
+
+	// A lost lease is granted again by the next call.
+	lost := cli.leaseID
+	if _, err := cli.lease.Revoke(context.Background(), lost); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for len(got) < 2 {
+		select {
+		case id := <-expired:
+			got[id] = true
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Expected the expiries of both docks, got %v", got)
+		}
+	}
+	err := utils.WaitForCondition(func() (bool, error) {
+		if err := client.KeepDockAlive("dock-1", 5*time.Second); err != nil {
+			return false, nil
+		}
+		return cli.leaseID != lost, nil
+	}, 10*time.Millisecond, 10*time.Second)
+	if err != nil {
+		t.Fatal("Expected a new lease to be granted")
+	}
+	if alive, _ := client.IsDockAlive("dock-1"); !alive {
+		t.Error("Expected dock-1 to be alive again")
+	}
+
+	if err = client.RevokeDockLease(); err != nil {
+		t.Fatal(err)
+	}
+	if alive, _ := client.IsDockAlive("dock-1"); alive {
+		t.Error("Expected dock-1 not to be alive after revoking")
+	}
+	select {
+	case id := <-expired:
+		if id != "dock-1" {
+			t.Errorf("Expected the expiry of dock-1, got %s", id)
+		}
+	case <-time.After(10 * time.Second):
+		t.Error("Expected the expiry of dock-1")
+	}
+
+	close(watchStop)
+	if err = <-watchDone; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLock(t *testing.T) {
+	cli, stop := startEtcd(t)
+	defer stop()
+	client := &Client{Store: cli}
+
+	unlock, err := client.Lock(context.Background(), "volumes/v1", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The lock is busy until it is released.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err = client.Lock(ctx, "volumes/v1", 5*time.Second)
+	cancel()
+	if err == nil {
+		t.Fatal("Expected the lock to be busy")
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		u, err := client.Lock(context.Background(), "volumes/v1", 5*time.Second)
+		if err == nil {
+			err = u()
+		}
+		acquired <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err = unlock(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the lock to be acquired after it is released")
+	}
+
+	resp, err := cli.cli.Get(context.Background(), urls.GenerateLockURL(urls.Etcd, "", "volumes/v1"))
+	if err != nil || resp.Count != 0 {
+		t.Errorf("Expected the lock key to be removed, got %v, %v", resp, err)
+	}
+}
@@ -82,14 +82,20 @@ func AuthorizeProjectContext(ctx *c.Context, tenantId string) bool {
 
 // NewClient
 func NewClient(etcd *config.Database) *Client {
+	return NewClientWithStore(Init(etcd))
+}
+
+// NewClientWithStore returns a client which keeps the resources in s instead
+// of etcd.
+func NewClientWithStore(s Store) *Client {
 	return &Client{
-		clientInterface: Init(etcd),
+		Store: s,
 	}
 }
 
 // Client
 type Client struct {
-	clientInterface
+	Store
 }
 
 //Parameter
@@ -338,33 +344,53 @@ func (c *Client) ListDocksWithFilter(ctx *c.Context, m map[string][]string) ([]*
 
 // UpdateDock
 func (c *Client) UpdateDock(ctx *c.Context, dckID, name, desp string) (*model.DockSpec, error) {
-	dck, err := c.GetDock(ctx, dckID)
-	if err != nil {
-		return nil, err
-	}
-	if name != "" {
-		dck.Name = name
-	}
-	if desp != "" {
-		dck.Description = desp
-	}
-	dck.UpdatedAt = time.Now().Format(constants.TimeFormat)
+	var dck *model.DockSpec
+	_, err := c.Modify(urls.GenerateDockURL(urls.Etcd, "", dckID), func(content string) (string, error) {
+		dck = &model.DockSpec{}
+		if err := json.Unmarshal([]byte(content), dck); err != nil {
+			return "", err
+		}
+		if name != "" {
+			dck.Name = name
+		}
+		if desp != "" {
+			dck.Description = desp
+		}
+		dck.UpdatedAt = time.Now().Format(constants.TimeFormat)
 
-	dckBody, err := json.Marshal(dck)
+		dckBody, err := json.Marshal(dck)
+		return string(dckBody), err
+	})
 	if err != nil {
+		log.Error("When update dock in db:", err)
 		return nil, err
 	}
+	return dck, nil
+}
 
-	dbReq := &Request{
-		Url:        urls.GenerateDockURL(urls.Etcd, "", dckID),
-		NewContent: string(dckBody),
-	}
-	dbRes := c.Update(dbReq)
-	if dbRes.Status != "Success" {
-		log.Error("When update dock in db:", dbRes.Error)
-		return nil, errors.New(dbRes.Error)
+// UpdateDockStatus
+func (c *Client) UpdateDockStatus(ctx *c.Context, dckID, status, reason string, check func(*model.DockSpec) (bool, error)) (bool, error) {
+	var updated bool
+	_, err := c.Modify(urls.GenerateDockURL(urls.Etcd, "", dckID), func(content string) (string, error) {
+		dck := &model.DockSpec{}
+		if err := json.Unmarshal([]byte(content), dck); err != nil {
+			return "", err
+		}
+		var err error
+		if updated, err = check(dck); err != nil || !updated {
+			return content, err
+		}
+		dck.Status, dck.StatusReason = status, reason
+		dck.UpdatedAt = time.Now().Format(constants.TimeFormat)
+
+		dckBody, err := json.Marshal(dck)
+		return string(dckBody), err
+	})
+	if err != nil {
+		log.Error("When update dock status in db:", err)
+		return false, err
 	}
-	return dck, nil
+	return updated, nil
 }
 
 // DeleteDock
@@ -469,33 +495,53 @@ func (c *Client) ListPools(ctx *c.Context) ([]*model.StoragePoolSpec, error) {
 
 // UpdatePool
 func (c *Client) UpdatePool(ctx *c.Context, polID, name, desp string, usedCapacity int64, used bool) (*model.StoragePoolSpec, error) {
-	pol, err := c.GetPool(ctx, polID)
-	if err != nil {
-		return nil, err
-	}
-	if name != "" {
-		pol.Name = name
-	}
-	if desp != "" {
-		pol.Description = desp
-	}
-	pol.UpdatedAt = time.Now().Format(constants.TimeFormat)
+	var pol *model.StoragePoolSpec
+	_, err := c.Modify(urls.GeneratePoolURL(urls.Etcd, "", polID), func(content string) (string, error) {
+		pol = &model.StoragePoolSpec{}
+		if err := json.Unmarshal([]byte(content), pol); err != nil {
+			return "", err
+		}
+		if name != "" {
+			pol.Name = name
+		}
+		if desp != "" {
+			pol.Description = desp
+		}
+		pol.UpdatedAt = time.Now().Format(constants.TimeFormat)
 
-	polBody, err := json.Marshal(pol)
+		polBody, err := json.Marshal(pol)
+		return string(polBody), err
+	})
 	if err != nil {
+		log.Error("When update pool in db:", err)
 		return nil, err
 	}
+	return pol, nil
+}
 
-	dbReq := &Request{
-		Url:        urls.GeneratePoolURL(urls.Etcd, "", polID),
-		NewContent: string(polBody),
-	}
-	dbRes := c.Update(dbReq)
-	if dbRes.Status != "Success" {
-		log.Error("When update pool in db:", dbRes.Error)
-		return nil, errors.New(dbRes.Error)
+// UpdatePoolStatus
+func (c *Client) UpdatePoolStatus(ctx *c.Context, polID, status, reason string, check func(*model.StoragePoolSpec) (bool, error)) (bool, error) {
+	var updated bool
+	_, err := c.Modify(urls.GeneratePoolURL(urls.Etcd, "", polID), func(content string) (string, error) {
+		pol := &model.StoragePoolSpec{}
+		if err := json.Unmarshal([]byte(content), pol); err != nil {
+			return "", err
+		}
+		var err error
+		if updated, err = check(pol); err != nil || !updated {
+			return content, err
+		}
+		pol.Status, pol.StatusReason = status, reason
+		pol.UpdatedAt = time.Now().Format(constants.TimeFormat)
+
+		polBody, err := json.Marshal(pol)
+		return string(polBody), err
+	})
+	if err != nil {
+		log.Error("When update pool status in db:", err)
+		return false, err
 	}
-	return pol, nil
+	return updated, nil
 }
 
 // DeletePool
@@ -585,6 +631,33 @@ func (c *Client) UpdateVolume(ctx *c.Context, vol *model.VolumeSpec) (*model.Vol
 	if err != nil {
 		return nil, err
 	}
+
+	// If an admin want to access other tenant's resource just fake other's tenantId.
+	if !IsAdminContext(ctx) && !AuthorizeProjectContext(ctx, result.TenantId) {
+		return nil, fmt.Errorf("opertaion is not permitted")
+	}
+
+	// The changes are applied to the latest volume, so that the concurrent
+	// updates of other fields are kept.
+	url := urls.GenerateVolumeURL(urls.Etcd, result.TenantId, vol.Id)
+	_, err = c.Modify(url, func(content string) (string, error) {
+		result = &model.VolumeSpec{}
+		if err := json.Unmarshal([]byte(content), result); err != nil {
+			return "", err
+		}
+		mergeVolume(result, vol)
+		body, err := json.Marshal(result)
+		return string(body), err
+	})
+	if err != nil {
+		log.Error("When update volume in db:", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeVolume applies the set fields of vol to result.
+func mergeVolume(result, vol *model.VolumeSpec) {
 	if vol.Name != "" {
 		result.Name = vol.Name
 	}
@@ -621,28 +694,6 @@ func (c *Client) UpdateVolume(ctx *c.Context, vol *model.VolumeSpec) (*model.Vol
 
 	// Set update time
 	result.UpdatedAt = time.Now().Format(constants.TimeFormat)
-
-	body, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
-	}
-
-	// If an admin want to access other tenant's resource just fake other's tenantId.
-	if !IsAdminContext(ctx) && !AuthorizeProjectContext(ctx, result.TenantId) {
-		return nil, fmt.Errorf("opertaion is not permitted")
-	}
-
-	dbReq := &Request{
-		Url:        urls.GenerateVolumeURL(urls.Etcd, result.TenantId, vol.Id),
-		NewContent: string(body),
-	}
-
-	dbRes := c.Update(dbReq)
-	if dbRes.Status != "Success" {
-		log.Error("When update volume in db:", dbRes.Error)
-		return nil, errors.New(dbRes.Error)
-	}
-	return result, nil
 }
 
 // DeleteVolume
@@ -742,10 +793,109 @@ func (c *Client) GetVolumeGroup(ctx *c.Context, vgId string) (*model.VolumeGroup
 }
 
 func (c *Client) UpdateVolumeGroup(ctx *c.Context, vgUpdate *model.VolumeGroupSpec) (*model.VolumeGroupSpec, error) {
-	vg, err := c.GetVolumeGroup(ctx, vgUpdate.Id)
+	var vg *model.VolumeGroupSpec
+	url := urls.GenerateVolumeGroupURL(urls.Etcd, ctx.TenantId, vgUpdate.Id)
+	_, err := c.Modify(url, func(content string) (string, error) {
+		vg = &model.VolumeGroupSpec{}
+		if err := json.Unmarshal([]byte(content), vg); err != nil {
+			return "", err
+		}
+		mergeVolumeGroup(vg, vgUpdate)
+		vgBody, err := json.Marshal(vg)
+		return string(vgBody), err
+	})
 	if err != nil {
+		log.Error("When update volume group in db:", err)
 		return nil, err
 	}
+	return vg, nil
+}
+
+func (c *Client) UpdateGroupSnapshots(ctx *c.Context, vgId string, add, remove []string) (*model.VolumeGroupSpec, error) {
+	var vg *model.VolumeGroupSpec
+	url := urls.GenerateVolumeGroupURL(urls.Etcd, ctx.TenantId, vgId)
+	_, err := c.Modify(url, func(content string) (string, error) {
+		vg = &model.VolumeGroupSpec{}
+		if err := json.Unmarshal([]byte(content), vg); err != nil {
+			return "", err
+		}
+		vg.UpdateGroupSnapshots(add, remove)
+		vg.UpdatedAt = time.Now().Format(constants.TimeFormat)
+		vgBody, err := json.Marshal(vg)
+		return string(vgBody), err
+	})
+	if err != nil {
+		log.Error("When update group snapshots of volume group in db:", err)
+		return nil, err
+	}
+	return vg, nil
+}
+
+// ListReplication
+func (c *Client) ListReplication(ctx *c.Context) ([]*model.ReplicationSpec, error) {
+	dbReq := &Request{
+		Url: urls.GenerateReplicationURL(urls.Etcd, ctx.TenantId),
+	}
+
+	// Admin user should get all replications including the ones whose tenant is not admin.
+	if IsAdminContext(ctx) {
+		dbReq.Url = urls.GenerateReplicationURL(urls.Etcd, "")
+	}
+
+	dbRes := c.List(dbReq)
+	if dbRes.Status != "Success" {
+		log.Error("When list replications in db:", dbRes.Error)
+		return nil, errors.New(dbRes.Error)
+	}
+
+	var replications = []*model.ReplicationSpec{}
+	for _, msg := range dbRes.Message {
+		var replication = &model.ReplicationSpec{}
+		if err := json.Unmarshal([]byte(msg), replication); err != nil {
+			log.Error("When parsing replication in db:", err)
+			return nil, err
+		}
+		replications = append(replications, replication)
+	}
+	return replications, nil
+}
+
+// UpdateReplicationPairStatus
+func (c *Client) UpdateReplicationPairStatus(ctx *c.Context, replicationId string, status *model.ReplicationPairStatus) error {
+	tenantId := ctx.TenantId
+	// The replications of other tenants are found by admin users.
+	if IsAdminContext(ctx) {
+		replications, err := c.ListReplication(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range replications {
+			if r.Id == replicationId {
+				tenantId = r.TenantId
+				break
+			}
+		}
+	}
+
+	url := urls.GenerateReplicationURL(urls.Etcd, tenantId, replicationId)
+	_, err := c.Modify(url, func(content string) (string, error) {
+		replication := &model.ReplicationSpec{}
+		if err := json.Unmarshal([]byte(content), replication); err != nil {
+			return "", err
+		}
+		replication.PairStatus = status
+		body, err := json.Marshal(replication)
+		return string(body), err
+	})
+	if err != nil {
+		log.Error("When update pair status of replication in db:", err)
+		return err
+	}
+	return nil
+}
+
+// mergeVolumeGroup applies the changed fields of vgUpdate to vg.
+func mergeVolumeGroup(vg, vgUpdate *model.VolumeGroupSpec) {
 	if vgUpdate.Name != "" && vgUpdate.Name != vg.Name {
 		vg.Name = vgUpdate.Name
 	}
@@ -770,48 +920,82 @@ func (c *Client) UpdateVolumeGroup(ctx *c.Context, vgUpdate *model.VolumeGroupSp
 	if vgUpdate.UpdatedAt != "" && vgUpdate.UpdatedAt != vg.UpdatedAt {
 		vg.UpdatedAt = vgUpdate.UpdatedAt
 	}
+}
 
-	vgBody, err := json.Marshal(vg)
+// UpdateStatus only changes the status of the latest records, so that the
+// other fields of the possibly stale objects passed in are never written.
+func (c *Client) UpdateStatus(ctx *c.Context, in interface{}, status string) error {
+	var err error
+	switch obj := in.(type) {
+	case *model.VolumeSpec:
+		err = c.updateVolumeStatus(ctx, obj, status)
+	case []*model.VolumeSpec:
+		for _, vol := range obj {
+			if err = c.updateVolumeStatus(ctx, vol, status); err != nil {
+				break
+			}
+		}
+	case *model.VolumeGroupSpec:
+		err = c.updateVolumeGroupStatus(ctx, obj, status)
+	default:
+		err = fmt.Errorf("updating status of %T is not supported", in)
+	}
 	if err != nil {
-		return nil, err
+		log.Error("When update status in db:", err)
 	}
+	return err
+}
 
-	dbReq := &Request{
-		Url:        urls.GenerateVolumeGroupURL(urls.Etcd, ctx.TenantId, vgUpdate.Id),
-		NewContent: string(vgBody),
+func (c *Client) updateVolumeStatus(ctx *c.Context, vol *model.VolumeSpec, status string) error {
+	if vol == nil || vol.BaseModel == nil {
+		return model.NewNotFoundError("volume to update is not found")
 	}
-	dbRes := c.Update(dbReq)
-	if dbRes.Status != "Success" {
-		log.Error("When update volume group in db:", dbRes.Error)
-		return nil, errors.New(dbRes.Error)
+	result, err := c.GetVolume(ctx, vol.Id)
+	if err != nil {
+		return err
+	}
+	if !IsAdminContext(ctx) && !AuthorizeProjectContext(ctx, result.TenantId) {
+		return fmt.Errorf("opertaion is not permitted")
 	}
-	return vg, nil
-}
-
-func (c *Client) UpdateStatus(ctx *c.Context, in interface{}, status string) error {
-	switch in.(type) {
-	case *model.VolumeSpec:
-		volume := in.(*model.VolumeSpec)
-		volume.Status = status
-		if _, errUpdate := c.UpdateVolume(ctx, volume); errUpdate != nil {
-			log.Error("When update volume status in db:", errUpdate.Error())
-			return errUpdate
-		}
 
-	case *model.VolumeGroupSpec:
-		vg := in.(*model.VolumeGroupSpec)
-		vg.Status = status
-		if _, errUpdate := c.UpdateVolumeGroup(ctx, vg); errUpdate != nil {
-			log.Error("When update volume status in db:", errUpdate.Error())
-			return errUpdate
+	url := urls.GenerateVolumeURL(urls.Etcd, result.TenantId, vol.Id)
+	_, err = c.Modify(url, func(content string) (string, error) {
+		result = &model.VolumeSpec{}
+		if err := json.Unmarshal([]byte(content), result); err != nil {
+			return "", err
 		}
+		result.Status = status
+		result.UpdatedAt = time.Now().Format(constants.TimeFormat)
+		body, err := json.Marshal(result)
+		return string(body), err
+	})
+	if err != nil {
+		return err
+	}
+	vol.Status, vol.UpdatedAt = status, result.UpdatedAt
+	return nil
+}
 
-	case []*model.VolumeSpec:
-		vols := in.([]*model.VolumeSpec)
-		if _, errUpdate := c.VolumesToUpdate(ctx, vols); errUpdate != nil {
-			return errUpdate
+func (c *Client) updateVolumeGroupStatus(ctx *c.Context, vg *model.VolumeGroupSpec, status string) error {
+	if vg == nil || vg.BaseModel == nil {
+		return model.NewNotFoundError("volume group to update is not found")
+	}
+	result := &model.VolumeGroupSpec{}
+	url := urls.GenerateVolumeGroupURL(urls.Etcd, ctx.TenantId, vg.Id)
+	_, err := c.Modify(url, func(content string) (string, error) {
+		result = &model.VolumeGroupSpec{}
+		if err := json.Unmarshal([]byte(content), result); err != nil {
+			return "", err
 		}
+		result.Status = status
+		result.UpdatedAt = time.Now().Format(constants.TimeFormat)
+		body, err := json.Marshal(result)
+		return string(body), err
+	})
+	if err != nil {
+		return err
 	}
+	vg.Status, vg.UpdatedAt = status, result.UpdatedAt
 	return nil
 }
 
@@ -21,6 +21,7 @@ defined in api module.
 package etcd
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 	"testing"
@@ -107,8 +108,12 @@ func (*fakeClientCaller) Delete(req *Request) *Response {
 	}
 }
 
+func (f *fakeClientCaller) Modify(url string, modify func(content string) (string, error)) (string, error) {
+	return modify(f.Get(&Request{Url: url}).Message[0])
+}
+
 var fc = &Client{
-	clientInterface: &fakeClientCaller{},
+	Store: &fakeClientCaller{},
 }
 
 func TestCreateDock(t *testing.T) {
@@ -963,3 +968,44 @@ func TestListVolumeSnapshotsWithFilter(t *testing.T) {
 		}
 	}
 }
+
+// modifyRecorder keeps the content of a single key, which is replaced by
+// Modify.
+type modifyRecorder struct {
+	fakeClientCaller
+	content string
+}
+
+func (m *modifyRecorder) Get(req *Request) *Response {
+	return &Response{Status: "Success", Message: []string{m.content}}
+}
+
+func (m *modifyRecorder) Modify(url string, modify func(content string) (string, error)) (string, error) {
+	content, err := modify(m.content)
+	if err == nil {
+		m.content = content
+	}
+	return content, err
+}
+
+func TestUpdateStatus(t *testing.T) {
+	body, _ := json.Marshal(&SampleVolumes[0])
+	recorder := &modifyRecorder{content: string(body)}
+	client := &Client{Store: recorder}
+	// The stale fields of the volume passed in are not written.
+	vol := &model.VolumeSpec{BaseModel: &model.BaseModel{Id: SampleVolumes[0].Id}, Size: 100, PoolId: "stale"}
+	if err := client.UpdateStatus(c.NewAdminContext(), vol, model.VolumeAvailable); err != nil {
+		t.Fatal("Update status failed:", err)
+	}
+
+	result := &model.VolumeSpec{}
+	if err := json.Unmarshal([]byte(recorder.content), result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != model.VolumeAvailable || result.Size != SampleVolumes[0].Size || result.PoolId != SampleVolumes[0].PoolId {
+		t.Errorf("Expected only status to be updated, got %+v", result)
+	}
+	if vol.Status != model.VolumeAvailable {
+		t.Errorf("Expected status of the volume to be %s, got %s", model.VolumeAvailable, vol.Status)
+	}
+}
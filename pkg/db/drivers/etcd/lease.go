@@ -0,0 +1,198 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+	"github.com/sodafoundation/dock/pkg/utils/urls"
+)
+
+// ErrLeaseNotSupported is returned by the dock lease methods of the clients
+// whose stores have no leases, such as the local one.
+var ErrLeaseNotSupported = errors.New("dock leases are not supported by the database")
+
+// leaseStore is implemented by the stores which keep the liveness of docks
+// by leases.
+type leaseStore interface {
+	keepDockAlive(dckID string, ttl time.Duration) error
+	revokeDockLease() error
+	isDockAlive(dckID string) (bool, error)
+	watchDockLeases(stop <-chan struct{}, expired func(dckID string)) error
+}
+
+// KeepDockAlive puts the liveness key of the dock under the lease of this
+// process, which is granted with ttl on the first call and kept alive until
+// RevokeDockLease is called. It is granted again if it is lost, for example
+// when etcd can't be reached for longer than ttl.
+func (c *Client) KeepDockAlive(dckID string, ttl time.Duration) error {
+	if ls, ok := c.Store.(leaseStore); ok {
+		return ls.keepDockAlive(dckID, ttl)
+	}
+	return ErrLeaseNotSupported
+}
+
+// RevokeDockLease revokes the lease of this process, so that the liveness
+// keys of its docks are removed at once.
+func (c *Client) RevokeDockLease() error {
+	if ls, ok := c.Store.(leaseStore); ok {
+		return ls.revokeDockLease()
+	}
+	return ErrLeaseNotSupported
+}
+
+// IsDockAlive reports whether the liveness key of the dock exists.
+func (c *Client) IsDockAlive(dckID string) (bool, error) {
+	if ls, ok := c.Store.(leaseStore); ok {
+		return ls.isDockAlive(dckID)
+	}
+	return false, ErrLeaseNotSupported
+}
+
+// WatchDockLeases calls expired with the id of each dock whose liveness key
+// is removed until stop is closed, or the watch fails.
+func (c *Client) WatchDockLeases(stop <-chan struct{}, expired func(dckID string)) error {
+	if ls, ok := c.Store.(leaseStore); ok {
+		return ls.watchDockLeases(stop, expired)
+	}
+	return ErrLeaseNotSupported
+}
+
+// grantLease returns the lease of this process, and grants a new one if there
+// is none or the current one is lost.
+func (c *client) grantLease(ttl time.Duration) (clientv3.LeaseID, error) {
+	c.leaseMu.Lock()
+	defer c.leaseMu.Unlock()
+	if c.leaseID != clientv3.NoLease {
+		return c.leaseID, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+	defer cancel()
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	resp, err := c.lease.Grant(ctx, seconds)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+
+	kaCtx, kaCancel := context.WithCancel(context.Background())
+	ch, err := c.lease.KeepAlive(kaCtx, resp.ID)
+	if err != nil {
+		kaCancel()
+		return clientv3.NoLease, err
+	}
+	c.leaseID, c.leaseCancel = resp.ID, kaCancel
+	go func(id clientv3.LeaseID) {
+		for range ch {
+		}
+		// The channel is closed when the lease is revoked, expires or can't
+		// be kept alive any more, so a new one is granted by the next call.
+		c.leaseMu.Lock()
+		defer c.leaseMu.Unlock()
+		if c.leaseID == id {
+			log.Warningf("Dock lease %x is lost, a new one will be granted", id)
+			c.leaseID, c.leaseCancel = clientv3.NoLease, nil
+		}
+		kaCancel()
+	}(resp.ID)
+	log.Infof("Granted dock lease %x with ttl %ds", resp.ID, seconds)
+	return resp.ID, nil
+}
+
+func (c *client) keepDockAlive(dckID string, ttl time.Duration) error {
+	id, err := c.grantLease(ttl)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+	defer cancel()
+	_, err = c.cli.Put(ctx, urls.GenerateDockLeaseURL(urls.Etcd, "", dckID),
+		time.Now().Format(constants.TimeFormat), clientv3.WithLease(id))
+	if err != nil {
+		// The lease may expire before the keep alive notices it, so forget
+		// it and let the next call grant a new one.
+		c.leaseMu.Lock()
+		if c.leaseID == id {
+			c.leaseCancel()
+			c.leaseID, c.leaseCancel = clientv3.NoLease, nil
+		}
+		c.leaseMu.Unlock()
+	}
+	return err
+}
+
+func (c *client) revokeDockLease() error {
+	c.leaseMu.Lock()
+	id, cancelKeepAlive := c.leaseID, c.leaseCancel
+	c.leaseID, c.leaseCancel = clientv3.NoLease, nil
+	c.leaseMu.Unlock()
+	if id == clientv3.NoLease {
+		return nil
+	}
+	cancelKeepAlive()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+	defer cancel()
+	_, err := c.lease.Revoke(ctx, id)
+	return err
+}
+
+func (c *client) isDockAlive(dckID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+	defer cancel()
+	resp, err := c.cli.Get(ctx, urls.GenerateDockLeaseURL(urls.Etcd, "", dckID), clientv3.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
+
+func (c *client) watchDockLeases(stop <-chan struct{}, expired func(dckID string)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	prefix := urls.GenerateDockLeaseURL(urls.Etcd, "", "")
+	for resp := range c.watcher.Watch(ctx, prefix, clientv3.WithPrefix(), clientv3.WithFilterPut()) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, ev := range resp.Events {
+			if ev.Type == mvccpb.DELETE {
+				expired(strings.TrimPrefix(string(ev.Kv.Key), prefix))
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("watch of dock leases is closed")
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+	"github.com/sodafoundation/dock/pkg/utils/urls"
+)
+
+// ErrLockNotSupported is returned by Lock of the clients whose stores can't
+// be shared by several processes, such as the local one.
+var ErrLockNotSupported = errors.New("distributed locks are not supported by the database")
+
+// lockStore is implemented by the stores which can lock resources across
+// processes.
+type lockStore interface {
+	lock(ctx context.Context, key string, ttl time.Duration) (func() error, error)
+}
+
+// Lock waits until the lock of key is acquired or ctx is done. The lock is
+// held under a lease of ttl, which is kept alive until the returned unlock is
+// called, so that it is released by etcd if this process dies.
+func (c *Client) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	if ls, ok := c.Store.(lockStore); ok {
+		return ls.lock(ctx, key, ttl)
+	}
+	return nil, ErrLockNotSupported
+}
+
+func (c *client) lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	grant, err := c.lease.Grant(ctx, seconds)
+	if err != nil {
+		return nil, err
+	}
+	kaCtx, kaCancel := context.WithCancel(context.Background())
+	ch, err := c.lease.KeepAlive(kaCtx, grant.ID)
+	if err != nil {
+		kaCancel()
+		return nil, err
+	}
+	go func() {
+		for range ch {
+		}
+	}()
+	// Revoking the lease removes the lock key at once.
+	unlock := func() error {
+		kaCancel()
+		ctx, cancel := context.WithTimeout(context.Background(), timeOut)
+		defer cancel()
+		_, err := c.lease.Revoke(ctx, grant.ID)
+		return err
+	}
+
+	url := urls.GenerateLockURL(urls.Etcd, "", key)
+	for {
+		resp, err := c.cli.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(url), "=", 0)).
+			Then(clientv3.OpPut(url, time.Now().Format(constants.TimeFormat), clientv3.WithLease(grant.ID))).
+			Commit()
+		if err == nil && resp.Succeeded {
+			return unlock, nil
+		}
+		if err == nil {
+			err = c.waitDeleted(ctx, url, resp.Header.Revision)
+		}
+		if err != nil {
+			if uerr := unlock(); uerr != nil {
+				log.Warningf("Failed to revoke the lease of lock %s: %v", key, uerr)
+			}
+			return nil, err
+		}
+	}
+}
+
+// waitDeleted waits until url is deleted after revision rev.
+func (c *client) waitDeleted(ctx context.Context, url string, rev int64) error {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for resp := range c.watcher.Watch(wctx, url, clientv3.WithRev(rev+1), clientv3.WithFilterPut()) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		for _, ev := range resp.Events {
+			if ev.Type == mvccpb.DELETE {
+				return nil
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("watch of lock " + url + " is closed")
+}
@@ -0,0 +1,224 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the local database, which keeps the resources in a
+single file for the docks running without etcd.
+
+*/
+
+package local
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/db/drivers/etcd"
+	"github.com/sodafoundation/dock/pkg/utils/config"
+)
+
+var (
+	// openTimeout is how long Open waits for the lock of the database file,
+	// which is held by the process using it.
+	openTimeout = 3 * time.Second
+
+	bucket = []byte("opensds")
+)
+
+// NewClient opens the database file of db.Path, and returns a client which
+// works on it the same way as on etcd.
+func NewClient(db *config.Database) *etcd.Client {
+	s, err := Open(db.Path)
+	if err != nil {
+		log.Error("When open local database:", err)
+		panic(err)
+	}
+	return etcd.NewClientWithStore(s)
+}
+
+// Store keeps the resources in a bbolt file under their etcd urls, so that
+// they can be moved onto etcd as they are. Each write is committed with
+// fsync before it returns, so it is kept after a crash.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the database file at path, and creates it if not exists.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func success(message ...string) *etcd.Response {
+	return &etcd.Response{
+		Status:  "Success",
+		Message: message,
+	}
+}
+
+func failure(err error) *etcd.Response {
+	log.Error("When access local database:", err)
+	return &etcd.Response{
+		Status: "Failure",
+		Error:  err.Error(),
+	}
+}
+
+func (s *Store) put(key, value string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), []byte(value))
+	})
+}
+
+func (s *Store) Create(req *etcd.Request) *etcd.Response {
+	if err := s.put(req.Url, req.Content); err != nil {
+		return failure(err)
+	}
+	return success(req.Content)
+}
+
+func (s *Store) Get(req *etcd.Request) *etcd.Response {
+	var value []byte
+	s.db.View(func(tx *bolt.Tx) error {
+		// The value is only valid in the transaction.
+		if v := tx.Bucket(bucket).Get([]byte(req.Url)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if value == nil {
+		return &etcd.Response{
+			Status: "Failure",
+			Error:  "Wrong resource uuid provided!",
+		}
+	}
+	return success(string(value))
+}
+
+func (s *Store) List(req *etcd.Request) *etcd.Response {
+	var message = []string{}
+	s.db.View(func(tx *bolt.Tx) error {
+		prefix := []byte(req.Url)
+		cur := tx.Bucket(bucket).Cursor()
+		for k, v := cur.Seek(prefix); k != nil && strings.HasPrefix(string(k), req.Url); k, v = cur.Next() {
+			message = append(message, string(v))
+		}
+		return nil
+	})
+	return success(message...)
+}
+
+func (s *Store) Update(req *etcd.Request) *etcd.Response {
+	if err := s.put(req.Url, req.NewContent); err != nil {
+		return failure(err)
+	}
+	return success(req.NewContent)
+}
+
+func (s *Store) Delete(req *etcd.Request) *etcd.Response {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(req.Url))
+	})
+	if err != nil {
+		return failure(err)
+	}
+	return success()
+}
+
+// Modify replaces the content of url with the result of modify in a single
+// transaction, the writes of bbolt are serialized so it never conflicts.
+func (s *Store) Modify(url string, modify func(content string) (string, error)) (string, error) {
+	var newContent string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		v := b.Get([]byte(url))
+		if v == nil {
+			return errors.New("Wrong resource uuid provided!")
+		}
+		var err error
+		if newContent, err = modify(string(v)); err != nil {
+			return err
+		}
+		return b.Put([]byte(url), []byte(newContent))
+	})
+	if err != nil {
+		return "", err
+	}
+	return newContent, nil
+}
+
+// Export writes all the resources to w as a stream of json encoded
+// etcd.Request, and returns the number of them.
+func (s *Store) Export(w io.Writer) (int, error) {
+	var n int
+	err := s.db.View(func(tx *bolt.Tx) error {
+		enc := json.NewEncoder(w)
+		return tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+			n++
+			return enc.Encode(&etcd.Request{Url: string(k), Content: string(v)})
+		})
+	})
+	return n, err
+}
+
+// Import creates the resources exported by Export in s, which is either a
+// local store or etcd, and returns the number of them. The existing resources
+// with the same urls are overwritten.
+func Import(s etcd.Store, r io.Reader) (int, error) {
+	var n int
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var req etcd.Request
+		if err := dec.Decode(&req); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("decode resource %d: %v", n+1, err)
+		}
+		if req.Url == "" {
+			return n, fmt.Errorf("url of resource %d is empty", n+1)
+		}
+		if res := s.Create(&req); res.Status != "Success" {
+			return n, fmt.Errorf("create %s: %s", req.Url, res.Error)
+		}
+		n++
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/db/drivers/etcd"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils/urls"
+)
+
+func openStore(t *testing.T) (*Store, string, func()) {
+	dir, err := ioutil.TempDir("", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "db", "opensds.db")
+	s, err := Open(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return s, path, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s, path, cleanup := openStore(t)
+	defer cleanup()
+
+	for _, kv := range [][2]string{{"/a/1", "1"}, {"/a/2", "2"}, {"/b/1", "3"}} {
+		if res := s.Create(&etcd.Request{Url: kv[0], Content: kv[1]}); res.Status != "Success" {
+			t.Fatal(res.Error)
+		}
+	}
+	if res := s.Get(&etcd.Request{Url: "/a/1"}); res.Status != "Success" || res.Message[0] != "1" {
+		t.Errorf("Unexpected response %+v", res)
+	}
+	if res := s.Get(&etcd.Request{Url: "/a/3"}); res.Status != "Failure" {
+		t.Errorf("Expected failure of missing key, got %+v", res)
+	}
+	if res := s.List(&etcd.Request{Url: "/a"}); !reflect.DeepEqual(res.Message, []string{"1", "2"}) {
+		t.Errorf("Expected the values with prefix /a, got %v", res.Message)
+	}
+	if res := s.List(&etcd.Request{Url: "/c"}); res.Status != "Success" || len(res.Message) != 0 {
+		t.Errorf("Expected empty list, got %+v", res)
+	}
+	if res := s.Update(&etcd.Request{Url: "/a/2", NewContent: "4"}); res.Status != "Success" {
+		t.Fatal(res.Error)
+	}
+	content, err := s.Modify("/a/2", func(content string) (string, error) { return content + "5", nil })
+	if err != nil || content != "45" {
+		t.Errorf("Expected 45, got %s, %v", content, err)
+	}
+	if _, err = s.Modify("/a/3", func(content string) (string, error) { return content, nil }); err == nil {
+		t.Error("Expected error of missing key")
+	}
+	if res := s.Delete(&etcd.Request{Url: "/b/1"}); res.Status != "Success" {
+		t.Fatal(res.Error)
+	}
+
+	// The file is locked by the open store.
+	openTimeout = 100 * time.Millisecond
+	if _, err = Open(path); err == nil {
+		t.Error("Expected error of locked database")
+	}
+
+	// The writes are kept after reopening.
+	s.Close()
+	if s, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if res := s.List(&etcd.Request{Url: "/"}); !reflect.DeepEqual(res.Message, []string{"1", "45"}) {
+		t.Errorf("Expected the writes to be kept, got %v", res.Message)
+	}
+	s.Close()
+}
+
+func TestClient(t *testing.T) {
+	s, _, cleanup := openStore(t)
+	defer cleanup()
+	cli := etcd.NewClientWithStore(s)
+	ctx := c.NewAdminContext()
+
+	for _, dck := range []*model.DockSpec{
+		{BaseModel: &model.BaseModel{Id: "d1"}, Name: "b", Status: "available"},
+		{BaseModel: &model.BaseModel{Id: "d2"}, Name: "a", Status: "available"},
+		{BaseModel: &model.BaseModel{Id: "d3"}, Name: "c", Status: "unavailable"},
+	} {
+		if _, err := cli.CreateDock(ctx, dck); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dcks, err := cli.ListDocksWithFilter(ctx, map[string][]string{"status": {"available"}, "sortKey": {"name"}, "sortDir": {"asc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dcks) != 2 || dcks[0].Id != "d2" || dcks[1].Id != "d1" {
+		t.Errorf("Expected d2 and d1, got %v", dcks)
+	}
+	dck, err := cli.UpdateDock(ctx, "d3", "", "updated")
+	if err != nil || dck.Description != "updated" {
+		t.Errorf("Unexpected updated dock %+v, %v", dck, err)
+	}
+
+	// The pair status of the replication of any tenant is updated by admin.
+	url := urls.GenerateReplicationURL(urls.Etcd, "tenant1", "r1")
+	if res := s.Create(&etcd.Request{Url: url, Content: `{"id": "r1", "tenantId": "tenant1", "replicationStatus": "enabled"}`}); res.Status != "Success" {
+		t.Fatal(res.Error)
+	}
+	st := &model.ReplicationPairStatus{Id: "r1", Healthy: true}
+	if err = cli.UpdateReplicationPairStatus(ctx, "r1", st); err != nil {
+		t.Fatal(err)
+	}
+	reps, err := cli.ListReplication(ctx)
+	if err != nil || len(reps) != 1 || !reflect.DeepEqual(reps[0].PairStatus, st) || reps[0].ReplicationStatus != "enabled" {
+		t.Errorf("Expected pair status of r1 to be updated, got %v, %v", reps, err)
+	}
+
+	// A standalone dock has no leases or distributed locks.
+	if err = cli.KeepDockAlive("d1", time.Minute); err != etcd.ErrLeaseNotSupported {
+		t.Errorf("Expected %v, got %v", etcd.ErrLeaseNotSupported, err)
+	}
+	if _, err = cli.Lock(context.Background(), "volumes/v1", time.Minute); err != etcd.ErrLockNotSupported {
+		t.Errorf("Expected %v, got %v", etcd.ErrLockNotSupported, err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src, _, cleanup := openStore(t)
+	defer cleanup()
+	cli := etcd.NewClientWithStore(src)
+	ctx := c.NewAdminContext()
+	if _, err := cli.CreateDock(ctx, &model.DockSpec{BaseModel: &model.BaseModel{Id: "d1"}, Name: "dock"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.CreatePool(ctx, &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "p1"}, DockId: "d1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if n, err := src.Export(&buf); err != nil || n != 2 {
+		t.Fatalf("Expected 2 resources exported, got %d, %v", n, err)
+	}
+
+	dst, _, cleanup2 := openStore(t)
+	defer cleanup2()
+	if n, err := Import(dst, &buf); err != nil || n != 2 {
+		t.Fatalf("Expected 2 resources imported, got %d, %v", n, err)
+	}
+	url := urls.GeneratePoolURL(urls.Etcd, "", "p1")
+	if res := dst.Get(&etcd.Request{Url: url}); res.Status != "Success" {
+		t.Errorf("Expected %s to be imported, got %+v", url, res)
+	}
+	dck, err := etcd.NewClientWithStore(dst).GetDockByPoolId(ctx, "p1")
+	if err != nil || dck.Name != "dock" {
+		t.Errorf("Unexpected dock %+v, %v", dck, err)
+	}
+
+	if _, err = Import(dst, strings.NewReader(`{"url": "/a", "content": "1"}`+"\n{")); err == nil {
+		t.Error("Expected error of malformed export")
+	}
+	if _, err = Import(dst, strings.NewReader(`{"content": "1"}`)); err == nil {
+		t.Error("Expected error of empty url")
+	}
+}
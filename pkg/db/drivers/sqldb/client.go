@@ -0,0 +1,158 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the sql database operation of data structure
+defined in api module.
+
+*/
+
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	log "github.com/golang/glog"
+	_ "github.com/lib/pq"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils/config"
+)
+
+// conflictRetryNum is the number of times a transaction is retried when the
+// resources it updates are changed by others in the meantime.
+const conflictRetryNum = 5
+
+// errConflict is returned inside a transaction when a resource is changed by
+// others since it was read.
+var errConflict = errors.New("resource is changed by others")
+
+// dialect describes how a supported database differs from the others.
+type dialect struct {
+	// The name of the database/sql driver.
+	driver string
+	// Whether the placeholders are numbered like $1 instead of ?.
+	numbered bool
+	// The column type of the json encoded resources.
+	text string
+	// The maximum number of open connections, 0 means unlimited.
+	maxOpenConns int
+	// Whether an insert updates the row of a duplicate key by ON DUPLICATE
+	// KEY UPDATE instead of ON CONFLICT.
+	duplicateKey bool
+}
+
+var dialects = map[string]*dialect{
+	"mysql":    {driver: "mysql", text: "LONGTEXT", duplicateKey: true},
+	"postgres": {driver: "postgres", numbered: true, text: "TEXT"},
+	// sqlite only allows a single writer, and the driver is not linked into
+	// the binaries, so it is mainly used for testing.
+	"sqlite3": {driver: "sqlite3", text: "TEXT", maxOpenConns: 1},
+}
+
+// rebind converts the ? placeholders of query to the ones of the dialect.
+func (d *dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// NewClient connects to the database of db.Driver with db.Credential as the
+// data source name, and upgrades its schema to the latest version.
+func NewClient(db *config.Database) *Client {
+	cli, err := Open(db.Driver, db.Credential)
+	if err != nil {
+		log.Error("When open sql database:", err)
+		panic(err)
+	}
+	return cli
+}
+
+// Open connects to the database with the given driver and data source name,
+// and upgrades its schema to the latest version.
+func Open(driver, dsn string) (*Client, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("sql database driver %s is not supported", driver)
+	}
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(d.maxOpenConns)
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	c := &Client{db: db, dialect: d}
+	if err = c.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Client
+type Client struct {
+	db      *sql.DB
+	dialect *dialect
+}
+
+// Close closes the connections to the database.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// transact runs fn in a transaction, and retries it if the resources it
+// updates are changed by others in the meantime.
+func (c *Client) transact(fn func(tx *sql.Tx) error) error {
+	for i := 0; i < conflictRetryNum; i++ {
+		tx, err := c.db.Begin()
+		if err != nil {
+			return err
+		}
+		if err = fn(tx); err != nil {
+			tx.Rollback()
+			if err == errConflict {
+				continue
+			}
+			return err
+		}
+		return tx.Commit()
+	}
+	return model.NewConflictError(fmt.Sprintf("resources keep being changed by others after %d retries", conflictRetryNum))
+}
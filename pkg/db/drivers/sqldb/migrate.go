@@ -0,0 +1,175 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// migration upgrades the schema from the previous version to version. The
+// {text} in the statements is replaced with the large text column type of the
+// dialect.
+type migration struct {
+	version     int
+	description string
+	stmts       []string
+}
+
+// migrations are applied in order, and must never be changed once released;
+// add a new one to change the schema instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create the resource tables",
+		stmts: []string{
+			`CREATE TABLE docks (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				status VARCHAR(64) NOT NULL,
+				endpoint VARCHAR(255) NOT NULL,
+				driver_name VARCHAR(255) NOT NULL,
+				description {text} NOT NULL,
+				created_at VARCHAR(64) NOT NULL,
+				revision BIGINT NOT NULL,
+				data {text} NOT NULL)`,
+			`CREATE TABLE pools (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				status VARCHAR(64) NOT NULL,
+				availability_zone VARCHAR(255) NOT NULL,
+				dock_id VARCHAR(255) NOT NULL,
+				created_at VARCHAR(64) NOT NULL,
+				revision BIGINT NOT NULL,
+				data {text} NOT NULL)`,
+			`CREATE TABLE volumes (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				tenant_id VARCHAR(255) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				status VARCHAR(64) NOT NULL,
+				availability_zone VARCHAR(255) NOT NULL,
+				size BIGINT NOT NULL,
+				pool_id VARCHAR(255) NOT NULL,
+				group_id VARCHAR(255) NOT NULL,
+				description {text} NOT NULL,
+				created_at VARCHAR(64) NOT NULL,
+				revision BIGINT NOT NULL,
+				data {text} NOT NULL)`,
+			`CREATE TABLE volume_groups (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				tenant_id VARCHAR(255) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				status VARCHAR(64) NOT NULL,
+				availability_zone VARCHAR(255) NOT NULL,
+				pool_id VARCHAR(255) NOT NULL,
+				description {text} NOT NULL,
+				created_at VARCHAR(64) NOT NULL,
+				revision BIGINT NOT NULL,
+				data {text} NOT NULL)`,
+		},
+	},
+	{
+		version:     2,
+		description: "index the resources by their owners",
+		stmts: []string{
+			`CREATE INDEX pools_dock_id ON pools (dock_id)`,
+			`CREATE INDEX volumes_tenant_id ON volumes (tenant_id)`,
+			`CREATE INDEX volumes_group_id ON volumes (group_id)`,
+			`CREATE INDEX volume_groups_tenant_id ON volume_groups (tenant_id)`,
+		},
+	},
+	{
+		version:     3,
+		description: "create the replications table",
+		stmts: []string{
+			`CREATE TABLE replications (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				tenant_id VARCHAR(255) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				pool_id VARCHAR(255) NOT NULL,
+				created_at VARCHAR(64) NOT NULL,
+				revision BIGINT NOT NULL,
+				data {text} NOT NULL)`,
+			`CREATE INDEX replications_tenant_id ON replications (tenant_id)`,
+		},
+	},
+}
+
+// SchemaVersion returns the version of the schema in the database, 0 means
+// no migration is applied yet.
+func (c *Client) SchemaVersion() (int, error) {
+	return c.schemaVersion(c.db)
+}
+
+func (c *Client) schemaVersion(q queryer) (int, error) {
+	var version int
+	err := q.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// migrate applies the migrations newer than the schema version in the
+// database, each in its own transaction. Note that mysql commits the schema
+// changes implicitly, so a migration failed halfway there has to be fixed by
+// hand.
+func (c *Client) migrate() error {
+	if _, err := c.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER NOT NULL PRIMARY KEY,
+		description VARCHAR(255) NOT NULL,
+		applied_at VARCHAR(64) NOT NULL)`); err != nil {
+		return err
+	}
+
+	current, err := c.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	latest := migrations[len(migrations)-1].version
+	if current > latest {
+		return fmt.Errorf("schema version %d of the database is newer than the supported %d", current, latest)
+	}
+
+	r := strings.NewReplacer("{text}", c.dialect.text)
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		tx, err := c.db.Begin()
+		if err != nil {
+			return err
+		}
+		for _, stmt := range m.stmts {
+			if _, err = tx.Exec(r.Replace(stmt)); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			_, err = tx.Exec(c.dialect.rebind(`INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)`),
+				m.version, m.description, time.Now().Format(constants.TimeFormat))
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate schema to version %d: %v", m.version, err)
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+		log.Infof("Migrated sql schema to version %d: %s", m.version, m.description)
+	}
+	return nil
+}
@@ -0,0 +1,501 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	log "github.com/golang/glog"
+	uuid "github.com/satori/go.uuid"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// tenantConds restricts the tenant resources to the ones of the tenant in ctx,
+// unless it is an admin context.
+func tenantConds(ctx *c.Context) []cond {
+	if ctx.IsAdmin {
+		return nil
+	}
+	return []cond{{"tenant_id", ctx.TenantId}}
+}
+
+// CreateDock
+func (c *Client) CreateDock(ctx *c.Context, dck *model.DockSpec) (*model.DockSpec, error) {
+	if dck.BaseModel == nil {
+		dck.BaseModel = &model.BaseModel{}
+	}
+	if dck.Id == "" {
+		dck.Id = uuid.NewV4().String()
+	}
+	if dck.CreatedAt == "" {
+		dck.CreatedAt = time.Now().Format(constants.TimeFormat)
+	}
+
+	// Docks are registered again on every discovery, so the existing one is
+	// replaced.
+	if err := c.upsert(c.db, docks, dck); err != nil {
+		log.Error("When create dock in db:", err)
+		return nil, err
+	}
+	return dck, nil
+}
+
+// GetDock
+func (c *Client) GetDock(ctx *c.Context, dckID string) (*model.DockSpec, error) {
+	var dck = &model.DockSpec{}
+	if _, err := c.get(c.db, docks, dckID, dck); err != nil {
+		log.Error("When get dock in db:", err)
+		return nil, err
+	}
+	return dck, nil
+}
+
+// GetDockByPoolId
+func (c *Client) GetDockByPoolId(ctx *c.Context, poolId string) (*model.DockSpec, error) {
+	var pol = &model.StoragePoolSpec{}
+	if _, err := c.get(c.db, pools, poolId, pol); err != nil {
+		log.Error("Get pool failed in db: ", err)
+		return nil, err
+	}
+	return c.GetDock(ctx, pol.DockId)
+}
+
+// ListDocks
+func (c *Client) ListDocks(ctx *c.Context) ([]*model.DockSpec, error) {
+	return c.listDocks(nil, false)
+}
+
+func (c *Client) ListDocksWithFilter(ctx *c.Context, m map[string][]string) ([]*model.DockSpec, error) {
+	return c.listDocks(m, true)
+}
+
+func (c *Client) listDocks(m map[string][]string, paginate bool) ([]*model.DockSpec, error) {
+	objs, err := c.list(c.db, docks, m, paginate)
+	if err != nil {
+		log.Error("When list docks in db:", err)
+		return nil, err
+	}
+	var dcks = []*model.DockSpec{}
+	for _, obj := range objs {
+		dcks = append(dcks, obj.(*model.DockSpec))
+	}
+	return dcks, nil
+}
+
+// UpdateDock
+func (c *Client) UpdateDock(ctx *c.Context, dckID, name, desp string) (*model.DockSpec, error) {
+	var dck *model.DockSpec
+	err := c.transact(func(tx *sql.Tx) error {
+		dck = &model.DockSpec{}
+		return c.update(tx, docks, dckID, dck, func() error {
+			if name != "" {
+				dck.Name = name
+			}
+			if desp != "" {
+				dck.Description = desp
+			}
+			dck.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Error("When update dock in db:", err)
+		return nil, err
+	}
+	return dck, nil
+}
+
+// UpdateDockStatus
+func (c *Client) UpdateDockStatus(ctx *c.Context, dckID, status, reason string, check func(*model.DockSpec) (bool, error)) (bool, error) {
+	var updated bool
+	err := c.transact(func(tx *sql.Tx) error {
+		dck := &model.DockSpec{}
+		return c.update(tx, docks, dckID, dck, func() error {
+			var err error
+			if updated, err = check(dck); err != nil || !updated {
+				return err
+			}
+			dck.Status, dck.StatusReason = status, reason
+			dck.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Error("When update dock status in db:", err)
+		return false, err
+	}
+	return updated, nil
+}
+
+// DeleteDock
+func (c *Client) DeleteDock(ctx *c.Context, dckID string) error {
+	if err := c.delete(c.db, docks, dckID); err != nil {
+		log.Error("When delete dock in db:", err)
+		return err
+	}
+	return nil
+}
+
+// CreatePool
+func (c *Client) CreatePool(ctx *c.Context, pol *model.StoragePoolSpec) (*model.StoragePoolSpec, error) {
+	if pol.BaseModel == nil {
+		pol.BaseModel = &model.BaseModel{}
+	}
+	if pol.Id == "" {
+		pol.Id = uuid.NewV4().String()
+	}
+	if pol.CreatedAt == "" {
+		pol.CreatedAt = time.Now().Format(constants.TimeFormat)
+	}
+
+	if err := c.upsert(c.db, pools, pol); err != nil {
+		log.Error("When create pol in db:", err)
+		return nil, err
+	}
+	return pol, nil
+}
+
+// GetPool
+func (c *Client) GetPool(ctx *c.Context, polID string) (*model.StoragePoolSpec, error) {
+	var pol = &model.StoragePoolSpec{}
+	if _, err := c.get(c.db, pools, polID, pol); err != nil {
+		log.Error("When get pool in db:", err)
+		return nil, err
+	}
+	return pol, nil
+}
+
+// ListPools
+func (c *Client) ListPools(ctx *c.Context) ([]*model.StoragePoolSpec, error) {
+	return c.listPools(nil, false)
+}
+
+func (c *Client) ListPoolsWithFilter(ctx *c.Context, m map[string][]string) ([]*model.StoragePoolSpec, error) {
+	return c.listPools(m, true)
+}
+
+func (c *Client) listPools(m map[string][]string, paginate bool) ([]*model.StoragePoolSpec, error) {
+	objs, err := c.list(c.db, pools, m, paginate)
+	if err != nil {
+		log.Error("When list pools in db:", err)
+		return nil, err
+	}
+	var pols = []*model.StoragePoolSpec{}
+	for _, obj := range objs {
+		pols = append(pols, obj.(*model.StoragePoolSpec))
+	}
+	return pols, nil
+}
+
+// UpdatePool
+func (c *Client) UpdatePool(ctx *c.Context, polID, name, desp string, usedCapacity int64, used bool) (*model.StoragePoolSpec, error) {
+	var pol *model.StoragePoolSpec
+	err := c.transact(func(tx *sql.Tx) error {
+		pol = &model.StoragePoolSpec{}
+		return c.update(tx, pools, polID, pol, func() error {
+			if name != "" {
+				pol.Name = name
+			}
+			if desp != "" {
+				pol.Description = desp
+			}
+			pol.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Error("When update pool in db:", err)
+		return nil, err
+	}
+	return pol, nil
+}
+
+// UpdatePoolStatus
+func (c *Client) UpdatePoolStatus(ctx *c.Context, polID, status, reason string, check func(*model.StoragePoolSpec) (bool, error)) (bool, error) {
+	var updated bool
+	err := c.transact(func(tx *sql.Tx) error {
+		pol := &model.StoragePoolSpec{}
+		return c.update(tx, pools, polID, pol, func() error {
+			var err error
+			if updated, err = check(pol); err != nil || !updated {
+				return err
+			}
+			pol.Status, pol.StatusReason = status, reason
+			pol.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		})
+	})
+	if err != nil {
+		log.Error("When update pool status in db:", err)
+		return false, err
+	}
+	return updated, nil
+}
+
+// DeletePool
+func (c *Client) DeletePool(ctx *c.Context, polID string) error {
+	if err := c.delete(c.db, pools, polID); err != nil {
+		log.Error("When delete pool in db:", err)
+		return err
+	}
+	return nil
+}
+
+// CreateVolume
+func (c *Client) CreateVolume(ctx *c.Context, vol *model.VolumeSpec) (*model.VolumeSpec, error) {
+	if vol.BaseModel == nil {
+		vol.BaseModel = &model.BaseModel{}
+	}
+	if vol.Id == "" {
+		vol.Id = uuid.NewV4().String()
+	}
+	if vol.CreatedAt == "" {
+		vol.CreatedAt = time.Now().Format(constants.TimeFormat)
+	}
+	if vol.TenantId == "" {
+		vol.TenantId = ctx.TenantId
+	}
+
+	if err := c.insert(c.db, volumes, vol); err != nil {
+		log.Error("When create volume in db:", err)
+		return nil, err
+	}
+	return vol, nil
+}
+
+// GetVolume
+func (c *Client) GetVolume(ctx *c.Context, volID string) (*model.VolumeSpec, error) {
+	var vol = &model.VolumeSpec{}
+	if _, err := c.get(c.db, volumes, volID, vol, tenantConds(ctx)...); err != nil {
+		log.Error("When get volume in db:", err)
+		return nil, err
+	}
+	return vol, nil
+}
+
+// ListVolumes
+func (c *Client) ListVolumes(ctx *c.Context) ([]*model.VolumeSpec, error) {
+	return c.listVolumes(ctx, nil, false)
+}
+
+func (c *Client) ListVolumesWithFilter(ctx *c.Context, m map[string][]string) ([]*model.VolumeSpec, error) {
+	return c.listVolumes(ctx, m, true)
+}
+
+func (c *Client) ListVolumesByGroupId(ctx *c.Context, vgId string) ([]*model.VolumeSpec, error) {
+	return c.listVolumes(ctx, map[string][]string{"groupId": {vgId}}, false)
+}
+
+func (c *Client) listVolumes(ctx *c.Context, m map[string][]string, paginate bool) ([]*model.VolumeSpec, error) {
+	objs, err := c.list(c.db, volumes, m, paginate, tenantConds(ctx)...)
+	if err != nil {
+		log.Error("When list volumes in db:", err)
+		return nil, err
+	}
+	var vols = []*model.VolumeSpec{}
+	for _, obj := range objs {
+		vols = append(vols, obj.(*model.VolumeSpec))
+	}
+	return vols, nil
+}
+
+// DeleteVolume
+func (c *Client) DeleteVolume(ctx *c.Context, volID string) error {
+	if err := c.delete(c.db, volumes, volID, tenantConds(ctx)...); err != nil {
+		log.Error("When delete volume in db:", err)
+		return err
+	}
+	return nil
+}
+
+// CreateVolumeGroup
+func (c *Client) CreateVolumeGroup(ctx *c.Context, vg *model.VolumeGroupSpec) (*model.VolumeGroupSpec, error) {
+	if vg.BaseModel == nil {
+		vg.BaseModel = &model.BaseModel{}
+	}
+	if vg.Id == "" {
+		vg.Id = uuid.NewV4().String()
+	}
+	if vg.CreatedAt == "" {
+		vg.CreatedAt = time.Now().Format(constants.TimeFormat)
+	}
+	vg.TenantId = ctx.TenantId
+
+	if err := c.insert(c.db, volumeGroups, vg); err != nil {
+		log.Error("When create volume group in db:", err)
+		return nil, err
+	}
+	return vg, nil
+}
+
+// GetVolumeGroup
+func (c *Client) GetVolumeGroup(ctx *c.Context, vgId string) (*model.VolumeGroupSpec, error) {
+	var vg = &model.VolumeGroupSpec{}
+	if _, err := c.get(c.db, volumeGroups, vgId, vg, tenantConds(ctx)...); err != nil {
+		log.Error("When get volume group in db:", err)
+		return nil, err
+	}
+	return vg, nil
+}
+
+// DeleteVolumeGroup
+func (c *Client) DeleteVolumeGroup(ctx *c.Context, vgId string) error {
+	if err := c.delete(c.db, volumeGroups, vgId, tenantConds(ctx)...); err != nil {
+		log.Error("When delete volume group in db:", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateGroupSnapshots
+func (c *Client) UpdateGroupSnapshots(ctx *c.Context, vgId string, add, remove []string) (*model.VolumeGroupSpec, error) {
+	vg := &model.VolumeGroupSpec{}
+	err := c.transact(func(tx *sql.Tx) error {
+		return c.update(tx, volumeGroups, vgId, vg, func() error {
+			vg.UpdateGroupSnapshots(add, remove)
+			vg.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		}, tenantConds(ctx)...)
+	})
+	if err != nil {
+		log.Error("When update group snapshots of volume group in db:", err)
+		return nil, err
+	}
+	return vg, nil
+}
+
+// CreateReplication
+func (c *Client) CreateReplication(ctx *c.Context, replication *model.ReplicationSpec) (*model.ReplicationSpec, error) {
+	if replication.BaseModel == nil {
+		replication.BaseModel = &model.BaseModel{}
+	}
+	if replication.Id == "" {
+		replication.Id = uuid.NewV4().String()
+	}
+	if replication.CreatedAt == "" {
+		replication.CreatedAt = time.Now().Format(constants.TimeFormat)
+	}
+	replication.TenantId = ctx.TenantId
+
+	if err := c.insert(c.db, replications, replication); err != nil {
+		log.Error("When create replication in db:", err)
+		return nil, err
+	}
+	return replication, nil
+}
+
+// ListReplication
+func (c *Client) ListReplication(ctx *c.Context) ([]*model.ReplicationSpec, error) {
+	objs, err := c.list(c.db, replications, nil, false, tenantConds(ctx)...)
+	if err != nil {
+		log.Error("When list replications in db:", err)
+		return nil, err
+	}
+	var reps = []*model.ReplicationSpec{}
+	for _, obj := range objs {
+		reps = append(reps, obj.(*model.ReplicationSpec))
+	}
+	return reps, nil
+}
+
+// UpdateReplicationPairStatus
+func (c *Client) UpdateReplicationPairStatus(ctx *c.Context, replicationId string, status *model.ReplicationPairStatus) error {
+	replication := &model.ReplicationSpec{}
+	err := c.transact(func(tx *sql.Tx) error {
+		return c.update(tx, replications, replicationId, replication, func() error {
+			replication.PairStatus = status
+			return nil
+		}, tenantConds(ctx)...)
+	})
+	if err != nil {
+		log.Error("When update pair status of replication in db:", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateStatus updates the status of a volume, a volume group or a list of
+// volumes in a single transaction, so either all or none of them are
+// updated.
+func (c *Client) UpdateStatus(ctx *c.Context, in interface{}, status string) error {
+	var err error
+	switch obj := in.(type) {
+	case *model.VolumeSpec:
+		err = c.updateVolumesStatus(ctx, []*model.VolumeSpec{obj}, status)
+	case []*model.VolumeSpec:
+		err = c.updateVolumesStatus(ctx, obj, status)
+	case *model.VolumeGroupSpec:
+		err = c.updateVolumeGroupStatus(ctx, obj, status)
+	default:
+		err = fmt.Errorf("updating status of %T is not supported", in)
+	}
+	if err != nil {
+		log.Error("When update status in db:", err)
+	}
+	return err
+}
+
+func (c *Client) updateVolumesStatus(ctx *c.Context, vols []*model.VolumeSpec, status string) error {
+	var results []*model.VolumeSpec
+	err := c.transact(func(tx *sql.Tx) error {
+		results = nil
+		for _, vol := range vols {
+			if vol == nil || vol.BaseModel == nil {
+				return model.NewNotFoundError("volume to update is not found")
+			}
+			result := &model.VolumeSpec{}
+			err := c.update(tx, volumes, vol.Id, result, func() error {
+				result.Status = status
+				result.UpdatedAt = time.Now().Format(constants.TimeFormat)
+				return nil
+			}, tenantConds(ctx)...)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, vol := range vols {
+		vol.Status, vol.UpdatedAt = status, results[i].UpdatedAt
+	}
+	return nil
+}
+
+func (c *Client) updateVolumeGroupStatus(ctx *c.Context, vg *model.VolumeGroupSpec, status string) error {
+	if vg == nil || vg.BaseModel == nil {
+		return model.NewNotFoundError("volume group to update is not found")
+	}
+	result := &model.VolumeGroupSpec{}
+	err := c.transact(func(tx *sql.Tx) error {
+		return c.update(tx, volumeGroups, vg.Id, result, func() error {
+			result.Status = status
+			result.UpdatedAt = time.Now().Format(constants.TimeFormat)
+			return nil
+		}, tenantConds(ctx)...)
+	})
+	if err != nil {
+		return err
+	}
+	vg.Status, vg.UpdatedAt = status, result.UpdatedAt
+	return nil
+}
@@ -0,0 +1,435 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldb
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+// openSqlite opens a client of a new sqlite database, and returns it along
+// with the function to remove the database.
+func openSqlite(t *testing.T) (*Client, func()) {
+	dir, err := ioutil.TempDir("", "sqldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := Open("sqlite3", filepath.Join(dir, "opensds.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return cli, func() {
+		cli.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	query := "SELECT data FROM volumes WHERE id = ? AND tenant_id IN (?, ?)"
+	if got := dialects["mysql"].rebind(query); got != query {
+		t.Errorf("Expected %q, got %q", query, got)
+	}
+	expected := "SELECT data FROM volumes WHERE id = $1 AND tenant_id IN ($2, $3)"
+	if got := dialects["postgres"].rebind(query); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "opensds.db")
+
+	cli, err := Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrations[len(migrations)-1].version
+	if version, err := cli.SchemaVersion(); err != nil || version != latest {
+		t.Errorf("Expected schema version %d, got %d, %v", latest, version, err)
+	}
+	if _, err = cli.CreateDock(c.NewAdminContext(), &model.DockSpec{Name: "dock"}); err != nil {
+		t.Fatal(err)
+	}
+	cli.Close()
+
+	// Reopening the database keeps the data and applies nothing.
+	if cli, err = Open("sqlite3", path); err != nil {
+		t.Fatal(err)
+	}
+	if dcks, err := cli.ListDocks(c.NewAdminContext()); err != nil || len(dcks) != 1 {
+		t.Errorf("Expected the dock to be kept, got %v, %v", dcks, err)
+	}
+
+	// A database migrated by a newer version is refused.
+	if _, err = cli.db.Exec(`INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, 'newer', '')`, latest+1); err != nil {
+		t.Fatal(err)
+	}
+	cli.Close()
+	if cli, err = Open("sqlite3", path); err == nil {
+		cli.Close()
+		t.Error("Expected error of newer schema version")
+	}
+}
+
+func TestDockAndPool(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx := c.NewAdminContext()
+
+	dck, err := cli.CreateDock(ctx, &model.DockSpec{Name: "dock", DriverName: "lvm", Endpoint: "localhost:50050"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dck.Id == "" || dck.CreatedAt == "" {
+		t.Errorf("Expected id and creation time to be set, got %+v", dck.BaseModel)
+	}
+	pol, err := cli.CreatePool(ctx, &model.StoragePoolSpec{
+		BaseModel: &model.BaseModel{Id: "pool"},
+		Name:      "pool",
+		DockId:    dck.Id,
+		Extras:    model.StoragePoolExtraSpec{Advanced: map[string]interface{}{"diskType": "SSD"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := cli.GetPool(ctx, pol.Id); err != nil || !reflect.DeepEqual(got, pol) {
+		t.Errorf("Expected %+v, got %+v, %v", pol, got, err)
+	}
+	if got, err := cli.GetDockByPoolId(ctx, pol.Id); err != nil || !reflect.DeepEqual(got, dck) {
+		t.Errorf("Expected %+v, got %+v, %v", dck, got, err)
+	}
+
+	got, err := cli.UpdateDock(ctx, dck.Id, "", "updated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "dock" || got.Description != "updated" || got.UpdatedAt == "" {
+		t.Errorf("Unexpected updated dock %+v", got)
+	}
+	if got, _ = cli.GetDock(ctx, dck.Id); got.Description != "updated" {
+		t.Errorf("Expected the update to be stored, got %+v", got)
+	}
+	if _, err = cli.UpdatePool(ctx, "missing", "name", "", 0, false); err == nil {
+		t.Error("Expected error of missing pool")
+	} else if _, ok := err.(*model.NotFoundError); !ok {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	// The status is only updated if the check passes on the latest dock.
+	if ok, err := cli.UpdateDockStatus(ctx, dck.Id, model.DockUnavailable, "expired", func(d *model.DockSpec) (bool, error) {
+		return d.Description != "updated", nil
+	}); err != nil || ok {
+		t.Errorf("Expected dock not to be updated, got %v, %v", ok, err)
+	}
+	if ok, err := cli.UpdatePoolStatus(ctx, pol.Id, "unavailable", "expired", func(p *model.StoragePoolSpec) (bool, error) {
+		return p.DockId == dck.Id, nil
+	}); err != nil || !ok {
+		t.Errorf("Expected pool to be updated, got %v, %v", ok, err)
+	}
+	if got, _ := cli.GetPool(ctx, pol.Id); got.Status != "unavailable" || got.StatusReason != "expired" {
+		t.Errorf("Expected the pool status to be stored, got %+v", got)
+	}
+
+	if err = cli.DeletePool(ctx, pol.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err = cli.DeleteDock(ctx, dck.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.GetDock(ctx, dck.Id); err == nil {
+		t.Error("Expected error of deleted dock")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx := c.NewAdminContext()
+
+	for _, status := range []string{"available", model.DockUnavailable} {
+		dck := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dock"}, Name: "dock", Status: status}
+		if _, err := cli.CreateDock(ctx, dck); err != nil {
+			t.Fatal(err)
+		}
+		pol := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool"}, Name: "pool", DockId: "dock", Status: status}
+		if _, err := cli.CreatePool(ctx, pol); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dck := &model.DockSpec{}
+	if revision, err := cli.get(cli.db, docks, "dock", dck); err != nil || revision != 2 {
+		t.Errorf("Expected revision 2, got %d, %v", revision, err)
+	}
+	if dck.Status != model.DockUnavailable {
+		t.Errorf("Expected the dock to be replaced, got %+v", dck)
+	}
+	pols, err := cli.ListPoolsWithFilter(ctx, map[string][]string{"status": {model.DockUnavailable}})
+	if err != nil || len(pols) != 1 {
+		t.Errorf("Expected the pool to be replaced, got %+v, %v", pols, err)
+	}
+}
+
+func TestListWithFilter(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx := c.NewAdminContext()
+
+	for _, dck := range []*model.DockSpec{
+		{BaseModel: &model.BaseModel{Id: "d1"}, Name: "b", Status: "available", StorageType: "block"},
+		{BaseModel: &model.BaseModel{Id: "d2"}, Name: "c", Status: "unavailable", StorageType: "file"},
+		{BaseModel: &model.BaseModel{Id: "d3"}, Name: "a", Status: "available", StorageType: "block"},
+		{BaseModel: &model.BaseModel{Id: "d4"}, Name: "d", Status: "available", StorageType: "file"},
+	} {
+		if _, err := cli.CreateDock(ctx, dck); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(dcks []*model.DockSpec) []string {
+		var ids = []string{}
+		for _, dck := range dcks {
+			ids = append(ids, dck.Id)
+		}
+		return ids
+	}
+	for _, tc := range []struct {
+		params   map[string][]string
+		expected []string
+	}{
+		{map[string][]string{}, []string{"d1", "d2", "d3", "d4"}},
+		{map[string][]string{"status": {"available"}}, []string{"d1", "d3", "d4"}},
+		{map[string][]string{"id": {"d1", "d2"}}, []string{"d1", "d2"}},
+		{map[string][]string{"sortKey": {"name"}}, []string{"d4", "d2", "d1", "d3"}},
+		{map[string][]string{"sortKey": {"NAME"}, "sortDir": {"asc"}}, []string{"d3", "d1", "d2", "d4"}},
+		{map[string][]string{"sortKey": {"unknown"}}, []string{"d1", "d2", "d3", "d4"}},
+		{map[string][]string{"limit": {"2"}, "offset": {"1"}}, []string{"d2", "d3"}},
+		{map[string][]string{"offset": {"-1"}, "limit": {"x"}}, []string{"d1", "d2", "d3", "d4"}},
+		{map[string][]string{"offset": {"x"}}, []string{}},
+		// The filters on the fields without columns are applied in memory.
+		{map[string][]string{"storageType": {"block"}, "status": {"available"}, "sortKey": {"name"}, "sortDir": {"asc"}}, []string{"d3", "d1"}},
+		{map[string][]string{"storageType": {"file"}, "limit": {"1"}, "offset": {"1"}}, []string{"d4"}},
+	} {
+		dcks, err := cli.ListDocksWithFilter(ctx, tc.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(dcks); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected %v with %v, got %v", tc.expected, tc.params, got)
+		}
+	}
+}
+
+func TestVolumeTenancy(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx1, ctx2 := &c.Context{TenantId: "t1"}, &c.Context{TenantId: "t2"}
+
+	for i, size := range []int64{1, 2, 3} {
+		ctx := ctx1
+		if i == 2 {
+			ctx = ctx2
+		}
+		if _, err := cli.CreateVolume(ctx, &model.VolumeSpec{Size: size, GroupId: "vg"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vols, err := cli.ListVolumes(ctx1)
+	if err != nil || len(vols) != 2 {
+		t.Fatalf("Expected 2 volumes of tenant t1, got %v, %v", vols, err)
+	}
+	if vols, _ := cli.ListVolumes(c.NewAdminContext()); len(vols) != 3 {
+		t.Errorf("Expected 3 volumes of all tenants, got %d", len(vols))
+	}
+	if vols, _ := cli.ListVolumesWithFilter(ctx1, map[string][]string{"size": {"2", "3"}}); len(vols) != 1 || vols[0].Size != 2 {
+		t.Errorf("Expected the volume of size 2, got %v", vols)
+	}
+	if vols, _ := cli.ListVolumesWithFilter(ctx1, map[string][]string{"size": {"x"}}); len(vols) != 0 {
+		t.Errorf("Expected no volumes of invalid size, got %v", vols)
+	}
+	if vols, _ := cli.ListVolumesByGroupId(ctx2, "vg"); len(vols) != 1 {
+		t.Errorf("Expected 1 volume of group vg, got %v", vols)
+	}
+
+	if _, err = cli.GetVolume(ctx2, vols[0].Id); err == nil {
+		t.Error("Expected error of getting the volume of other tenants")
+	}
+	if _, err = cli.GetVolume(c.NewAdminContext(), vols[0].Id); err != nil {
+		t.Error(err)
+	}
+	if err = cli.DeleteVolume(ctx2, vols[0].Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.GetVolume(ctx1, vols[0].Id); err != nil {
+		t.Errorf("Expected the volume to be kept, got %v", err)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx := c.NewAdminContext()
+
+	vg, err := cli.CreateVolumeGroup(ctx, &model.VolumeGroupSpec{Name: "vg", Status: "creating"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cli.UpdateStatus(ctx, vg, "available"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cli.GetVolumeGroup(ctx, vg.Id); got.Status != "available" || vg.Status != "available" {
+		t.Errorf("Expected status available, got %s and %s", got.Status, vg.Status)
+	}
+
+	var vols []*model.VolumeSpec
+	for i := 0; i < 3; i++ {
+		vol, err := cli.CreateVolume(ctx, &model.VolumeSpec{Status: "available", GroupId: vg.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		vols = append(vols, vol)
+	}
+	if err = cli.UpdateStatus(ctx, vols, "in-use"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := cli.ListVolumesByGroupId(ctx, vg.Id)
+	for _, vol := range got {
+		if vol.Status != "in-use" {
+			t.Errorf("Expected status in-use, got %s", vol.Status)
+		}
+	}
+
+	// None of the volumes is updated if any of them fails.
+	missing := &model.VolumeSpec{BaseModel: &model.BaseModel{Id: "missing"}}
+	if err = cli.UpdateStatus(ctx, append(vols, missing), "error"); err == nil {
+		t.Error("Expected error of missing volume")
+	}
+	got, _ = cli.ListVolumesByGroupId(ctx, vg.Id)
+	for _, vol := range got {
+		if vol.Status != "in-use" {
+			t.Errorf("Expected status in-use to be kept, got %s", vol.Status)
+		}
+	}
+
+	if err = cli.UpdateStatus(ctx, (*model.VolumeSpec)(nil), "error"); err == nil {
+		t.Error("Expected error of nil volume")
+	}
+	if err = cli.UpdateStatus(ctx, &model.DockSpec{}, "error"); err == nil {
+		t.Error("Expected error of unsupported type")
+	}
+}
+
+func TestReplicationPairStatus(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	admin := c.NewAdminContext()
+	tenant := &c.Context{TenantId: "tenant1"}
+
+	r, err := cli.CreateReplication(tenant, &model.ReplicationSpec{Name: "r", ReplicationStatus: "enabled"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := &model.ReplicationPairStatus{Id: r.Id, Healthy: true, RunningStatus: model.ReplicationRunningSynced}
+	if err = cli.UpdateReplicationPairStatus(admin, r.Id, st); err != nil {
+		t.Fatal(err)
+	}
+	reps, err := cli.ListReplication(tenant)
+	if err != nil || len(reps) != 1 {
+		t.Fatalf("Expected the replication of tenant, got %v, %v", reps, err)
+	}
+	if !reflect.DeepEqual(reps[0].PairStatus, st) || reps[0].ReplicationStatus != "enabled" {
+		t.Errorf("Expected pair status %+v to be stored, got %+v", st, reps[0])
+	}
+	if reps, _ = cli.ListReplication(&c.Context{TenantId: "tenant2"}); len(reps) != 0 {
+		t.Errorf("Expected no replication of other tenant, got %v", reps)
+	}
+	if err = cli.UpdateReplicationPairStatus(admin, "missing", st); err == nil {
+		t.Error("Expected error of missing replication")
+	}
+}
+
+func TestConcurrentUpdate(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+	ctx := c.NewAdminContext()
+
+	dck, err := cli.CreateDock(ctx, &model.DockSpec{Name: "dock"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vol, err := cli.CreateVolume(ctx, &model.VolumeSpec{Status: "creating"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := cli.UpdateDock(ctx, dck.Id, "", "updated"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			v := &model.VolumeSpec{BaseModel: &model.BaseModel{Id: vol.Id}}
+			if err := cli.UpdateStatus(ctx, v, "available"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var revision int64
+	if err = cli.db.QueryRow(`SELECT revision FROM volumes WHERE id = ?`, vol.Id).Scan(&revision); err != nil {
+		t.Fatal(err)
+	}
+	if revision != 11 {
+		t.Errorf("Expected revision 11 after 10 updates, got %d", revision)
+	}
+}
+
+func TestTransactConflict(t *testing.T) {
+	cli, cleanup := openSqlite(t)
+	defer cleanup()
+
+	var calls int
+	err := cli.transact(func(tx *sql.Tx) error {
+		calls++
+		return errConflict
+	})
+	if _, ok := err.(*model.ConflictError); !ok {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+	if calls != conflictRetryNum {
+		t.Errorf("Expected %d attempts, got %d", conflictRetryNum, calls)
+	}
+}
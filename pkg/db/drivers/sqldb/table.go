@@ -0,0 +1,380 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqldb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// column is a field of the resource stored in its own column, so that the
+// resources can be filtered and sorted by it in the database.
+type column struct {
+	field, name string
+}
+
+// table stores the json encoded resources of typ along with their columns.
+type table struct {
+	name    string
+	typ     reflect.Type
+	columns []column
+}
+
+var (
+	docks = &table{
+		name: "docks",
+		typ:  reflect.TypeOf(model.DockSpec{}),
+		columns: []column{
+			{"Id", "id"}, {"Name", "name"}, {"Status", "status"}, {"Endpoint", "endpoint"},
+			{"DriverName", "driver_name"}, {"Description", "description"}, {"CreatedAt", "created_at"},
+		},
+	}
+	pools = &table{
+		name: "pools",
+		typ:  reflect.TypeOf(model.StoragePoolSpec{}),
+		columns: []column{
+			{"Id", "id"}, {"Name", "name"}, {"Status", "status"}, {"AvailabilityZone", "availability_zone"},
+			{"DockId", "dock_id"}, {"CreatedAt", "created_at"},
+		},
+	}
+	volumes = &table{
+		name: "volumes",
+		typ:  reflect.TypeOf(model.VolumeSpec{}),
+		columns: []column{
+			{"Id", "id"}, {"TenantId", "tenant_id"}, {"Name", "name"}, {"Status", "status"},
+			{"AvailabilityZone", "availability_zone"}, {"Size", "size"}, {"PoolId", "pool_id"},
+			{"GroupId", "group_id"}, {"Description", "description"}, {"CreatedAt", "created_at"},
+		},
+	}
+	volumeGroups = &table{
+		name: "volume_groups",
+		typ:  reflect.TypeOf(model.VolumeGroupSpec{}),
+		columns: []column{
+			{"Id", "id"}, {"TenantId", "tenant_id"}, {"Name", "name"}, {"Status", "status"},
+			{"AvailabilityZone", "availability_zone"}, {"PoolId", "pool_id"},
+			{"Description", "description"}, {"CreatedAt", "created_at"},
+		},
+	}
+	replications = &table{
+		name: "replications",
+		typ:  reflect.TypeOf(model.ReplicationSpec{}),
+		columns: []column{
+			{"Id", "id"}, {"TenantId", "tenant_id"}, {"Name", "name"}, {"PoolId", "pool_id"},
+			{"CreatedAt", "created_at"},
+		},
+	}
+)
+
+// cond restricts the rows to the ones whose column equals value.
+type cond struct {
+	column string
+	value  interface{}
+}
+
+// where returns the where clause of conds along with its arguments.
+func where(conds []cond) (string, []interface{}) {
+	if len(conds) == 0 {
+		return "", nil
+	}
+	var clauses []string
+	var args []interface{}
+	for _, cd := range conds {
+		clauses = append(clauses, cd.column+" = ?")
+		args = append(args, cd.value)
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// fieldValue returns the value of the named field of the struct v, or the zero
+// value if the field is promoted through a nil embedded pointer.
+func fieldValue(v reflect.Value, name string) reflect.Value {
+	f, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}
+	}
+	for i, idx := range f.Index {
+		if i > 0 {
+			if v.IsNil() {
+				return reflect.Zero(f.Type)
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// values returns the column values of obj in the order of t.columns.
+func (t *table) values(obj interface{}) []interface{} {
+	v := reflect.ValueOf(obj).Elem()
+	var vals []interface{}
+	for _, col := range t.columns {
+		vals = append(vals, fieldValue(v, col.field).Interface())
+	}
+	return vals
+}
+
+func (t *table) names() []string {
+	var names []string
+	for _, col := range t.columns {
+		names = append(names, col.name)
+	}
+	return names
+}
+
+// newObj returns a pointer to a new resource of the table.
+func (t *table) newObj() interface{} {
+	return reflect.New(t.typ).Interface()
+}
+
+func (c *Client) insert(q queryer, t *table, obj interface{}) error {
+	return c.exec(q, t, obj, false)
+}
+
+// upsert inserts obj, or replaces the resource with the same id and bumps its
+// revision, the same way as the etcd client puts the resource.
+func (c *Client) upsert(q queryer, t *table, obj interface{}) error {
+	return c.exec(q, t, obj, true)
+}
+
+func (c *Client) exec(q queryer, t *table, obj interface{}, replace bool) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	names := append(t.names(), "revision", "data")
+	args := append(t.values(obj), 1, string(data))
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?%s)", t.name,
+		strings.Join(names, ", "), strings.Repeat(", ?", len(names)-1))
+	if replace {
+		var sets []string
+		for _, name := range names {
+			switch {
+			case name == "id":
+			case name == "revision":
+				sets = append(sets, fmt.Sprintf("revision = %s.revision + 1", t.name))
+			case c.dialect.duplicateKey:
+				sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", name, name))
+			default:
+				sets = append(sets, fmt.Sprintf("%s = excluded.%s", name, name))
+			}
+		}
+		if c.dialect.duplicateKey {
+			query += " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+		} else {
+			query += " ON CONFLICT (id) DO UPDATE SET " + strings.Join(sets, ", ")
+		}
+	}
+	_, err = q.Exec(c.dialect.rebind(query), args...)
+	return err
+}
+
+// get decodes the resource with id into obj, and returns its revision.
+func (c *Client) get(q queryer, t *table, id string, obj interface{}, conds ...cond) (int64, error) {
+	clause, args := where(append([]cond{{"id", id}}, conds...))
+	var revision int64
+	var data string
+	err := q.QueryRow(c.dialect.rebind("SELECT revision, data FROM "+t.name+clause), args...).Scan(&revision, &data)
+	if err == sql.ErrNoRows {
+		return 0, model.NewNotFoundError(fmt.Sprintf("%s %s is not found", strings.TrimSuffix(t.name, "s"), id))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return revision, json.Unmarshal([]byte(data), obj)
+}
+
+// update decodes the resource with id into obj, applies modify to it and
+// writes it back, or returns errConflict if it is changed by others in the
+// meantime.
+func (c *Client) update(tx *sql.Tx, t *table, id string, obj interface{}, modify func() error, conds ...cond) error {
+	revision, err := c.get(tx, t, id, obj, conds...)
+	if err != nil {
+		return err
+	}
+	if err = modify(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	names := append(t.names(), "revision", "data")
+	args := append(t.values(obj), revision+1, string(data), id, revision)
+	query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ? AND revision = ?", t.name, strings.Join(names, " = ?, "))
+	res, err := tx.Exec(c.dialect.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errConflict
+	}
+	return nil
+}
+
+func (c *Client) delete(q queryer, t *table, id string, conds ...cond) error {
+	clause, args := where(append([]cond{{"id", id}}, conds...))
+	_, err := q.Exec(c.dialect.rebind("DELETE FROM "+t.name+clause), args...)
+	return err
+}
+
+// list returns the resources matching conds and the parameters of the list
+// request, which are filtered, sorted and paginated the same way as the etcd
+// client does. The filters on the stored columns are applied in the database,
+// and the others on the decoded resources. All the resources are returned
+// unless paginate is true.
+func (c *Client) list(q queryer, t *table, params map[string][]string, paginate bool, conds ...cond) ([]interface{}, error) {
+	clause, args := where(conds)
+	var filters []string
+	inMemory := false
+	for key, values := range params {
+		field := strings.Title(key)
+		col := t.column(func(f string) bool { return f == field })
+		if col == nil {
+			if _, ok := t.typ.FieldByName(field); ok {
+				inMemory = true
+			}
+			continue
+		}
+		in, inArgs := t.in(col, values)
+		filters = append(filters, in)
+		args = append(args, inArgs...)
+	}
+	if len(filters) > 0 {
+		if clause == "" {
+			clause = " WHERE "
+		} else {
+			clause += " AND "
+		}
+		clause += strings.Join(filters, " AND ")
+	}
+
+	order := " ORDER BY id"
+	if keys := params["sortKey"]; len(keys) > 0 {
+		if col := t.column(func(f string) bool { return strings.EqualFold(f, keys[0]) }); col != nil {
+			dir := "DESC"
+			if dirs := params["sortDir"]; len(dirs) > 0 && strings.EqualFold(dirs[0], "asc") {
+				dir = "ASC"
+			}
+			order = fmt.Sprintf(" ORDER BY %s %s, id", col.name, dir)
+		}
+	}
+
+	offset, limit, ok := 0, -1, true
+	if paginate {
+		offset, limit, ok = pagination(params)
+	}
+	if !ok {
+		return []interface{}{}, nil
+	}
+	query := "SELECT data FROM " + t.name + clause + order
+	if !inMemory && limit >= 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, offset)
+	}
+
+	rows, err := q.Query(c.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var objs = []interface{}{}
+	for rows.Next() {
+		var data string
+		if err = rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		obj := t.newObj()
+		if err = json.Unmarshal([]byte(data), obj); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if inMemory {
+		objs = utils.Filter(objs, params).([]interface{})
+	}
+	if inMemory && limit >= 0 {
+		objs = utils.Slice(objs, offset, limit).([]interface{})
+	}
+	return objs, nil
+}
+
+func (t *table) column(match func(field string) bool) *column {
+	for i := range t.columns {
+		if match(t.columns[i].field) {
+			return &t.columns[i]
+		}
+	}
+	return nil
+}
+
+// in returns the condition that col equals any of values. The values which
+// can't be converted to the type of the column never match.
+func (t *table) in(col *column, values []string) (string, []interface{}) {
+	f, _ := t.typ.FieldByName(col.field)
+	var args []interface{}
+	for _, value := range values {
+		switch f.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			args = append(args, n)
+		default:
+			args = append(args, value)
+		}
+	}
+	if len(args) == 0 {
+		return "1 = 0", nil
+	}
+	return fmt.Sprintf("%s IN (?%s)", col.name, strings.Repeat(", ?", len(args)-1)), args
+}
+
+// pagination returns the offset and limit of the list request. An invalid
+// offset is out of bounds, so that nothing is listed.
+func pagination(params map[string][]string) (offset, limit int, ok bool) {
+	limit = constants.DefaultLimit
+	if v, found := params["limit"]; found {
+		if n, err := strconv.Atoi(v[0]); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	if v, found := params["offset"]; found {
+		n, err := strconv.Atoi(v[0])
+		if err != nil {
+			return 0, 0, false
+		}
+		if n > 0 {
+			offset = n
+		}
+	}
+	return offset, limit, true
+}
@@ -0,0 +1,455 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the alerting engine of dock, which evaluates threshold
+rules over the output of metric drivers and discovery results, and posts the
+firing and resolved alerts to an Alertmanager compatible endpoint.
+*/
+
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/contrib/drivers"
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+const (
+	alertNameLabel = "alertname"
+	severityLabel  = "severity"
+
+	alertsPath  = "/api/v2/alerts"
+	postTimeout = 10 * time.Second
+)
+
+// collectMetrics collects the metrics of a backend, it is a variable so that
+// it can be faked in unit tests.
+var collectMetrics = func(backend string) ([]*model.MetricSpec, error) {
+	d := drivers.InitMetricDriver(backend)
+	if d == nil {
+		return nil, fmt.Errorf("metric driver of backend %s is not supported", backend)
+	}
+	defer drivers.CleanMetricDriver(d)
+	return d.CollectMetrics()
+}
+
+// listReplicationPairs lists the replication pairs of a backend, it is a
+// variable so that it can be faked in unit tests.
+var listReplicationPairs = func(backend string) ([]*model.ReplicationPairStatus, error) {
+	d, err := drivers.InitReplicationDriver(backend)
+	defer drivers.CleanReplicationDriver(d)
+	if err != nil {
+		return nil, err
+	}
+	return drivers.ListReplicationPairs(d)
+}
+
+// Config is the config of alerting engine.
+type Config struct {
+	// Endpoint is the url of Alertmanager, such as http://localhost:9093.
+	Endpoint string
+	// RulesFile is the path of yaml file of alert rules.
+	RulesFile string
+	// Interval is the interval of rule evaluations.
+	Interval time.Duration
+	// ResendInterval is the interval of resending firing alerts which are
+	// not changed, so that Alertmanager does not resolve them.
+	ResendInterval time.Duration
+	// Labels are added to all the alerts, such as the node of dock.
+	Labels map[string]string
+}
+
+type activeAlert struct {
+	alert    *model.PostableAlertSpec
+	lastSent time.Time
+	resolved bool
+}
+
+// firing is an alert found by an evaluation.
+type firing struct {
+	labels      model.LabelSet
+	annotations model.LabelSet
+}
+
+// Engine evaluates the rules periodically and keeps the state of alerts, so
+// that each alert is posted once when it fires or resolves, and resent only
+// after the resend interval.
+type Engine struct {
+	conf   *Config
+	rules  []*Rule
+	client *http.Client
+
+	// mu guards the latest discovery results.
+	mu    sync.Mutex
+	docks []*model.DockSpec
+	pools []*model.StoragePoolSpec
+
+	// active is only accessed in the evaluation loop.
+	active map[string]*activeAlert
+}
+
+// NewEngine loads the rules and creates the engine, nil is returned if the
+// Alertmanager endpoint is not configured.
+func NewEngine(conf *Config) (*Engine, error) {
+	if conf.Endpoint == "" {
+		return nil, nil
+	}
+	if conf.Interval <= 0 {
+		return nil, fmt.Errorf("alert evaluation interval %v must be positive", conf.Interval)
+	}
+	if conf.ResendInterval <= 0 {
+		return nil, fmt.Errorf("alert resend interval %v must be positive", conf.ResendInterval)
+	}
+	rules, err := LoadRules(conf.RulesFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{
+		conf:   conf,
+		rules:  rules,
+		client: &http.Client{Timeout: postTimeout},
+		active: map[string]*activeAlert{},
+	}, nil
+}
+
+// ObserveDiscovery keeps a copy of the discovery results for the next
+// evaluation.
+func (e *Engine) ObserveDiscovery(dcks []*model.DockSpec, pols []*model.StoragePoolSpec) {
+	docks := make([]*model.DockSpec, 0, len(dcks))
+	for _, dck := range dcks {
+		d := *dck
+		docks = append(docks, &d)
+	}
+	pools := make([]*model.StoragePoolSpec, 0, len(pols))
+	for _, pol := range pols {
+		p := *pol
+		if pol.BaseModel != nil {
+			bm := *pol.BaseModel
+			p.BaseModel = &bm
+		}
+		pools = append(pools, &p)
+	}
+	e.mu.Lock()
+	e.docks, e.pools = docks, pools
+	e.mu.Unlock()
+}
+
+// Run evaluates the rules every interval until the stop channel is closed.
+func (e *Engine) Run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(e.conf.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := e.Evaluate(time.Now()); err != nil {
+				log.Error("Failed to post alerts:", err)
+			}
+		}
+	}
+}
+
+// Evaluate evaluates all the rules and posts the alerts which are changed or
+// due to be resent.
+func (e *Engine) Evaluate(now time.Time) error {
+	e.mu.Lock()
+	docks, pools := e.docks, e.pools
+	e.mu.Unlock()
+
+	found := map[string]*firing{}
+	// The alerts of a rule whose source fails are kept as they are, since
+	// it is unknown whether they are resolved.
+	failed := map[string]bool{}
+	metrics := map[string][]*model.MetricSpec{}
+	for _, r := range e.rules {
+		var fs []*firing
+		var err error
+		switch r.Type {
+		case PoolFreeCapacityRule:
+			fs = evalPoolFreeCapacity(r, docks, pools)
+		case BackendUnreachableRule:
+			fs = evalBackendUnreachable(r, docks)
+		case MetricRule:
+			ms, ok := metrics[r.Backend]
+			if !ok {
+				if ms, err = collectMetrics(r.Backend); err == nil {
+					metrics[r.Backend] = ms
+				}
+			}
+			fs = evalMetric(r, ms)
+		case ReplicationUnhealthyRule:
+			var pairs []*model.ReplicationPairStatus
+			if pairs, err = listReplicationPairs(r.Backend); err == nil {
+				fs = evalReplication(r, pairs)
+			}
+		}
+		if err != nil {
+			log.Errorf("Failed to evaluate alert rule %s: %v", r.Name, err)
+			failed[r.Name] = true
+			continue
+		}
+		for _, f := range fs {
+			e.addLabels(r, f)
+			found[fingerprint(f.labels)] = f
+		}
+	}
+	return e.sync(now, found, failed)
+}
+
+func (e *Engine) addLabels(r *Rule, f *firing) {
+	for k, v := range e.conf.Labels {
+		f.labels[k] = v
+	}
+	for k, v := range r.Labels {
+		f.labels[k] = v
+	}
+	f.labels[alertNameLabel] = r.Name
+	f.labels[severityLabel] = r.Severity
+	for k, v := range r.Annotations {
+		f.annotations[k] = v
+	}
+}
+
+// sync updates the active alerts with the evaluation result, and posts the
+// new, resolved and due ones.
+func (e *Engine) sync(now time.Time, found map[string]*firing, failed map[string]bool) error {
+	var due []*activeAlert
+	for fp, f := range found {
+		a, ok := e.active[fp]
+		if !ok || a.resolved {
+			a = &activeAlert{alert: &model.PostableAlertSpec{
+				StartAt:   now,
+				AlertSpec: model.AlertSpec{Labels: f.labels},
+			}}
+			e.active[fp] = a
+		}
+		a.alert.Annotations = f.annotations
+		if a.lastSent.IsZero() || now.Sub(a.lastSent) >= e.conf.ResendInterval {
+			// Alertmanager resolves the alert if it is not resent in time.
+			a.alert.EndAt = now.Add(3 * e.conf.ResendInterval)
+			due = append(due, a)
+		}
+	}
+	for fp, a := range e.active {
+		if _, ok := found[fp]; ok {
+			continue
+		}
+		if !a.resolved {
+			if failed[a.alert.Labels[alertNameLabel]] {
+				continue
+			}
+			a.resolved = true
+			a.alert.EndAt = now
+		}
+		// The resolved alert is retried until it is posted.
+		due = append(due, a)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	alerts := make([]*model.PostableAlertSpec, 0, len(due))
+	for _, a := range due {
+		alerts = append(alerts, a.alert)
+	}
+	if err := e.post(alerts); err != nil {
+		return err
+	}
+	for fp, a := range e.active {
+		if a.resolved {
+			delete(e.active, fp)
+		}
+	}
+	for _, a := range due {
+		a.lastSent = now
+	}
+	return nil
+}
+
+func (e *Engine) post(alerts []*model.PostableAlertSpec) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	url := strings.TrimRight(e.conf.Endpoint, "/") + alertsPath
+	resp, err := e.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager %s responded %s: %s", url, resp.Status, msg)
+	}
+	log.V(5).Infof("Posted %d alerts to %s", len(alerts), url)
+	return nil
+}
+
+// fingerprint identifies an alert by its labels, so that the same alert
+// found by different evaluations is deduplicated.
+func fingerprint(labels model.LabelSet) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + "\xff" + labels[k] + "\xff")
+	}
+	return b.String()
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// labelName converts a metric label to a valid alert label name.
+func labelName(s string) string {
+	s = invalidLabelChars.ReplaceAllString(s, "_")
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	return s
+}
+
+func evalPoolFreeCapacity(r *Rule, docks []*model.DockSpec, pools []*model.StoragePoolSpec) []*firing {
+	dockMap := map[string]*model.DockSpec{}
+	for _, dck := range docks {
+		dockMap[dck.Id] = dck
+	}
+	var fs []*firing
+	for _, pol := range pools {
+		dck := dockMap[pol.DockId]
+		if dck == nil || pol.Status != model.PoolAvailable || pol.TotalCapacity <= 0 {
+			continue
+		}
+		pct := float64(pol.FreeCapacity) / float64(pol.TotalCapacity) * 100
+		if !r.exceeds(pct) {
+			continue
+		}
+		fs = append(fs, &firing{
+			labels: model.LabelSet{
+				"backend": dck.DriverName,
+				"dock":    dck.Name,
+				"pool":    pol.Name,
+				"pool_id": pol.Id,
+			},
+			annotations: model.LabelSet{
+				"summary": fmt.Sprintf("Free capacity of pool %s is %.1f%%", pol.Name, pct),
+				"description": fmt.Sprintf("Pool %s of backend %s has %d GB free of %d GB, which is %s %g%%.",
+					pol.Name, dck.DriverName, pol.FreeCapacity, pol.TotalCapacity, r.Operator, r.Threshold),
+			},
+		})
+	}
+	return fs
+}
+
+func evalBackendUnreachable(r *Rule, docks []*model.DockSpec) []*firing {
+	var fs []*firing
+	for _, dck := range docks {
+		if dck.Status != model.DockUnavailable {
+			continue
+		}
+		fs = append(fs, &firing{
+			labels: model.LabelSet{
+				"backend": dck.DriverName,
+				"dock":    dck.Name,
+				"dock_id": dck.Id,
+			},
+			annotations: model.LabelSet{
+				"summary":     fmt.Sprintf("Backend %s is unreachable", dck.DriverName),
+				"description": dck.StatusReason,
+			},
+		})
+	}
+	return fs
+}
+
+func matchLabels(match, labels map[string]string) bool {
+	for k, v := range match {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func evalMetric(r *Rule, metrics []*model.MetricSpec) []*firing {
+	var fs []*firing
+	for _, m := range metrics {
+		if m == nil || m.Name != r.Metric || (r.Component != "" && m.Component != r.Component) ||
+			!matchLabels(r.MatchLabels, m.Labels) || len(m.MetricValues) == 0 {
+			continue
+		}
+		latest := m.MetricValues[len(m.MetricValues)-1]
+		if latest == nil || !r.exceeds(latest.Value) {
+			continue
+		}
+		f := &firing{
+			labels: model.LabelSet{},
+			annotations: model.LabelSet{
+				"summary": fmt.Sprintf("%s of %s %s is %g%s", m.Name, m.Component, m.InstanceName,
+					latest.Value, m.Unit),
+				"description": fmt.Sprintf("%s of %s %s on backend %s is %g%s, which is %s %g.",
+					m.Name, m.Component, m.InstanceID, r.Backend, latest.Value, m.Unit, r.Operator, r.Threshold),
+				"value": fmt.Sprintf("%g", latest.Value),
+			},
+		}
+		for k, v := range m.Labels {
+			if k = labelName(k); k != "" {
+				f.labels[k] = v
+			}
+		}
+		f.labels["backend"] = r.Backend
+		f.labels["component"] = m.Component
+		f.labels["instance_id"] = m.InstanceID
+		f.labels["instance_name"] = m.InstanceName
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+func evalReplication(r *Rule, pairs []*model.ReplicationPairStatus) []*firing {
+	var fs []*firing
+	for _, p := range pairs {
+		if p.Healthy {
+			continue
+		}
+		fs = append(fs, &firing{
+			labels: model.LabelSet{
+				"backend":        r.Backend,
+				"replication_id": p.Id,
+				"role":           p.Role,
+			},
+			annotations: model.LabelSet{
+				"summary":     fmt.Sprintf("Replication %s is unhealthy", p.Id),
+				"description": p.Reason,
+			},
+		})
+	}
+	return fs
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+var testRules = `
+rules:
+  - name: PoolFreeCapacityLow
+    type: pool_free_capacity
+    threshold: 10
+  - name: BackendUnreachable
+    type: backend_unreachable
+    severity: critical
+  - name: VolumeLatencyHigh
+    type: metric
+    backend: lvm
+    component: volume
+    metric: response_time
+    threshold: 50
+    labels:
+      team: storage
+  - name: ReplicationUnhealthy
+    type: replication_unhealthy
+    backend: drbd
+`
+
+type fakeAlertmanager struct {
+	mu     sync.Mutex
+	posts  [][]*model.PostableAlertSpec
+	status int
+}
+
+func (am *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	if r.URL.Path != alertsPath {
+		http.NotFound(w, r)
+		return
+	}
+	if am.status != 0 {
+		w.WriteHeader(am.status)
+		return
+	}
+	var alerts []*model.PostableAlertSpec
+	json.NewDecoder(r.Body).Decode(&alerts)
+	am.posts = append(am.posts, alerts)
+}
+
+// last returns the alerts of last post by their names.
+func (am *fakeAlertmanager) last() map[string]*model.PostableAlertSpec {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	m := map[string]*model.PostableAlertSpec{}
+	if len(am.posts) == 0 {
+		return m
+	}
+	for _, a := range am.posts[len(am.posts)-1] {
+		m[a.Labels[alertNameLabel]] = a
+	}
+	return m
+}
+
+func writeRules(t *testing.T, rules string) string {
+	f, err := ioutil.TempFile("", "alert-rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString(rules)
+	return f.Name()
+}
+
+func TestLoadRules(t *testing.T) {
+	path := writeRules(t, testRules)
+	defer os.Remove(path)
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 4 || rules[0].Operator != "<" || rules[2].Operator != ">" ||
+		rules[0].Severity != defaultSeverity || rules[1].Severity != "critical" {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+
+	for _, invalid := range []string{
+		"rules:\n  - name: a\n    type: unknown\n",
+		"rules:\n  - name: a\n    type: metric\n",
+		"rules:\n  - name: a\n    type: pool_free_capacity\n    operator: '=<'\n",
+		"rules:\n  - name: a\n    type: backend_unreachable\n  - name: a\n    type: backend_unreachable\n",
+	} {
+		path := writeRules(t, invalid)
+		if _, err := LoadRules(path); err == nil {
+			t.Errorf("Expected error of rules %q", invalid)
+		}
+		os.Remove(path)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	am := &fakeAlertmanager{}
+	srv := httptest.NewServer(am)
+	defer srv.Close()
+	path := writeRules(t, testRules)
+	defer os.Remove(path)
+
+	var latency float64
+	var metricErr error
+	collectMetrics = func(backend string) ([]*model.MetricSpec, error) {
+		return []*model.MetricSpec{{
+			InstanceID: "vol-1", InstanceName: "vol", Component: "volume", Name: "response_time",
+			Unit: "ms", Labels: map[string]string{"device-name": "dm-0"},
+			MetricValues: []*model.Metric{{Value: 1}, {Value: latency}},
+		}}, metricErr
+	}
+	healthy := true
+	listReplicationPairs = func(backend string) ([]*model.ReplicationPairStatus, error) {
+		return []*model.ReplicationPairStatus{{
+			Id: "rep-1", Role: "primary", Healthy: healthy, Reason: "connection to peer is StandAlone",
+		}}, nil
+	}
+
+	e, err := NewEngine(&Config{
+		Endpoint: srv.URL + "/", RulesFile: path, Interval: time.Minute, ResendInterval: 5 * time.Minute,
+		Labels: map[string]string{"node": "host"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dck := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dock-1"}, Name: "dock", DriverName: "lvm",
+		Status: model.DockAvailable}
+	pol := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-1"}, Name: "pool", DockId: "dock-1",
+		Status: model.PoolAvailable, TotalCapacity: 100, FreeCapacity: 50}
+	e.ObserveDiscovery([]*model.DockSpec{dck}, []*model.StoragePoolSpec{pol})
+
+	// Nothing fires.
+	now := time.Now()
+	if err := e.Evaluate(now); err != nil || len(am.posts) != 0 {
+		t.Fatalf("Expected no post, got %v and %d posts", err, len(am.posts))
+	}
+
+	// All the rules fire.
+	pol.FreeCapacity, dck.Status, dck.StatusReason = 5, model.DockUnavailable, "connection refused"
+	e.ObserveDiscovery([]*model.DockSpec{dck}, []*model.StoragePoolSpec{pol})
+	latency, healthy = 80, false
+	if err := e.Evaluate(now); err != nil {
+		t.Fatal(err)
+	}
+	alerts := am.last()
+	if len(alerts) != 4 {
+		t.Fatalf("Expected 4 firing alerts, got %d", len(alerts))
+	}
+	for name, a := range alerts {
+		if !a.StartAt.Equal(now) || !a.EndAt.Equal(now.Add(15*time.Minute)) || a.Labels["node"] != "host" {
+			t.Errorf("Unexpected alert %s %+v", name, a)
+		}
+	}
+	if a := alerts["PoolFreeCapacityLow"]; a.Labels["pool_id"] != "pool-1" || a.Labels[severityLabel] != defaultSeverity {
+		t.Errorf("Unexpected pool alert %+v", a)
+	}
+	if a := alerts["BackendUnreachable"]; a.Annotations["description"] != "connection refused" ||
+		a.Labels[severityLabel] != "critical" {
+		t.Errorf("Unexpected backend alert %+v", a)
+	}
+	if a := alerts["VolumeLatencyHigh"]; a.Labels["instance_id"] != "vol-1" || a.Labels["device_name"] != "dm-0" ||
+		a.Labels["team"] != "storage" || a.Annotations["value"] != "80" {
+		t.Errorf("Unexpected metric alert %+v", a)
+	}
+	if a := alerts["ReplicationUnhealthy"]; a.Labels["replication_id"] != "rep-1" ||
+		a.Annotations["description"] != "connection to peer is StandAlone" {
+		t.Errorf("Unexpected replication alert %+v", a)
+	}
+
+	// The firing alerts are not posted again until the resend interval.
+	if err := e.Evaluate(now.Add(time.Minute)); err != nil || len(am.posts) != 1 {
+		t.Fatalf("Expected no more post, got %v and %d posts", err, len(am.posts))
+	}
+	resend := now.Add(5 * time.Minute)
+	if err := e.Evaluate(resend); err != nil {
+		t.Fatal(err)
+	}
+	if alerts = am.last(); len(am.posts) != 2 || len(alerts) != 4 ||
+		!alerts["PoolFreeCapacityLow"].StartAt.Equal(now) ||
+		!alerts["PoolFreeCapacityLow"].EndAt.Equal(resend.Add(15*time.Minute)) {
+		t.Fatalf("Expected 4 resent alerts, got %d posts %+v", len(am.posts), alerts)
+	}
+
+	// The latency alert is kept if the metrics can not be collected, and the
+	// others are resolved.
+	pol.FreeCapacity, dck.Status = 50, model.DockAvailable
+	e.ObserveDiscovery([]*model.DockSpec{dck}, []*model.StoragePoolSpec{pol})
+	healthy, metricErr = true, errors.New("iostat failed")
+	resolve := resend.Add(time.Minute)
+	if err := e.Evaluate(resolve); err != nil {
+		t.Fatal(err)
+	}
+	alerts = am.last()
+	if len(alerts) != 3 || alerts["VolumeLatencyHigh"] != nil {
+		t.Fatalf("Expected 3 resolved alerts, got %+v", alerts)
+	}
+	for name, a := range alerts {
+		if !a.EndAt.Equal(resolve) {
+			t.Errorf("Expected alert %s to end at %v, got %v", name, resolve, a.EndAt)
+		}
+	}
+
+	// The resolved alerts are retried until they are posted.
+	latency, metricErr = 1, nil
+	am.status = http.StatusServiceUnavailable
+	if err := e.Evaluate(resolve.Add(time.Minute)); err == nil {
+		t.Fatal("Expected error of unavailable alertmanager")
+	}
+	am.status = 0
+	if err := e.Evaluate(resolve.Add(2 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if alerts = am.last(); len(alerts) != 1 || !alerts["VolumeLatencyHigh"].EndAt.Equal(resolve.Add(time.Minute)) {
+		t.Fatalf("Expected resolved latency alert, got %+v", alerts)
+	}
+	if len(e.active) != 0 {
+		t.Errorf("Expected no active alert, got %d", len(e.active))
+	}
+}
+
+func TestDisabledEngine(t *testing.T) {
+	if e, err := NewEngine(&Config{}); e != nil || err != nil {
+		t.Errorf("Expected no engine, got %v and %v", e, err)
+	}
+}
+
+func TestInvalidIntervals(t *testing.T) {
+	for _, conf := range []*Config{
+		{Endpoint: "http://localhost:9093/", ResendInterval: time.Minute},
+		{Endpoint: "http://localhost:9093/", Interval: -time.Minute, ResendInterval: time.Minute},
+		{Endpoint: "http://localhost:9093/", Interval: time.Minute},
+	} {
+		if _, err := NewEngine(conf); err == nil {
+			t.Errorf("Expected error of invalid intervals in %+v", conf)
+		}
+	}
+}
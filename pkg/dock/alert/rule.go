@@ -0,0 +1,146 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Types of alert rules.
+const (
+	// PoolFreeCapacityRule fires when the free capacity percentage of a pool
+	// found by discovery crosses the threshold.
+	PoolFreeCapacityRule = "pool_free_capacity"
+	// MetricRule fires when the latest value of a metric collected by the
+	// metric driver of backend crosses the threshold.
+	MetricRule = "metric"
+	// ReplicationUnhealthyRule fires when a replication pair reported by the
+	// replication driver of backend is unhealthy.
+	ReplicationUnhealthyRule = "replication_unhealthy"
+	// BackendUnreachableRule fires when discovery can not reach a backend.
+	BackendUnreachableRule = "backend_unreachable"
+
+	defaultSeverity = "warning"
+)
+
+// Rule is a threshold rule, the alerts of a rule are named after it.
+type Rule struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Backend is the driver type of metric and replication rules.
+	Backend string `yaml:"backend,omitempty"`
+	// Component and Metric select the metrics of a metric rule, and the
+	// metrics must have all the MatchLabels.
+	Component   string            `yaml:"component,omitempty"`
+	Metric      string            `yaml:"metric,omitempty"`
+	MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
+	// Operator is one of >, >=, <, <=, == and !=, which defaults to < for
+	// pool free capacity rules and > for metric rules.
+	Operator  string  `yaml:"operator,omitempty"`
+	Threshold float64 `yaml:"threshold,omitempty"`
+	Severity  string  `yaml:"severity,omitempty"`
+	// Labels and Annotations are added to the alerts of the rule.
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type ruleFile struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// LoadRules loads and validates the rules from the yaml file.
+func LoadRules(path string) ([]*Rule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rf := &ruleFile{}
+	if err := yaml.Unmarshal(data, rf); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules %s: %v", path, err)
+	}
+	names := map[string]bool{}
+	for _, r := range rf.Rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("invalid alert rule %s in %s: %v", r.Name, path, err)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicated alert rule %s in %s", r.Name, path)
+		}
+		names[r.Name] = true
+	}
+	return rf.Rules, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch r.Type {
+	case PoolFreeCapacityRule:
+		if r.Operator == "" {
+			r.Operator = "<"
+		}
+	case MetricRule:
+		if r.Backend == "" || r.Metric == "" {
+			return fmt.Errorf("backend and metric are required")
+		}
+		if r.Operator == "" {
+			r.Operator = ">"
+		}
+	case ReplicationUnhealthyRule:
+		if r.Backend == "" {
+			return fmt.Errorf("backend is required")
+		}
+	case BackendUnreachableRule:
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+	if r.Operator != "" {
+		if _, err := compare(0, r.Operator, 0); err != nil {
+			return err
+		}
+	}
+	if r.Severity == "" {
+		r.Severity = defaultSeverity
+	}
+	return nil
+}
+
+// exceeds returns whether the value crosses the threshold of rule.
+func (r *Rule) exceeds(v float64) bool {
+	ok, _ := compare(v, r.Operator, r.Threshold)
+	return ok
+}
+
+func compare(v float64, op string, threshold float64) (bool, error) {
+	switch op {
+	case ">":
+		return v > threshold, nil
+	case ">=":
+		return v >= threshold, nil
+	case "<":
+		return v < threshold, nil
+	case "<=":
+		return v <= threshold, nil
+	case "==":
+		return v == threshold, nil
+	case "!=":
+		return v != threshold, nil
+	}
+	return false, fmt.Errorf("unknown operator %q", op)
+}
@@ -0,0 +1,309 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the audit log of the mutating operations of dock,
+the records are written as JSON lines to a rotating file and syslog.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/model"
+	"google.golang.org/grpc"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+
+	redactedValue = "******"
+)
+
+// Record is an audit record of a mutating operation.
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Service    string                 `json:"service"`
+	Method     string                 `json:"method"`
+	RequestId  string                 `json:"requestId,omitempty"`
+	TenantId   string                 `json:"tenantId,omitempty"`
+	UserId     string                 `json:"userId,omitempty"`
+	Driver     string                 `json:"driver,omitempty"`
+	Resources  map[string]string      `json:"resources,omitempty"`
+	Request    map[string]interface{} `json:"request,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	DurationMs int64                  `json:"durationMs"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Config is the config of audit sinks, the audit log is disabled if none of
+// the sinks is configured.
+type Config struct {
+	// File is the path of audit log file.
+	File string
+	// MaxSize is the size in megabytes of the file before it is rotated.
+	MaxSize int
+	// MaxBackups is the number of rotated files to retain.
+	MaxBackups int
+	// SyslogEndpoint is either "local" for the local syslog daemon, or the
+	// address of a remote one in the form of "udp://host:port".
+	SyslogEndpoint string
+}
+
+// Auditor writes the audit records to all the sinks.
+type Auditor struct {
+	mu    sync.Mutex
+	sinks []io.WriteCloser
+}
+
+// NewAuditor creates the auditor with the configured sinks, nil is returned
+// if no sink is configured.
+func NewAuditor(conf *Config) (*Auditor, error) {
+	a := &Auditor{}
+	if conf.File != "" {
+		f, err := NewRotatingFile(conf.File, int64(conf.MaxSize)*1024*1024, conf.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		a.sinks = append(a.sinks, f)
+	}
+	if conf.SyslogEndpoint != "" {
+		var network, raddr string
+		if conf.SyslogEndpoint != "local" {
+			segs := strings.SplitN(conf.SyslogEndpoint, "://", 2)
+			if len(segs) != 2 {
+				a.Close()
+				return nil, fmt.Errorf("invalid syslog endpoint %s", conf.SyslogEndpoint)
+			}
+			network, raddr = segs[0], segs[1]
+		}
+		w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_AUTH, "osdsdock")
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.sinks = append(a.sinks, w)
+	}
+	if len(a.sinks) == 0 {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// Write writes the record as a JSON line, the failure of sinks is only logged
+// so that the operations are not affected.
+func (a *Auditor) Write(rec *Record) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		log.Error("Failed to marshal audit record:", err)
+		return
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range a.sinks {
+		if _, err := s.Write(data); err != nil {
+			log.Error("Failed to write audit record:", err)
+		}
+	}
+}
+
+// Close closes all the sinks.
+func (a *Auditor) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var err error
+	for _, s := range a.sinks {
+		if e := s.Close(); e != nil {
+			err = e
+		}
+	}
+	a.sinks = nil
+	return err
+}
+
+// readOnlyPrefixes are the prefixes of the methods which do not change any
+// resource, they are not audited.
+var readOnlyPrefixes = []string{"Get", "List", "Collect"}
+
+// IsMutating returns whether the rpc method changes any resource.
+func IsMutating(fullMethod string) bool {
+	if strings.HasPrefix(fullMethod, "/grpc.") {
+		return false
+	}
+	method := path.Base(fullMethod)
+	for _, p := range readOnlyPrefixes {
+		if strings.HasPrefix(method, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnaryServerInterceptor writes an audit record for each mutating rpc.
+func (a *Auditor) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if !IsMutating(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	rec := NewRecord(info.FullMethod, req)
+	rec.Time = start.UTC()
+	rec.DurationMs = int64(time.Since(start) / time.Millisecond)
+	rec.Outcome = OutcomeSuccess
+	if err != nil {
+		rec.Outcome = OutcomeFailure
+		rec.Error = err.Error()
+	}
+	a.Write(rec)
+	return resp, err
+}
+
+// NewRecord creates the record of the request without outcome.
+func NewRecord(fullMethod string, req interface{}) *Record {
+	rec := &Record{
+		Service: strings.TrimPrefix(path.Dir(fullMethod), "/"),
+		Method:  path.Base(fullMethod),
+	}
+	if r, ok := req.(interface{ GetDriverName() string }); ok {
+		rec.Driver = r.GetDriverName()
+	}
+	if r, ok := req.(interface{ GetContext() string }); ok && r.GetContext() != "" {
+		ctx := c.NewContextFromJson(r.GetContext())
+		rec.RequestId, rec.TenantId, rec.UserId = ctx.RequestId, ctx.TenantId, ctx.UserId
+	}
+	rec.Resources = resourceIds(req)
+	rec.Request = redactRequest(req)
+	return rec
+}
+
+// resourceIds collects the non-empty string fields whose names end with Id.
+func resourceIds(req interface{}) map[string]string {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	ids := map[string]string{}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.Type.Kind() != reflect.String || !strings.HasSuffix(f.Name, "Id") {
+			continue
+		}
+		if s := v.Field(i).String(); s != "" {
+			ids[jsonName(f)] = s
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+func jsonName(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+// secretKeyPatterns are the substrings of the keys whose values are secrets.
+var secretKeyPatterns = []string{"password", "secret", "token", "dhchap"}
+
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, p := range secretKeyPatterns {
+		if strings.Contains(k, p) {
+			return true
+		}
+	}
+	for _, s := range model.SecretConnectionDataKeys {
+		if key == s {
+			return true
+		}
+	}
+	return false
+}
+
+// redactRequest converts the request to a generic map with the secrets
+// masked. The context is dropped since its fields are recorded separately
+// and it contains the auth token.
+func redactRequest(req interface{}) map[string]interface{} {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	delete(m, "context")
+	return redactValue(m).(map[string]interface{})
+}
+
+// RequestIds returns the ids of the resources in the request, so that the
+// request can be logged without dumping its context and secrets.
+func RequestIds(req interface{}) map[string]interface{} {
+	ids := map[string]interface{}{}
+	for k, v := range redactRequest(req) {
+		if k == "id" || strings.HasSuffix(k, "Id") {
+			ids[k] = v
+		}
+	}
+	return ids
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range val {
+			if isSecretKey(k) {
+				val[k] = redactedValue
+				continue
+			}
+			// The connection data is serialized in a string.
+			if s, ok := sub.(string); ok && strings.HasPrefix(strings.TrimSpace(s), "{") {
+				var inner map[string]interface{}
+				if json.Unmarshal([]byte(s), &inner) == nil {
+					val[k] = redactValue(inner)
+					continue
+				}
+			}
+			val[k] = redactValue(sub)
+		}
+		return val
+	case []interface{}:
+		for i := range val {
+			val[i] = redactValue(val[i])
+		}
+		return val
+	default:
+		return v
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"google.golang.org/grpc"
+)
+
+func readRecords(t *testing.T, file string) []*Record {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var recs []*Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rec := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestIsMutating(t *testing.T) {
+	for method, expected := range map[string]bool{
+		"/proto.ProvisionDock/CreateVolume":        true,
+		"/proto.AttachDock/DetachVolume":           true,
+		"/proto.FileShareDock/DeleteFileShare":     true,
+		"/proto.ProvisionDock/CollectMetrics":      false,
+		"/proto.ProvisionDock/GetMetrics":          false,
+		"/grpc.health.v1.Health/Check":             false,
+		"/proto.ProvisionDock/ExtendVolume":        true,
+		"/proto.ProvisionDock/FailoverReplication": true,
+	} {
+		if IsMutating(method) != expected {
+			t.Errorf("Expected %s mutating %v", method, expected)
+		}
+	}
+}
+
+func TestRequestIds(t *testing.T) {
+	ids := RequestIds(&pb.CreateVolumeAttachmentOpts{
+		Id:       "attachment1",
+		VolumeId: "volume1",
+		Metadata: map[string]string{"chapPassword": "secret"},
+		Context:  `{"auth_token":"token"}`,
+	})
+	expected := map[string]interface{}{"id": "attachment1", "volumeId": "volume1"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestAuditor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensds-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "audit.log")
+
+	if a, err := NewAuditor(&Config{}); a != nil || err != nil {
+		t.Fatalf("Expected auditor to be disabled, got %v, %v", a, err)
+	}
+	a, err := NewAuditor(&Config{File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.ProvisionDock/CreateVolumeAttachment"}
+	opt := &pb.CreateVolumeAttachmentOpts{
+		Id:             "attachment-1",
+		VolumeId:       "volume-1",
+		AccessProtocol: "iscsi",
+		DriverName:     "lvm",
+		Metadata: map[string]string{
+			"chapPassword":   "secret1",
+			"connectionData": `{"targetIQN":"iqn.2017-10.io.opensds:volume-1","authPassword":"secret2"}`,
+		},
+		Context: `{"auth_token":"token1","user_id":"user-1","tenant_id":"tenant-1","request_id":"req-1"}`,
+	}
+	a.UnaryServerInterceptor(context.Background(), opt, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("attach failed")
+	})
+	a.UnaryServerInterceptor(context.Background(), &pb.CollectMetricsOpts{},
+		&grpc.UnaryServerInfo{FullMethod: "/proto.ProvisionDock/CollectMetrics"},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+
+	data, _ := ioutil.ReadFile(file)
+	for _, secret := range []string{"secret1", "secret2", "token1"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %s to be redacted in %s", secret, data)
+		}
+	}
+	recs := readRecords(t, file)
+	if len(recs) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(recs))
+	}
+	rec := recs[0]
+	if rec.Service != "proto.ProvisionDock" || rec.Method != "CreateVolumeAttachment" || rec.Driver != "lvm" ||
+		rec.RequestId != "req-1" || rec.TenantId != "tenant-1" || rec.UserId != "user-1" ||
+		rec.Outcome != OutcomeFailure || rec.Error != "attach failed" {
+		t.Errorf("Unexpected record %+v", rec)
+	}
+	if rec.Resources["id"] != "attachment-1" || rec.Resources["volumeId"] != "volume-1" {
+		t.Errorf("Unexpected resources %v", rec.Resources)
+	}
+	metadata, _ := rec.Request["metadata"].(map[string]interface{})
+	conn, ok := metadata["connectionData"].(map[string]interface{})
+	if !ok || conn["targetIQN"] != "iqn.2017-10.io.opensds:volume-1" || conn["authPassword"] != redactedValue {
+		t.Errorf("Unexpected connection data %v", metadata["connectionData"])
+	}
+	if _, ok := rec.Request["context"]; ok {
+		t.Error("Expected context to be dropped from request")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opensds-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "audit.log")
+
+	r, err := NewRotatingFile(file, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, line := range []string{"record-1\n", "record-2\n", "record-3\n", "record-4\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for f, expected := range map[string]string{
+		file:        "record-4\n",
+		file + ".1": "record-3\n",
+		file + ".2": "record-2\n",
+	} {
+		if data, _ := ioutil.ReadFile(f); string(data) != expected {
+			t.Errorf("Expected %q in %s, got %q", expected, f, data)
+		}
+	}
+	if _, err := os.Stat(file + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected only 2 backups to be retained")
+	}
+	if fi, _ := os.Stat(file); fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", fi.Mode().Perm())
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a file writer which renames the file to file.1 when it
+// exceeds the max size, and the older ones are shifted to file.2, file.3 and
+// so on until the max backups.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewRotatingFile opens the file for appending, the file is never rotated if
+// maxSize is not positive.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	// The audit records may be sensitive, so it is only readable by owner.
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, fi.Size()
+	return nil
+}
+
+func (r *RotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
+
+// rotate renames the current file and opens a new one, the file is reopened
+// even if it fails to be renamed so that the following records are kept.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		r.file = nil
+		return err
+	}
+	err := r.shift()
+	if e := r.open(); e != nil {
+		r.file = nil
+		return e
+	}
+	return err
+}
+
+func (r *RotatingFile) shift() error {
+	if r.maxBackups > 0 {
+		os.Remove(r.backupPath(r.maxBackups))
+		for n := r.maxBackups - 1; n > 0; n-- {
+			if _, err := os.Stat(r.backupPath(n)); err == nil {
+				if err := os.Rename(r.backupPath(n), r.backupPath(n+1)); err != nil {
+					return err
+				}
+			}
+		}
+		return os.Rename(r.path, r.backupPath(1))
+	}
+	return os.Remove(r.path)
+}
+
+// Write implements io.Writer, the file is rotated before the data is written
+// if it would exceed the max size.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
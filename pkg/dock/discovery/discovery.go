@@ -21,8 +21,10 @@ package discovery
 
 import (
 	"fmt"
+	"math/rand"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,39 +38,238 @@ import (
 	"github.com/sodafoundation/dock/contrib/drivers/utils/config"
 	c "github.com/sodafoundation/dock/pkg/context"
 	"github.com/sodafoundation/dock/pkg/db"
+	"github.com/sodafoundation/dock/pkg/dock/metrics"
 	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
 	"github.com/sodafoundation/dock/pkg/utils"
 	. "github.com/sodafoundation/dock/pkg/utils/config"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
-	availableStatus   = "available"
-	unavailableStatus = "unavailable"
+	availableStatus   = model.PoolAvailable
+	unavailableStatus = model.PoolUnavailable
+)
+
+// Names of the grpc services of dock, whose serving status is reported by the
+// grpc health service.
+const (
+	ProvisionDockService = "proto.ProvisionDock"
+	AttachDockService    = "proto.AttachDock"
+	FileShareDockService = "proto.FileShareDock"
+)
+
+// Default settings of the discovery loop, which are used if the ones of
+// Context are not set.
+const (
+	defaultInterval      = 60 * time.Second
+	defaultRetryInterval = 5 * time.Second
+	// maxJitter is the largest jitter, which keeps the randomized interval
+	// positive.
+	maxJitter = 0.9
 )
 
 type Context struct {
 	StopChan chan bool
-	ErrChan  chan error
 	MetaChan chan string
+	// RefreshChan triggers an immediate discovery, it is created by
+	// NewContext with a buffer of one so that the triggers are coalesced.
+	RefreshChan chan struct{}
+	// ReloadChan triggers the reload of backends from CONF followed by a
+	// discovery, it is buffered in the same way as RefreshChan.
+	ReloadChan chan struct{}
+	// Interval is the period of discovery, which is randomized by Jitter,
+	// a fraction of it, so that the docks do not report at the same time.
+	Interval time.Duration
+	Jitter   float64
+	// RetryInterval is the delay after the first failed discovery, which is
+	// doubled by each consecutive failure up to MaxBackoff.
+	RetryInterval time.Duration
+	MaxBackoff    time.Duration
+	// Health is updated with the result of each discovery if it is set.
+	Health *health.Server
+	// Observer is given the docks and pools of each discovery if it is set.
+	Observer DiscoveryObserver
+}
+
+// HealthReporter is implemented by the discoverers which know the serving
+// status of dock services from the status of backends.
+type HealthReporter interface {
+	ReportHealth(hs *health.Server)
+}
+
+// DiscoveryObserver consumes the discovery results, such as the alerting
+// engine.
+type DiscoveryObserver interface {
+	ObserveDiscovery(dcks []*model.DockSpec, pols []*model.StoragePoolSpec)
+}
+
+// ResultReporter is implemented by the discoverers which can give their
+// results to a DiscoveryObserver.
+type ResultReporter interface {
+	ReportResult(o DiscoveryObserver)
+}
+
+// Reloader is implemented by the discoverers whose backends are read from
+// CONF, so that they can follow the changes of it.
+type Reloader interface {
+	Reload() error
+}
+
+// ReplicationRefresher is implemented by the discoverers which keep the state
+// of the replications of their pools up to date.
+type ReplicationRefresher interface {
+	RefreshReplications() error
+}
+
+// Disabler is implemented by the discoverers which can mark their docks and
+// pools as unavailable when the dock shuts down.
+type Disabler interface {
+	Disable(reason string) error
+}
+
+// NewContext returns a discovery context with the loop settings. The jitter is
+// clamped to [0, 1), otherwise the randomized interval may not be positive.
+func NewContext(interval time.Duration, jitter float64, retryInterval, maxBackoff time.Duration) *Context {
+	if jitter < 0 {
+		log.Warningf("Discovery jitter %v is negative, using 0 instead", jitter)
+		jitter = 0
+	} else if jitter >= 1 {
+		log.Warningf("Discovery jitter %v is not less than 1, using %v instead", jitter, maxJitter)
+		jitter = maxJitter
+	}
+	return &Context{
+		StopChan:      make(chan bool),
+		MetaChan:      make(chan string),
+		RefreshChan:   make(chan struct{}, 1),
+		ReloadChan:    make(chan struct{}, 1),
+		Interval:      interval,
+		Jitter:        jitter,
+		RetryInterval: retryInterval,
+		MaxBackoff:    maxBackoff,
+	}
+}
+
+// Refresh triggers an immediate discovery without blocking, it is a no-op if
+// one is already pending.
+func (ctx *Context) Refresh() {
+	select {
+	case ctx.RefreshChan <- struct{}{}:
+	default:
+	}
+}
+
+// Reload triggers the reload of backends without blocking, it is a no-op if
+// one is already pending.
+func (ctx *Context) Reload() {
+	select {
+	case ctx.ReloadChan <- struct{}{}:
+	default:
+	}
 }
 
+// nextDelay returns the delay before the next discovery, failures is the
+// number of consecutive failed discoveries.
+func (ctx *Context) nextDelay(failures int) time.Duration {
+	interval := ctx.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if failures > 0 {
+		delay := ctx.RetryInterval
+		if delay <= 0 {
+			delay = defaultRetryInterval
+		}
+		max := ctx.MaxBackoff
+		if max <= 0 {
+			max = interval
+		}
+		for i := 1; i < failures && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+	if ctx.Jitter > 0 {
+		// Randomize the interval in [1-jitter, 1+jitter) of it.
+		interval += time.Duration((rand.Float64()*2 - 1) * ctx.Jitter * float64(interval))
+	}
+	return interval
+}
+
+// DiscoveryAndReport discovers and reports the backends periodically until
+// the stop channel is signaled. The failures are retried with exponential
+// backoff, so the loop never stops because of them.
 func DiscoveryAndReport(dd DockDiscoverer, ctx *Context) {
+	failures := 0
 	for {
+		if err := discoverAndReport(dd, ctx); err != nil {
+			failures++
+			log.Errorf("when calling capability report method (%d consecutive failures): %v", failures, err)
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(ctx.nextDelay(failures))
 		select {
 		case <-ctx.StopChan:
+			timer.Stop()
 			return
-		default:
-			if err := dd.Discover(); err != nil {
-				ctx.ErrChan <- err
-			}
-
-			if err := dd.Report(); err != nil {
-				ctx.ErrChan <- err
+		case <-ctx.RefreshChan:
+			timer.Stop()
+			log.V(5).Info("Refreshing discovery on demand")
+		case <-ctx.ReloadChan:
+			timer.Stop()
+			// The backends are reloaded by this goroutine, so that they are
+			// never changed during a discovery.
+			if r, ok := dd.(Reloader); ok {
+				if err := r.Reload(); err != nil {
+					log.Error("when reloading backends:", err)
+				}
 			}
+		case <-timer.C:
 		}
+	}
+}
+
+func discoverAndReport(dd DockDiscoverer, ctx *Context) error {
+	start := time.Now()
+	err := dd.Discover()
+	metrics.ObserveDiscovery("discover", start, err)
+	if hr, ok := dd.(HealthReporter); ok && ctx.Health != nil {
+		hr.ReportHealth(ctx.Health)
+	}
+	if rr, ok := dd.(ResultReporter); ok && ctx.Observer != nil {
+		rr.ReportResult(ctx.Observer)
+	}
 
-		time.Sleep(60 * time.Second)
+	// The docks found by a failed discovery are still reported, so that
+	// their status is updated.
+	start = time.Now()
+	rerr := dd.Report()
+	metrics.ObserveDiscovery("report", start, rerr)
+	if err != nil {
+		return err
+	}
+	if rerr != nil {
+		return rerr
+	}
+
+	// The replication status is refreshed once the pools are reported, its
+	// failures do not back off the discovery of pools.
+	if rr, ok := dd.(ReplicationRefresher); ok {
+		start = time.Now()
+		ferr := rr.RefreshReplications()
+		metrics.ObserveDiscovery("replication", start, ferr)
+		if ferr != nil {
+			log.Error("when refreshing replication status:", ferr)
+		}
 	}
+	return nil
 }
 
 type DockDiscoverer interface {
@@ -104,15 +305,93 @@ type provisionDockDiscoverer struct {
 }
 
 func (pdd *provisionDockDiscoverer) Init() error {
+	dcks, err := pdd.loadDocks()
+	if err != nil {
+		return err
+	}
+	pdd.dcks = dcks
+	return nil
+}
+
+// disabledReason is the status reason of the docks whose backends are no
+// longer enabled.
+const disabledReason = "backend is disabled"
+
+// Reload replaces the docks with the enabled backends of CONF. The docks of
+// the new backends are registered by the next Report, and the ones whose
+// backends are removed are marked as unavailable along with their pools.
+func (pdd *provisionDockDiscoverer) Reload() error {
+	dcks, err := pdd.loadDocks()
+	if err != nil {
+		return err
+	}
+	enabled := make(map[string]bool)
+	for _, dck := range dcks {
+		enabled[dck.Id] = true
+	}
+	var removed []*model.DockSpec
+	for _, dck := range pdd.dcks {
+		if !enabled[dck.Id] {
+			log.Infof("Backend %s is disabled", dck.Name)
+			removed = append(removed, dck)
+		}
+	}
+	pdd.dcks = dcks
+	return pdd.disable(removed, disabledReason)
+}
+
+// Disable marks all the docks and their pools as unavailable in db, and
+// revokes their lease.
+func (pdd *provisionDockDiscoverer) Disable(reason string) error {
+	if err := pdd.disable(pdd.dcks, reason); err != nil {
+		return err
+	}
+	return pdd.revokeLease()
+}
+
+func (pdd *provisionDockDiscoverer) disable(dcks []*model.DockSpec, reason string) error {
+	if len(dcks) == 0 {
+		return nil
+	}
+	disabled := make(map[string]bool)
+	for _, dck := range dcks {
+		dck.Status = model.DockUnavailable
+		dck.StatusReason = reason
+		disabled[dck.Id] = true
+		if err := pdd.Register(dck); err != nil {
+			return err
+		}
+	}
+	pols, err := pdd.c.ListPools(c.NewAdminContext())
+	if err != nil {
+		return fmt.Errorf("can not read pools in db")
+	}
+	for _, pol := range pols {
+		if !disabled[pol.DockId] {
+			continue
+		}
+		pol.Status = unavailableStatus
+		pol.StatusReason = reason
+		if err = pdd.Register(pol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDocks returns the docks of the enabled backends in CONF.
+func (pdd *provisionDockDiscoverer) loadDocks() ([]*model.DockSpec, error) {
 	// Load resource from specified file
 	bm := GetBackendsMap()
 	host, err := os.Hostname()
 	if err != nil {
 		log.Error("When get os hostname:", err)
-		return err
+		return nil, err
 	}
 
-	for _, v := range CONF.EnabledBackends {
+	var dcks []*model.DockSpec
+
+	for _, v := range GetEnabledBackends() {
 		b := bm[v]
 		if b.Name == "" {
 			continue
@@ -128,24 +407,43 @@ func (pdd *provisionDockDiscoverer) Init() error {
 			Endpoint:    CONF.OsdsDock.ApiEndpoint,
 			NodeId:      host,
 			Type:        model.DockTypeProvioner,
-			Metadata:    map[string]string{"HostReplicationDriver": CONF.OsdsDock.HostBasedReplicationDriver},
+			Metadata:    map[string]string{"HostReplicationDriver": GetHostBasedReplicationDriver()},
 		}
-		// Update the id if the dock is already in etcd
-		name := map[string][]string{
-			"Name": {dck.Name},
-		}
-		docks, err := pdd.DockRegister.c.ListDocksWithFilter(c.NewAdminContext(), name)
-		if err == nil && len(docks) != 0 {
-			dck.Id = docks[0].Id
+		// A restarted dock reclaims its records by the id derived from the
+		// host and driver. The docks registered before the id is derived
+		// are found by name on the same host.
+		if _, err := pdd.c.GetDock(c.NewAdminContext(), dck.Id); err != nil {
+			name := map[string][]string{
+				"Name":   {dck.Name},
+				"NodeId": {host},
+			}
+			docks, err := pdd.DockRegister.c.ListDocksWithFilter(c.NewAdminContext(), name)
+			if err == nil && len(docks) != 0 {
+				dck.Id = docks[0].Id
+			}
 		}
-		pdd.dcks = append(pdd.dcks, dck)
+		dcks = append(dcks, dck)
 	}
 
-	return nil
+	return dcks, nil
 }
 
 var filesharedrivers = []string{config.NFSDriverType, config.HuaweiOceanStorFileDriverType, config.ManilaDriverType, config.ChubaofsDriverType, config.NetappOntapNasDriverType}
 
+// probeBackend lists the pools of the backend, which also checks whether the
+// backend can be reached. It is a variable so that it can be faked in unit
+// tests.
+var probeBackend = func(driverName string) ([]*model.StoragePoolSpec, error) {
+	if utils.Contains(filesharedrivers, driverName) {
+		d := fd.Init(driverName)
+		defer fd.Clean(d)
+		return d.ListPools()
+	}
+	d := drivers.Init(driverName)
+	defer drivers.Clean(d)
+	return d.ListPools()
+}
+
 func (pdd *provisionDockDiscoverer) Discover() error {
 	// Clear existing pool info
 	pdd.pols = pdd.pols[:0]
@@ -167,12 +465,26 @@ func (pdd *provisionDockDiscoverer) Discover() error {
 			dbPolsMap[polInDb.DockId][polInDb.Id] = polInDb
 		}
 	}
+	metrics.ResetPoolCapacities()
 	for _, dck := range pdd.dcks {
 		// Call function of StorageDrivers configured by storage drivers.
+		start := time.Now()
+		pols, err = probeBackend(dck.DriverName)
+		metrics.ObserveDriverOperation(dck.DriverName, "probe", start)
+		if err != nil {
+			log.Error("Call driver to list pools failed:", err)
+			// The backend can not be reached, so none of its pools can be
+			// used until it recovers.
+			dck.Status = model.DockUnavailable
+			dck.StatusReason = fmt.Sprintf("failed to list pools: %v", err)
+			for _, pol := range dbPolsMap[dck.Id] {
+				pol.StatusReason = dck.StatusReason
+				pdd.pols = append(pdd.pols, pol)
+			}
+			continue
+		}
+
 		if utils.Contains(filesharedrivers, dck.DriverName) {
-			d := fd.Init(dck.DriverName)
-			defer fd.Clean(d)
-			pols, err = d.ListPools()
 			for _, pol := range pols {
 				log.Infof("Backend %s discovered pool %s", dck.DriverName, pol.Name)
 				delete(dbPolsMap[dck.Id], pol.Id)
@@ -180,10 +492,6 @@ func (pdd *provisionDockDiscoverer) Discover() error {
 				pol.Status = availableStatus
 			}
 		} else {
-			d := drivers.Init(dck.DriverName)
-			defer drivers.Clean(d)
-			pols, err = d.ListPools()
-
 			replicationDriverName := dck.Metadata["HostReplicationDriver"]
 			replicationType := model.ReplicationTypeHost
 			if drivers.IsSupportArrayBasedReplication(dck.DriverName) {
@@ -208,20 +516,26 @@ func (pdd *provisionDockDiscoverer) Discover() error {
 				pol.Status = availableStatus
 			}
 		}
-		if err != nil {
-			log.Error("Call driver to list pools failed:", err)
-			continue
-		}
+		metrics.SetPoolCapacities(dck.DriverName, pols)
 
+		dck.Status, dck.StatusReason = model.DockAvailable, ""
 		if len(pols) == 0 {
 			log.Warningf("The pool of dock %s is empty!\n", dck.Id)
+			dck.Status, dck.StatusReason = model.DockDegraded, "no pool is found on backend"
 		}
 
 		pdd.pols = append(pdd.pols, pols...)
+		var missing []string
 		for _, pol := range dbPolsMap[dck.Id] {
+			pol.StatusReason = "pool is not found on backend"
+			missing = append(missing, pol.Name)
 			pdd.pols = append(pdd.pols, pol)
 		}
-
+		if len(missing) != 0 {
+			sort.Strings(missing)
+			dck.Status = model.DockDegraded
+			dck.StatusReason = fmt.Sprintf("pools %s are not found on backend", strings.Join(missing, ","))
+		}
 	}
 	if len(pdd.pols) == 0 {
 		return fmt.Errorf("there is no pool can be found")
@@ -230,6 +544,103 @@ func (pdd *provisionDockDiscoverer) Discover() error {
 	return nil
 }
 
+// getReplicationStatus gets the state of a replication from the replication
+// driver, it is a variable so that it can be faked in unit tests.
+var getReplicationStatus = func(driverName string, opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	d, err := drivers.InitReplicationDriver(driverName)
+	defer drivers.CleanReplicationDriver(d)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetReplicationStatus(opt)
+}
+
+// RefreshReplications stores the state of the replications whose primary
+// pools are found by this dock. The replications whose state can't be got
+// are marked as unhealthy with the reason.
+func (pdd *provisionDockDiscoverer) RefreshReplications() error {
+	ctx := c.NewAdminContext()
+	replications, err := pdd.c.ListReplication(ctx)
+	if err != nil {
+		return fmt.Errorf("can not read replications in db: %v", err)
+	}
+	pols := make(map[string]*model.StoragePoolSpec)
+	for _, pol := range pdd.pols {
+		if pol.Status == availableStatus && pol.ReplicationDriverName != "" {
+			pols[pol.Id] = pol
+		}
+	}
+
+	var failed []string
+	for _, r := range replications {
+		pol, ok := pols[r.PoolId]
+		if !ok {
+			continue
+		}
+		st, err := getReplicationStatus(pol.ReplicationDriverName, &pb.GetReplicationStatusOpts{
+			Id:                             r.Id,
+			PrimaryVolumeId:                r.PrimaryVolumeId,
+			SecondaryVolumeId:              r.SecondaryVolumeId,
+			PoolId:                         r.PoolId,
+			PrimaryReplicationDriverData:   r.PrimaryReplicationDriverData,
+			SecondaryReplicationDriverData: r.SecondaryReplicationDriverData,
+			DockId:                         pol.DockId,
+			DriverName:                     pol.ReplicationDriverName,
+			Metadata:                       r.Metadata,
+			IsPrimary:                      true,
+		})
+		if err != nil {
+			log.Errorf("Get status of replication %s failed: %v", r.Id, err)
+			st = &model.ReplicationPairStatus{
+				Id:            r.Id,
+				Reason:        fmt.Sprintf("failed to get status: %v", err),
+				RunningStatus: model.ReplicationRunningUnknown,
+				RpoLag:        -1,
+			}
+		}
+		st.UpdatedAt = time.Now().Format(constants.TimeFormat)
+		if err := pdd.c.UpdateReplicationPairStatus(ctx, r.Id, st); err != nil {
+			failed = append(failed, r.Id)
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed to update status of replications %s", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// ReportHealth sets a dock service to not serving if all of its backends are
+// unavailable.
+func (pdd *provisionDockDiscoverer) ReportHealth(hs *health.Server) {
+	var block, fileshare []*model.DockSpec
+	for _, dck := range pdd.dcks {
+		if utils.Contains(filesharedrivers, dck.DriverName) {
+			fileshare = append(fileshare, dck)
+		} else {
+			block = append(block, dck)
+		}
+	}
+	hs.SetServingStatus(ProvisionDockService, servingStatus(block))
+	hs.SetServingStatus(FileShareDockService, servingStatus(fileshare))
+}
+
+// ReportResult gives the discovered docks and pools to the observer.
+func (pdd *provisionDockDiscoverer) ReportResult(o DiscoveryObserver) {
+	o.ObserveDiscovery(pdd.dcks, pdd.pols)
+}
+
+func servingStatus(dcks []*model.DockSpec) healthpb.HealthCheckResponse_ServingStatus {
+	for _, dck := range dcks {
+		if dck.Status != model.DockUnavailable {
+			return healthpb.HealthCheckResponse_SERVING
+		}
+	}
+	if len(dcks) == 0 {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
+
 func (pdd *provisionDockDiscoverer) Report() error {
 	var err error
 
@@ -311,15 +722,37 @@ func (add *attachDockDiscoverer) Discover() error {
 }
 
 func (add *attachDockDiscoverer) Report() error {
+	if add.dck == nil {
+		return fmt.Errorf("attacher dock has not been discovered")
+	}
 	return add.Register(add.dck)
 }
 
+// Disable marks the attacher dock as unavailable in db if it is discovered.
+func (add *attachDockDiscoverer) Disable(reason string) error {
+	if add.dck == nil {
+		return nil
+	}
+	add.dck.Status = model.DockUnavailable
+	add.dck.StatusReason = reason
+	if err := add.Register(add.dck); err != nil {
+		return err
+	}
+	return add.revokeLease()
+}
+
 func NewDockRegister() *DockRegister {
-	return &DockRegister{c: db.C}
+	dr := &DockRegister{c: db.C, ttl: CONF.OsdsDock.LeaseTTL}
+	dr.liveness, _ = db.C.(db.Liveness)
+	return dr
 }
 
 type DockRegister struct {
 	c db.Client
+	// The registered docks are kept alive by the lease of ttl if the db
+	// supports it, see liveness.go.
+	liveness db.Liveness
+	ttl      time.Duration
 }
 
 func (dr *DockRegister) Register(in interface{}) error {
@@ -328,11 +761,16 @@ func (dr *DockRegister) Register(in interface{}) error {
 	switch in.(type) {
 	case *model.DockSpec:
 		dck := in.(*model.DockSpec)
+		dck.LastHeartbeat = heartbeat()
 		// Call db module to create dock resource.
 		if _, err := dr.c.CreateDock(ctx, dck); err != nil {
 			log.Errorf("When create dock %s in db: %v\n", dck.Id, err)
 			return err
 		}
+		if err := dr.keepAlive(dck); err != nil {
+			log.Errorf("When keep dock %s alive in db: %v\n", dck.Id, err)
+			return err
+		}
 		break
 	case *model.StoragePoolSpec:
 		pol := in.(*model.StoragePoolSpec)
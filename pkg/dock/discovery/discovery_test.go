@@ -15,14 +15,23 @@
 package discovery
 
 import (
+	"context"
+	"errors"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	c "github.com/sodafoundation/dock/pkg/context"
 	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
 	. "github.com/sodafoundation/dock/pkg/utils/config"
 	. "github.com/sodafoundation/dock/testutils/collection"
 	dbtest "github.com/sodafoundation/dock/testutils/db/testing"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -58,8 +67,10 @@ func TestInit(t *testing.T) {
 	for i := range SampleDocks {
 		expected = append(expected, &SampleDocks[i])
 	}
-	name := map[string][]string{"Name": {SampleDocks[0].Name}}
+	host, _ := os.Hostname()
+	name := map[string][]string{"Name": {SampleDocks[0].Name}, "NodeId": {host}}
 	mockClient := new(dbtest.Client)
+	mockClient.On("GetDock", c.NewAdminContext(), mock.Anything).Return(nil, errors.New("not found"))
 	mockClient.On("ListDocksWithFilter", c.NewAdminContext(), name).Return(expected, nil)
 	fdd.c = mockClient
 	if err := fdd.Init(); err != nil {
@@ -139,3 +150,289 @@ func TestReport(t *testing.T) {
 		t.Errorf("Failed to store docks and pools into database: %v\n", err)
 	}
 }
+
+func TestRefreshReplications(t *testing.T) {
+	defer func(f func(string, *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error)) {
+		getReplicationStatus = f
+	}(getReplicationStatus)
+
+	fdd := NewFakeDockDiscoverer()
+	fdd.pols = []*model.StoragePoolSpec{
+		{BaseModel: &model.BaseModel{Id: "pool1"}, Status: availableStatus, ReplicationDriverName: "drbd"},
+		{BaseModel: &model.BaseModel{Id: "pool2"}, Status: unavailableStatus, ReplicationDriverName: "drbd"},
+	}
+	replications := []*model.ReplicationSpec{
+		{BaseModel: &model.BaseModel{Id: "r1"}, PoolId: "pool1", Metadata: map[string]string{"k": "v"}},
+		{BaseModel: &model.BaseModel{Id: "r2"}, PoolId: "pool1"},
+		// The pools which are unavailable or of other docks are skipped.
+		{BaseModel: &model.BaseModel{Id: "r3"}, PoolId: "pool2"},
+		{BaseModel: &model.BaseModel{Id: "r4"}, PoolId: "pool3"},
+	}
+	getReplicationStatus = func(driverName string, opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+		if driverName != "drbd" || !opt.GetIsPrimary() {
+			t.Errorf("Unexpected driver %s and options %+v", driverName, opt)
+		}
+		if opt.GetId() == "r2" {
+			return nil, errors.New("drbdsetup failed")
+		}
+		return &model.ReplicationPairStatus{Id: opt.GetId(), Healthy: true, RunningStatus: model.ReplicationRunningSynced}, nil
+	}
+
+	mockClient := new(dbtest.Client)
+	mockClient.On("ListReplication", c.NewAdminContext()).Return(replications, nil)
+	mockClient.On("UpdateReplicationPairStatus", c.NewAdminContext(), "r1", mock.MatchedBy(func(st *model.ReplicationPairStatus) bool {
+		return st.Healthy && st.UpdatedAt != ""
+	})).Return(nil)
+	mockClient.On("UpdateReplicationPairStatus", c.NewAdminContext(), "r2", mock.MatchedBy(func(st *model.ReplicationPairStatus) bool {
+		return !st.Healthy && strings.Contains(st.Reason, "drbdsetup failed") && st.RunningStatus == model.ReplicationRunningUnknown
+	})).Return(errors.New("conflict"))
+	fdd.c = mockClient
+
+	err := fdd.RefreshReplications()
+	if err == nil || !strings.Contains(err.Error(), "r2") {
+		t.Errorf("Expected error of updating r2, got %v", err)
+	}
+	mockClient.AssertNumberOfCalls(t, "UpdateReplicationPairStatus", 2)
+}
+
+func TestDiscoverBackendStatus(t *testing.T) {
+	defer func(f func(string) ([]*model.StoragePoolSpec, error)) { probeBackend = f }(probeBackend)
+
+	dck := &model.DockSpec{
+		BaseModel:  &model.BaseModel{Id: "dock-1"},
+		DriverName: "lvm",
+	}
+	polsInDb := []*model.StoragePoolSpec{
+		{BaseModel: &model.BaseModel{Id: "pool-1"}, Name: "vg001", DockId: "dock-1", Status: "available"},
+		{BaseModel: &model.BaseModel{Id: "pool-2"}, Name: "vg002", DockId: "dock-1", Status: "available"},
+	}
+	mockClient := new(dbtest.Client)
+	mockClient.On("ListPools", c.NewAdminContext()).Return(polsInDb, nil)
+	mockClient.On("ListPoolsWithFilter", c.NewAdminContext(), map[string][]string{
+		"Name": {"vg001"}, "DockId": {"dock-1"}}).Return(polsInDb[:1], nil)
+	fdd := NewFakeDockDiscoverer()
+	fdd.c = mockClient
+	fdd.dcks = []*model.DockSpec{dck}
+	hs := health.NewServer()
+
+	// The backend can not be reached.
+	probeBackend = func(string) ([]*model.StoragePoolSpec, error) {
+		return nil, errors.New("connection refused")
+	}
+	if err := fdd.Discover(); err != nil {
+		t.Fatal(err)
+	}
+	if dck.Status != model.DockUnavailable || !strings.Contains(dck.StatusReason, "connection refused") {
+		t.Errorf("Unexpected dock status %s: %s", dck.Status, dck.StatusReason)
+	}
+	for _, pol := range fdd.pols {
+		if pol.Status != model.PoolUnavailable || pol.StatusReason != dck.StatusReason {
+			t.Errorf("Unexpected pool %s status %s: %s", pol.Name, pol.Status, pol.StatusReason)
+		}
+	}
+	fdd.ReportHealth(hs)
+	resp, _ := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: ProvisionDockService})
+	if resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected provision dock not serving, got %v", resp.Status)
+	}
+
+	// Only one of the pools is found on the backend.
+	probeBackend = func(string) ([]*model.StoragePoolSpec, error) {
+		return []*model.StoragePoolSpec{{BaseModel: &model.BaseModel{}, Name: "vg001"}}, nil
+	}
+	if err := fdd.Discover(); err != nil {
+		t.Fatal(err)
+	}
+	if dck.Status != model.DockDegraded || dck.StatusReason != "pools vg002 are not found on backend" {
+		t.Errorf("Unexpected dock status %s: %s", dck.Status, dck.StatusReason)
+	}
+	for _, pol := range fdd.pols {
+		if pol.Name == "vg001" && pol.Status != model.PoolAvailable ||
+			pol.Name == "vg002" && pol.Status != model.PoolUnavailable {
+			t.Errorf("Unexpected pool %s status %s", pol.Name, pol.Status)
+		}
+	}
+	fdd.ReportHealth(hs)
+	resp, _ = hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: ProvisionDockService})
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected provision dock serving, got %v", resp.Status)
+	}
+}
+
+type fakeLoopDiscoverer struct {
+	errs    []error
+	calls   chan int
+	n       int
+	reloads int
+}
+
+func (f *fakeLoopDiscoverer) Init() error { return nil }
+
+func (f *fakeLoopDiscoverer) Discover() error {
+	var err error
+	if f.n < len(f.errs) {
+		err = f.errs[f.n]
+	}
+	f.n++
+	f.calls <- f.n
+	return err
+}
+
+func (f *fakeLoopDiscoverer) Report() error { return nil }
+
+func (f *fakeLoopDiscoverer) Reload() error {
+	f.reloads++
+	return nil
+}
+
+func TestNextDelay(t *testing.T) {
+	ctx := NewContext(time.Minute, 0.1, time.Second, 10*time.Second)
+	for i := 0; i < 100; i++ {
+		if d := ctx.nextDelay(0); d < 54*time.Second || d >= 66*time.Second {
+			t.Fatalf("Expected delay within jitter of interval, got %v", d)
+		}
+	}
+	for failures, expected := range map[int]time.Duration{
+		1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second,
+		100: 10 * time.Second,
+	} {
+		if d := ctx.nextDelay(failures); d != expected {
+			t.Errorf("Expected delay %v after %d failures, got %v", expected, failures, d)
+		}
+	}
+
+	ctx = &Context{}
+	if d := ctx.nextDelay(0); d != defaultInterval {
+		t.Errorf("Expected default interval, got %v", d)
+	}
+
+	// The jitter is clamped so that the delay stays positive.
+	for jitter, expected := range map[float64]float64{-0.5: 0, 1: maxJitter, 3: maxJitter} {
+		ctx = NewContext(time.Minute, jitter, time.Second, 10*time.Second)
+		if ctx.Jitter != expected {
+			t.Errorf("Expected jitter %v to be clamped to %v, got %v", jitter, expected, ctx.Jitter)
+		}
+		for i := 0; i < 100; i++ {
+			if d := ctx.nextDelay(0); d <= 0 {
+				t.Fatalf("Expected positive delay with jitter %v, got %v", jitter, d)
+			}
+		}
+	}
+}
+
+func TestDiscoveryAndReport(t *testing.T) {
+	// The loop keeps going after failures, which are retried soon.
+	dd := &fakeLoopDiscoverer{
+		errs:  []error{errors.New("etcd is down"), errors.New("etcd is down")},
+		calls: make(chan int, 10),
+	}
+	ctx := NewContext(time.Hour, 0, time.Millisecond, 2*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		DiscoveryAndReport(dd, ctx)
+		close(done)
+	}()
+	for i := 1; i <= 3; i++ {
+		select {
+		case n := <-dd.calls:
+			if n != i {
+				t.Fatalf("Expected discovery %d, got %d", i, n)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected discovery %d to be retried", i)
+		}
+	}
+
+	// After a success, the next discovery waits for the interval unless it
+	// is refreshed.
+	select {
+	case <-dd.calls:
+		t.Fatal("Expected no discovery before the interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+	ctx.Refresh()
+	ctx.Refresh()
+	select {
+	case <-dd.calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected discovery to be refreshed")
+	}
+
+	// The backends are reloaded before the next discovery, which may follow
+	// a pending refresh.
+	ctx.Reload()
+	for dd.reloads == 0 {
+		select {
+		case <-dd.calls:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected discovery after reload")
+		}
+	}
+	if dd.reloads != 1 {
+		t.Errorf("Expected backends reloaded once, got %d", dd.reloads)
+	}
+
+	ctx.StopChan <- true
+	<-done
+}
+
+func TestReload(t *testing.T) {
+	defer func(dock OsdsDock) { CONF.OsdsDock = dock }(CONF.OsdsDock)
+	CONF.OsdsDock.EnabledBackends = []string{"lvm"}
+	CONF.OsdsDock.Backends.LVM = BackendProperties{Name: "lvm", DriverName: "lvm"}
+
+	sample := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dock-sample"}, Name: "sample",
+		DriverName: "sample", Status: model.DockAvailable}
+	samplePool := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-sample"}, DockId: "dock-sample",
+		Status: availableStatus}
+	lvmPool := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-lvm"}, DockId: "dock-lvm",
+		Status: availableStatus}
+	host, _ := os.Hostname()
+	mockClient := new(dbtest.Client)
+	mockClient.On("GetDock", c.NewAdminContext(), mock.Anything).Return(nil, errors.New("not found"))
+	mockClient.On("ListDocksWithFilter", c.NewAdminContext(), map[string][]string{"Name": {"lvm"}, "NodeId": {host}}).Return(
+		[]*model.DockSpec{{BaseModel: &model.BaseModel{Id: "dock-lvm"}, Name: "lvm"}}, nil)
+	mockClient.On("CreateDock", c.NewAdminContext(), sample).Return(sample, nil)
+	mockClient.On("ListPools", c.NewAdminContext()).Return([]*model.StoragePoolSpec{samplePool, lvmPool}, nil)
+	mockClient.On("CreatePool", c.NewAdminContext(), samplePool).Return(samplePool, nil)
+	fdd := NewFakeDockDiscoverer()
+	fdd.c = mockClient
+	fdd.dcks = []*model.DockSpec{sample}
+
+	if err := fdd.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fdd.dcks) != 1 || fdd.dcks[0].Id != "dock-lvm" {
+		t.Errorf("Expected lvm dock, got %+v", fdd.dcks)
+	}
+	if sample.Status != model.DockUnavailable || sample.StatusReason != disabledReason {
+		t.Errorf("Expected sample dock disabled, got %+v", sample)
+	}
+	if samplePool.Status != unavailableStatus || samplePool.StatusReason != disabledReason ||
+		lvmPool.Status != availableStatus {
+		t.Errorf("Expected sample pool disabled, got %+v and %+v", samplePool, lvmPool)
+	}
+	mockClient.AssertNumberOfCalls(t, "CreateDock", 1)
+	mockClient.AssertNumberOfCalls(t, "CreatePool", 1)
+}
+
+func TestDisable(t *testing.T) {
+	dck := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dock-1"}, Status: model.DockAvailable}
+	pol := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-1"}, DockId: "dock-1",
+		Status: availableStatus}
+	mockClient := new(dbtest.Client)
+	mockClient.On("CreateDock", c.NewAdminContext(), dck).Return(dck, nil)
+	mockClient.On("ListPools", c.NewAdminContext()).Return([]*model.StoragePoolSpec{pol}, nil)
+	mockClient.On("CreatePool", c.NewAdminContext(), pol).Return(pol, nil)
+	fdd := NewFakeDockDiscoverer()
+	fdd.c = mockClient
+	fdd.dcks = []*model.DockSpec{dck}
+
+	if err := fdd.Disable("dock is shut down"); err != nil {
+		t.Fatal(err)
+	}
+	if dck.Status != model.DockUnavailable || pol.Status != unavailableStatus ||
+		dck.StatusReason != "dock is shut down" || pol.StatusReason != "dock is shut down" {
+		t.Errorf("Expected dock and pool disabled, got %+v and %+v", dck, pol)
+	}
+}
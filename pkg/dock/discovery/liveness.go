@@ -0,0 +1,211 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/golang/glog"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/db"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// leaseExpiredReason is the status reason of the docks whose hosts stop
+// keeping their leases alive.
+const leaseExpiredReason = "dock lease expired"
+
+// heartbeat returns the current time in UTC, so that the heartbeats of the
+// docks on different hosts can be compared.
+func heartbeat() string {
+	return time.Now().UTC().Format(constants.TimeFormat)
+}
+
+// heartbeatExpired reports whether the heartbeat is older than ttl, the
+// heartbeats which can't be parsed are expired.
+func heartbeatExpired(hb string, ttl time.Duration) bool {
+	t, err := time.Parse(constants.TimeFormat, hb)
+	return err != nil || time.Now().UTC().Sub(t) > ttl
+}
+
+// keepAlive keeps the dock alive by the lease of this process, unless it is
+// unavailable. The lease is disabled if the db does not support it.
+func (dr *DockRegister) keepAlive(dck *model.DockSpec) error {
+	if dr.liveness == nil || dck.Status == model.DockUnavailable {
+		return nil
+	}
+	err := dr.liveness.KeepDockAlive(dck.Id, dr.ttl)
+	if err == db.ErrLeaseNotSupported {
+		log.Info("Dock leases are disabled: ", err)
+		dr.liveness = nil
+		return nil
+	}
+	return err
+}
+
+// revokeLease removes the liveness of the docks of this process, which are
+// already marked as unavailable.
+func (dr *DockRegister) revokeLease() error {
+	if dr.liveness == nil {
+		return nil
+	}
+	if err := dr.liveness.RevokeDockLease(); err != nil && err != db.ErrLeaseNotSupported {
+		return err
+	}
+	return nil
+}
+
+// WatchDockLiveness marks the docks whose leases expire as unavailable along
+// with their pools until stop is closed. Every dock service watches the
+// leases, so the docks of a dead host are marked as long as any of them is
+// running. It returns at once if the db does not support leases.
+func WatchDockLiveness(stop <-chan struct{}, ttl time.Duration) {
+	liveness, ok := db.C.(db.Liveness)
+	if !ok {
+		return
+	}
+	lw := &livenessWatcher{c: db.C, liveness: liveness, ttl: ttl}
+	lw.run(stop, defaultRetryInterval)
+}
+
+type livenessWatcher struct {
+	c        db.Client
+	liveness db.Liveness
+	ttl      time.Duration
+}
+
+func (lw *livenessWatcher) run(stop <-chan struct{}, retryInterval time.Duration) {
+	delay := retryInterval
+	for {
+		// The docks are scanned before each watch, so that the ones expired
+		// while nobody is watching are marked as well.
+		err := lw.scan()
+		if err == nil {
+			err = lw.liveness.WatchDockLeases(stop, lw.expire)
+		}
+		if err == db.ErrLeaseNotSupported {
+			log.Info("Dock leases are not watched: ", err)
+			return
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if err == nil {
+			delay = retryInterval
+			continue
+		}
+
+		log.Errorf("when watching dock leases, retrying in %v: %v", delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if delay *= 2; delay > defaultInterval {
+			delay = defaultInterval
+		}
+	}
+}
+
+// scan marks the available docks which are not alive as unavailable.
+func (lw *livenessWatcher) scan() error {
+	dcks, err := lw.c.ListDocks(c.NewAdminContext())
+	if err != nil {
+		return err
+	}
+	for _, dck := range dcks {
+		if dck.Status == model.DockUnavailable {
+			continue
+		}
+		if err = lw.markDead(dck.Id, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expire handles the expired lease of the dock, which is trusted without
+// checking the heartbeat of the dock, since the clocks of the hosts may differ
+// and nothing checks the dock again later.
+func (lw *livenessWatcher) expire(dckID string) {
+	if err := lw.markDead(dckID, false); err != nil {
+		log.Errorf("when marking dock %s with expired lease as unavailable: %v", dckID, err)
+	}
+}
+
+// markDead marks the dock and its pools as unavailable if the dock is not
+// alive. If checkHeartbeat is true, the dock whose heartbeat is newer than the
+// ttl is left as it is, since its lease can't be expired yet, and it is being
+// registered again after restarting. The checks are done again on the latest
+// dock and pools when they are updated, so that a dock registered again or a
+// pool refreshed by discovery meanwhile is not overwritten.
+func (lw *livenessWatcher) markDead(dckID string, checkHeartbeat bool) error {
+	ctx := c.NewAdminContext()
+	var expired bool
+	if _, err := lw.c.UpdateDockStatus(ctx, dckID, model.DockUnavailable, leaseExpiredReason, func(dck *model.DockSpec) (bool, error) {
+		dead, err := lw.isDead(dck, checkHeartbeat)
+		if err != nil || !dead {
+			expired = false
+			return false, err
+		}
+		expired = true
+		if dck.Status == model.DockUnavailable {
+			return false, nil
+		}
+		log.Warningf("Lease of dock %s on %s expired, marking it as unavailable", dck.Name, dck.NodeId)
+		return true, nil
+	}); err != nil {
+		return fmt.Errorf("can not update dock in db: %v", err)
+	}
+	if !expired {
+		return nil
+	}
+
+	pols, err := lw.c.ListPools(ctx)
+	if err != nil {
+		return fmt.Errorf("can not read pools in db: %v", err)
+	}
+	for _, pol := range pols {
+		if pol.DockId != dckID || pol.Status == unavailableStatus {
+			continue
+		}
+		if _, err = lw.c.UpdatePoolStatus(ctx, pol.Id, unavailableStatus, leaseExpiredReason, func(pol *model.StoragePoolSpec) (bool, error) {
+			if pol.DockId != dckID || pol.Status == unavailableStatus {
+				return false, nil
+			}
+			alive, err := lw.liveness.IsDockAlive(dckID)
+			return !alive, err
+		}); err != nil {
+			return fmt.Errorf("can not update pool in db: %v", err)
+		}
+	}
+	return nil
+}
+
+// isDead checks whether the dock has no lease, and no heartbeat newer than the
+// ttl either if checkHeartbeat is true.
+func (lw *livenessWatcher) isDead(dck *model.DockSpec, checkHeartbeat bool) (bool, error) {
+	alive, err := lw.liveness.IsDockAlive(dck.Id)
+	if err != nil || alive {
+		return false, err
+	}
+	return !checkHeartbeat || heartbeatExpired(dck.LastHeartbeat, lw.ttl), nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/db"
+	"github.com/sodafoundation/dock/pkg/model"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+	dbtest "github.com/sodafoundation/dock/testutils/db/testing"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeLiveness keeps the liveness of docks in memory.
+type fakeLiveness struct {
+	mu      sync.Mutex
+	alive   map[string]bool
+	err     error
+	expired chan string
+}
+
+func newFakeLiveness() *fakeLiveness {
+	return &fakeLiveness{alive: map[string]bool{}, expired: make(chan string)}
+}
+
+func (f *fakeLiveness) KeepDockAlive(dckID string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.alive[dckID] = true
+	return nil
+}
+
+func (f *fakeLiveness) RevokeDockLease() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.alive = map[string]bool{}
+	return f.err
+}
+
+func (f *fakeLiveness) IsDockAlive(dckID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.alive[dckID], f.err
+}
+
+func (f *fakeLiveness) WatchDockLeases(stop <-chan struct{}, expired func(dckID string)) error {
+	for {
+		select {
+		case <-stop:
+			return nil
+		case id := <-f.expired:
+			expired(id)
+		}
+	}
+}
+
+func TestRegisterKeepAlive(t *testing.T) {
+	dck := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dock-1"}, Status: model.DockAvailable}
+	mockClient := new(dbtest.Client)
+	mockClient.On("CreateDock", c.NewAdminContext(), dck).Return(dck, nil)
+	liveness := newFakeLiveness()
+	dr := &DockRegister{c: mockClient, liveness: liveness, ttl: time.Minute}
+
+	if err := dr.Register(dck); err != nil {
+		t.Fatal(err)
+	}
+	if heartbeatExpired(dck.LastHeartbeat, time.Minute) {
+		t.Errorf("Expected fresh heartbeat, got %s", dck.LastHeartbeat)
+	}
+	if alive, _ := liveness.IsDockAlive(dck.Id); !alive {
+		t.Error("Expected the dock to be kept alive")
+	}
+
+	// The unavailable docks are not kept alive, and the lease is revoked on
+	// disabling.
+	add := &attachDockDiscoverer{DockRegister: dr, dck: dck}
+	if err := add.Disable("dock is shut down"); err != nil {
+		t.Fatal(err)
+	}
+	if alive, _ := liveness.IsDockAlive(dck.Id); alive {
+		t.Error("Expected the lease to be revoked")
+	}
+
+	// The leases are disabled if the db does not support them.
+	liveness.err = db.ErrLeaseNotSupported
+	dck.Status = model.DockAvailable
+	if err := dr.Register(dck); err != nil {
+		t.Fatal(err)
+	}
+	if dr.liveness != nil {
+		t.Error("Expected the leases to be disabled")
+	}
+}
+
+func TestReclaimDock(t *testing.T) {
+	host, _ := os.Hostname()
+	id := uuid.NewV5(uuid.NamespaceOID, host+":sample").String()
+	mockClient := new(dbtest.Client)
+	mockClient.On("GetDock", c.NewAdminContext(), id).Return(&model.DockSpec{BaseModel: &model.BaseModel{Id: id}}, nil)
+	fdd := NewFakeDockDiscoverer()
+	fdd.c = mockClient
+
+	if err := fdd.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fdd.dcks) != 1 || fdd.dcks[0].Id != id {
+		t.Errorf("Expected dock %s to be reclaimed, got %+v", id, fdd.dcks)
+	}
+	mockClient.AssertNotCalled(t, "ListDocksWithFilter", mock.Anything, mock.Anything)
+}
+
+func TestMarkDead(t *testing.T) {
+	old := time.Now().UTC().Add(-time.Hour).Format(constants.TimeFormat)
+	dead := &model.DockSpec{BaseModel: &model.BaseModel{Id: "dead"}, Status: model.DockAvailable, LastHeartbeat: old}
+	alive := &model.DockSpec{BaseModel: &model.BaseModel{Id: "alive"}, Status: model.DockAvailable, LastHeartbeat: old}
+	restarted := &model.DockSpec{BaseModel: &model.BaseModel{Id: "restarted"}, Status: model.DockAvailable,
+		LastHeartbeat: heartbeat()}
+	deadPool := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-dead"}, DockId: "dead", Status: availableStatus}
+	alivePool := &model.StoragePoolSpec{BaseModel: &model.BaseModel{Id: "pool-alive"}, DockId: "alive", Status: availableStatus}
+
+	// The status updates apply the checks to the latest docks and pools.
+	docks := map[string]*model.DockSpec{"dead": dead, "alive": alive, "restarted": restarted}
+	pools := map[string]*model.StoragePoolSpec{"pool-dead": deadPool, "pool-alive": alivePool}
+	var dockUpdates, poolUpdates int
+	mockClient := new(dbtest.Client)
+	mockClient.On("ListDocks", c.NewAdminContext()).Return([]*model.DockSpec{dead, alive, restarted}, nil)
+	mockClient.On("UpdateDockStatus", c.NewAdminContext(), mock.Anything, model.DockUnavailable, leaseExpiredReason, mock.Anything).
+		Return(func(_ *c.Context, id, status, reason string, check func(*model.DockSpec) (bool, error)) bool {
+			ok, _ := check(docks[id])
+			if ok {
+				dockUpdates++
+				docks[id].Status, docks[id].StatusReason = status, reason
+			}
+			return ok
+		}, nil)
+	mockClient.On("ListPools", c.NewAdminContext()).Return([]*model.StoragePoolSpec{deadPool, alivePool}, nil)
+	mockClient.On("UpdatePoolStatus", c.NewAdminContext(), mock.Anything, unavailableStatus, leaseExpiredReason, mock.Anything).
+		Return(func(_ *c.Context, id, status, reason string, check func(*model.StoragePoolSpec) (bool, error)) bool {
+			ok, _ := check(pools[id])
+			if ok {
+				poolUpdates++
+				pools[id].Status, pools[id].StatusReason = status, reason
+			}
+			return ok
+		}, nil)
+	liveness := newFakeLiveness()
+	liveness.alive["alive"] = true
+	lw := &livenessWatcher{c: mockClient, liveness: liveness, ttl: time.Minute}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		lw.run(stop, time.Millisecond)
+		close(done)
+	}()
+	// The expiry is only handled after the scan.
+	liveness.expired <- "alive"
+	close(stop)
+	<-done
+
+	if dead.Status != model.DockUnavailable || dead.StatusReason != leaseExpiredReason ||
+		deadPool.Status != unavailableStatus || deadPool.StatusReason != leaseExpiredReason {
+		t.Errorf("Expected dead dock and pool to be unavailable, got %+v and %+v", dead, deadPool)
+	}
+	if alive.Status != model.DockAvailable || alivePool.Status != availableStatus || restarted.Status != model.DockAvailable {
+		t.Errorf("Expected live docks to be kept, got %+v, %+v and %+v", alive, alivePool, restarted)
+	}
+	if dockUpdates != 1 || poolUpdates != 1 {
+		t.Errorf("Expected 1 dock and 1 pool to be updated, got %d and %d", dockUpdates, poolUpdates)
+	}
+
+	// The expired lease is trusted even if the heartbeat is newer than the
+	// ttl by the clock of this host.
+	lw.expire("restarted")
+	if restarted.Status != model.DockUnavailable {
+		t.Errorf("Expected dock with expired lease to be unavailable, got %+v", restarted)
+	}
+
+	// The watch is retried after failures until stopped.
+	liveness.err = errors.New("etcd is unreachable")
+	stop = make(chan struct{})
+	done = make(chan struct{})
+	go func() {
+		lw.run(stop, time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected the watcher to stop")
+	}
+}
@@ -24,6 +24,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	log "github.com/golang/glog"
@@ -32,14 +36,24 @@ import (
 	"github.com/sodafoundation/dock/contrib/drivers/filesharedrivers"
 	c "github.com/sodafoundation/dock/pkg/context"
 	"github.com/sodafoundation/dock/pkg/db"
+	"github.com/sodafoundation/dock/pkg/dock/alert"
+	"github.com/sodafoundation/dock/pkg/dock/audit"
 	"github.com/sodafoundation/dock/pkg/dock/discovery"
+	"github.com/sodafoundation/dock/pkg/dock/lock"
+	"github.com/sodafoundation/dock/pkg/dock/metrics"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	. "github.com/sodafoundation/dock/pkg/utils/config"
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 
 	_ "github.com/sodafoundation/dock/contrib/connector/fc"
 	_ "github.com/sodafoundation/dock/contrib/connector/iscsi"
+	_ "github.com/sodafoundation/dock/contrib/connector/local"
 	_ "github.com/sodafoundation/dock/contrib/connector/nfs"
 	_ "github.com/sodafoundation/dock/contrib/connector/nvmeof"
 	_ "github.com/sodafoundation/dock/contrib/connector/rbd"
@@ -60,6 +74,12 @@ type dockServer struct {
 	// FileShareDriver represents the specified backend resource. This field is used
 	// for initializing the specified file share driver.
 	FileShareDriver filesharedrivers.FileShareDriver
+
+	// discoveryCtx controls the discovery loop, which is nil until Run.
+	discoveryCtx *discovery.Context
+
+	// locks serialises the conflicting rpcs on the same resources.
+	locks *lock.Manager
 }
 
 // NewDockServer returns a dockServer instance.
@@ -67,44 +87,138 @@ func NewDockServer(dockType, port string) *dockServer {
 	return &dockServer{
 		Port:       port,
 		Discoverer: discovery.NewDockDiscoverer(dockType),
+		locks:      lock.NewManager(CONF.OsdsDock.LockTimeout),
 	}
 }
 
 // Run method would automatically discover dock and pool resources from
 // backends, and then start the listen mechanism of dock module.
 func (ds *dockServer) Run() error {
+	if err := trace.Init(&trace.Config{
+		ServiceName: "osdsdock",
+		Exporter:    CONF.OsdsDock.TraceExporter,
+		Endpoint:    CONF.OsdsDock.TraceEndpoint,
+		File:        CONF.OsdsDock.TraceFile,
+		SampleRatio: CONF.OsdsDock.TraceSampleRatio,
+	}); err != nil {
+		log.Error("when initializing tracing:", err)
+		return err
+	}
+	defer trace.Shutdown()
+
+	// The tracing interceptor is the outermost one so that the spans cover
+	// the other interceptors.
+	interceptors := []grpc.UnaryServerInterceptor{trace.UnaryServerInterceptor, metrics.UnaryServerInterceptor}
+	auditor, err := audit.NewAuditor(&audit.Config{
+		File:           CONF.OsdsDock.AuditLogFile,
+		MaxSize:        CONF.OsdsDock.AuditLogMaxSize,
+		MaxBackups:     CONF.OsdsDock.AuditLogMaxBackups,
+		SyslogEndpoint: CONF.OsdsDock.AuditSyslogEndpoint,
+	})
+	if err != nil {
+		log.Error("when creating audit log:", err)
+		return err
+	}
+	if auditor != nil {
+		defer auditor.Close()
+		interceptors = append(interceptors, auditor.UnaryServerInterceptor)
+	}
+
 	// New Grpc Server
 	s := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{
 		MaxConnectionIdle: 5 * time.Minute, // Keep the connection alive
 	}),
+		grpc.ChainUnaryInterceptor(interceptors...),
 	)
 	// Register dock service.
 	pb.RegisterProvisionDockServer(s, ds)
 	pb.RegisterAttachDockServer(s, ds)
 	pb.RegisterFileShareDockServer(s, ds)
 
+	// Register grpc health service, the dock services are serving until the
+	// discovery finds all of their backends unavailable.
+	hs := health.NewServer()
+	for name := range s.GetServiceInfo() {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, hs)
+
+	// Start the alerting engine if the Alertmanager endpoint is configured,
+	// it is given the results of discovery loop.
+	hostname, _ := os.Hostname()
+	engine, err := alert.NewEngine(&alert.Config{
+		Endpoint:       CONF.OsdsDock.AlertmanagerEndpoint,
+		RulesFile:      CONF.OsdsDock.AlertRulesFile,
+		Interval:       CONF.OsdsDock.AlertEvaluationInterval,
+		ResendInterval: CONF.OsdsDock.AlertResendInterval,
+		Labels:         map[string]string{"node": hostname},
+	})
+	if err != nil {
+		log.Error("when creating alerting engine:", err)
+		return err
+	}
+	alertStop := make(chan struct{})
+	defer close(alertStop)
+
 	// Trigger the discovery and report loop so that the dock service would
 	// update the capabilities from backends automatically.
-	if err := func() error {
-		var err error
-		if err = ds.Discoverer.Init(); err != nil {
+	if err := ds.Discoverer.Init(); err != nil {
+		return err
+	}
+	// The resources are also locked in db if the backends are shared with
+	// other docks.
+	if CONF.OsdsDock.DistributedLock {
+		locker, ok := db.C.(db.Locker)
+		if !ok {
+			err := fmt.Errorf("database driver %s does not support distributed locks", CONF.Database.Driver)
+			log.Error(err)
 			return err
 		}
-		ctx := &discovery.Context{
-			StopChan: make(chan bool),
-			ErrChan:  make(chan error),
-			MetaChan: make(chan string),
+		ds.locks = lock.NewDistributedManager(CONF.OsdsDock.LockTimeout, locker, CONF.OsdsDock.LeaseTTL)
+	}
+	ds.discoveryCtx = discovery.NewContext(CONF.OsdsDock.DiscoveryInterval, CONF.OsdsDock.DiscoveryJitter,
+		CONF.OsdsDock.DiscoveryRetryInterval, CONF.OsdsDock.DiscoveryMaxBackoff)
+	ds.discoveryCtx.Health = hs
+	if engine != nil {
+		ds.discoveryCtx.Observer = engine
+		go engine.Run(alertStop)
+	}
+	discoveryDone := make(chan struct{})
+	go func() {
+		discovery.DiscoveryAndReport(ds.Discoverer, ds.discoveryCtx)
+		close(discoveryDone)
+	}()
+
+	// Mark the docks of the dead hosts as unavailable when their leases
+	// expire.
+	livenessStop := make(chan struct{})
+	defer close(livenessStop)
+	go discovery.WatchDockLiveness(livenessStop, CONF.OsdsDock.LeaseTTL)
+
+	// SIGUSR1 forces an immediate discovery, and SIGHUP reloads the backends
+	// without restarting the grpc server.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+	go func() {
+		for sig := range sigCh {
+			if sig == syscall.SIGHUP {
+				log.Info("Received SIGHUP, reloading config")
+				ds.reloadConfig()
+				continue
+			}
+			log.Info("Received SIGUSR1, refreshing discovery")
+			ds.refreshDiscovery()
 		}
-		go discovery.DiscoveryAndReport(ds.Discoverer, ctx)
-		go func(ctx *discovery.Context) {
-			if err = <-ctx.ErrChan; err != nil {
-				log.Error("when calling capabilty report method:", err)
-				ctx.StopChan <- true
+	}()
+
+	// Serve the prometheus metrics of dock if the endpoint is configured.
+	if ep := CONF.OsdsDock.MetricsEndpoint; ep != "" {
+		go func() {
+			if err := metrics.ListenAndServe(ep); err != nil {
+				log.Error("when serving dock metrics:", err)
 			}
-		}(ctx)
-		return err
-	}(); err != nil {
-		return err
+		}()
 	}
 
 	// Listen the dock server port.
@@ -114,11 +228,86 @@ func (ds *dockServer) Run() error {
 		return err
 	}
 
+	// SIGINT and SIGTERM stop accepting new rpcs and wait for the running
+	// ones to finish before shutting down, a second signal exits at once.
+	grace := CONF.OsdsDock.ShutdownGracePeriod
+	stopCh := make(chan os.Signal, 1)
+	signal.Notify(stopCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stopCh)
+	drained := make(chan error, 1)
+	go func() {
+		sig := <-stopCh
+		log.Infof("Received %v, shutting down within %v", sig, grace)
+		hs.Shutdown()
+		go func() { drained <- drain(s, grace) }()
+		sig = <-stopCh
+		log.Warningf("Received %v during shutdown, exiting at once", sig)
+		log.Flush()
+		os.Exit(1)
+	}()
+
 	log.Info("Dock server initialized! Start listening on port:", lis.Addr())
 
 	// Start dock server watching loop.
 	defer s.Stop()
-	return s.Serve(lis)
+	if err := s.Serve(lis); err != nil {
+		return err
+	}
+	// Serve returns nil once the server is being stopped.
+	err = <-drained
+	if serr := ds.shutdown(discoveryDone, grace); err == nil {
+		err = serr
+	}
+	log.Info("Dock server is shut down")
+	return err
+}
+
+// errDrainTimeout is returned if the running rpcs are stopped because they do
+// not finish within the shutdown grace period.
+var errDrainTimeout = errors.New("running rpcs did not finish within shutdown grace period")
+
+// drain stops the grpc server from accepting new rpcs, and waits for the
+// running ones to finish within the grace period before stopping them.
+func drain(s *grpc.Server, grace time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		log.Warningf("Running rpcs did not finish within %v, stopping them", grace)
+		s.Stop()
+		return errDrainTimeout
+	}
+}
+
+// shutdown stops the discovery loop, marks the docks and pools of this dock
+// as unavailable in db, and unsets the drivers left by the stopped rpcs.
+func (ds *dockServer) shutdown(discoveryDone <-chan struct{}, grace time.Duration) error {
+	var err error
+	close(ds.discoveryCtx.StopChan)
+	select {
+	case <-discoveryDone:
+		if d, ok := ds.Discoverer.(discovery.Disabler); ok {
+			if err = d.Disable("dock is shut down"); err != nil {
+				log.Error("when marking docks as unavailable:", err)
+			}
+		}
+	case <-time.After(grace):
+		// The docks are still used by the discovery loop, so they are left
+		// to it rather than updated concurrently.
+		err = errors.New("discovery loop did not stop within shutdown grace period")
+		log.Error(err)
+	}
+	if n := drivers.CleanAll() + filesharedrivers.CleanAll(); n > 0 {
+		log.Warningf("Unset %d drivers left by the stopped rpcs", n)
+	}
+	return err
 }
 
 // CreateVolume implements pb.DockServer.CreateVolume
@@ -127,29 +316,75 @@ func (ds *dockServer) CreateVolume(ctx context.Context, opt *pb.CreateVolumeOpts
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive create volume request, vr =", opt)
+	log.Info("Dock server receive create volume request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetId()), lock.Snapshot(opt.GetSnapshotId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	if err := checkPoolCapacity(c.NewContextFromJson(opt.GetContext()), opt.GetPoolId(), opt.GetSize()); err != nil {
+		log.Error("when create volume in dock module:", err)
+		return pb.GenericResponseError(err), err
+	}
 
 	vol, err := ds.Driver.CreateVolume(opt)
 	if err != nil {
 		log.Error("when create volume in dock module:", err)
 		return pb.GenericResponseError(err), err
 	}
+	ds.refreshDiscovery()
 
 	return pb.GenericResponseResult(vol), nil
 }
 
+// checkPoolCapacity rejects the request which takes more capacity than the
+// pool can provision considering its reserved percentage and over-subscription
+// ratio. The check is skipped if the pool is unknown, so that a stale db does
+// not block provisioning.
+func checkPoolCapacity(ctx *c.Context, poolId string, size int64) error {
+	if size <= 0 || poolId == "" {
+		return nil
+	}
+	pol, err := db.C.GetPool(ctx, poolId)
+	if err != nil {
+		log.Warningf("Skip checking capacity of pool %s: %v", poolId, err)
+		return nil
+	}
+	if pol.TotalCapacity <= 0 {
+		return nil
+	}
+	if avail := pol.ProvisionableCapacity(); size > avail {
+		return fmt.Errorf("pool %s can provision %d GB at most, which is less than the requested %d GB "+
+			"(total %d GB, free %d GB, provisioned %d GB, reserved %d%%, max over-subscription ratio %g)",
+			pol.Name, avail, size, pol.TotalCapacity, pol.FreeCapacity, pol.ProvisionedCapacity,
+			pol.ReservedPercentage, pol.MaxOverSubscriptionRatio)
+	}
+	return nil
+}
+
 // DeleteVolume implements pb.DockServer.DeleteVolume
 func (ds *dockServer) DeleteVolume(ctx context.Context, opt *pb.DeleteVolumeOpts) (*pb.GenericResponse, error) {
 	// Get the storage drivers and do some initializations.
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive delete volume request, vr =", opt)
+	log.Info("Dock server receive delete volume request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.Driver.DeleteVolume(opt); err != nil {
 		log.Error("error occurred in dock module when delete volume:", err)
 		return pb.GenericResponseError(err), err
 	}
+	ds.refreshDiscovery()
 
 	return pb.GenericResponseResult(nil), nil
 }
@@ -160,13 +395,30 @@ func (ds *dockServer) ExtendVolume(ctx context.Context, opt *pb.ExtendVolumeOpts
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive extend volume request, vr =", opt)
+	log.Info("Dock server receive extend volume request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	dbCtx := c.NewContextFromJson(opt.GetContext())
+	if vol, err := db.C.GetVolume(dbCtx, opt.GetId()); err != nil {
+		log.Warningf("Skip checking capacity of pool %s since volume %s is not found: %v",
+			opt.GetPoolId(), opt.GetId(), err)
+	} else if err := checkPoolCapacity(dbCtx, opt.GetPoolId(), opt.GetSize()-vol.Size); err != nil {
+		log.Error("when extend volume in dock module:", err)
+		return pb.GenericResponseError(err), err
+	}
 
 	vol, err := ds.Driver.ExtendVolume(opt)
 	if err != nil {
 		log.Error("when extend volume in dock module:", err)
 		return pb.GenericResponseError(err), err
 	}
+	ds.refreshDiscovery()
 
 	return pb.GenericResponseResult(vol), nil
 }
@@ -177,7 +429,14 @@ func (ds *dockServer) CreateVolumeAttachment(ctx context.Context, opt *pb.Create
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive create volume attachment request, vr =", opt)
+	log.Info("Dock server receive create volume attachment request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetVolumeId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	connInfo, err := ds.Driver.InitializeConnection(opt)
 	if err != nil {
@@ -191,7 +450,8 @@ func (ds *dockServer) CreateVolumeAttachment(ctx context.Context, opt *pb.Create
 		},
 		ConnectionInfo: *connInfo,
 	}
-	log.V(8).Infof("CreateVolumeAttachment result: %v", atc)
+	log.V(8).Infof("CreateVolumeAttachment result: %s, %v", atc.DriverVolumeType,
+		model.RedactConnectionData(atc.ConnectionData))
 	return pb.GenericResponseResult(atc), nil
 }
 
@@ -201,7 +461,14 @@ func (ds *dockServer) DeleteVolumeAttachment(ctx context.Context, opt *pb.Delete
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive delete volume attachment request, vr =", opt)
+	log.Info("Dock server receive delete volume attachment request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetVolumeId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.Driver.TerminateConnection(opt); err != nil {
 		log.Error("error occurred in dock module when terminate volume connection:", err)
@@ -217,7 +484,14 @@ func (ds *dockServer) CreateVolumeSnapshot(ctx context.Context, opt *pb.CreateVo
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive create volume snapshot request, vr =", opt)
+	log.Info("Dock server receive create volume snapshot request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetVolumeId()), lock.Snapshot(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	snp, err := ds.Driver.CreateSnapshot(opt)
 	if err != nil {
@@ -234,7 +508,14 @@ func (ds *dockServer) DeleteVolumeSnapshot(ctx context.Context, opt *pb.DeleteVo
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive delete volume snapshot request, vr =", opt)
+	log.Info("Dock server receive delete volume snapshot request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.Volume(opt.GetVolumeId()), lock.Snapshot(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.Driver.DeleteSnapshot(opt); err != nil {
 		log.Error("error occurred in dock module when delete snapshot:", err)
@@ -252,7 +533,9 @@ func (ds *dockServer) AttachVolume(ctx context.Context, opt *pb.AttachVolumeOpts
 		return pb.GenericResponseError(err), err
 	}
 
-	log.Info("Dock server receive attach volume request, vr =", opt)
+	// The connection data may contain secrets, so only the redacted one is logged.
+	log.Infof("Dock server receive attach volume request, access protocol: %s, connection data: %v",
+		opt.GetAccessProtocol(), model.RedactConnectionData(connData))
 
 	con := connector.NewConnector(opt.GetAccessProtocol())
 	if con == nil {
@@ -276,7 +559,8 @@ func (ds *dockServer) DetachVolume(ctx context.Context, opt *pb.DetachVolumeOpts
 		return pb.GenericResponseError(err), err
 	}
 
-	log.Info("Dock server receive detach volume request, vr =", opt)
+	log.Infof("Dock server receive detach volume request, access protocol: %s, connection data: %v",
+		opt.GetAccessProtocol(), model.RedactConnectionData(connData))
 
 	con := connector.NewConnector(opt.GetAccessProtocol())
 	if con == nil {
@@ -291,13 +575,60 @@ func (ds *dockServer) DetachVolume(ctx context.Context, opt *pb.DetachVolumeOpts
 	return pb.GenericResponseResult(nil), nil
 }
 
+// replicationLocks returns the lock keys of the replication and its volumes.
+func replicationLocks(id, primaryVolumeId, secondaryVolumeId string) []string {
+	return []string{lock.Replication(id), lock.Volume(primaryVolumeId), lock.Volume(secondaryVolumeId)}
+}
+
+// checkReplicationDetached rejects the request if any volume of the replication
+// is attached, since the data would be changed underneath the users. The check
+// is skipped for the volumes which are not found, so that a stale db does not
+// block the recovery.
+func checkReplicationDetached(ctx *c.Context, action string, volIds ...string) error {
+	for _, id := range volIds {
+		vol, err := db.C.GetVolume(ctx, id)
+		if err != nil {
+			log.Warningf("Skip checking attachment of volume %s: %v", id, err)
+			continue
+		}
+		if vol.Status == model.VolumeInUse || vol.AttachStatus == model.VolumeAttached {
+			return fmt.Errorf("can not %s replication since volume %s is attached", action, id)
+		}
+	}
+	return nil
+}
+
+// checkReplicationState rejects the request if the replication pair on the
+// backend is not in one of the expected running states.
+func checkReplicationState(driver drivers.ReplicationDriver, opt *pb.GetReplicationStatusOpts, action string, states ...string) error {
+	st, err := driver.GetReplicationStatus(opt)
+	if err != nil {
+		return fmt.Errorf("failed to get the status of replication %s before %s: %v", opt.GetId(), action, err)
+	}
+	for _, s := range states {
+		if st.RunningStatus == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("can not %s replication %s whose running status is %s, expected %s",
+		action, opt.GetId(), st.RunningStatus, strings.Join(states, " or "))
+}
+
 // CreateReplication implements opensds.DockServer
 func (ds *dockServer) CreateReplication(ctx context.Context, opt *pb.CreateReplicationOpts) (*pb.GenericResponse, error) {
 	//Get the storage replication drivers and do some initializations.
 	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
 	defer drivers.CleanReplicationDriver(driver)
 
-	log.Info("Dock server receive create replication request, vr =", opt)
+	log.Info("Dock server receive create replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
 	replica, err := driver.CreateReplication(opt)
 	if err != nil {
 		log.Error("error occurred in dock module when create replication:", err)
@@ -315,7 +646,14 @@ func (ds *dockServer) DeleteReplication(ctx context.Context, opt *pb.DeleteRepli
 	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
 	defer drivers.CleanReplicationDriver(driver)
 
-	log.Info("Dock server receive delete replication request, vr =", opt)
+	log.Info("Dock server receive delete replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := driver.DeleteReplication(opt); err != nil {
 		log.Error("error occurred in dock module when delete replication:", err)
@@ -330,7 +668,14 @@ func (ds *dockServer) EnableReplication(ctx context.Context, opt *pb.EnableRepli
 	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
 	defer drivers.CleanReplicationDriver(driver)
 
-	log.Info("Dock server receive enable replication request, vr =", opt)
+	log.Info("Dock server receive enable replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := driver.EnableReplication(opt); err != nil {
 		log.Error("error occurred in dock module when enable replication:", err)
@@ -345,7 +690,14 @@ func (ds *dockServer) DisableReplication(ctx context.Context, opt *pb.DisableRep
 	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
 	defer drivers.CleanReplicationDriver(driver)
 
-	log.Info("Dock server receive disable replication request, vr =", opt)
+	log.Info("Dock server receive disable replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := driver.DisableReplication(opt); err != nil {
 		log.Error("error occurred in dock module when disable replication:", err)
@@ -360,7 +712,24 @@ func (ds *dockServer) FailoverReplication(ctx context.Context, opt *pb.FailoverR
 	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
 	defer drivers.CleanReplicationDriver(driver)
 
-	log.Info("Dock server receive failover replication request, vr =", opt)
+	log.Info("Dock server receive failover replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	// The primary site may be unavailable, so the state of the pair is not
+	// checked, only the volume which keeps being written is.
+	if !opt.GetAllowAttachedVolume() {
+		if err := checkReplicationDetached(c.NewContextFromJson(opt.GetContext()), "failover",
+			opt.GetPrimaryVolumeId()); err != nil {
+			log.Error("error occurred in dock module when failover replication:", err)
+			return pb.GenericResponseError(err), err
+		}
+	}
 
 	if err := driver.FailoverReplication(opt); err != nil {
 		log.Error("error occurred in dock module when failover replication:", err)
@@ -370,30 +739,137 @@ func (ds *dockServer) FailoverReplication(ctx context.Context, opt *pb.FailoverR
 	return pb.GenericResponseResult(nil), nil
 }
 
+// SwitchoverReplication implements pb.DockServer.SwitchoverReplication, the
+// replication must be healthy and its volumes must not be attached, so that
+// no data is lost when the roles are swapped.
+func (ds *dockServer) SwitchoverReplication(ctx context.Context, opt *pb.SwitchoverReplicationOpts) (*pb.GenericResponse, error) {
+	// Get the storage replication drivers and do some initializations.
+	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
+	defer drivers.CleanReplicationDriver(driver)
+
+	log.Info("Dock server receive switchover replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	if !opt.GetAllowAttachedVolume() {
+		err = checkReplicationDetached(c.NewContextFromJson(opt.GetContext()), "switchover",
+			opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())
+	}
+	if err == nil && opt.GetIsPrimary() {
+		err = checkReplicationState(driver, &pb.GetReplicationStatusOpts{
+			Id:                             opt.GetId(),
+			PrimaryVolumeId:                opt.GetPrimaryVolumeId(),
+			SecondaryVolumeId:              opt.GetSecondaryVolumeId(),
+			PoolId:                         opt.GetPoolId(),
+			PrimaryReplicationDriverData:   opt.GetPrimaryReplicationDriverData(),
+			SecondaryReplicationDriverData: opt.GetSecondaryReplicationDriverData(),
+			DriverName:                     opt.GetDriverName(),
+			Context:                        opt.GetContext(),
+			Metadata:                       opt.GetMetadata(),
+			IsPrimary:                      true,
+		}, "switchover", model.ReplicationRunningSynced, model.ReplicationRunningSyncing)
+	}
+	if err != nil {
+		log.Error("error occurred in dock module when switchover replication:", err)
+		return pb.GenericResponseError(err), err
+	}
+
+	if err := driver.SwitchoverReplication(opt); err != nil {
+		log.Error("error occurred in dock module when switchover replication:", err)
+		return pb.GenericResponseError(err), err
+	}
+
+	return pb.GenericResponseResult(nil), nil
+}
+
+// ReprotectReplication implements pb.DockServer.ReprotectReplication, the
+// replication must be failed over, and the primary volume whose data is
+// overwritten must not be attached.
+func (ds *dockServer) ReprotectReplication(ctx context.Context, opt *pb.ReprotectReplicationOpts) (*pb.GenericResponse, error) {
+	// Get the storage replication drivers and do some initializations.
+	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
+	defer drivers.CleanReplicationDriver(driver)
+
+	log.Info("Dock server receive reprotect replication request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, replicationLocks(opt.GetId(), opt.GetPrimaryVolumeId(), opt.GetSecondaryVolumeId())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	err = checkReplicationDetached(c.NewContextFromJson(opt.GetContext()), "reprotect", opt.GetPrimaryVolumeId())
+	if err == nil && opt.GetIsPrimary() {
+		err = checkReplicationState(driver, &pb.GetReplicationStatusOpts{
+			Id:                             opt.GetId(),
+			PrimaryVolumeId:                opt.GetPrimaryVolumeId(),
+			SecondaryVolumeId:              opt.GetSecondaryVolumeId(),
+			PoolId:                         opt.GetPoolId(),
+			PrimaryReplicationDriverData:   opt.GetPrimaryReplicationDriverData(),
+			SecondaryReplicationDriverData: opt.GetSecondaryReplicationDriverData(),
+			DriverName:                     opt.GetDriverName(),
+			Context:                        opt.GetContext(),
+			Metadata:                       opt.GetMetadata(),
+			IsPrimary:                      true,
+		}, "reprotect", model.ReplicationRunningSplit, model.ReplicationRunningInterrupted)
+	}
+	if err != nil {
+		log.Error("error occurred in dock module when reprotect replication:", err)
+		return pb.GenericResponseError(err), err
+	}
+
+	if err := driver.ReprotectReplication(opt); err != nil {
+		log.Error("error occurred in dock module when reprotect replication:", err)
+		return pb.GenericResponseError(err), err
+	}
+
+	return pb.GenericResponseResult(nil), nil
+}
+
+// GetReplicationStatus implements pb.DockServer.GetReplicationStatus, it only
+// reads the state of the replication, so it is not blocked by the operations
+// in progress.
+func (ds *dockServer) GetReplicationStatus(ctx context.Context, opt *pb.GetReplicationStatusOpts) (*pb.GenericResponse, error) {
+	// Get the storage replication drivers and do some initializations.
+	driver, _ := drivers.InitReplicationDriver(opt.GetDriverName())
+	defer drivers.CleanReplicationDriver(driver)
+
+	log.V(5).Info("Dock server receive get replication status request:", audit.RequestIds(opt))
+
+	st, err := driver.GetReplicationStatus(opt)
+	if err != nil {
+		log.Error("error occurred in dock module when get replication status:", err)
+		return pb.GenericResponseError(err), err
+	}
+	st.UpdatedAt = time.Now().Format(constants.TimeFormat)
+
+	return pb.GenericResponseResult(st), nil
+}
+
 // CreateVolumeGroup implements pb.DockServer.CreateVolumeGroup
 func (ds *dockServer) CreateVolumeGroup(ctx context.Context, opt *pb.CreateVolumeGroupOpts) (*pb.GenericResponse, error) {
 	// Get the storage drivers and do some initializations.
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive create volume group request, vr =", opt)
+	log.Info("Dock server receive create volume group request:", audit.RequestIds(opt))
 
+	// A backend without group support can't create the group, which would
+	// only fail later when the group snapshot is taken.
 	vg, err := ds.Driver.CreateVolumeGroup(opt)
 	if err != nil {
-		if _, ok := err.(*model.NotImplementError); !ok {
-			log.Error("when calling volume driver to create volume group:", err)
-			return pb.GenericResponseError(err), err
-		}
+		log.Error("when calling volume driver to create volume group:", err)
+		return pb.GenericResponseError(err), err
 	}
 
 	log.Infof("Create volume group (%s) successfully.\n", opt.GetId())
-	// TODO Currently no storage driver has implemented with volume group operations,
-	// So we will fetch the volume group resource from database as the fake one.
-	vg, err = db.C.GetVolumeGroup(c.NewContextFromJson(opt.GetContext()), opt.GetId())
-	if err != nil {
-		return pb.GenericResponseError(err), err
-	}
-	return pb.GenericResponseResult(vg), nil
+	return ds.volumeGroupResult(opt.GetContext(), opt.GetId(), vg)
 }
 
 func (ds *dockServer) UpdateVolumeGroup(ctx context.Context, opt *pb.UpdateVolumeGroupOpts) (*pb.GenericResponse, error) {
@@ -401,24 +877,16 @@ func (ds *dockServer) UpdateVolumeGroup(ctx context.Context, opt *pb.UpdateVolum
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive update volume group request, vr =", opt)
+	log.Info("Dock server receive update volume group request:", audit.RequestIds(opt))
 
 	vg, err := ds.Driver.UpdateVolumeGroup(opt)
 	if err != nil {
-		if _, ok := err.(*model.NotImplementError); !ok {
-			err = errors.New("error occurred when updating group" + opt.GetId() + "," + err.Error())
-			return pb.GenericResponseError(err), err
-		}
+		err = errors.New("error occurred when updating group" + opt.GetId() + "," + err.Error())
+		return pb.GenericResponseError(err), err
 	}
 
 	log.Infof("Update volume group (%s) successfully.\n", opt.GetId())
-	// TODO Currently no storage driver has implemented with volume group operations,
-	// So we will fetch the volume group resource from database as the fake one.
-	vg, err = db.C.GetVolumeGroup(c.NewContextFromJson(opt.GetContext()), opt.GetId())
-	if err != nil {
-		return pb.GenericResponseError(err), err
-	}
-	return pb.GenericResponseResult(vg), nil
+	return ds.volumeGroupResult(opt.GetContext(), opt.GetId(), vg)
 }
 
 func (ds *dockServer) DeleteVolumeGroup(ctx context.Context, opt *pb.DeleteVolumeGroupOpts) (*pb.GenericResponse, error) {
@@ -426,30 +894,146 @@ func (ds *dockServer) DeleteVolumeGroup(ctx context.Context, opt *pb.DeleteVolum
 	ds.Driver = drivers.Init(opt.GetDriverName())
 	defer drivers.Clean(ds.Driver)
 
-	log.Info("Dock server receive delete volume group request, vr =", opt)
+	log.Info("Dock server receive delete volume group request:", audit.RequestIds(opt))
+
+	dbCtx := c.NewContextFromJson(opt.GetContext())
+	volumes, err := db.C.ListVolumesByGroupId(dbCtx, opt.GetId())
+	if err != nil {
+		return pb.GenericResponseError(err), err
+	}
+	// The group snapshots are created while the member volumes are locked, so
+	// they can't be added once the group is checked.
+	var keys []string
+	for _, volRef := range volumes {
+		keys = append(keys, lock.Volume(volRef.Id))
+	}
+	unlock, err := ds.locks.Lock(ctx, keys...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	// The snapshots of the member volumes which are taken by the group
+	// snapshots keep the volumes from being deleted.
+	vg, err := db.C.GetVolumeGroup(dbCtx, opt.GetId())
+	if err != nil {
+		return pb.GenericResponseError(err), err
+	}
+	if len(vg.GroupSnapshots) != 0 {
+		err = fmt.Errorf("volume group %s still has group snapshots %v, delete them first", opt.GetId(), vg.GroupSnapshots)
+		log.Error(err)
+		return pb.GenericResponseError(err), err
+	}
 
+	// The drivers only remove the group on the backend, the volumes of the
+	// group are deleted one by one afterwards.
 	if err := ds.Driver.DeleteVolumeGroup(opt); err != nil {
 		if _, ok := err.(*model.NotImplementError); !ok {
 			return pb.GenericResponseError(err), err
 		}
-		if err = ds.deleteGroupGeneric(opt); err != nil {
-			return pb.GenericResponseError(err), err
-		}
 	}
+	ds.deleteGroupGeneric(dbCtx, opt, volumes)
 
 	log.Infof("Delete volume group (%s) successfully.\n", opt.GetId())
 	return pb.GenericResponseResult(nil), nil
 }
 
-func (ds *dockServer) deleteGroupGeneric(opt *pb.DeleteVolumeGroupOpts) error {
-	ctx := c.NewContextFromJson(opt.GetContext())
+// volumeGroupResult returns the volume group in database with the status and
+// metadata returned by the driver, if it implements volume groups.
+func (ds *dockServer) volumeGroupResult(ctxJson, vgId string, driverVg *model.VolumeGroupSpec) (*pb.GenericResponse, error) {
+	vg, err := db.C.GetVolumeGroup(c.NewContextFromJson(ctxJson), vgId)
+	if err != nil {
+		return pb.GenericResponseError(err), err
+	}
+	if driverVg != nil {
+		if driverVg.Status != "" {
+			vg.Status = driverVg.Status
+		}
+		if len(driverVg.Metadata) != 0 {
+			if vg.Metadata == nil {
+				vg.Metadata = map[string]string{}
+			}
+			for k, v := range driverVg.Metadata {
+				vg.Metadata[k] = v
+			}
+		}
+	}
+	return pb.GenericResponseResult(vg), nil
+}
+
+// CreateGroupSnapshot implements pb.DockServer.CreateGroupSnapshot
+func (ds *dockServer) CreateGroupSnapshot(ctx context.Context, opt *pb.CreateGroupSnapshotOpts) (*pb.GenericResponse, error) {
+	// Get the storage drivers and do some initializations.
+	ds.Driver = drivers.Init(opt.GetDriverName())
+	defer drivers.Clean(ds.Driver)
+
+	log.Info("Dock server receive create group snapshot request:", audit.RequestIds(opt))
 
-	volumes, err := db.C.ListVolumesByGroupId(ctx, opt.GetId())
+	unlock, err := ds.locks.Lock(ctx, groupSnapshotLocks(opt.GetSnapshots())...)
 	if err != nil {
-		return err
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	gs, err := ds.Driver.CreateGroupSnapshot(opt)
+	if err != nil {
+		log.Error("error occurred in dock module when create group snapshot:", err)
+		return pb.GenericResponseError(err), err
+	}
+	if _, err = db.C.UpdateGroupSnapshots(c.NewContextFromJson(opt.GetContext()), opt.GetGroupId(), []string{opt.GetId()}, nil); err != nil {
+		log.Error("error occurred in dock module when update volume group:", err)
+		return pb.GenericResponseError(err), err
 	}
+
+	log.Infof("Create group snapshot (%s) successfully.\n", opt.GetId())
+	return pb.GenericResponseResult(gs), nil
+}
+
+// DeleteGroupSnapshot implements pb.DockServer.DeleteGroupSnapshot
+func (ds *dockServer) DeleteGroupSnapshot(ctx context.Context, opt *pb.DeleteGroupSnapshotOpts) (*pb.GenericResponse, error) {
+	// Get the storage drivers and do some initializations.
+	ds.Driver = drivers.Init(opt.GetDriverName())
+	defer drivers.Clean(ds.Driver)
+
+	log.Info("Dock server receive delete group snapshot request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, groupSnapshotLocks(opt.GetSnapshots())...)
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
+
+	if err = ds.Driver.DeleteGroupSnapshot(opt); err != nil {
+		log.Error("error occurred in dock module when delete group snapshot:", err)
+		return pb.GenericResponseError(err), err
+	}
+	if _, err = db.C.UpdateGroupSnapshots(c.NewContextFromJson(opt.GetContext()), opt.GetGroupId(), nil, []string{opt.GetId()}); err != nil {
+		log.Error("error occurred in dock module when update volume group:", err)
+		return pb.GenericResponseError(err), err
+	}
+
+	log.Infof("Delete group snapshot (%s) successfully.\n", opt.GetId())
+	return pb.GenericResponseResult(nil), nil
+}
+
+// groupSnapshotLocks returns the lock keys of the volumes and snapshots of a
+// group snapshot.
+func groupSnapshotLocks(snps []*pb.GroupSnapshotMember) []string {
+	var keys []string
+	for _, s := range snps {
+		keys = append(keys, lock.Volume(s.GetVolumeId()), lock.Snapshot(s.GetId()))
+	}
+	return keys
+}
+
+// deleteGroupGeneric deletes the volumes of the group, which are locked by the
+// caller so that no group snapshot is taken from them meanwhile.
+func (ds *dockServer) deleteGroupGeneric(ctx *c.Context, opt *pb.DeleteVolumeGroupOpts, volumes []*model.VolumeSpec) {
 	for _, volRef := range volumes {
-		if err = ds.Driver.DeleteVolume(&pb.DeleteVolumeOpts{
+		if err := ds.Driver.DeleteVolume(&pb.DeleteVolumeOpts{
 			Id:       volRef.Id,
 			Metadata: volRef.Metadata,
 		}); err != nil {
@@ -462,18 +1046,20 @@ func (ds *dockServer) deleteGroupGeneric(opt *pb.DeleteVolumeGroupOpts) error {
 			db.C.DeleteVolume(ctx, volRef.Id)
 		}
 	}
-
-	return nil
 }
 
 // Collect the specified metrics from the metric driver
 func (ds *dockServer) CollectMetrics(ctx context.Context, opt *pb.CollectMetricsOpts) (*pb.GenericResponse, error) {
 	log.V(5).Info("in dock CollectMetrics methods")
 	ds.MetricDriver = drivers.InitMetricDriver(opt.GetDriverName())
-
+	if ds.MetricDriver == nil {
+		err := fmt.Errorf("metric driver %s is not supported", opt.GetDriverName())
+		log.Error(err)
+		return pb.GenericResponseError(err), err
+	}
 	defer drivers.CleanMetricDriver(ds.MetricDriver)
 
-	log.Infof("dock server receive CollectMetrics request, vr =%s", opt)
+	log.Info("dock server receive CollectMetrics request:", audit.RequestIds(opt))
 
 	result, err := ds.MetricDriver.CollectMetrics()
 	if err != nil {
@@ -490,7 +1076,14 @@ func (ds *dockServer) CreateFileShareAcl(ctx context.Context, opt *pb.CreateFile
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("dock server receive create file share acl request, vr =", opt)
+	log.Info("dock server receive create file share acl request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetFileshareId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	fileshare, err := ds.FileShareDriver.CreateFileShareAcl(opt)
 	if err != nil {
@@ -507,7 +1100,14 @@ func (ds *dockServer) DeleteFileShareAcl(ctx context.Context, opt *pb.DeleteFile
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("dock server receive delete file share acl request, vr =", opt)
+	log.Info("dock server receive delete file share acl request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetFileshareId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.FileShareDriver.DeleteFileShareAcl(opt); err != nil {
 		log.Error("when delete file share acl in dock module:", err)
@@ -523,7 +1123,14 @@ func (ds *dockServer) CreateFileShare(ctx context.Context, opt *pb.CreateFileSha
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("Dock server receive create file share request, vr =", opt)
+	log.Info("Dock server receive create file share request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetId()), lock.FileShareSnapshot(opt.GetSnapshotId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	log.V(5).Infof("Dock server create fleshare: sent to Driver %+v", opt.GetDriverName())
 
@@ -532,6 +1139,7 @@ func (ds *dockServer) CreateFileShare(ctx context.Context, opt *pb.CreateFileSha
 		log.Error("when create file share in dock module:", err)
 		return pb.GenericResponseError(err), err
 	}
+	ds.refreshDiscovery()
 
 	return pb.GenericResponseResult(fileshare), nil
 }
@@ -543,12 +1151,20 @@ func (ds *dockServer) DeleteFileShare(ctx context.Context, opt *pb.DeleteFileSha
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("Dock server receive delete file share request, vr =", opt)
+	log.Info("Dock server receive delete file share request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.FileShareDriver.DeleteFileShare(opt); err != nil {
 		log.Error("error occurred in dock module when delete file share:", err)
 		return pb.GenericResponseError(err), err
 	}
+	ds.refreshDiscovery()
 
 	return pb.GenericResponseResult(nil), nil
 }
@@ -559,7 +1175,14 @@ func (ds *dockServer) CreateFileShareSnapshot(ctx context.Context, opt *pb.Creat
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("Dock server receive create file share snapshot request, vr =", opt)
+	log.Info("Dock server receive create file share snapshot request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetFileshareId()), lock.FileShareSnapshot(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	snp, err := ds.FileShareDriver.CreateFileShareSnapshot(opt)
 	if err != nil {
@@ -575,7 +1198,14 @@ func (ds *dockServer) DeleteFileShareSnapshot(ctx context.Context, opt *pb.Delet
 	ds.FileShareDriver = filesharedrivers.Init(opt.GetDriverName())
 	defer filesharedrivers.Clean(ds.FileShareDriver)
 
-	log.Info("Dock server receive delete file share snapshot request, vr =", opt)
+	log.Info("Dock server receive delete file share snapshot request:", audit.RequestIds(opt))
+
+	unlock, err := ds.locks.Lock(ctx, lock.FileShare(opt.GetFileshareId()), lock.FileShareSnapshot(opt.GetId()))
+	if err != nil {
+		log.Error("error occurred in dock module when lock resources:", err)
+		return pb.GenericResponseError(err), err
+	}
+	defer unlock()
 
 	if err := ds.FileShareDriver.DeleteFileShareSnapshot(opt); err != nil {
 		log.Error("error occurred in dock module when delete snapshot:", err)
@@ -596,3 +1226,52 @@ func (ds *dockServer) GetMetrics(context.Context, *pb.GetMetricsOpts) (*pb.Gener
 func (ds *dockServer) GetUrls(context.Context, *pb.NoParams) (*pb.GenericResponse, error) {
 	return nil, &model.NotImplementError{"method GetUrls has not been implemented yet"}
 }
+
+// RefreshDiscovery implements pb.DockServer.RefreshDiscovery, the discovery
+// runs asynchronously and the triggers during a pending one are coalesced.
+func (ds *dockServer) RefreshDiscovery(context.Context, *pb.NoParams) (*pb.GenericResponse, error) {
+	log.Info("Dock server receive refresh discovery request")
+	if ds.discoveryCtx == nil {
+		err := errors.New("discovery loop is not running")
+		return pb.GenericResponseError(err), err
+	}
+	ds.refreshDiscovery()
+	return pb.GenericResponseResult(nil), nil
+}
+
+// ReloadConfig implements pb.DockServer.ReloadConfig, the new backends are
+// validated before the reply, and then discovered asynchronously.
+func (ds *dockServer) ReloadConfig(context.Context, *pb.NoParams) (*pb.GenericResponse, error) {
+	log.Info("Dock server receive reload config request")
+	if ds.discoveryCtx == nil {
+		err := errors.New("discovery loop is not running")
+		return pb.GenericResponseError(err), err
+	}
+	if err := ds.reloadConfig(); err != nil {
+		return pb.GenericResponseError(err), err
+	}
+	return pb.GenericResponseResult(nil), nil
+}
+
+// reloadConfig re-reads the backends from the config file, and makes the
+// discovery loop register the newly enabled ones and disable the removed
+// ones. The config is kept unchanged if the new one is invalid.
+func (ds *dockServer) reloadConfig() error {
+	if err := ReloadBackends(); err != nil {
+		log.Error("Reload config failed:", err)
+		return err
+	}
+	log.Infof("Config reloaded, enabled backends: %v", GetEnabledBackends())
+	if ds.discoveryCtx != nil {
+		ds.discoveryCtx.Reload()
+	}
+	return nil
+}
+
+// refreshDiscovery triggers a discovery so that the pool capacities in db are
+// updated soon after they are changed by the dock.
+func (ds *dockServer) refreshDiscovery() {
+	if ds.discoveryCtx != nil {
+		ds.discoveryCtx.Refresh()
+	}
+}
@@ -20,21 +20,38 @@ package dock
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sodafoundation/dock/contrib/drivers"
 	"github.com/sodafoundation/dock/contrib/drivers/filesharedrivers"
+	c "github.com/sodafoundation/dock/pkg/context"
+	"github.com/sodafoundation/dock/pkg/db"
 	"github.com/sodafoundation/dock/pkg/dock/discovery"
+	"github.com/sodafoundation/dock/pkg/dock/lock"
 	"github.com/sodafoundation/dock/pkg/model"
 	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"github.com/sodafoundation/dock/pkg/utils/config"
 	data "github.com/sodafoundation/dock/testutils/collection"
+	dbtest "github.com/sodafoundation/dock/testutils/db/testing"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func NewFakeDockServer() *dockServer {
 	return &dockServer{
 		Port:       "50050",
 		Discoverer: discovery.NewDockDiscoverer(model.DockTypeProvioner),
+		locks:      lock.NewManager(config.CONF.OsdsDock.LockTimeout),
 	}
 }
 
@@ -42,6 +59,7 @@ func NewFakeAttachDockServer() *dockServer {
 	return &dockServer{
 		Port:       "50050",
 		Discoverer: discovery.NewDockDiscoverer(model.DockTypeAttacher),
+		locks:      lock.NewManager(config.CONF.OsdsDock.LockTimeout),
 	}
 }
 
@@ -386,3 +404,342 @@ func Test_dockServer_DeleteFileShareSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func Test_dockServer_GroupSnapshot(t *testing.T) {
+	mockClient := new(dbtest.Client)
+	mockClient.On("UpdateGroupSnapshots", mock.Anything, "group1", []string{"gsnap1"}, []string(nil)).
+		Return(&model.VolumeGroupSpec{GroupSnapshots: []string{"gsnap1"}}, nil)
+	mockClient.On("UpdateGroupSnapshots", mock.Anything, "group1", []string(nil), []string{"gsnap1"}).
+		Return(&model.VolumeGroupSpec{}, nil)
+	oldClient := db.C
+	db.C = mockClient
+	defer func() { db.C = oldClient }()
+
+	ds := NewFakeDockServer()
+	snps := []*pb.GroupSnapshotMember{{Id: "snap1", VolumeId: "vol1"}, {Id: "snap2", VolumeId: "vol2"}}
+	resp, err := ds.CreateGroupSnapshot(context.Background(), &pb.CreateGroupSnapshotOpts{
+		Id: "gsnap1", GroupId: "group1", Snapshots: snps,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gs model.VolumeGroupSnapshotSpec
+	if err = json.Unmarshal([]byte(resp.GetResult().GetMessage()), &gs); err != nil {
+		t.Fatal(err)
+	}
+	if gs.Id != "gsnap1" || len(gs.Snapshots) != 2 {
+		t.Errorf("unexpected group snapshot %+v", gs)
+	}
+
+	// The group snapshot can't be taken while one of the volumes is busy.
+	ds.locks = lock.NewManager(10 * time.Millisecond)
+	unlock, err := ds.locks.Lock(context.Background(), lock.Volume("vol2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ds.DeleteGroupSnapshot(context.Background(), &pb.DeleteGroupSnapshotOpts{
+		Id: "gsnap1", GroupId: "group1", Snapshots: snps,
+	}); err == nil {
+		t.Error("expected resource busy error")
+	}
+	unlock()
+
+	if _, err = ds.DeleteGroupSnapshot(context.Background(), &pb.DeleteGroupSnapshotOpts{
+		Id: "gsnap1", GroupId: "group1", Snapshots: snps,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func Test_dockServer_VolumeGroup(t *testing.T) {
+	mockClient := new(dbtest.Client)
+	mockClient.On("ListVolumesByGroupId", mock.Anything, "group1").
+		Return([]*model.VolumeSpec{{BaseModel: &model.BaseModel{Id: "vol1"}}}, nil)
+	mockClient.On("DeleteVolume", mock.Anything, "vol1").Return(nil)
+	mockClient.On("GetVolumeGroup", mock.Anything, "group1").
+		Return(&model.VolumeGroupSpec{GroupSnapshots: []string{"gsnap1"}}, nil).Once()
+	mockClient.On("GetVolumeGroup", mock.Anything, "group1").Return(&model.VolumeGroupSpec{}, nil)
+	oldClient := db.C
+	db.C = mockClient
+	defer func() { db.C = oldClient }()
+
+	// The sample driver doesn't support volume groups.
+	ds := NewFakeDockServer()
+	if _, err := ds.CreateVolumeGroup(context.Background(), &pb.CreateVolumeGroupOpts{Id: "group1"}); err == nil {
+		t.Error("expected error of creating volume group")
+	}
+	if _, err := ds.UpdateVolumeGroup(context.Background(), &pb.UpdateVolumeGroupOpts{Id: "group1"}); err == nil {
+		t.Error("expected error of updating volume group")
+	}
+
+	// The volumes of the group can't be deleted while one of them is busy.
+	ds.locks = lock.NewManager(10 * time.Millisecond)
+	unlock, err := ds.locks.Lock(context.Background(), lock.Volume("vol1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ds.DeleteVolumeGroup(context.Background(), &pb.DeleteVolumeGroupOpts{Id: "group1"}); err == nil {
+		t.Error("expected resource busy error")
+	}
+	unlock()
+
+	// The group can't be deleted while it has group snapshots.
+	if _, err = ds.DeleteVolumeGroup(context.Background(), &pb.DeleteVolumeGroupOpts{Id: "group1"}); err == nil {
+		t.Error("expected error of deleting volume group with group snapshots")
+	}
+	mockClient.AssertNotCalled(t, "DeleteVolume", mock.Anything, "vol1")
+
+	if _, err = ds.DeleteVolumeGroup(context.Background(), &pb.DeleteVolumeGroupOpts{Id: "group1"}); err != nil {
+		t.Fatal(err)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func Test_dockServer_GetReplicationStatus(t *testing.T) {
+	ds := NewFakeDockServer()
+	resp, err := ds.GetReplicationStatus(context.Background(), &pb.GetReplicationStatusOpts{Id: "r1", IsPrimary: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st model.ReplicationPairStatus
+	if err = json.Unmarshal([]byte(resp.GetResult().GetMessage()), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Id != "r1" || !st.Healthy || st.RunningStatus != model.ReplicationRunningSynced || st.UpdatedAt == "" {
+		t.Errorf("unexpected replication status %+v", st)
+	}
+}
+
+func Test_dockServer_SwitchoverReplication(t *testing.T) {
+	mockClient := new(dbtest.Client)
+	mockClient.On("GetVolume", mock.Anything, "vol1").Return(&model.VolumeSpec{Status: model.VolumeInUse}, nil)
+	mockClient.On("GetVolume", mock.Anything, "vol2").Return(&model.VolumeSpec{Status: model.VolumeAvailable}, nil)
+	oldClient := db.C
+	db.C = mockClient
+	defer func() { db.C = oldClient }()
+
+	ds := NewFakeDockServer()
+	opt := &pb.SwitchoverReplicationOpts{Id: "r1", PrimaryVolumeId: "vol1", SecondaryVolumeId: "vol2", IsPrimary: true}
+	if _, err := ds.SwitchoverReplication(context.Background(), opt); err == nil {
+		t.Error("expected switching over the replication of attached volume to fail")
+	}
+
+	opt.AllowAttachedVolume = true
+	if _, err := ds.SwitchoverReplication(context.Background(), opt); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_dockServer_ReprotectReplication(t *testing.T) {
+	mockClient := new(dbtest.Client)
+	mockClient.On("GetVolume", mock.Anything, "vol1").Return(&model.VolumeSpec{Status: model.VolumeAvailable}, nil)
+	oldClient := db.C
+	db.C = mockClient
+	defer func() { db.C = oldClient }()
+
+	// The sample replication driver reports the pair is synced, so it is not
+	// failed over and can not be reprotected.
+	ds := NewFakeDockServer()
+	_, err := ds.ReprotectReplication(context.Background(), &pb.ReprotectReplicationOpts{
+		Id: "r1", PrimaryVolumeId: "vol1", SecondaryVolumeId: "vol2", IsPrimary: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), model.ReplicationRunningSynced) {
+		t.Errorf("expected reprotecting the synced replication to fail, got %v", err)
+	}
+}
+
+func Test_dockServer_ResourceBusy(t *testing.T) {
+	ds := &dockServer{locks: lock.NewManager(10 * time.Millisecond)}
+	id := "bd5b12a8-a101-11e7-941e-d77981b584d8"
+	unlock, err := ds.locks.Lock(context.Background(), lock.FileShare(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	resp, err := ds.DeleteFileShare(context.Background(), &pb.DeleteFileShareOpts{Id: id})
+	if _, ok := err.(*model.ResourceBusyError); !ok {
+		t.Fatalf("expected resource busy error, got %v", err)
+	}
+	if resp.GetError() == nil {
+		t.Error("expected error reply")
+	}
+}
+
+func Test_dockServer_RefreshDiscovery(t *testing.T) {
+	ds := NewFakeDockServer()
+	if _, err := ds.RefreshDiscovery(context.Background(), &pb.NoParams{}); err == nil {
+		t.Error("Expected error when the discovery loop is not running")
+	}
+
+	ds.discoveryCtx = discovery.NewContext(0, 0, 0, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := ds.RefreshDiscovery(context.Background(), &pb.NoParams{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(ds.discoveryCtx.RefreshChan) != 1 {
+		t.Errorf("Expected one pending refresh, got %d", len(ds.discoveryCtx.RefreshChan))
+	}
+}
+
+func Test_dockServer_ReloadConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "opensds.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("[osdsdock]\nenabled_backends = sample\n[sample]\nname = sample\ndriver_name = sample\n")
+	f.Close()
+	args, conf := os.Args, config.CONF
+	defer func() { os.Args, config.CONF = args, conf }()
+	os.Args = []string{"osdsdock", "--config-file", f.Name()}
+
+	ds := NewFakeDockServer()
+	if _, err := ds.ReloadConfig(context.Background(), &pb.NoParams{}); err == nil {
+		t.Error("Expected error when the discovery loop is not running")
+	}
+
+	ds.discoveryCtx = discovery.NewContext(0, 0, 0, 0)
+	if _, err := ds.ReloadConfig(context.Background(), &pb.NoParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.discoveryCtx.ReloadChan) != 1 || !reflect.DeepEqual(config.CONF.EnabledBackends, []string{"sample"}) {
+		t.Errorf("Expected sample backend to be reloaded, got %v", config.CONF.EnabledBackends)
+	}
+
+	<-ds.discoveryCtx.ReloadChan
+	ioutil.WriteFile(f.Name(), []byte("[osdsdock]\nenabled_backends = unknown\n"), 0644)
+	if _, err := ds.ReloadConfig(context.Background(), &pb.NoParams{}); err == nil {
+		t.Error("Expected error of invalid config")
+	}
+	if len(ds.discoveryCtx.ReloadChan) != 0 || !reflect.DeepEqual(config.CONF.EnabledBackends, []string{"sample"}) {
+		t.Errorf("Expected config unchanged, got %v", config.CONF.EnabledBackends)
+	}
+}
+
+func Test_checkPoolCapacity(t *testing.T) {
+	ctx := c.NewAdminContext()
+	mockClient := new(dbtest.Client)
+	mockClient.On("GetPool", ctx, "thick").Return(&model.StoragePoolSpec{
+		Name: "thick", TotalCapacity: 100, FreeCapacity: 30, ReservedPercentage: 10,
+		MaxOverSubscriptionRatio: 1,
+	}, nil)
+	mockClient.On("GetPool", ctx, "thin").Return(&model.StoragePoolSpec{
+		Name: "thin", TotalCapacity: 100, FreeCapacity: 30, ProvisionedCapacity: 250,
+		ReservedPercentage: 10, MaxOverSubscriptionRatio: 3,
+	}, nil)
+	mockClient.On("GetPool", ctx, "full").Return(&model.StoragePoolSpec{
+		Name: "full", TotalCapacity: 100, FreeCapacity: 5, ProvisionedCapacity: 100,
+		ReservedPercentage: 10, MaxOverSubscriptionRatio: 3,
+	}, nil)
+	mockClient.On("GetPool", ctx, "unknown").Return(nil, errors.New("not found"))
+	oldClient := db.C
+	db.C = mockClient
+	defer func() { db.C = oldClient }()
+
+	tests := []struct {
+		pool    string
+		size    int64
+		wantErr bool
+	}{
+		// 30 GB free minus 10 GB reserved.
+		{"thick", 20, false},
+		{"thick", 21, true},
+		// 3 times 90 GB usable minus 250 GB provisioned.
+		{"thin", 20, false},
+		{"thin", 21, true},
+		// No physical capacity is left beyond the reserved one.
+		{"full", 1, true},
+		{"unknown", 1000, false},
+		{"", 1000, false},
+	}
+	for _, tt := range tests {
+		if err := checkPoolCapacity(ctx, tt.pool, tt.size); (err != nil) != tt.wantErr {
+			t.Errorf("checkPoolCapacity(%s, %d) error = %v, wantErr %v", tt.pool, tt.size, err, tt.wantErr)
+		}
+	}
+}
+
+func Test_drain(t *testing.T) {
+	serve := func() (*grpc.Server, healthpb.HealthClient) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := grpc.NewServer()
+		healthpb.RegisterHealthServer(s, health.NewServer())
+		go s.Serve(lis)
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, healthpb.NewHealthClient(conn)
+	}
+
+	// The finished rpcs do not hold the shutdown.
+	s, client := serve()
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(s, time.Minute); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// The running rpcs are stopped after the grace period.
+	s, client = serve()
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if err := drain(s, 10*time.Millisecond); err != errDrainTimeout {
+		t.Errorf("Expected %v, got %v", errDrainTimeout, err)
+	}
+}
+
+type fakeShutdownDiscoverer struct {
+	discovery.DockDiscoverer
+	reason string
+}
+
+func (f *fakeShutdownDiscoverer) Discover() error { return nil }
+
+func (f *fakeShutdownDiscoverer) Report() error { return nil }
+
+func (f *fakeShutdownDiscoverer) Disable(reason string) error {
+	f.reason = reason
+	return nil
+}
+
+func Test_dockServer_shutdown(t *testing.T) {
+	dd := &fakeShutdownDiscoverer{}
+	ds := &dockServer{Discoverer: dd, discoveryCtx: discovery.NewContext(time.Hour, 0, 0, 0)}
+	done := make(chan struct{})
+	go func() {
+		discovery.DiscoveryAndReport(dd, ds.discoveryCtx)
+		close(done)
+	}()
+	drivers.CleanAll()
+	drivers.Init("sample")
+
+	if err := ds.shutdown(done, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if dd.reason == "" {
+		t.Error("Expected docks to be disabled")
+	}
+	if n := drivers.CleanAll(); n != 0 {
+		t.Errorf("Expected drivers to be unset, got %d live drivers", n)
+	}
+
+	// The docks are not disabled if the discovery loop does not stop.
+	dd.reason = ""
+	ds.discoveryCtx = discovery.NewContext(time.Hour, 0, 0, 0)
+	if err := ds.shutdown(make(chan struct{}), time.Millisecond); err == nil || dd.reason != "" {
+		t.Errorf("Expected error and docks not disabled, got %v and %q", err, dd.reason)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the resource locks of dock, which serialise the
+conflicting operations on the same volume, snapshot, file share or
+replication. The locks are held in process, and also in etcd if several docks
+share a backend.
+*/
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/db"
+	"github.com/sodafoundation/dock/pkg/model"
+)
+
+// Volume returns the lock key of the volume.
+func Volume(id string) string { return key("volumes", id) }
+
+// Snapshot returns the lock key of the volume snapshot.
+func Snapshot(id string) string { return key("snapshots", id) }
+
+// FileShare returns the lock key of the file share.
+func FileShare(id string) string { return key("fileshares", id) }
+
+// FileShareSnapshot returns the lock key of the file share snapshot.
+func FileShareSnapshot(id string) string { return key("fileshareSnapshots", id) }
+
+// Replication returns the lock key of the replication.
+func Replication(id string) string { return key("replications", id) }
+
+// key returns an empty key for an empty id, which is not locked, so that the
+// optional ids of the requests can be passed as they are.
+func key(kind, id string) string {
+	if id == "" {
+		return ""
+	}
+	return kind + "/" + id
+}
+
+// Manager locks the resources by their keys. The operations wait for the
+// locks held by others for at most the lock timeout, and fail with a
+// *model.ResourceBusyError afterwards. A nil Manager locks nothing.
+type Manager struct {
+	timeout time.Duration
+	// distributed holds the locks across the docks sharing the backends, it
+	// is nil if the locks are only held in process.
+	distributed db.Locker
+	ttl         time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*entry
+}
+
+// entry is the in-process lock of a key, which is removed when nobody holds
+// or waits for it.
+type entry struct {
+	sem  chan struct{}
+	refs int
+}
+
+// NewManager returns a Manager holding the locks in process.
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout, locks: map[string]*entry{}}
+}
+
+// NewDistributedManager returns a Manager which also holds the locks by the
+// locker, under leases of ttl so that the locks of a dead dock are released.
+func NewDistributedManager(timeout time.Duration, locker db.Locker, ttl time.Duration) *Manager {
+	m := NewManager(timeout)
+	m.distributed, m.ttl = locker, ttl
+	return m
+}
+
+// Lock acquires the locks of keys in order, so that the operations locking
+// the same resources don't deadlock, and returns the function releasing them.
+// The empty and duplicate keys are ignored.
+func (m *Manager) Lock(ctx context.Context, keys ...string) (func(), error) {
+	if m == nil {
+		return func() {}, nil
+	}
+	keys = normalize(keys)
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	var unlocks []func()
+	unlock := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, k := range keys {
+		u, err := m.lock(ctx, k)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		unlocks = append(unlocks, u)
+	}
+	return unlock, nil
+}
+
+func (m *Manager) lock(ctx context.Context, key string) (func(), error) {
+	e := m.acquire(key)
+	select {
+	case e.sem <- struct{}{}:
+	case <-ctx.Done():
+		m.release(key)
+		return nil, busy(key, ctx.Err())
+	}
+	local := func() {
+		<-e.sem
+		m.release(key)
+	}
+	if m.distributed == nil {
+		return local, nil
+	}
+
+	unlock, err := m.distributed.Lock(ctx, key, m.ttl)
+	if err != nil {
+		local()
+		if ctx.Err() != nil {
+			return nil, busy(key, ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to lock resource %s: %v", key, err)
+	}
+	return func() {
+		if err := unlock(); err != nil {
+			log.Warningf("Failed to unlock resource %s, it is released when its lease expires: %v", key, err)
+		}
+		local()
+	}, nil
+}
+
+// acquire returns the in-process lock of key, and counts the caller in.
+func (m *Manager) acquire(key string) *entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.locks[key]
+	if !ok {
+		e = &entry{sem: make(chan struct{}, 1)}
+		m.locks[key] = e
+	}
+	e.refs++
+	return e
+}
+
+func (m *Manager) release(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.locks[key]; e != nil {
+		if e.refs--; e.refs == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
+
+func normalize(keys []string) []string {
+	var ks []string
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			ks = append(ks, k)
+		}
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func busy(key string, err error) error {
+	if err == context.DeadlineExceeded {
+		return model.NewResourceBusyError(fmt.Sprintf("resource %s is busy with another operation, please retry later", key))
+	}
+	return fmt.Errorf("stopped waiting for resource %s: %v", key, err)
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeLocker struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	err    error
+	events []string
+}
+
+func (f *fakeLocker) Lock(ctx context.Context, key string, ttl time.Duration) (func() error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.held[key] {
+		return nil, errors.New("held twice")
+	}
+	f.held[key] = true
+	f.events = append(f.events, "lock "+key)
+	return func() error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.held, key)
+		f.events = append(f.events, "unlock "+key)
+		return nil
+	}, nil
+}
+
+func TestLockBusy(t *testing.T) {
+	m := NewManager(50 * time.Millisecond)
+	unlock, err := m.Lock(context.Background(), Volume("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = m.Lock(context.Background(), Snapshot("s1"), Volume("v1"))
+	if _, ok := err.(*model.ResourceBusyError); !ok {
+		t.Fatalf("expected resource busy error, got %v", err)
+	}
+	if code := status.Code(err); code != codes.Aborted {
+		t.Errorf("expected code %v, got %v", codes.Aborted, code)
+	}
+	// The snapshot locked before the busy volume is released.
+	if u, err := m.Lock(context.Background(), Snapshot("s1")); err != nil {
+		t.Errorf("snapshot is not released: %v", err)
+	} else {
+		u()
+	}
+
+	unlock()
+	if len(m.locks) != 0 {
+		t.Errorf("expected no locks left, got %v", m.locks)
+	}
+}
+
+func TestLockWait(t *testing.T) {
+	m := NewManager(time.Second)
+	unlock, err := m.Lock(context.Background(), Volume("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		unlock()
+	}()
+	u, err := m.Lock(context.Background(), Volume("v1"), "", Volume("v1"))
+	if err != nil {
+		t.Fatalf("expected lock to be acquired after release, got %v", err)
+	}
+	u()
+}
+
+func TestLockCanceled(t *testing.T) {
+	m := NewManager(time.Minute)
+	unlock, _ := m.Lock(context.Background(), FileShare("f1"))
+	defer unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := m.Lock(ctx, FileShare("f1"))
+	if err == nil {
+		t.Fatal("expected error when ctx is canceled")
+	}
+	if _, ok := err.(*model.ResourceBusyError); ok {
+		t.Errorf("canceled lock should not be reported as busy: %v", err)
+	}
+}
+
+func TestDistributedLock(t *testing.T) {
+	f := &fakeLocker{held: map[string]bool{}}
+	m := NewDistributedManager(time.Second, f, time.Second)
+	unlock, err := m.Lock(context.Background(), Volume("v2"), Replication("r1"), Volume("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	want := []string{
+		"lock replications/r1", "lock volumes/v1", "lock volumes/v2",
+		"unlock volumes/v2", "unlock volumes/v1", "unlock replications/r1",
+	}
+	if !reflect.DeepEqual(f.events, want) {
+		t.Errorf("expected %v, got %v", want, f.events)
+	}
+
+	f.err = errors.New("etcd is down")
+	if _, err := m.Lock(context.Background(), Volume("v1")); err == nil {
+		t.Error("expected error when distributed lock fails")
+	}
+	if len(m.locks) != 0 {
+		t.Errorf("expected in-process lock to be released, got %v", m.locks)
+	}
+}
+
+func TestNilManager(t *testing.T) {
+	var m *Manager
+	unlock, err := m.Lock(context.Background(), Volume("v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
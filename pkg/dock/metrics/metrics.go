@@ -0,0 +1,207 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements the prometheus metrics of dock service itself, which
+are served on the metrics endpoint of dock.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+
+	log "github.com/golang/glog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sodafoundation/dock/pkg/model"
+	"google.golang.org/grpc"
+)
+
+const (
+	namespace = "opensds"
+	subsystem = "dock"
+
+	// MetricsPath is the http path of the metrics endpoint.
+	MetricsPath = "/metrics"
+)
+
+// The driver operations and discovery may take minutes on some backends, so
+// the buckets range from 10ms to about 5 minutes.
+var durationBuckets = prometheus.ExponentialBuckets(0.01, 2, 16)
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "rpc_requests_total",
+		Help:      "Total number of rpc requests received by dock.",
+	}, []string{"method", "driver"})
+
+	rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "rpc_errors_total",
+		Help:      "Total number of rpc requests failed in dock.",
+	}, []string{"method", "driver"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of rpc requests handled by dock.",
+		Buckets:   durationBuckets,
+	}, []string{"method", "driver"})
+
+	driverOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "driver_operation_duration_seconds",
+		Help:      "Duration of operations called on storage drivers.",
+		Buckets:   durationBuckets,
+	}, []string{"driver", "operation"})
+
+	discoveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "discovery_duration_seconds",
+		Help:      "Duration of each phase of the discovery loop.",
+		Buckets:   durationBuckets,
+	}, []string{"phase"})
+
+	discoveryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "discovery_errors_total",
+		Help:      "Total number of failures of each phase of the discovery loop.",
+	}, []string{"phase"})
+
+	discoveryLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "discovery_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of each phase of the discovery loop.",
+	}, []string{"phase"})
+
+	poolTotalCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "pool_total_capacity_gigabytes",
+		Help:      "Total capacity of the pools in the last pool discovery.",
+	}, []string{"driver", "pool", "pool_id"})
+
+	poolFreeCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "pool_free_capacity_gigabytes",
+		Help:      "Free capacity of the pools in the last pool discovery.",
+	}, []string{"driver", "pool", "pool_id"})
+)
+
+// Registry contains all the metrics of dock, the go and process metrics are
+// included as well.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		rpcRequests,
+		rpcErrors,
+		rpcDuration,
+		driverOperationDuration,
+		discoveryDuration,
+		discoveryErrors,
+		discoveryLastSuccess,
+		poolTotalCapacity,
+		poolFreeCapacity,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+}
+
+// driverNamer is implemented by the rpc options which specify the driver.
+type driverNamer interface {
+	GetDriverName() string
+}
+
+// UnaryServerInterceptor counts the rpc requests and errors and observes the
+// latency of them, which are labelled by method and driver.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	var driver string
+	if dn, ok := req.(driverNamer); ok {
+		driver = dn.GetDriverName()
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	rpcDuration.WithLabelValues(method, driver).Observe(time.Since(start).Seconds())
+	rpcRequests.WithLabelValues(method, driver).Inc()
+	if err != nil {
+		rpcErrors.WithLabelValues(method, driver).Inc()
+	}
+	return resp, err
+}
+
+// ObserveDriverOperation records the duration of a driver operation which
+// is started at the given time.
+func ObserveDriverOperation(driver, operation string, start time.Time) {
+	driverOperationDuration.WithLabelValues(driver, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDiscovery records the duration and result of a discovery phase which
+// is started at the given time.
+func ObserveDiscovery(phase string, start time.Time, err error) {
+	discoveryDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+	if err != nil {
+		discoveryErrors.WithLabelValues(phase).Inc()
+		return
+	}
+	discoveryLastSuccess.WithLabelValues(phase).SetToCurrentTime()
+}
+
+// SetPoolCapacities replaces the pool capacity gauges of the driver with the
+// pools it lists, so that the removed pools are not reported any more.
+func SetPoolCapacities(driver string, pols []*model.StoragePoolSpec) {
+	for _, pol := range pols {
+		if pol.BaseModel == nil {
+			continue
+		}
+		poolTotalCapacity.WithLabelValues(driver, pol.Name, pol.Id).Set(float64(pol.TotalCapacity))
+		poolFreeCapacity.WithLabelValues(driver, pol.Name, pol.Id).Set(float64(pol.FreeCapacity))
+	}
+}
+
+// ResetPoolCapacities removes all the pool capacity gauges, it is called
+// before a new round of pool discovery.
+func ResetPoolCapacities() {
+	poolTotalCapacity.Reset()
+	poolFreeCapacity.Reset()
+}
+
+// NewHandler returns the http handler which serves the metrics of dock.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// ListenAndServe serves the metrics on the endpoint until it fails.
+func ListenAndServe(endpoint string) error {
+	log.Info("Dock metrics server start listening on", endpoint)
+	return http.ListenAndServe(endpoint, NewHandler())
+}
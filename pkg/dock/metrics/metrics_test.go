@@ -0,0 +1,80 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sodafoundation/dock/pkg/model"
+	pb "github.com/sodafoundation/dock/pkg/model/proto"
+	"google.golang.org/grpc"
+)
+
+func scrape(t *testing.T) string {
+	srv := httptest.NewServer(NewHandler())
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL + MetricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.ProvisionDock/CreateVolume"}
+	opt := &pb.CreateVolumeOpts{DriverName: "lvm"}
+	UnaryServerInterceptor(context.Background(), opt, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	UnaryServerInterceptor(context.Background(), opt, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+	ObserveDriverOperation("lvm", "list_pools", time.Now())
+	ObserveDiscovery("discover", time.Now(), nil)
+	SetPoolCapacities("lvm", []*model.StoragePoolSpec{{
+		BaseModel:     &model.BaseModel{Id: "pool-1"},
+		Name:          "vg001",
+		TotalCapacity: 100,
+		FreeCapacity:  60,
+	}})
+
+	out := scrape(t)
+	for _, expected := range []string{
+		`opensds_dock_rpc_requests_total{driver="lvm",method="CreateVolume"} 2`,
+		`opensds_dock_rpc_errors_total{driver="lvm",method="CreateVolume"} 1`,
+		`opensds_dock_rpc_duration_seconds_count{driver="lvm",method="CreateVolume"} 2`,
+		`opensds_dock_driver_operation_duration_seconds_count{driver="lvm",operation="list_pools"} 1`,
+		`opensds_dock_discovery_duration_seconds_count{phase="discover"} 1`,
+		`opensds_dock_pool_total_capacity_gigabytes{driver="lvm",pool="vg001",pool_id="pool-1"} 100`,
+		`opensds_dock_pool_free_capacity_gigabytes{driver="lvm",pool="vg001",pool_id="pool-1"} 60`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %s in metrics", expected)
+		}
+	}
+
+	ResetPoolCapacities()
+	if strings.Contains(scrape(t), "opensds_dock_pool_total_capacity_gigabytes{") {
+		t.Error("Expected pool capacities to be reset")
+	}
+}
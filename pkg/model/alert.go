@@ -44,13 +44,14 @@ type PostableAlertSpec struct {
 	// annotations
 	Annotations LabelSet `json:"annotations,omitempty"`
 
-	// end at
+	// end at, the alert is resolved if it is in the past
 	// Format: date-time
-	EndAt time.Time `json:"endAt,omitempty"`
+	EndAt time.Time `json:"endsAt,omitempty"`
 
 	// start at
 	// Format: date-time
-	StartAt time.Time `json:"startAt,omitempty"`
+	StartAt time.Time `json:"startsAt,omitempty"`
 
-	AlertSpec `json:"alert,omitempty"`
+	// The labels and generator URL are inlined as required by Alertmanager.
+	AlertSpec
 }
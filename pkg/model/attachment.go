@@ -58,6 +58,33 @@ type ConnectionInfo struct {
 	AdditionalProperties map[string]interface{} `json:"additionalProperties,omitempty"`
 }
 
+// SecretConnectionDataKeys are the keys of connection data whose values are
+// secrets, which must never be logged.
+var SecretConnectionDataKeys = []string{
+	"authPassword",
+	"mutualAuthPassword",
+	"dhchapHostKey",
+	"dhchapCtrlKey",
+}
+
+// RedactConnectionData returns a copy of the connection data in which the
+// secrets are masked, so that it can be logged safely.
+func RedactConnectionData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		redacted[k] = v
+	}
+	for _, k := range SecretConnectionDataKeys {
+		if _, ok := redacted[k]; ok {
+			redacted[k] = "******"
+		}
+	}
+	return redacted
+}
+
 // EncodeConnectionData will marshal itself to byte
 func (con *ConnectionInfo) EncodeConnectionData() []byte {
 	conBody, _ := json.Marshal(&con.ConnectionData)
@@ -41,9 +41,13 @@ type DockSpec struct {
 	Description string `json:"description,omitempty"`
 
 	// The status of the dock.
-	// One of: "available" or "unavailable".
+	// One of: "available", "degraded" or "unavailable".
 	Status string `json:"status,omitempty"`
 
+	// The reason why the dock is degraded or unavailable.
+	// +optional
+	StatusReason string `json:"statusReason,omitempty"`
+
 	// The storage type of the dock.
 	// One of: "block", "file" or "object".
 	StorageType string `json:"storageType,omitempty"`
@@ -63,4 +67,9 @@ type DockSpec struct {
 	// attachment and backend attached storage resouce description are clear.
 	// +optional
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// LastHeartbeat is the server time when the dock was registered by its
+	// running dock service last time.
+	// +readOnly
+	LastHeartbeat string `json:"lastHeartbeat,omitempty"`
 }
@@ -22,6 +22,9 @@ package model
 import (
 	"encoding/json"
 	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -104,3 +107,37 @@ func NewNotFoundError(msg string) error {
 func (e *NotFoundError) Error() string {
 	return e.S
 }
+
+// ConflictError is returned when a resource is changed by others during an
+// update, and the update is not applied.
+type ConflictError struct {
+	S string
+}
+
+func NewConflictError(msg string) error {
+	return &ConflictError{S: msg}
+}
+
+func (e *ConflictError) Error() string {
+	return e.S
+}
+
+// ResourceBusyError is returned when a resource is locked by another operation
+// in progress, and the lock is not released within the lock wait timeout.
+type ResourceBusyError struct {
+	S string
+}
+
+func NewResourceBusyError(msg string) error {
+	return &ResourceBusyError{S: msg}
+}
+
+func (e *ResourceBusyError) Error() string {
+	return e.S
+}
+
+// GRPCStatus makes the dock reply with codes.Aborted, so that the callers can
+// tell the operation is not started and can be retried later.
+func (e *ResourceBusyError) GRPCStatus() *status.Status {
+	return status.New(codes.Aborted, e.S)
+}
@@ -35,6 +35,25 @@ type FileShareAclSpec struct {
 	// accessTo of the fileshare.
 	AccessTo string `json:"accessTo,omitempty"`
 
+	// The squash mode of nfs export.
+	// One of: "root_squash", "no_root_squash", "all_squash".
+	// +optional
+	Squash string `json:"squash,omitempty"`
+
+	// The uid and gid that squashed users are mapped to.
+	// +optional
+	AnonUid int64 `json:"anonUid,omitempty"`
+	AnonGid int64 `json:"anonGid,omitempty"`
+
+	// The security flavors of nfs export.
+	// One or more of: "sys", "krb5", "krb5i", "krb5p".
+	// +optional
+	SecurityFlavors []string `json:"securityFlavors,omitempty"`
+
+	// The write mode of nfs export, "sync" or "async".
+	// +optional
+	SyncMode string `json:"syncMode,omitempty"`
+
 	// The description of the fileshare acl.
 	Description string `json:"description,omitempty"`
 
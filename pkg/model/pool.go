@@ -41,6 +41,10 @@ type StoragePoolSpec struct {
 	// One of: "available" or "unavailable".
 	Status string `json:"status,omitempty"`
 
+	// The reason why the pool is unavailable.
+	// +optional
+	StatusReason string `json:"statusReason,omitempty"`
+
 	// The uuid of the dock which the pool belongs to.
 	DockId string `json:"dockId,omitempty"`
 
@@ -59,6 +63,24 @@ type StoragePoolSpec struct {
       // Default unit of ConsumedCapacity is GB.
       ConsumedCapacity int64 `json:"consumedCapacity,omitempty"`
 
+	// The provisioned capacity of the pool, which is the sum of the sizes of
+	// all the volumes in it.
+	// Default unit of ProvisionedCapacity is GB.
+	ProvisionedCapacity int64 `json:"provisionedCapacity,omitempty"`
+
+	// The capacity actually allocated on the backend, which is less than the
+	// provisioned capacity for thin provisioned volumes.
+	// Default unit of AllocatedCapacity is GB.
+	AllocatedCapacity int64 `json:"allocatedCapacity,omitempty"`
+
+	// The ratio of provisioned capacity to usable capacity allowed in the
+	// pool, it is not over-subscribed if the ratio is not greater than 1.
+	MaxOverSubscriptionRatio float64 `json:"maxOverSubscriptionRatio,omitempty"`
+
+	// The percentage of total capacity which is reserved and never used by
+	// new volumes.
+	ReservedPercentage int64 `json:"reservedPercentage,omitempty"`
+
 	// MultiAttach
 	// If true, this volume can attach to more than one instance. Default will be multiattach:False
 	MultiAttach bool `json:"multiAttach"`
@@ -93,3 +115,27 @@ type StoragePoolExtraSpec struct {
 	// and filtered by selector in a extensible way.
 	Advanced map[string]interface{} `json:"advanced,omitempty" yaml:"advanced,omitempty"`
 }
+
+// ProvisionableCapacity returns the capacity in GB which new volumes can
+// still take in the pool without exceeding its reserved percentage and max
+// over-subscription ratio.
+func (p *StoragePoolSpec) ProvisionableCapacity() int64 {
+	reserved := p.TotalCapacity * p.ReservedPercentage / 100
+	free := p.FreeCapacity - reserved
+	if free < 0 {
+		free = 0
+	}
+	if p.MaxOverSubscriptionRatio <= 1 {
+		return free
+	}
+	// The pool can be over-subscribed as long as there is physical capacity
+	// left.
+	if free == 0 {
+		return 0
+	}
+	virtual := int64(p.MaxOverSubscriptionRatio*float64(p.TotalCapacity-reserved)) - p.ProvisionedCapacity
+	if virtual < 0 {
+		return 0
+	}
+	return virtual
+}
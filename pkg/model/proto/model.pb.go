@@ -1925,7 +1925,8 @@ type FailoverReplicationOpts struct {
 	Context string `protobuf:"bytes,15,opt,name=context,proto3" json:"context,omitempty"`
 	// Allow attached volume
 	AllowAttachedVolume bool `protobuf:"varint,16,opt,name=allowAttachedVolume,proto3" json:"allowAttachedVolume,omitempty"`
-	// The secondary backend id.
+	// The secondary backend id, which is not used by the drivers any more
+	// since failing back is done by reprotecting and switching over.
 	SecondaryBackendId string `protobuf:"bytes,17,opt,name=secondaryBackendId,proto3" json:"secondaryBackendId,omitempty"`
 	// The replication metadata
 	Metadata map[string]string `protobuf:"bytes,18,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -2129,6 +2130,431 @@ func (m *FailoverReplicationOpts_FailoverRequest) GetSecondaryBackendId() string
 	return ""
 }
 
+// GetReplicationStatusOpts is a structure which indicates all required
+// properties for getting the status of a replication.
+type GetReplicationStatusOpts struct {
+	// The uuid of the replication, required.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The uuid of the primary volume. This field is required.
+	PrimaryVolumeId string `protobuf:"bytes,2,opt,name=primaryVolumeId,proto3" json:"primaryVolumeId,omitempty"`
+	// The uuid of the secondary volume. This field is required.
+	SecondaryVolumeId string `protobuf:"bytes,3,opt,name=secondaryVolumeId,proto3" json:"secondaryVolumeId,omitempty"`
+	// The uuid of the pool of the replication.
+	PoolId string `protobuf:"bytes,4,opt,name=poolId,proto3" json:"poolId,omitempty"`
+	// The metadata of the primary replication, optional.
+	PrimaryReplicationDriverData map[string]string `protobuf:"bytes,5,rep,name=primaryReplicationDriverData,proto3" json:"primaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The metadata of the seondary replication, optional.
+	SecondaryReplicationDriverData map[string]string `protobuf:"bytes,6,rep,name=secondaryReplicationDriverData,proto3" json:"secondaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The dock id.
+	DockId string `protobuf:"bytes,7,opt,name=dockId,proto3" json:"dockId,omitempty"`
+	// The replication driver type.
+	DriverName string `protobuf:"bytes,8,opt,name=driverName,proto3" json:"driverName,omitempty"`
+	// The Context
+	Context string `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	// The replication metadata
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Whether is primary replication
+	IsPrimary            bool     `protobuf:"varint,11,opt,name=isPrimary,proto3" json:"isPrimary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReplicationStatusOpts) Reset()         { *m = GetReplicationStatusOpts{} }
+func (m *GetReplicationStatusOpts) String() string { return proto.CompactTextString(m) }
+func (*GetReplicationStatusOpts) ProtoMessage()    {}
+func (*GetReplicationStatusOpts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{17}
+}
+
+func (m *GetReplicationStatusOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReplicationStatusOpts.Unmarshal(m, b)
+}
+func (m *GetReplicationStatusOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReplicationStatusOpts.Marshal(b, m, deterministic)
+}
+func (m *GetReplicationStatusOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReplicationStatusOpts.Merge(m, src)
+}
+func (m *GetReplicationStatusOpts) XXX_Size() int {
+	return xxx_messageInfo_GetReplicationStatusOpts.Size(m)
+}
+func (m *GetReplicationStatusOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReplicationStatusOpts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReplicationStatusOpts proto.InternalMessageInfo
+
+func (m *GetReplicationStatusOpts) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetPrimaryVolumeId() string {
+	if m != nil {
+		return m.PrimaryVolumeId
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetSecondaryVolumeId() string {
+	if m != nil {
+		return m.SecondaryVolumeId
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetPoolId() string {
+	if m != nil {
+		return m.PoolId
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetPrimaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.PrimaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *GetReplicationStatusOpts) GetSecondaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.SecondaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *GetReplicationStatusOpts) GetDockId() string {
+	if m != nil {
+		return m.DockId
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetDriverName() string {
+	if m != nil {
+		return m.DriverName
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+func (m *GetReplicationStatusOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *GetReplicationStatusOpts) GetIsPrimary() bool {
+	if m != nil {
+		return m.IsPrimary
+	}
+	return false
+}
+
+// SwitchoverReplicationOpts is a structure which indicates all required
+// properties for switching over a replication, which synchronizes the
+// secondary volume and then swaps the roles of the volumes.
+type SwitchoverReplicationOpts struct {
+	// The uuid of the replication, required.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The uuid of the primary volume. This field is required.
+	PrimaryVolumeId string `protobuf:"bytes,2,opt,name=primaryVolumeId,proto3" json:"primaryVolumeId,omitempty"`
+	// The uuid of the secondary volume. This field is required.
+	SecondaryVolumeId string `protobuf:"bytes,3,opt,name=secondaryVolumeId,proto3" json:"secondaryVolumeId,omitempty"`
+	// The uuid of the pool of the replication.
+	PoolId string `protobuf:"bytes,4,opt,name=poolId,proto3" json:"poolId,omitempty"`
+	// The metadata of the primary replication, optional.
+	PrimaryReplicationDriverData map[string]string `protobuf:"bytes,5,rep,name=primaryReplicationDriverData,proto3" json:"primaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The metadata of the seondary replication, optional.
+	SecondaryReplicationDriverData map[string]string `protobuf:"bytes,6,rep,name=secondaryReplicationDriverData,proto3" json:"secondaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The dock id.
+	DockId string `protobuf:"bytes,7,opt,name=dockId,proto3" json:"dockId,omitempty"`
+	// The replication driver type.
+	DriverName string `protobuf:"bytes,8,opt,name=driverName,proto3" json:"driverName,omitempty"`
+	// The Context
+	Context string `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	// The replication metadata
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Whether is primary replication
+	IsPrimary bool `protobuf:"varint,11,opt,name=isPrimary,proto3" json:"isPrimary,omitempty"`
+	// Allow attached volume
+	AllowAttachedVolume bool `protobuf:"varint,12,opt,name=allowAttachedVolume,proto3" json:"allowAttachedVolume,omitempty"`
+	// The bandwidth of the replication.
+	ReplicationBandwidth int64    `protobuf:"varint,13,opt,name=replicationBandwidth,proto3" json:"replicationBandwidth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SwitchoverReplicationOpts) Reset()         { *m = SwitchoverReplicationOpts{} }
+func (m *SwitchoverReplicationOpts) String() string { return proto.CompactTextString(m) }
+func (*SwitchoverReplicationOpts) ProtoMessage()    {}
+func (*SwitchoverReplicationOpts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{18}
+}
+
+func (m *SwitchoverReplicationOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SwitchoverReplicationOpts.Unmarshal(m, b)
+}
+func (m *SwitchoverReplicationOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SwitchoverReplicationOpts.Marshal(b, m, deterministic)
+}
+func (m *SwitchoverReplicationOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SwitchoverReplicationOpts.Merge(m, src)
+}
+func (m *SwitchoverReplicationOpts) XXX_Size() int {
+	return xxx_messageInfo_SwitchoverReplicationOpts.Size(m)
+}
+func (m *SwitchoverReplicationOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_SwitchoverReplicationOpts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SwitchoverReplicationOpts proto.InternalMessageInfo
+
+func (m *SwitchoverReplicationOpts) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetPrimaryVolumeId() string {
+	if m != nil {
+		return m.PrimaryVolumeId
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetSecondaryVolumeId() string {
+	if m != nil {
+		return m.SecondaryVolumeId
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetPoolId() string {
+	if m != nil {
+		return m.PoolId
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetPrimaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.PrimaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *SwitchoverReplicationOpts) GetSecondaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.SecondaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *SwitchoverReplicationOpts) GetDockId() string {
+	if m != nil {
+		return m.DockId
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetDriverName() string {
+	if m != nil {
+		return m.DriverName
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+func (m *SwitchoverReplicationOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *SwitchoverReplicationOpts) GetIsPrimary() bool {
+	if m != nil {
+		return m.IsPrimary
+	}
+	return false
+}
+
+func (m *SwitchoverReplicationOpts) GetAllowAttachedVolume() bool {
+	if m != nil {
+		return m.AllowAttachedVolume
+	}
+	return false
+}
+
+func (m *SwitchoverReplicationOpts) GetReplicationBandwidth() int64 {
+	if m != nil {
+		return m.ReplicationBandwidth
+	}
+	return 0
+}
+
+// ReprotectReplicationOpts is a structure which indicates all required
+// properties for reprotecting a replication after it is failed over, which
+// replicates the data of the secondary volume back to the primary volume.
+type ReprotectReplicationOpts struct {
+	// The uuid of the replication, required.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The uuid of the primary volume. This field is required.
+	PrimaryVolumeId string `protobuf:"bytes,2,opt,name=primaryVolumeId,proto3" json:"primaryVolumeId,omitempty"`
+	// The uuid of the secondary volume. This field is required.
+	SecondaryVolumeId string `protobuf:"bytes,3,opt,name=secondaryVolumeId,proto3" json:"secondaryVolumeId,omitempty"`
+	// The uuid of the pool of the replication.
+	PoolId string `protobuf:"bytes,4,opt,name=poolId,proto3" json:"poolId,omitempty"`
+	// The metadata of the primary replication, optional.
+	PrimaryReplicationDriverData map[string]string `protobuf:"bytes,5,rep,name=primaryReplicationDriverData,proto3" json:"primaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The metadata of the seondary replication, optional.
+	SecondaryReplicationDriverData map[string]string `protobuf:"bytes,6,rep,name=secondaryReplicationDriverData,proto3" json:"secondaryReplicationDriverData,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The dock id.
+	DockId string `protobuf:"bytes,7,opt,name=dockId,proto3" json:"dockId,omitempty"`
+	// The replication driver type.
+	DriverName string `protobuf:"bytes,8,opt,name=driverName,proto3" json:"driverName,omitempty"`
+	// The Context
+	Context string `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	// The replication metadata
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Whether is primary replication
+	IsPrimary bool `protobuf:"varint,11,opt,name=isPrimary,proto3" json:"isPrimary,omitempty"`
+	// The bandwidth of the replication.
+	ReplicationBandwidth int64    `protobuf:"varint,12,opt,name=replicationBandwidth,proto3" json:"replicationBandwidth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReprotectReplicationOpts) Reset()         { *m = ReprotectReplicationOpts{} }
+func (m *ReprotectReplicationOpts) String() string { return proto.CompactTextString(m) }
+func (*ReprotectReplicationOpts) ProtoMessage()    {}
+func (*ReprotectReplicationOpts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{19}
+}
+
+func (m *ReprotectReplicationOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReprotectReplicationOpts.Unmarshal(m, b)
+}
+func (m *ReprotectReplicationOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReprotectReplicationOpts.Marshal(b, m, deterministic)
+}
+func (m *ReprotectReplicationOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReprotectReplicationOpts.Merge(m, src)
+}
+func (m *ReprotectReplicationOpts) XXX_Size() int {
+	return xxx_messageInfo_ReprotectReplicationOpts.Size(m)
+}
+func (m *ReprotectReplicationOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReprotectReplicationOpts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReprotectReplicationOpts proto.InternalMessageInfo
+
+func (m *ReprotectReplicationOpts) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetPrimaryVolumeId() string {
+	if m != nil {
+		return m.PrimaryVolumeId
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetSecondaryVolumeId() string {
+	if m != nil {
+		return m.SecondaryVolumeId
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetPoolId() string {
+	if m != nil {
+		return m.PoolId
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetPrimaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.PrimaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *ReprotectReplicationOpts) GetSecondaryReplicationDriverData() map[string]string {
+	if m != nil {
+		return m.SecondaryReplicationDriverData
+	}
+	return nil
+}
+
+func (m *ReprotectReplicationOpts) GetDockId() string {
+	if m != nil {
+		return m.DockId
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetDriverName() string {
+	if m != nil {
+		return m.DriverName
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+func (m *ReprotectReplicationOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ReprotectReplicationOpts) GetIsPrimary() bool {
+	if m != nil {
+		return m.IsPrimary
+	}
+	return false
+}
+
+func (m *ReprotectReplicationOpts) GetReplicationBandwidth() int64 {
+	if m != nil {
+		return m.ReplicationBandwidth
+	}
+	return 0
+}
+
 // CreateVolumeGroupOpts is a structure which indicates all required
 // properties for creating a volume group.
 type CreateVolumeGroupOpts struct {
@@ -2149,17 +2575,21 @@ type CreateVolumeGroupOpts struct {
 	// The pool belongs to the group.
 	PoolId string `protobuf:"bytes,8,opt,name=poolId,proto3" json:"poolId,omitempty"`
 	// The Context
-	Context              string   `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Context string `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	// The name of the pool which the group belongs to.
+	PoolName string `protobuf:"bytes,10,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	// The metadata of the volume group.
+	Metadata             map[string]string `protobuf:"bytes,11,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *CreateVolumeGroupOpts) Reset()         { *m = CreateVolumeGroupOpts{} }
 func (m *CreateVolumeGroupOpts) String() string { return proto.CompactTextString(m) }
 func (*CreateVolumeGroupOpts) ProtoMessage()    {}
 func (*CreateVolumeGroupOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{17}
+	return fileDescriptor_4c16552f9fdb66d8, []int{20}
 }
 
 func (m *CreateVolumeGroupOpts) XXX_Unmarshal(b []byte) error {
@@ -2243,6 +2673,20 @@ func (m *CreateVolumeGroupOpts) GetContext() string {
 	return ""
 }
 
+func (m *CreateVolumeGroupOpts) GetPoolName() string {
+	if m != nil {
+		return m.PoolName
+	}
+	return ""
+}
+
+func (m *CreateVolumeGroupOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type UpdateVolumeGroupOpts struct {
 	// The uuid of the volume group, optional when updating.
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -2255,17 +2699,21 @@ type UpdateVolumeGroupOpts struct {
 	// The pool belongs to the group.
 	PoolId string `protobuf:"bytes,5,opt,name=poolId,proto3" json:"poolId,omitempty"`
 	// The Context
-	Context              string   `protobuf:"bytes,6,opt,name=context,proto3" json:"context,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Context string `protobuf:"bytes,6,opt,name=context,proto3" json:"context,omitempty"`
+	// The name of the pool which the group belongs to.
+	PoolName string `protobuf:"bytes,7,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	// The metadata of the volume group returned when it is created.
+	Metadata             map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *UpdateVolumeGroupOpts) Reset()         { *m = UpdateVolumeGroupOpts{} }
 func (m *UpdateVolumeGroupOpts) String() string { return proto.CompactTextString(m) }
 func (*UpdateVolumeGroupOpts) ProtoMessage()    {}
 func (*UpdateVolumeGroupOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{18}
+	return fileDescriptor_4c16552f9fdb66d8, []int{21}
 }
 
 func (m *UpdateVolumeGroupOpts) XXX_Unmarshal(b []byte) error {
@@ -2328,6 +2776,20 @@ func (m *UpdateVolumeGroupOpts) GetContext() string {
 	return ""
 }
 
+func (m *UpdateVolumeGroupOpts) GetPoolName() string {
+	if m != nil {
+		return m.PoolName
+	}
+	return ""
+}
+
+func (m *UpdateVolumeGroupOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type DeleteVolumeGroupOpts struct {
 	// The uuid of the volume group, optional when deleting.
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -2336,59 +2798,383 @@ type DeleteVolumeGroupOpts struct {
 	// The driver of the volume group.
 	DriverName string `protobuf:"bytes,3,opt,name=driverName,proto3" json:"driverName,omitempty"`
 	// The Context
-	Context              string   `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Context string `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	// The name of the pool which the group belongs to.
+	PoolName string `protobuf:"bytes,5,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	// The metadata of the volume group returned when it is created.
+	Metadata             map[string]string `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *DeleteVolumeGroupOpts) Reset()         { *m = DeleteVolumeGroupOpts{} }
 func (m *DeleteVolumeGroupOpts) String() string { return proto.CompactTextString(m) }
 func (*DeleteVolumeGroupOpts) ProtoMessage()    {}
 func (*DeleteVolumeGroupOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{19}
+	return fileDescriptor_4c16552f9fdb66d8, []int{22}
+}
+
+func (m *DeleteVolumeGroupOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteVolumeGroupOpts.Unmarshal(m, b)
+}
+func (m *DeleteVolumeGroupOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteVolumeGroupOpts.Marshal(b, m, deterministic)
+}
+func (m *DeleteVolumeGroupOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteVolumeGroupOpts.Merge(m, src)
+}
+func (m *DeleteVolumeGroupOpts) XXX_Size() int {
+	return xxx_messageInfo_DeleteVolumeGroupOpts.Size(m)
+}
+func (m *DeleteVolumeGroupOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteVolumeGroupOpts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteVolumeGroupOpts proto.InternalMessageInfo
+
+func (m *DeleteVolumeGroupOpts) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeleteVolumeGroupOpts) GetPoolId() string {
+	if m != nil {
+		return m.PoolId
+	}
+	return ""
+}
+
+func (m *DeleteVolumeGroupOpts) GetDriverName() string {
+	if m != nil {
+		return m.DriverName
+	}
+	return ""
+}
+
+func (m *DeleteVolumeGroupOpts) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+func (m *DeleteVolumeGroupOpts) GetPoolName() string {
+	if m != nil {
+		return m.PoolName
+	}
+	return ""
+}
+
+func (m *DeleteVolumeGroupOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// GroupSnapshotMember is the snapshot of a volume in a group snapshot.
+type GroupSnapshotMember struct {
+	// The uuid of the volume snapshot.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The uuid of the volume that snapshot belongs to.
+	VolumeId string `protobuf:"bytes,2,opt,name=volumeId,proto3" json:"volumeId,omitempty"`
+	// The size of the volume.
+	Size int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// The metadata of the volume when creating, or the metadata of the
+	// volume snapshot when deleting.
+	Metadata             map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GroupSnapshotMember) Reset()         { *m = GroupSnapshotMember{} }
+func (m *GroupSnapshotMember) String() string { return proto.CompactTextString(m) }
+func (*GroupSnapshotMember) ProtoMessage()    {}
+func (*GroupSnapshotMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{23}
+}
+
+func (m *GroupSnapshotMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupSnapshotMember.Unmarshal(m, b)
+}
+func (m *GroupSnapshotMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupSnapshotMember.Marshal(b, m, deterministic)
+}
+func (m *GroupSnapshotMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupSnapshotMember.Merge(m, src)
+}
+func (m *GroupSnapshotMember) XXX_Size() int {
+	return xxx_messageInfo_GroupSnapshotMember.Size(m)
+}
+func (m *GroupSnapshotMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupSnapshotMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupSnapshotMember proto.InternalMessageInfo
+
+func (m *GroupSnapshotMember) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GroupSnapshotMember) GetVolumeId() string {
+	if m != nil {
+		return m.VolumeId
+	}
+	return ""
+}
+
+func (m *GroupSnapshotMember) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *GroupSnapshotMember) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// CreateGroupSnapshotOpts is a structure which indicates all required
+// properties for creating a group snapshot.
+type CreateGroupSnapshotOpts struct {
+	// The uuid of the group snapshot, required.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the group snapshot.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The description of the group snapshot, optional.
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The uuid of the volume group, required.
+	GroupId string `protobuf:"bytes,4,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	// The snapshots of the volumes in the group.
+	Snapshots []*GroupSnapshotMember `protobuf:"bytes,5,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	// The pool belongs to the group.
+	PoolId string `protobuf:"bytes,6,opt,name=poolId,proto3" json:"poolId,omitempty"`
+	// The name of the pool which the group belongs to.
+	PoolName string `protobuf:"bytes,7,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	// The metadata of the volume group.
+	GroupMetadata map[string]string `protobuf:"bytes,8,rep,name=groupMetadata,proto3" json:"groupMetadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The storage driver type.
+	DriverName string `protobuf:"bytes,9,opt,name=driverName,proto3" json:"driverName,omitempty"`
+	// The Context
+	Context              string   `protobuf:"bytes,10,opt,name=context,proto3" json:"context,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateGroupSnapshotOpts) Reset()         { *m = CreateGroupSnapshotOpts{} }
+func (m *CreateGroupSnapshotOpts) String() string { return proto.CompactTextString(m) }
+func (*CreateGroupSnapshotOpts) ProtoMessage()    {}
+func (*CreateGroupSnapshotOpts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{24}
+}
+
+func (m *CreateGroupSnapshotOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGroupSnapshotOpts.Unmarshal(m, b)
+}
+func (m *CreateGroupSnapshotOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGroupSnapshotOpts.Marshal(b, m, deterministic)
+}
+func (m *CreateGroupSnapshotOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGroupSnapshotOpts.Merge(m, src)
+}
+func (m *CreateGroupSnapshotOpts) XXX_Size() int {
+	return xxx_messageInfo_CreateGroupSnapshotOpts.Size(m)
+}
+func (m *CreateGroupSnapshotOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGroupSnapshotOpts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateGroupSnapshotOpts proto.InternalMessageInfo
+
+func (m *CreateGroupSnapshotOpts) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetSnapshots() []*GroupSnapshotMember {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+func (m *CreateGroupSnapshotOpts) GetPoolId() string {
+	if m != nil {
+		return m.PoolId
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetPoolName() string {
+	if m != nil {
+		return m.PoolName
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetGroupMetadata() map[string]string {
+	if m != nil {
+		return m.GroupMetadata
+	}
+	return nil
+}
+
+func (m *CreateGroupSnapshotOpts) GetDriverName() string {
+	if m != nil {
+		return m.DriverName
+	}
+	return ""
+}
+
+func (m *CreateGroupSnapshotOpts) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+// DeleteGroupSnapshotOpts is a structure which indicates all required
+// properties for deleting a group snapshot.
+type DeleteGroupSnapshotOpts struct {
+	// The uuid of the group snapshot, required.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The uuid of the volume group, required.
+	GroupId string `protobuf:"bytes,2,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	// The snapshots of the volumes in the group.
+	Snapshots []*GroupSnapshotMember `protobuf:"bytes,3,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	// The pool belongs to the group.
+	PoolId string `protobuf:"bytes,4,opt,name=poolId,proto3" json:"poolId,omitempty"`
+	// The name of the pool which the group belongs to.
+	PoolName string `protobuf:"bytes,5,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	// The metadata of the volume group.
+	GroupMetadata map[string]string `protobuf:"bytes,6,rep,name=groupMetadata,proto3" json:"groupMetadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The metadata of the group snapshot.
+	Metadata map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The storage driver type.
+	DriverName string `protobuf:"bytes,8,opt,name=driverName,proto3" json:"driverName,omitempty"`
+	// The Context
+	Context              string   `protobuf:"bytes,9,opt,name=context,proto3" json:"context,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DeleteVolumeGroupOpts) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteVolumeGroupOpts.Unmarshal(m, b)
+func (m *DeleteGroupSnapshotOpts) Reset()         { *m = DeleteGroupSnapshotOpts{} }
+func (m *DeleteGroupSnapshotOpts) String() string { return proto.CompactTextString(m) }
+func (*DeleteGroupSnapshotOpts) ProtoMessage()    {}
+func (*DeleteGroupSnapshotOpts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4c16552f9fdb66d8, []int{25}
 }
-func (m *DeleteVolumeGroupOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteVolumeGroupOpts.Marshal(b, m, deterministic)
+
+func (m *DeleteGroupSnapshotOpts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteGroupSnapshotOpts.Unmarshal(m, b)
 }
-func (m *DeleteVolumeGroupOpts) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteVolumeGroupOpts.Merge(m, src)
+func (m *DeleteGroupSnapshotOpts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteGroupSnapshotOpts.Marshal(b, m, deterministic)
 }
-func (m *DeleteVolumeGroupOpts) XXX_Size() int {
-	return xxx_messageInfo_DeleteVolumeGroupOpts.Size(m)
+func (m *DeleteGroupSnapshotOpts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteGroupSnapshotOpts.Merge(m, src)
 }
-func (m *DeleteVolumeGroupOpts) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteVolumeGroupOpts.DiscardUnknown(m)
+func (m *DeleteGroupSnapshotOpts) XXX_Size() int {
+	return xxx_messageInfo_DeleteGroupSnapshotOpts.Size(m)
+}
+func (m *DeleteGroupSnapshotOpts) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteGroupSnapshotOpts.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteVolumeGroupOpts proto.InternalMessageInfo
+var xxx_messageInfo_DeleteGroupSnapshotOpts proto.InternalMessageInfo
 
-func (m *DeleteVolumeGroupOpts) GetId() string {
+func (m *DeleteGroupSnapshotOpts) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *DeleteVolumeGroupOpts) GetPoolId() string {
+func (m *DeleteGroupSnapshotOpts) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *DeleteGroupSnapshotOpts) GetSnapshots() []*GroupSnapshotMember {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+func (m *DeleteGroupSnapshotOpts) GetPoolId() string {
 	if m != nil {
 		return m.PoolId
 	}
 	return ""
 }
 
-func (m *DeleteVolumeGroupOpts) GetDriverName() string {
+func (m *DeleteGroupSnapshotOpts) GetPoolName() string {
+	if m != nil {
+		return m.PoolName
+	}
+	return ""
+}
+
+func (m *DeleteGroupSnapshotOpts) GetGroupMetadata() map[string]string {
+	if m != nil {
+		return m.GroupMetadata
+	}
+	return nil
+}
+
+func (m *DeleteGroupSnapshotOpts) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *DeleteGroupSnapshotOpts) GetDriverName() string {
 	if m != nil {
 		return m.DriverName
 	}
 	return ""
 }
 
-func (m *DeleteVolumeGroupOpts) GetContext() string {
+func (m *DeleteGroupSnapshotOpts) GetContext() string {
 	if m != nil {
 		return m.Context
 	}
@@ -2415,7 +3201,7 @@ func (m *AttachVolumeOpts) Reset()         { *m = AttachVolumeOpts{} }
 func (m *AttachVolumeOpts) String() string { return proto.CompactTextString(m) }
 func (*AttachVolumeOpts) ProtoMessage()    {}
 func (*AttachVolumeOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{20}
+	return fileDescriptor_4c16552f9fdb66d8, []int{26}
 }
 
 func (m *AttachVolumeOpts) XXX_Unmarshal(b []byte) error {
@@ -2484,7 +3270,7 @@ func (m *DetachVolumeOpts) Reset()         { *m = DetachVolumeOpts{} }
 func (m *DetachVolumeOpts) String() string { return proto.CompactTextString(m) }
 func (*DetachVolumeOpts) ProtoMessage()    {}
 func (*DetachVolumeOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{21}
+	return fileDescriptor_4c16552f9fdb66d8, []int{27}
 }
 
 func (m *DetachVolumeOpts) XXX_Unmarshal(b []byte) error {
@@ -2566,7 +3352,7 @@ func (m *DeleteFileShareAclOpts) Reset()         { *m = DeleteFileShareAclOpts{}
 func (m *DeleteFileShareAclOpts) String() string { return proto.CompactTextString(m) }
 func (*DeleteFileShareAclOpts) ProtoMessage()    {}
 func (*DeleteFileShareAclOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{22}
+	return fileDescriptor_4c16552f9fdb66d8, []int{28}
 }
 
 func (m *DeleteFileShareAclOpts) XXX_Unmarshal(b []byte) error {
@@ -2687,7 +3473,17 @@ type CreateFileShareAclOpts struct {
 	// The metadata of the file share, optional.
 	Metadata map[string]string `protobuf:"bytes,11,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// The protocol
-	AccessProtocol       string   `protobuf:"bytes,12,opt,name=AccessProtocol,proto3" json:"AccessProtocol,omitempty"`
+	AccessProtocol string `protobuf:"bytes,12,opt,name=AccessProtocol,proto3" json:"AccessProtocol,omitempty"`
+	// The squash mode of nfs export: root_squash, no_root_squash or all_squash.
+	Squash string `protobuf:"bytes,13,opt,name=squash,proto3" json:"squash,omitempty"`
+	// The uid that squashed users are mapped to, 0 means server default.
+	AnonUid int64 `protobuf:"varint,14,opt,name=anonUid,proto3" json:"anonUid,omitempty"`
+	// The gid that squashed users are mapped to, 0 means server default.
+	AnonGid int64 `protobuf:"varint,15,opt,name=anonGid,proto3" json:"anonGid,omitempty"`
+	// The security flavors of nfs export: sys, krb5, krb5i or krb5p.
+	SecurityFlavors []string `protobuf:"bytes,16,rep,name=securityFlavors,proto3" json:"securityFlavors,omitempty"`
+	// The write mode of nfs export: sync or async.
+	SyncMode             string   `protobuf:"bytes,17,opt,name=syncMode,proto3" json:"syncMode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2697,7 +3493,7 @@ func (m *CreateFileShareAclOpts) Reset()         { *m = CreateFileShareAclOpts{}
 func (m *CreateFileShareAclOpts) String() string { return proto.CompactTextString(m) }
 func (*CreateFileShareAclOpts) ProtoMessage()    {}
 func (*CreateFileShareAclOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{23}
+	return fileDescriptor_4c16552f9fdb66d8, []int{29}
 }
 
 func (m *CreateFileShareAclOpts) XXX_Unmarshal(b []byte) error {
@@ -2795,6 +3591,41 @@ func (m *CreateFileShareAclOpts) GetAccessProtocol() string {
 	return ""
 }
 
+func (m *CreateFileShareAclOpts) GetSquash() string {
+	if m != nil {
+		return m.Squash
+	}
+	return ""
+}
+
+func (m *CreateFileShareAclOpts) GetAnonUid() int64 {
+	if m != nil {
+		return m.AnonUid
+	}
+	return 0
+}
+
+func (m *CreateFileShareAclOpts) GetAnonGid() int64 {
+	if m != nil {
+		return m.AnonGid
+	}
+	return 0
+}
+
+func (m *CreateFileShareAclOpts) GetSecurityFlavors() []string {
+	if m != nil {
+		return m.SecurityFlavors
+	}
+	return nil
+}
+
+func (m *CreateFileShareAclOpts) GetSyncMode() string {
+	if m != nil {
+		return m.SyncMode
+	}
+	return ""
+}
+
 // CreateFileShareOpts is a structure which indicates all required properties for creating a file share.
 type CreateFileShareOpts struct {
 	// The uuid of the file share, optional when creating.
@@ -2834,7 +3665,7 @@ func (m *CreateFileShareOpts) Reset()         { *m = CreateFileShareOpts{} }
 func (m *CreateFileShareOpts) String() string { return proto.CompactTextString(m) }
 func (*CreateFileShareOpts) ProtoMessage()    {}
 func (*CreateFileShareOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{24}
+	return fileDescriptor_4c16552f9fdb66d8, []int{30}
 }
 
 func (m *CreateFileShareOpts) XXX_Unmarshal(b []byte) error {
@@ -2983,7 +3814,7 @@ func (m *DeleteFileShareOpts) Reset()         { *m = DeleteFileShareOpts{} }
 func (m *DeleteFileShareOpts) String() string { return proto.CompactTextString(m) }
 func (*DeleteFileShareOpts) ProtoMessage()    {}
 func (*DeleteFileShareOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{25}
+	return fileDescriptor_4c16552f9fdb66d8, []int{31}
 }
 
 func (m *DeleteFileShareOpts) XXX_Unmarshal(b []byte) error {
@@ -3095,7 +3926,7 @@ func (m *CreateFileShareSnapshotOpts) Reset()         { *m = CreateFileShareSnap
 func (m *CreateFileShareSnapshotOpts) String() string { return proto.CompactTextString(m) }
 func (*CreateFileShareSnapshotOpts) ProtoMessage()    {}
 func (*CreateFileShareSnapshotOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{26}
+	return fileDescriptor_4c16552f9fdb66d8, []int{32}
 }
 
 func (m *CreateFileShareSnapshotOpts) XXX_Unmarshal(b []byte) error {
@@ -3194,7 +4025,7 @@ func (m *DeleteFileShareSnapshotOpts) Reset()         { *m = DeleteFileShareSnap
 func (m *DeleteFileShareSnapshotOpts) String() string { return proto.CompactTextString(m) }
 func (*DeleteFileShareSnapshotOpts) ProtoMessage()    {}
 func (*DeleteFileShareSnapshotOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{27}
+	return fileDescriptor_4c16552f9fdb66d8, []int{33}
 }
 
 func (m *DeleteFileShareSnapshotOpts) XXX_Unmarshal(b []byte) error {
@@ -3268,7 +4099,7 @@ func (m *GenericResponse) Reset()         { *m = GenericResponse{} }
 func (m *GenericResponse) String() string { return proto.CompactTextString(m) }
 func (*GenericResponse) ProtoMessage()    {}
 func (*GenericResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{28}
+	return fileDescriptor_4c16552f9fdb66d8, []int{34}
 }
 
 func (m *GenericResponse) XXX_Unmarshal(b []byte) error {
@@ -3345,7 +4176,7 @@ func (m *GenericResponse_Result) Reset()         { *m = GenericResponse_Result{}
 func (m *GenericResponse_Result) String() string { return proto.CompactTextString(m) }
 func (*GenericResponse_Result) ProtoMessage()    {}
 func (*GenericResponse_Result) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{28, 0}
+	return fileDescriptor_4c16552f9fdb66d8, []int{34, 0}
 }
 
 func (m *GenericResponse_Result) XXX_Unmarshal(b []byte) error {
@@ -3385,7 +4216,7 @@ func (m *GenericResponse_Error) Reset()         { *m = GenericResponse_Error{} }
 func (m *GenericResponse_Error) String() string { return proto.CompactTextString(m) }
 func (*GenericResponse_Error) ProtoMessage()    {}
 func (*GenericResponse_Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{28, 1}
+	return fileDescriptor_4c16552f9fdb66d8, []int{34, 1}
 }
 
 func (m *GenericResponse_Error) XXX_Unmarshal(b []byte) error {
@@ -3441,7 +4272,7 @@ func (m *GetMetricsOpts) Reset()         { *m = GetMetricsOpts{} }
 func (m *GetMetricsOpts) String() string { return proto.CompactTextString(m) }
 func (*GetMetricsOpts) ProtoMessage()    {}
 func (*GetMetricsOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{29}
+	return fileDescriptor_4c16552f9fdb66d8, []int{35}
 }
 
 func (m *GetMetricsOpts) XXX_Unmarshal(b []byte) error {
@@ -3516,7 +4347,7 @@ func (m *CollectMetricsOpts) Reset()         { *m = CollectMetricsOpts{} }
 func (m *CollectMetricsOpts) String() string { return proto.CompactTextString(m) }
 func (*CollectMetricsOpts) ProtoMessage()    {}
 func (*CollectMetricsOpts) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{30}
+	return fileDescriptor_4c16552f9fdb66d8, []int{36}
 }
 
 func (m *CollectMetricsOpts) XXX_Unmarshal(b []byte) error {
@@ -3575,7 +4406,7 @@ func (m *NoParams) Reset()         { *m = NoParams{} }
 func (m *NoParams) String() string { return proto.CompactTextString(m) }
 func (*NoParams) ProtoMessage()    {}
 func (*NoParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4c16552f9fdb66d8, []int{31}
+	return fileDescriptor_4c16552f9fdb66d8, []int{37}
 }
 
 func (m *NoParams) XXX_Unmarshal(b []byte) error {
@@ -3641,9 +4472,31 @@ func init() {
 	proto.RegisterMapType((map[string]string)(nil), "proto.FailoverReplicationOpts.PrimaryReplicationDriverDataEntry")
 	proto.RegisterMapType((map[string]string)(nil), "proto.FailoverReplicationOpts.SecondaryReplicationDriverDataEntry")
 	proto.RegisterType((*FailoverReplicationOpts_FailoverRequest)(nil), "proto.FailoverReplicationOpts.FailoverRequest")
+	proto.RegisterType((*GetReplicationStatusOpts)(nil), "proto.GetReplicationStatusOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.GetReplicationStatusOpts.MetadataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.GetReplicationStatusOpts.PrimaryReplicationDriverDataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.GetReplicationStatusOpts.SecondaryReplicationDriverDataEntry")
+	proto.RegisterType((*SwitchoverReplicationOpts)(nil), "proto.SwitchoverReplicationOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.SwitchoverReplicationOpts.MetadataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.SwitchoverReplicationOpts.PrimaryReplicationDriverDataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.SwitchoverReplicationOpts.SecondaryReplicationDriverDataEntry")
+	proto.RegisterType((*ReprotectReplicationOpts)(nil), "proto.ReprotectReplicationOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.ReprotectReplicationOpts.MetadataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.ReprotectReplicationOpts.PrimaryReplicationDriverDataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.ReprotectReplicationOpts.SecondaryReplicationDriverDataEntry")
 	proto.RegisterType((*CreateVolumeGroupOpts)(nil), "proto.CreateVolumeGroupOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.CreateVolumeGroupOpts.MetadataEntry")
 	proto.RegisterType((*UpdateVolumeGroupOpts)(nil), "proto.UpdateVolumeGroupOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.UpdateVolumeGroupOpts.MetadataEntry")
 	proto.RegisterType((*DeleteVolumeGroupOpts)(nil), "proto.DeleteVolumeGroupOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.DeleteVolumeGroupOpts.MetadataEntry")
+	proto.RegisterType((*GroupSnapshotMember)(nil), "proto.GroupSnapshotMember")
+	proto.RegisterMapType((map[string]string)(nil), "proto.GroupSnapshotMember.MetadataEntry")
+	proto.RegisterType((*CreateGroupSnapshotOpts)(nil), "proto.CreateGroupSnapshotOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.CreateGroupSnapshotOpts.GroupMetadataEntry")
+	proto.RegisterType((*DeleteGroupSnapshotOpts)(nil), "proto.DeleteGroupSnapshotOpts")
+	proto.RegisterMapType((map[string]string)(nil), "proto.DeleteGroupSnapshotOpts.GroupMetadataEntry")
+	proto.RegisterMapType((map[string]string)(nil), "proto.DeleteGroupSnapshotOpts.MetadataEntry")
 	proto.RegisterType((*AttachVolumeOpts)(nil), "proto.AttachVolumeOpts")
 	proto.RegisterMapType((map[string]string)(nil), "proto.AttachVolumeOpts.MetadataEntry")
 	proto.RegisterType((*DetachVolumeOpts)(nil), "proto.DetachVolumeOpts")
@@ -3668,168 +4521,200 @@ func init() {
 	proto.RegisterType((*NoParams)(nil), "proto.NoParams")
 }
 
-func init() {
-	proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8)
-}
+func init() { proto.RegisterFile("model.proto", fileDescriptor_4c16552f9fdb66d8) }
 
 var fileDescriptor_4c16552f9fdb66d8 = []byte{
-	// 2347 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5b, 0xcd, 0x6f, 0x24, 0x47,
-	0x15, 0xcf, 0x74, 0xcf, 0xe7, 0x1b, 0xdb, 0x33, 0x2e, 0xaf, 0x77, 0x9b, 0x59, 0xc7, 0x38, 0x43,
-	0x88, 0xac, 0x24, 0x38, 0x8b, 0x89, 0x14, 0x3e, 0x14, 0xc0, 0x6b, 0x7b, 0x6d, 0x2b, 0xeb, 0xac,
-	0x33, 0xbb, 0x1b, 0x24, 0x6e, 0xbd, 0xdd, 0xb5, 0xb8, 0xb5, 0x3d, 0x5d, 0x43, 0x77, 0x7b, 0xb2,
-	0xe6, 0xc8, 0x87, 0x14, 0x38, 0x21, 0xc4, 0x95, 0x03, 0x39, 0x70, 0xe3, 0x4f, 0x00, 0x24, 0xc4,
-	0x5f, 0x90, 0x03, 0x47, 0xee, 0x48, 0x5c, 0x90, 0xb8, 0x20, 0xed, 0x01, 0x75, 0xf5, 0xc7, 0x54,
-	0x57, 0x57, 0x57, 0xf7, 0xec, 0x8c, 0x9d, 0x45, 0xeb, 0x93, 0xa7, 0x5e, 0x55, 0xbf, 0x7a, 0xf5,
-	0x7b, 0x1f, 0xf5, 0xaa, 0xea, 0x19, 0xda, 0x43, 0x62, 0x62, 0x7b, 0x6b, 0xe4, 0x12, 0x9f, 0xa0,
-	0x1a, 0xfd, 0xd3, 0xff, 0xbc, 0x06, 0xdd, 0x5d, 0x17, 0xeb, 0x3e, 0xfe, 0x98, 0xd8, 0x67, 0x43,
-	0x7c, 0x6f, 0xe4, 0x7b, 0x68, 0x09, 0x14, 0xcb, 0xd4, 0x2a, 0x1b, 0x95, 0xcd, 0xd6, 0x40, 0xb1,
-	0x4c, 0x84, 0xa0, 0xea, 0xe8, 0x43, 0xac, 0x29, 0x94, 0x42, 0x7f, 0x07, 0x34, 0xcf, 0xfa, 0x09,
-	0xd6, 0xd4, 0x8d, 0xca, 0xa6, 0x3a, 0xa0, 0xbf, 0xd1, 0x06, 0xb4, 0x4d, 0xec, 0x19, 0xae, 0x35,
-	0xf2, 0x2d, 0xe2, 0x68, 0x55, 0x3a, 0x9c, 0x25, 0xa1, 0x75, 0x00, 0xcf, 0xd1, 0x47, 0xde, 0x29,
-	0xf1, 0x8f, 0x4c, 0xad, 0x46, 0x07, 0x30, 0x14, 0xf4, 0x26, 0x74, 0xf5, 0xb1, 0x6e, 0xd9, 0xfa,
-	0x23, 0xcb, 0xb6, 0xfc, 0xf3, 0x1f, 0x12, 0x07, 0x6b, 0x75, 0x3a, 0x2a, 0x43, 0x47, 0xd7, 0xa1,
-	0x3e, 0x22, 0xc4, 0x3e, 0x32, 0xb5, 0x26, 0x1d, 0x11, 0xb5, 0x50, 0x0f, 0x9a, 0xc1, 0xaf, 0x0f,
-	0x03, 0x89, 0x5b, 0xb4, 0x27, 0x69, 0xa3, 0x1d, 0x68, 0x0e, 0xb1, 0xaf, 0x9b, 0xba, 0xaf, 0x6b,
-	0xb0, 0xa1, 0x6e, 0xb6, 0xb7, 0xbf, 0x1a, 0xe2, 0xb1, 0xc5, 0x83, 0xb0, 0x75, 0x1c, 0x8d, 0xdb,
-	0x77, 0x7c, 0xf7, 0x7c, 0x90, 0x7c, 0x16, 0x2c, 0xc1, 0x74, 0xad, 0x31, 0x76, 0xe9, 0x04, 0xed,
-	0x70, 0x09, 0x13, 0x0a, 0xd2, 0xa0, 0x61, 0x10, 0xc7, 0xc7, 0x4f, 0x7d, 0x6d, 0x81, 0x76, 0xc6,
-	0x4d, 0x74, 0x0a, 0xab, 0x2e, 0x1e, 0xd9, 0x96, 0xa1, 0x07, 0x58, 0xec, 0xd1, 0x4f, 0xf6, 0x02,
-	0x49, 0x16, 0xa9, 0x24, 0xdb, 0x79, 0x92, 0x0c, 0x44, 0x1f, 0x85, 0x62, 0x89, 0x19, 0xa2, 0xd7,
-	0x61, 0x91, 0xe9, 0x38, 0x32, 0xb5, 0x25, 0x2a, 0x49, 0x9a, 0x88, 0xfa, 0xb0, 0x10, 0x43, 0x7f,
-	0x3f, 0x50, 0x65, 0x87, 0xaa, 0x32, 0x45, 0x43, 0x6f, 0xc3, 0x72, 0xdc, 0xbe, 0xe3, 0x92, 0xe1,
-	0xae, 0x4d, 0xce, 0x4c, 0xad, 0xbb, 0x51, 0xd9, 0x6c, 0x0e, 0xb2, 0x1d, 0xbd, 0xef, 0xc0, 0x62,
-	0x0a, 0x36, 0xd4, 0x05, 0xf5, 0x09, 0x3e, 0x8f, 0x4c, 0x29, 0xf8, 0x89, 0xae, 0x41, 0x6d, 0xac,
-	0xdb, 0x67, 0xb1, 0x31, 0x85, 0x8d, 0x6f, 0x2b, 0xdf, 0xac, 0xf4, 0x0e, 0xa1, 0x97, 0xbf, 0xd2,
-	0x69, 0x38, 0xf5, 0xff, 0x53, 0x81, 0xee, 0x1e, 0xb6, 0xb1, 0xd4, 0xa8, 0x27, 0xe6, 0xa3, 0xa6,
-	0xcc, 0x87, 0x35, 0x91, 0x6a, 0xca, 0x44, 0x78, 0x96, 0x25, 0x4d, 0xa4, 0x26, 0x33, 0x91, 0x7a,
-	0xca, 0x44, 0x66, 0x02, 0xb0, 0xff, 0x07, 0x15, 0xba, 0xfb, 0x4f, 0x7d, 0xec, 0x98, 0x2f, 0xb9,
-	0x2f, 0xf3, 0x20, 0xcc, 0xdf, 0x97, 0x67, 0x53, 0xd4, 0xe7, 0x0a, 0x68, 0xac, 0x97, 0xdf, 0x8f,
-	0x40, 0xbb, 0x60, 0x85, 0xf5, 0xa0, 0x39, 0xa6, 0xf3, 0x25, 0xea, 0x4a, 0xda, 0xe8, 0x88, 0x01,
-	0xb3, 0x41, 0xc1, 0xfc, 0x9a, 0x20, 0x1c, 0xb1, 0x82, 0x96, 0x04, 0xb5, 0x29, 0x03, 0xb5, 0x35,
-	0x47, 0x50, 0x3f, 0x55, 0x40, 0x63, 0x3d, 0x54, 0x0a, 0x2a, 0x0b, 0x85, 0x22, 0x81, 0x42, 0x4d,
-	0x41, 0x91, 0xc7, 0xbe, 0x24, 0x14, 0x55, 0x19, 0x14, 0xb5, 0x39, 0x42, 0xf1, 0x27, 0x15, 0x7a,
-	0xac, 0xda, 0x76, 0x7c, 0x5f, 0x37, 0x4e, 0x87, 0xd8, 0x99, 0x1e, 0x8c, 0xbc, 0x28, 0xf9, 0x3a,
-	0x2c, 0x9a, 0xe4, 0x2e, 0x31, 0x74, 0x3b, 0x64, 0x4e, 0x17, 0xd7, 0x1c, 0xa4, 0x89, 0x68, 0x0d,
-	0x5a, 0xc3, 0x33, 0xdb, 0xb7, 0x4e, 0x74, 0xff, 0x94, 0xae, 0xb0, 0x39, 0x98, 0x10, 0xd0, 0x5b,
-	0xd0, 0x3c, 0x25, 0x9e, 0x7f, 0xe4, 0x3c, 0x26, 0x34, 0x30, 0xb4, 0xb7, 0x3b, 0x11, 0xd0, 0x87,
-	0x11, 0x79, 0x90, 0x0c, 0x40, 0x1f, 0x64, 0x0c, 0xf4, 0x1d, 0x81, 0x81, 0xa6, 0x57, 0x3a, 0x7f,
-	0x13, 0x45, 0x6f, 0xc0, 0xd2, 0x8e, 0x61, 0x60, 0xcf, 0x3b, 0x09, 0xe6, 0x36, 0x88, 0xad, 0x01,
-	0x1d, 0xc0, 0x51, 0x67, 0xd3, 0xdf, 0x7f, 0x15, 0xe8, 0xb1, 0xb6, 0x76, 0x01, 0xfa, 0x63, 0xb1,
-	0xaf, 0x4e, 0x83, 0x7d, 0x2d, 0x85, 0x7d, 0xbe, 0x94, 0x25, 0xb1, 0xaf, 0xcb, 0xb0, 0x6f, 0x14,
-	0x61, 0xdf, 0x9c, 0x3f, 0xf6, 0x7f, 0x54, 0x61, 0x2d, 0xb4, 0xa8, 0xd8, 0xc3, 0x0b, 0xd0, 0x4f,
-	0x6f, 0x83, 0x4a, 0x66, 0x1b, 0xcc, 0x78, 0x8a, 0x5a, 0xe8, 0x29, 0x55, 0x99, 0xa7, 0xd4, 0x8a,
-	0xb4, 0x75, 0xcc, 0x68, 0xab, 0x4e, 0xb5, 0xf5, 0xf5, 0x94, 0xa7, 0x88, 0xd7, 0x55, 0x52, 0x5f,
-	0x0d, 0x99, 0xbe, 0x9a, 0x45, 0xfa, 0x6a, 0xcd, 0x5f, 0x5f, 0xff, 0x54, 0x60, 0x2d, 0xb4, 0xc2,
-	0x39, 0xe9, 0x8b, 0xc5, 0x5a, 0x9d, 0x06, 0xeb, 0x6a, 0x0a, 0x6b, 0x99, 0x4c, 0xf3, 0x4f, 0x1c,
-	0x05, 0x58, 0x37, 0xe6, 0x8f, 0xf5, 0x6f, 0x2b, 0xd0, 0x8c, 0x41, 0xa0, 0xa9, 0x99, 0xad, 0xfb,
-	0x8f, 0x89, 0x3b, 0x8c, 0xbe, 0x4e, 0xda, 0x41, 0xd4, 0x21, 0xde, 0x83, 0xf3, 0x51, 0xcc, 0x23,
-	0x6a, 0x05, 0x79, 0x4b, 0x00, 0x5d, 0x14, 0x8b, 0xe8, 0x6f, 0xaa, 0x9f, 0x51, 0xb4, 0x37, 0x2a,
-	0xd6, 0x08, 0xdd, 0x02, 0xb0, 0x1c, 0xcb, 0xb7, 0x74, 0x9f, 0xb8, 0x5e, 0x14, 0x6e, 0xba, 0x11,
-	0xa8, 0x47, 0x71, 0xc7, 0x80, 0x19, 0xd3, 0xdf, 0x85, 0x56, 0xd2, 0x11, 0x66, 0x8c, 0xae, 0x4f,
-	0x01, 0x8c, 0xc5, 0x8a, 0xda, 0xb4, 0x2f, 0x86, 0x27, 0x0a, 0x94, 0x71, 0xbb, 0x3f, 0x06, 0x08,
-	0xc3, 0x18, 0x3d, 0x40, 0xbd, 0x03, 0x55, 0xaa, 0xd3, 0x0a, 0x9d, 0xfe, 0x66, 0x34, 0xfd, 0x64,
-	0xc0, 0xd6, 0xe4, 0x08, 0x46, 0x07, 0xf6, 0xde, 0x83, 0xd6, 0xf3, 0x9d, 0x55, 0x7e, 0xd1, 0x82,
-	0xd5, 0xd0, 0x2f, 0x99, 0xc3, 0x4f, 0xe9, 0x44, 0x90, 0x4b, 0xfa, 0xd4, 0x6c, 0xd2, 0xb7, 0x09,
-	0x9d, 0x91, 0x6b, 0x0d, 0x75, 0xf7, 0xfc, 0xe3, 0x78, 0x8f, 0x08, 0xb1, 0xe6, 0xc9, 0xf4, 0xa8,
-	0x87, 0x0d, 0xe2, 0x98, 0xec, 0xd8, 0xd0, 0x06, 0xb3, 0x1d, 0x17, 0x9e, 0xdd, 0xff, 0xb4, 0x02,
-	0x6b, 0x91, 0x84, 0xc2, 0x53, 0xa1, 0xd6, 0xa6, 0xaa, 0xf9, 0x6e, 0x2a, 0xb4, 0x71, 0x10, 0x6e,
-	0x9d, 0x48, 0x18, 0x84, 0xda, 0x93, 0xce, 0x81, 0x3e, 0xad, 0xc0, 0x7a, 0xb2, 0x74, 0xb1, 0x18,
-	0x0b, 0x54, 0x8c, 0xef, 0x4b, 0xc5, 0xb8, 0x2f, 0x65, 0x11, 0x0a, 0x52, 0x30, 0x4f, 0x80, 0xa1,
-	0x49, 0x8c, 0x27, 0x47, 0xa6, 0xb6, 0x18, 0x62, 0x18, 0xb6, 0xb8, 0x90, 0xb1, 0x24, 0x0b, 0x19,
-	0x9d, 0x74, 0xc8, 0x58, 0x83, 0x96, 0xe5, 0x45, 0x08, 0x45, 0x47, 0xfa, 0x09, 0x01, 0xdd, 0x61,
-	0x22, 0xdb, 0x32, 0x5d, 0xe3, 0x9b, 0xd2, 0x35, 0xe6, 0x85, 0xb4, 0x6f, 0xc1, 0xd2, 0x38, 0x71,
-	0x9b, 0xbb, 0x96, 0xe7, 0x6b, 0x88, 0x72, 0x5b, 0xce, 0xf8, 0xd4, 0x80, 0x1b, 0x18, 0x98, 0x2e,
-	0x73, 0x61, 0x71, 0x4c, 0x4c, 0xac, 0xad, 0x84, 0xa6, 0xcb, 0x91, 0x03, 0xd3, 0x65, 0xe4, 0x39,
-	0xc1, 0xae, 0x45, 0x4c, 0xed, 0x1a, 0x3d, 0x1c, 0x65, 0x3b, 0xd0, 0x36, 0x5c, 0x63, 0x88, 0xb7,
-	0x75, 0xc7, 0xfc, 0xc4, 0x32, 0xfd, 0x53, 0x6d, 0x95, 0x7e, 0x20, 0xec, 0xeb, 0xdd, 0x83, 0xd7,
-	0x0a, 0x8d, 0x69, 0xaa, 0xdb, 0x8e, 0x8f, 0xe0, 0x2b, 0x25, 0xcc, 0x62, 0x2a, 0x96, 0x33, 0xc5,
-	0xf6, 0x3f, 0x37, 0x60, 0x35, 0xdc, 0xb3, 0xae, 0xe2, 0xd0, 0x0c, 0x71, 0x48, 0x08, 0xe1, 0xe5,
-	0xc7, 0x21, 0xb1, 0x18, 0x2f, 0x66, 0x1c, 0x62, 0x23, 0x4d, 0x37, 0x15, 0x69, 0xc4, 0xab, 0xc8,
-	0x8b, 0x34, 0xa9, 0x78, 0xb6, 0xcc, 0xc5, 0xb3, 0x97, 0xc3, 0x81, 0xf7, 0x1d, 0xfd, 0x91, 0x7d,
-	0xe5, 0xc0, 0xb3, 0x38, 0xb0, 0x10, 0xc2, 0xcb, 0x77, 0x60, 0xb1, 0x18, 0xff, 0x6f, 0x0e, 0x2c,
-	0x5e, 0xc5, 0x95, 0x03, 0x0b, 0x1d, 0xf8, 0xaf, 0x0d, 0xb8, 0xbe, 0x67, 0x79, 0x57, 0x1e, 0xcc,
-	0x7b, 0xf0, 0xcf, 0xca, 0x79, 0xf0, 0xf7, 0xe2, 0x5d, 0x43, 0x88, 0xe1, 0xcc, 0x2e, 0xfc, 0xcb,
-	0xb2, 0x2e, 0xbc, 0x23, 0x97, 0xe3, 0xc5, 0xf4, 0xe1, 0x83, 0x8c, 0x0f, 0xbf, 0x25, 0x5f, 0xc6,
-	0x95, 0x13, 0x0b, 0x9d, 0xf8, 0x77, 0x2d, 0xb8, 0x71, 0x47, 0xb7, 0x6c, 0x32, 0xc6, 0xee, 0x95,
-	0x17, 0xb3, 0x5e, 0xfc, 0xf3, 0x72, 0x5e, 0x1c, 0x6f, 0x80, 0x39, 0x20, 0xce, 0xec, 0xc6, 0xbf,
-	0x2a, 0xeb, 0xc6, 0xb7, 0x0b, 0x04, 0x79, 0x31, 0xfd, 0xf8, 0x16, 0xac, 0xe8, 0xb6, 0x4d, 0x3e,
-	0x09, 0x2f, 0x1c, 0x71, 0xf4, 0x08, 0x1a, 0x1d, 0xef, 0x45, 0x5d, 0x68, 0x0b, 0x50, 0x22, 0xe5,
-	0x6d, 0xdd, 0x78, 0x82, 0x1d, 0xf3, 0xc8, 0xa4, 0x9e, 0xdb, 0x1a, 0x08, 0x7a, 0xd0, 0x21, 0x13,
-	0x29, 0xc2, 0xa3, 0xfc, 0xdb, 0x05, 0x48, 0x95, 0x0a, 0x15, 0x2b, 0x2f, 0x5b, 0xa8, 0xe8, 0x79,
-	0xd0, 0x99, 0x20, 0xf6, 0xe3, 0x33, 0xec, 0xe5, 0x6a, 0xaf, 0x32, 0xad, 0xf6, 0x94, 0x3c, 0xed,
-	0xf5, 0x7f, 0xaf, 0xc4, 0xd7, 0x8d, 0x21, 0x83, 0x03, 0x97, 0x9c, 0x8d, 0x4a, 0x47, 0xa7, 0xb4,
-	0x5d, 0xaa, 0x19, 0xbb, 0x2c, 0x7e, 0x83, 0x16, 0x45, 0x99, 0x5a, 0x4e, 0x94, 0x59, 0x07, 0xd0,
-	0xcd, 0x68, 0xa1, 0x1e, 0x7d, 0xca, 0x68, 0x0d, 0x18, 0x4a, 0x58, 0xe5, 0x32, 0x24, 0x63, 0x1c,
-	0x0f, 0x69, 0xd0, 0x21, 0x69, 0x62, 0x6e, 0xac, 0xca, 0x7d, 0xe3, 0xeb, 0xff, 0xa5, 0x02, 0xab,
-	0x0f, 0x47, 0x66, 0x09, 0x8c, 0xd2, 0x78, 0x28, 0x19, 0x3c, 0xd2, 0x2b, 0x50, 0x8b, 0x57, 0x50,
-	0x95, 0xaf, 0xa0, 0x96, 0xb7, 0x82, 0xf4, 0x6b, 0x40, 0xff, 0x3c, 0xbe, 0xcb, 0x29, 0x5a, 0xc0,
-	0x84, 0xb5, 0x92, 0x62, 0x5d, 0xa4, 0x68, 0x66, 0xea, 0x6a, 0x7a, 0xea, 0x67, 0x15, 0xe8, 0x86,
-	0x36, 0xca, 0x14, 0xa1, 0xbc, 0x01, 0x4b, 0x7a, 0xfa, 0x75, 0x22, 0x14, 0x81, 0xa3, 0x06, 0xe3,
-	0x0c, 0xe2, 0x38, 0xd8, 0xa0, 0x8e, 0x19, 0x44, 0x98, 0x50, 0x2c, 0x8e, 0x9a, 0x2a, 0xfd, 0x50,
-	0x53, 0xa5, 0x1f, 0xfc, 0xd4, 0xb9, 0xc1, 0x27, 0x77, 0x05, 0xb3, 0xed, 0xff, 0xcf, 0x68, 0xe9,
-	0xd1, 0x17, 0xb6, 0x7c, 0x7e, 0xea, 0xcb, 0x5e, 0xfe, 0xdf, 0x54, 0xb8, 0x1e, 0x5a, 0xde, 0x1d,
-	0xcb, 0xc6, 0xf7, 0x4f, 0x75, 0x17, 0xef, 0x18, 0xb6, 0xd0, 0xf4, 0x36, 0xa0, 0xfd, 0xd8, 0xb2,
-	0xb1, 0x17, 0x8c, 0x49, 0xec, 0x8f, 0x25, 0x95, 0xc8, 0x85, 0x10, 0x54, 0xfd, 0xf3, 0x51, 0x5c,
-	0x59, 0x41, 0x7f, 0xd3, 0x08, 0x43, 0x61, 0xdd, 0xd5, 0x47, 0x51, 0x34, 0xa1, 0xaf, 0x48, 0x41,
-	0x84, 0xe1, 0xe8, 0x41, 0xbe, 0x12, 0xd2, 0x1e, 0x90, 0xc8, 0x85, 0x92, 0xf6, 0x0c, 0x35, 0x02,
-	0x08, 0xaa, 0xf4, 0x9b, 0xb0, 0x32, 0x80, 0xfe, 0x4e, 0xe5, 0xd7, 0xed, 0x74, 0x7e, 0x2d, 0x84,
-	0x2b, 0x57, 0x71, 0xd9, 0x87, 0xbe, 0x85, 0xf9, 0x3f, 0xf4, 0x05, 0x6a, 0x0c, 0x77, 0x89, 0x2b,
-	0x35, 0x96, 0x54, 0xa3, 0x18, 0xae, 0x2f, 0x56, 0x8d, 0x9f, 0x55, 0x61, 0x85, 0x93, 0xeb, 0x82,
-	0x4b, 0xcc, 0xa6, 0xd9, 0xde, 0x27, 0x7b, 0x4f, 0x3d, 0xf7, 0x10, 0xd1, 0xe0, 0x0e, 0x11, 0x7b,
-	0x0c, 0xfe, 0x4d, 0x8a, 0xff, 0xa6, 0x18, 0xff, 0x29, 0x9e, 0xd9, 0x5b, 0x32, 0x9b, 0x80, 0xb4,
-	0x4d, 0x6c, 0x42, 0x07, 0x3f, 0x1d, 0x11, 0xd7, 0xbf, 0x4b, 0xc2, 0xec, 0xd1, 0xa3, 0xa7, 0x85,
-	0xd6, 0x80, 0x27, 0x73, 0x65, 0x06, 0x8b, 0x99, 0x32, 0x03, 0xa6, 0xf8, 0x96, 0x49, 0xf2, 0x53,
-	0x34, 0x81, 0x91, 0x74, 0xe6, 0x6f, 0x24, 0xcf, 0x14, 0x58, 0xe1, 0x62, 0xd0, 0x54, 0xa9, 0xc2,
-	0x5e, 0x66, 0x33, 0xda, 0x14, 0x47, 0xb6, 0x8b, 0xa9, 0x94, 0x4b, 0x8c, 0xb7, 0xc1, 0x18, 0x6f,
-	0x64, 0x42, 0xce, 0xc4, 0xe5, 0x93, 0xb6, 0x48, 0x85, 0x2d, 0xb1, 0x0a, 0x2f, 0xa5, 0xd6, 0xeb,
-	0x1f, 0x0a, 0xdc, 0xe4, 0x6c, 0xf7, 0x92, 0xca, 0x41, 0xb9, 0xc8, 0x5d, 0xcb, 0x46, 0xee, 0xe7,
-	0x2f, 0xd5, 0xba, 0xcb, 0x18, 0x45, 0x8b, 0x1a, 0xc5, 0x2d, 0xb1, 0x9f, 0x96, 0x29, 0xa3, 0x9c,
-	0xb1, 0x68, 0x45, 0x81, 0x9b, 0x9c, 0x25, 0x4a, 0x01, 0x2e, 0xde, 0xd0, 0x9e, 0x3b, 0x39, 0x4e,
-	0xc1, 0x52, 0x4f, 0xc1, 0x22, 0x91, 0xf0, 0x62, 0x60, 0xf9, 0x57, 0x05, 0x3a, 0x07, 0xd8, 0xc1,
-	0xae, 0x65, 0x0c, 0xb0, 0x37, 0x22, 0x8e, 0x87, 0xd1, 0x7b, 0x50, 0x77, 0xb1, 0x77, 0x66, 0xfb,
-	0x94, 0x45, 0x7b, 0xfb, 0xd5, 0x48, 0x38, 0x6e, 0xdc, 0xd6, 0x80, 0x0e, 0x3a, 0x7c, 0x65, 0x10,
-	0x0d, 0x47, 0xef, 0x42, 0x0d, 0xbb, 0x2e, 0x71, 0xe9, 0x34, 0xed, 0xed, 0xb5, 0x9c, 0xef, 0xf6,
-	0x83, 0x31, 0x87, 0xaf, 0x0c, 0xc2, 0xc1, 0xbd, 0x3e, 0xd4, 0x43, 0x4e, 0x01, 0x62, 0x43, 0xec,
-	0x79, 0xfa, 0x8f, 0xe2, 0x9a, 0x9d, 0xb8, 0xd9, 0x7b, 0x1f, 0x6a, 0xf4, 0xab, 0xc0, 0xc6, 0x0d,
-	0x62, 0xc6, 0xfd, 0xf4, 0x37, 0x6f, 0xe3, 0x4a, 0xc6, 0xc6, 0x6f, 0x37, 0xa0, 0xe6, 0xe2, 0x91,
-	0x7d, 0xde, 0xff, 0xac, 0x02, 0x4b, 0x07, 0xd8, 0x3f, 0xc6, 0xbe, 0x6b, 0x19, 0x1e, 0x55, 0xfc,
-	0x3a, 0x80, 0xe5, 0x78, 0xbe, 0xee, 0x18, 0x81, 0x9e, 0x43, 0xbe, 0x0c, 0x25, 0xe8, 0x1f, 0xd2,
-	0xe1, 0xec, 0xe1, 0x6e, 0x42, 0x41, 0x6b, 0xd0, 0xf2, 0x7c, 0xdd, 0xf5, 0x1f, 0x58, 0x89, 0x15,
-	0x4c, 0x08, 0xc1, 0x92, 0xb0, 0x63, 0xd2, 0xbe, 0xc8, 0x08, 0xa2, 0x66, 0x7e, 0x28, 0xeb, 0xff,
-	0xba, 0x02, 0x68, 0x97, 0xd8, 0x36, 0x36, 0xa6, 0x12, 0x74, 0x03, 0xda, 0x13, 0xb1, 0x3c, 0x4d,
-	0xa1, 0xd1, 0x8c, 0x25, 0xb1, 0x53, 0xaa, 0x69, 0x8b, 0x2c, 0x88, 0xbb, 0x7d, 0x80, 0xe6, 0x87,
-	0xe4, 0x44, 0x77, 0xf5, 0xa1, 0xb7, 0xfd, 0x77, 0x80, 0xc5, 0x13, 0x97, 0x8c, 0x2d, 0x2f, 0x38,
-	0x88, 0x10, 0xe3, 0x09, 0xda, 0x81, 0x05, 0xf6, 0x32, 0x01, 0xdd, 0xc8, 0xf9, 0x17, 0x96, 0xde,
-	0x75, 0xb1, 0x45, 0xf4, 0x5f, 0x09, 0x58, 0xb0, 0x47, 0xd5, 0x84, 0x05, 0xff, 0xcf, 0x16, 0x72,
-	0x16, 0x6c, 0xc5, 0x7f, 0xc2, 0x82, 0xff, 0x37, 0x00, 0x09, 0x8b, 0x8f, 0xe0, 0x9a, 0xa8, 0xce,
-	0x1d, 0x7d, 0xb9, 0xa0, 0x08, 0x5e, 0xce, 0x52, 0x54, 0x2f, 0x9e, 0xb0, 0xcc, 0x2b, 0x26, 0x97,
-	0xb0, 0x7c, 0x18, 0x67, 0xe5, 0x7c, 0xc1, 0x2d, 0x7a, 0xad, 0xb0, 0x16, 0x5a, 0xce, 0x56, 0x5c,
-	0xc7, 0x9b, 0xb0, 0xcd, 0x2f, 0xf3, 0x95, 0xb0, 0xfd, 0x00, 0x96, 0x33, 0xa5, 0x42, 0x68, 0x4d,
-	0x56, 0x44, 0x24, 0x67, 0x96, 0xa9, 0x06, 0x48, 0x98, 0x09, 0xeb, 0x04, 0xe4, 0xcc, 0x32, 0x2f,
-	0x93, 0x09, 0x33, 0xe1, 0x9b, 0xa5, 0x84, 0xd9, 0x31, 0xa0, 0xec, 0x13, 0x09, 0x7a, 0x55, 0xfa,
-	0x7a, 0x22, 0x61, 0x77, 0x0f, 0x56, 0x04, 0xf7, 0xa8, 0x68, 0x5d, 0x7e, 0xc7, 0x5a, 0x46, 0x0d,
-	0xcc, 0x5d, 0x10, 0xa7, 0x06, 0xee, 0x96, 0x48, 0xce, 0x2c, 0x73, 0x33, 0x96, 0x30, 0x13, 0xde,
-	0x99, 0x95, 0xd1, 0xa9, 0x88, 0x99, 0xf0, 0xfe, 0x4a, 0xc2, 0x6c, 0x1f, 0x96, 0xd2, 0xa1, 0x13,
-	0x7d, 0x29, 0x5e, 0x63, 0x26, 0xa2, 0x4a, 0xd8, 0xbc, 0x0f, 0x30, 0xd9, 0x26, 0xd0, 0x6a, 0x32,
-	0xae, 0xe4, 0xe7, 0xef, 0x42, 0xe3, 0x00, 0xfb, 0x0f, 0x5d, 0xdb, 0x43, 0x71, 0xd5, 0x70, 0x1c,
-	0x3e, 0xf3, 0xbf, 0xda, 0xfe, 0xb7, 0x0a, 0x8b, 0xc9, 0xd6, 0x4f, 0x03, 0xeb, 0x01, 0x74, 0xb8,
-	0x44, 0x09, 0xf5, 0xf2, 0x0f, 0x3a, 0x12, 0x81, 0x0e, 0xa0, 0xc3, 0xa5, 0x16, 0x09, 0x23, 0x41,
-	0x7a, 0x2e, 0x61, 0xf4, 0x03, 0xb8, 0x91, 0x93, 0xba, 0xa1, 0x7e, 0x71, 0x6a, 0x27, 0x67, 0x9c,
-	0x93, 0xfc, 0x24, 0x8c, 0x25, 0xc9, 0x91, 0xdc, 0x31, 0xb3, 0x87, 0xf2, 0xc4, 0x31, 0xc5, 0xe7,
-	0xf5, 0x02, 0x3f, 0xcf, 0x5c, 0xd5, 0x4c, 0xfc, 0x5c, 0x78, 0x8b, 0x23, 0xd1, 0xf9, 0x6f, 0x2a,
-	0x00, 0x61, 0x28, 0x8d, 0x77, 0x52, 0xf6, 0xea, 0x32, 0xd9, 0xc3, 0xf8, 0xfb, 0xcc, 0xa2, 0x9d,
-	0x54, 0xc0, 0x82, 0xbf, 0x13, 0xcc, 0x67, 0xf1, 0xa8, 0x4e, 0x3b, 0xbe, 0xf1, 0xbf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xd1, 0xdb, 0x5f, 0x2c, 0x2a, 0x3c, 0x00, 0x00,
+	// 2883 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5c, 0x4b, 0x6c, 0xdc, 0xc6,
+	0x19, 0xce, 0x92, 0xfb, 0xfc, 0x57, 0x2f, 0x8f, 0x2c, 0x9b, 0xd9, 0x28, 0xae, 0xb2, 0x4d, 0x03,
+	0x21, 0x71, 0x14, 0x57, 0x0d, 0x90, 0xa4, 0x45, 0xda, 0xc8, 0x5a, 0x7b, 0xa5, 0xc6, 0x8a, 0x15,
+	0xca, 0x4e, 0x80, 0xde, 0x68, 0x72, 0x6c, 0x11, 0xe6, 0x92, 0x1b, 0x92, 0x92, 0xad, 0x9e, 0x8a,
+	0xb6, 0x29, 0xd2, 0x17, 0x50, 0x14, 0x3d, 0x15, 0xe8, 0x25, 0x87, 0xde, 0x7a, 0xed, 0xa9, 0xed,
+	0x21, 0xe8, 0xb1, 0xa7, 0x1c, 0x8a, 0x9e, 0x8a, 0x5e, 0x0b, 0xb4, 0x87, 0x02, 0xbd, 0x14, 0xc8,
+	0xa1, 0xe0, 0xf0, 0xb1, 0x43, 0x72, 0x66, 0xc8, 0xd5, 0xae, 0x14, 0x07, 0xd2, 0x49, 0x3b, 0x0f,
+	0xfe, 0xf3, 0xff, 0xdf, 0xff, 0x9a, 0xa7, 0xa0, 0x3d, 0x70, 0x0c, 0x6c, 0xad, 0x0d, 0x5d, 0xc7,
+	0x77, 0x50, 0x8d, 0xfc, 0xe9, 0x7e, 0x5a, 0x83, 0x85, 0x4d, 0x17, 0x6b, 0x3e, 0x7e, 0xcf, 0xb1,
+	0x0e, 0x06, 0xf8, 0xf6, 0xd0, 0xf7, 0xd0, 0x1c, 0x48, 0xa6, 0xa1, 0x54, 0x56, 0x2a, 0xab, 0x2d,
+	0x55, 0x32, 0x0d, 0x84, 0xa0, 0x6a, 0x6b, 0x03, 0xac, 0x48, 0xa4, 0x86, 0xfc, 0x0e, 0xea, 0x3c,
+	0xf3, 0xbb, 0x58, 0x91, 0x57, 0x2a, 0xab, 0xb2, 0x4a, 0x7e, 0xa3, 0x15, 0x68, 0x1b, 0xd8, 0xd3,
+	0x5d, 0x73, 0xe8, 0x9b, 0x8e, 0xad, 0x54, 0x49, 0x77, 0xba, 0x0a, 0x5d, 0x01, 0xf0, 0x6c, 0x6d,
+	0xe8, 0xed, 0x3b, 0xfe, 0xb6, 0xa1, 0xd4, 0x48, 0x07, 0xaa, 0x06, 0xbd, 0x08, 0x0b, 0xda, 0xa1,
+	0x66, 0x5a, 0xda, 0x3d, 0xd3, 0x32, 0xfd, 0xa3, 0xef, 0x38, 0x36, 0x56, 0xea, 0xa4, 0x57, 0xae,
+	0x1e, 0x5d, 0x82, 0xfa, 0xd0, 0x71, 0xac, 0x6d, 0x43, 0x69, 0x92, 0x1e, 0x51, 0x09, 0x75, 0xa0,
+	0x19, 0xfc, 0x7a, 0x27, 0xe0, 0xb8, 0x45, 0x5a, 0x92, 0x32, 0xda, 0x80, 0xe6, 0x00, 0xfb, 0x9a,
+	0xa1, 0xf9, 0x9a, 0x02, 0x2b, 0xf2, 0x6a, 0x7b, 0xfd, 0x2b, 0x21, 0x1e, 0x6b, 0x59, 0x10, 0xd6,
+	0x76, 0xa2, 0x7e, 0x37, 0x6c, 0xdf, 0x3d, 0x52, 0x93, 0xcf, 0x02, 0x11, 0x0c, 0xd7, 0x3c, 0xc4,
+	0x2e, 0x19, 0xa0, 0x1d, 0x8a, 0x30, 0xaa, 0x41, 0x0a, 0x34, 0x74, 0xc7, 0xf6, 0xf1, 0x63, 0x5f,
+	0x99, 0x21, 0x8d, 0x71, 0x11, 0xed, 0xc3, 0x92, 0x8b, 0x87, 0x96, 0xa9, 0x6b, 0x01, 0x16, 0x3d,
+	0xf2, 0x49, 0x2f, 0xe0, 0x64, 0x96, 0x70, 0xb2, 0xce, 0xe3, 0x44, 0x65, 0x7d, 0x14, 0xb2, 0xc5,
+	0x26, 0x88, 0x9e, 0x87, 0x59, 0xaa, 0x61, 0xdb, 0x50, 0xe6, 0x08, 0x27, 0xe9, 0x4a, 0xd4, 0x85,
+	0x99, 0x18, 0xfa, 0xbd, 0x40, 0x95, 0xf3, 0x44, 0x95, 0xa9, 0x3a, 0x74, 0x15, 0x2e, 0xc4, 0xe5,
+	0x9b, 0xae, 0x33, 0xd8, 0xb4, 0x9c, 0x03, 0x43, 0x59, 0x58, 0xa9, 0xac, 0x36, 0xd5, 0x7c, 0x43,
+	0xe7, 0x1b, 0x30, 0x9b, 0x82, 0x0d, 0x2d, 0x80, 0xfc, 0x10, 0x1f, 0x45, 0xa6, 0x14, 0xfc, 0x44,
+	0x17, 0xa1, 0x76, 0xa8, 0x59, 0x07, 0xb1, 0x31, 0x85, 0x85, 0xaf, 0x4b, 0xaf, 0x57, 0x3a, 0x5b,
+	0xd0, 0xe1, 0x4b, 0x3a, 0x0e, 0xa5, 0xee, 0x7f, 0x2b, 0xb0, 0xd0, 0xc3, 0x16, 0x16, 0x1a, 0xf5,
+	0xc8, 0x7c, 0xe4, 0x94, 0xf9, 0xd0, 0x26, 0x52, 0x4d, 0x99, 0x48, 0x96, 0x64, 0x49, 0x13, 0xa9,
+	0x89, 0x4c, 0xa4, 0x9e, 0x32, 0x91, 0x89, 0x00, 0xec, 0xfe, 0x56, 0x86, 0x85, 0x1b, 0x8f, 0x7d,
+	0x6c, 0x1b, 0x67, 0xdc, 0x97, 0xb3, 0x20, 0x4c, 0xdf, 0x97, 0x27, 0x53, 0xd4, 0xa7, 0x12, 0x28,
+	0xb4, 0x97, 0xef, 0x45, 0xa0, 0x9d, 0xb0, 0xc2, 0x3a, 0xd0, 0x3c, 0x24, 0xe3, 0x25, 0xea, 0x4a,
+	0xca, 0x68, 0x9b, 0x02, 0xb3, 0x41, 0xc0, 0x7c, 0x99, 0x11, 0x8e, 0x68, 0x46, 0x4b, 0x82, 0xda,
+	0x14, 0x81, 0xda, 0x9a, 0x22, 0xa8, 0x1f, 0x49, 0xa0, 0xd0, 0x1e, 0x2a, 0x04, 0x95, 0x86, 0x42,
+	0x12, 0x40, 0x21, 0xa7, 0xa0, 0xe0, 0x91, 0x2f, 0x09, 0x45, 0x55, 0x04, 0x45, 0x6d, 0x8a, 0x50,
+	0xfc, 0x51, 0x86, 0x0e, 0xad, 0xb6, 0x0d, 0xdf, 0xd7, 0xf4, 0xfd, 0x01, 0xb6, 0xc7, 0x07, 0x83,
+	0x17, 0x25, 0x9f, 0x87, 0x59, 0xc3, 0xb9, 0xe5, 0xe8, 0x9a, 0x15, 0x12, 0x27, 0xc2, 0x35, 0xd5,
+	0x74, 0x25, 0x5a, 0x86, 0xd6, 0xe0, 0xc0, 0xf2, 0xcd, 0x5d, 0xcd, 0xdf, 0x27, 0x12, 0x36, 0xd5,
+	0x51, 0x05, 0x7a, 0x09, 0x9a, 0xfb, 0x8e, 0xe7, 0x6f, 0xdb, 0xf7, 0x1d, 0x12, 0x18, 0xda, 0xeb,
+	0xf3, 0x11, 0xd0, 0x5b, 0x51, 0xb5, 0x9a, 0x74, 0x40, 0x6f, 0xe7, 0x0c, 0xf4, 0x15, 0x86, 0x81,
+	0xa6, 0x25, 0x9d, 0xbe, 0x89, 0xa2, 0x17, 0x60, 0x6e, 0x43, 0xd7, 0xb1, 0xe7, 0xed, 0x06, 0x63,
+	0xeb, 0x8e, 0xa5, 0x00, 0xe9, 0x90, 0xa9, 0x9d, 0x4c, 0x7f, 0xff, 0x93, 0xa0, 0x43, 0xdb, 0xda,
+	0x09, 0xe8, 0x8f, 0xc6, 0xbe, 0x3a, 0x0e, 0xf6, 0xb5, 0x14, 0xf6, 0x7c, 0x2e, 0x4b, 0x62, 0x5f,
+	0x17, 0x61, 0xdf, 0x28, 0xc2, 0xbe, 0x39, 0x7d, 0xec, 0x7f, 0x27, 0xc3, 0x72, 0x68, 0x51, 0xb1,
+	0x87, 0x17, 0xa0, 0x9f, 0x4e, 0x83, 0x52, 0x2e, 0x0d, 0xe6, 0x3c, 0x45, 0x2e, 0xf4, 0x94, 0xaa,
+	0xc8, 0x53, 0x6a, 0x45, 0xda, 0xda, 0xa1, 0xb4, 0x55, 0x27, 0xda, 0xfa, 0x6a, 0xca, 0x53, 0xd8,
+	0x72, 0x95, 0xd4, 0x57, 0x43, 0xa4, 0xaf, 0x66, 0x91, 0xbe, 0x5a, 0xd3, 0xd7, 0xd7, 0x3f, 0x25,
+	0x58, 0x0e, 0xad, 0x70, 0x4a, 0xfa, 0xa2, 0xb1, 0x96, 0xc7, 0xc1, 0xba, 0x9a, 0xc2, 0x5a, 0xc4,
+	0xd3, 0xf4, 0x27, 0x8e, 0x0c, 0xac, 0x1b, 0xd3, 0xc7, 0xfa, 0x57, 0x15, 0x68, 0xc6, 0x20, 0x90,
+	0xa9, 0x99, 0xa5, 0xf9, 0xf7, 0x1d, 0x77, 0x10, 0x7d, 0x9d, 0x94, 0x83, 0xa8, 0xe3, 0x78, 0x77,
+	0x8e, 0x86, 0x31, 0x8d, 0xa8, 0x14, 0xcc, 0x5b, 0x02, 0xe8, 0xa2, 0x58, 0x44, 0x7e, 0x13, 0xfd,
+	0x0c, 0xa3, 0xdc, 0x28, 0x99, 0x43, 0x74, 0x0d, 0xc0, 0xb4, 0x4d, 0xdf, 0xd4, 0x7c, 0xc7, 0xf5,
+	0xa2, 0x70, 0xb3, 0x10, 0x81, 0xba, 0x1d, 0x37, 0xa8, 0x54, 0x9f, 0xee, 0x26, 0xb4, 0x92, 0x06,
+	0xc2, 0x96, 0xe3, 0xfa, 0x04, 0xc0, 0x98, 0xad, 0xa8, 0x4c, 0xda, 0x62, 0x78, 0xa2, 0x40, 0x19,
+	0x97, 0xbb, 0x87, 0x00, 0x61, 0x18, 0x23, 0x0b, 0xa8, 0x57, 0xa0, 0x4a, 0x74, 0x5a, 0x21, 0xc3,
+	0x3f, 0x13, 0x0d, 0x3f, 0xea, 0xb0, 0x36, 0x5a, 0x82, 0x91, 0x8e, 0x9d, 0xd7, 0xa0, 0x75, 0xbc,
+	0xb5, 0xca, 0x87, 0x2d, 0x58, 0x0a, 0xfd, 0x92, 0x5a, 0xfc, 0x94, 0x9e, 0x08, 0x66, 0x26, 0x7d,
+	0x72, 0x7e, 0xd2, 0xb7, 0x0a, 0xf3, 0x43, 0xd7, 0x1c, 0x68, 0xee, 0xd1, 0x7b, 0x71, 0x8e, 0x08,
+	0xb1, 0xce, 0x56, 0x93, 0xa5, 0x1e, 0xd6, 0x1d, 0xdb, 0xa0, 0xfb, 0x86, 0x36, 0x98, 0x6f, 0x38,
+	0xf1, 0xd9, 0xfd, 0xf7, 0x2b, 0xb0, 0x1c, 0x71, 0xc8, 0x5c, 0x15, 0x2a, 0x6d, 0xa2, 0x9a, 0x6f,
+	0xa6, 0x42, 0x5b, 0x06, 0xc2, 0xb5, 0x5d, 0x01, 0x81, 0x50, 0x7b, 0xc2, 0x31, 0xd0, 0x47, 0x15,
+	0xb8, 0x92, 0x88, 0xce, 0x66, 0x63, 0x86, 0xb0, 0xf1, 0x96, 0x90, 0x8d, 0x3d, 0x21, 0x89, 0x90,
+	0x91, 0x82, 0x71, 0x02, 0x0c, 0x0d, 0x47, 0x7f, 0xb8, 0x6d, 0x28, 0xb3, 0x21, 0x86, 0x61, 0x29,
+	0x13, 0x32, 0xe6, 0x44, 0x21, 0x63, 0x3e, 0x1d, 0x32, 0x96, 0xa1, 0x65, 0x7a, 0x11, 0x42, 0xd1,
+	0x92, 0x7e, 0x54, 0x81, 0x6e, 0x52, 0x91, 0xed, 0x02, 0x91, 0xf1, 0x45, 0xa1, 0x8c, 0xbc, 0x90,
+	0xf6, 0x06, 0xcc, 0x1d, 0x26, 0x6e, 0x73, 0xcb, 0xf4, 0x7c, 0x05, 0x11, 0x6a, 0x17, 0x72, 0x3e,
+	0xa5, 0x66, 0x3a, 0x06, 0xa6, 0x4b, 0x6d, 0x58, 0xec, 0x38, 0x06, 0x56, 0x16, 0x43, 0xd3, 0xcd,
+	0x54, 0x07, 0xa6, 0x4b, 0xf1, 0xb3, 0x8b, 0x5d, 0xd3, 0x31, 0x94, 0x8b, 0x64, 0x71, 0x94, 0x6f,
+	0x40, 0xeb, 0x70, 0x91, 0xaa, 0xbc, 0xae, 0xd9, 0xc6, 0x23, 0xd3, 0xf0, 0xf7, 0x95, 0x25, 0xf2,
+	0x01, 0xb3, 0xad, 0x73, 0x1b, 0x9e, 0x2b, 0x34, 0xa6, 0xb1, 0x76, 0x3b, 0xde, 0x85, 0x2f, 0x97,
+	0x30, 0x8b, 0xb1, 0x48, 0x4e, 0x14, 0xdb, 0xff, 0xd4, 0x80, 0xa5, 0x30, 0x67, 0x9d, 0xc7, 0xa1,
+	0x09, 0xe2, 0x10, 0x13, 0xc2, 0xd3, 0x8f, 0x43, 0x6c, 0x36, 0x9e, 0xcc, 0x38, 0x44, 0x47, 0x9a,
+	0x85, 0x54, 0xa4, 0x61, 0x4b, 0xc1, 0x8b, 0x34, 0xa9, 0x78, 0x76, 0x21, 0x13, 0xcf, 0xce, 0x86,
+	0x03, 0xdf, 0xb0, 0xb5, 0x7b, 0xd6, 0xb9, 0x03, 0x4f, 0xe2, 0xc0, 0x4c, 0x08, 0x4f, 0xdf, 0x81,
+	0xd9, 0x6c, 0x7c, 0xd1, 0x1c, 0x98, 0x2d, 0xc5, 0xb9, 0x03, 0x33, 0x1d, 0xf8, 0x93, 0x06, 0x5c,
+	0xea, 0x99, 0xde, 0xb9, 0x07, 0x67, 0x3d, 0xf8, 0x07, 0xe5, 0x3c, 0xf8, 0x5b, 0x71, 0xd6, 0x30,
+	0xbd, 0x93, 0x70, 0xe1, 0x1f, 0x97, 0x75, 0xe1, 0x0d, 0x31, 0x1f, 0x4f, 0xa6, 0x0f, 0xf7, 0x73,
+	0x3e, 0xfc, 0x92, 0x58, 0x8c, 0x73, 0x27, 0x66, 0x3a, 0xf1, 0x6f, 0x5a, 0x70, 0xf9, 0xa6, 0x66,
+	0x5a, 0xce, 0x21, 0x76, 0xcf, 0xbd, 0x98, 0xf6, 0xe2, 0x1f, 0x96, 0xf3, 0xe2, 0x38, 0x01, 0x72,
+	0x40, 0x9c, 0xd8, 0x8d, 0x7f, 0x52, 0xd6, 0x8d, 0xaf, 0x17, 0x30, 0xf2, 0x64, 0xfa, 0xf1, 0x35,
+	0x58, 0xd4, 0x2c, 0xcb, 0x79, 0x14, 0x6e, 0x38, 0xe2, 0xe8, 0x10, 0x34, 0x5a, 0xde, 0xb3, 0x9a,
+	0xd0, 0x1a, 0xa0, 0x84, 0xcb, 0xeb, 0x9a, 0xfe, 0x10, 0xdb, 0xc6, 0xb6, 0x41, 0x3c, 0xb7, 0xa5,
+	0x32, 0x5a, 0xd0, 0x16, 0x15, 0x29, 0xc2, 0xa5, 0xfc, 0xd5, 0x02, 0xa4, 0x4a, 0x85, 0x8a, 0xc5,
+	0xb3, 0x16, 0x2a, 0x3a, 0x1e, 0xcc, 0x8f, 0x10, 0xfb, 0xe0, 0x00, 0x7b, 0x5c, 0xed, 0x55, 0xc6,
+	0xd5, 0x9e, 0xc4, 0xd3, 0x5e, 0xf7, 0x2f, 0x75, 0x50, 0xfa, 0xd8, 0xa7, 0xe4, 0xdf, 0xf3, 0x35,
+	0xff, 0xc0, 0x63, 0x06, 0x28, 0x46, 0xa8, 0x91, 0xc6, 0x08, 0x35, 0x32, 0x2f, 0xd4, 0x8c, 0xc2,
+	0x47, 0x35, 0x15, 0x3e, 0x3e, 0x2c, 0x0a, 0x11, 0xb5, 0x54, 0x82, 0xe5, 0xc9, 0x31, 0x71, 0x8c,
+	0xf8, 0x69, 0x71, 0x8c, 0x08, 0x0f, 0x56, 0x36, 0x8b, 0x38, 0x99, 0x6e, 0x90, 0x68, 0x08, 0x82,
+	0xc4, 0x38, 0x87, 0x98, 0xdb, 0xb9, 0x9b, 0x13, 0x2f, 0x17, 0x09, 0x52, 0xca, 0x87, 0xdb, 0x67,
+	0x2e, 0xdd, 0xff, 0xbe, 0x01, 0x4f, 0xef, 0x3d, 0x32, 0x7d, 0x7d, 0xbf, 0x4c, 0xc2, 0x3f, 0x6d,
+	0x7f, 0xfa, 0x51, 0x39, 0x7f, 0x8a, 0x33, 0x1d, 0x57, 0x90, 0x89, 0x1d, 0xea, 0x67, 0x65, 0x1d,
+	0xaa, 0x57, 0xc8, 0xca, 0x93, 0xe9, 0x51, 0xdf, 0xce, 0x79, 0xd4, 0x5a, 0xa1, 0x24, 0xc7, 0x72,
+	0x29, 0x5e, 0x8a, 0x98, 0xe1, 0xa7, 0x88, 0x75, 0xb8, 0xe8, 0xb2, 0xb6, 0xbb, 0x67, 0xc3, 0xed,
+	0x6e, 0xf7, 0x4c, 0x6e, 0x77, 0x7f, 0xaf, 0x01, 0x8a, 0x8a, 0x03, 0x15, 0x61, 0xdd, 0x7f, 0xd2,
+	0xfc, 0x76, 0xcc, 0x3c, 0xc8, 0x93, 0xe3, 0xf4, 0xf3, 0x20, 0x97, 0x93, 0x2f, 0x5a, 0x1e, 0xe4,
+	0x0a, 0x72, 0x3c, 0xa7, 0xe5, 0xb9, 0xe0, 0xcc, 0x59, 0x76, 0xc1, 0x4f, 0xe4, 0xf8, 0xe4, 0x3b,
+	0x74, 0x88, 0xbe, 0xeb, 0x1c, 0x0c, 0x4b, 0x2f, 0x94, 0xd3, 0x5a, 0x97, 0x73, 0x5a, 0x2f, 0xbe,
+	0x0e, 0xc9, 0x5a, 0xf0, 0xd6, 0x38, 0x0b, 0xde, 0x2b, 0x00, 0x9a, 0x11, 0x05, 0x54, 0x8f, 0x18,
+	0x7d, 0x4b, 0xa5, 0x6a, 0xc2, 0x0b, 0xd7, 0x03, 0xe7, 0x10, 0xc7, 0x5d, 0x1a, 0xa4, 0x4b, 0xba,
+	0x92, 0xbb, 0x6c, 0xe6, 0x5b, 0x28, 0xbd, 0xa0, 0x86, 0xcc, 0x82, 0x9a, 0xde, 0x76, 0x6d, 0x33,
+	0x4e, 0x68, 0x33, 0xa8, 0xf2, 0x4c, 0x77, 0x32, 0x25, 0xfe, 0x43, 0x82, 0xa5, 0xbb, 0x43, 0xa3,
+	0x84, 0x12, 0xd3, 0x0a, 0x93, 0x72, 0x0a, 0x4b, 0x43, 0x2c, 0x17, 0x43, 0x5c, 0x15, 0x43, 0x5c,
+	0xe3, 0x41, 0x5c, 0xe7, 0x43, 0xdc, 0x10, 0x40, 0xdc, 0x4c, 0x41, 0xcc, 0x94, 0xf9, 0x64, 0x20,
+	0xfe, 0xb5, 0x14, 0x9f, 0xcc, 0x16, 0x41, 0x3c, 0x12, 0x5e, 0x4a, 0x09, 0x5f, 0xe4, 0x2b, 0x14,
+	0x38, 0x55, 0x3e, 0x38, 0x35, 0x01, 0x38, 0x75, 0xc6, 0xb9, 0xdd, 0xa9, 0x80, 0xf3, 0xb7, 0x0a,
+	0x2c, 0x92, 0x21, 0xe2, 0x9b, 0x56, 0x3b, 0x78, 0x70, 0x0f, 0xbb, 0x63, 0xdd, 0x91, 0x64, 0xdd,
+	0xa6, 0xee, 0xe5, 0x2e, 0x76, 0xad, 0xc6, 0x4b, 0xa4, 0xfc, 0x68, 0x27, 0x23, 0xda, 0x1f, 0x64,
+	0xb8, 0x1c, 0x7a, 0x72, 0x6a, 0xc8, 0x29, 0x6e, 0x25, 0x2a, 0xd0, 0x78, 0x10, 0x90, 0x4e, 0x26,
+	0x20, 0x71, 0x11, 0xbd, 0x0e, 0xad, 0xf8, 0x4a, 0x5c, 0x7c, 0x07, 0xab, 0xc3, 0x97, 0x5f, 0x1d,
+	0x75, 0xa6, 0x6c, 0xb0, 0xce, 0xdd, 0x1a, 0xcc, 0xba, 0xd9, 0xfb, 0x30, 0x4b, 0x06, 0xde, 0x49,
+	0xfb, 0x5a, 0xfa, 0xda, 0x62, 0x0e, 0x84, 0xb5, 0x3e, 0xfd, 0x4d, 0x08, 0x7d, 0x9a, 0x4e, 0xc6,
+	0xf0, 0x5b, 0x22, 0xc3, 0x87, 0xf4, 0xfd, 0xeb, 0xb7, 0x00, 0xe5, 0xc9, 0x8f, 0xa5, 0xbe, 0x9f,
+	0x57, 0xe1, 0x72, 0xe8, 0x08, 0xc5, 0xea, 0xa3, 0x14, 0x21, 0x09, 0x14, 0x21, 0x1f, 0x4f, 0x11,
+	0x55, 0xae, 0x22, 0x6a, 0x45, 0x8a, 0xa8, 0x33, 0xee, 0x34, 0x1e, 0x47, 0x11, 0x5b, 0xb9, 0xdb,
+	0xdb, 0x57, 0x0b, 0x68, 0x4e, 0xff, 0x75, 0xc1, 0xc4, 0x2a, 0x9d, 0xcc, 0x9d, 0x3f, 0xab, 0xc0,
+	0x42, 0xb8, 0x32, 0xa3, 0x5e, 0xe7, 0xbc, 0x00, 0x73, 0x5a, 0xfa, 0xda, 0x66, 0x48, 0x2b, 0x53,
+	0x1b, 0xf4, 0xd3, 0x1d, 0xdb, 0xc6, 0x3a, 0x99, 0xb1, 0x05, 0x28, 0x86, 0xf4, 0x33, 0xb5, 0xa9,
+	0x37, 0x31, 0x72, 0xea, 0x4d, 0x4c, 0x76, 0x68, 0x2e, 0xc0, 0xdc, 0x64, 0x30, 0xb9, 0xf8, 0x3d,
+	0xfc, 0xb9, 0x89, 0xdf, 0xc3, 0x9f, 0xaf, 0xf8, 0x7f, 0x96, 0xe1, 0x52, 0x68, 0xea, 0x37, 0x4d,
+	0x0b, 0xef, 0xed, 0x6b, 0x2e, 0xde, 0xd0, 0x2d, 0x66, 0x30, 0x58, 0x81, 0xf6, 0x7d, 0xd3, 0xc2,
+	0x5e, 0xd0, 0x27, 0x09, 0x08, 0x74, 0x55, 0x89, 0xc8, 0x8e, 0xa0, 0xea, 0x07, 0xd7, 0x6f, 0x43,
+	0x11, 0xc8, 0x6f, 0x32, 0xdf, 0x25, 0xb0, 0x6e, 0x6a, 0xc3, 0x68, 0x6e, 0x4b, 0x42, 0x7b, 0x4b,
+	0xcd, 0xd5, 0x07, 0x41, 0x22, 0xac, 0xbb, 0xe3, 0x44, 0x71, 0x3c, 0x29, 0x4f, 0xb0, 0xde, 0x42,
+	0x50, 0xa5, 0x66, 0xb2, 0xe4, 0x37, 0xea, 0xe7, 0x66, 0xb1, 0x2f, 0xa5, 0x22, 0x43, 0x16, 0x2e,
+	0xae, 0xe2, 0xf2, 0x37, 0xa0, 0x67, 0xa6, 0x7f, 0x03, 0xfa, 0xaf, 0x55, 0xb8, 0x14, 0xa6, 0xa3,
+	0x73, 0x35, 0x96, 0x54, 0x23, 0x1b, 0xae, 0x49, 0xd5, 0x18, 0xa4, 0x37, 0xef, 0x83, 0x03, 0xcd,
+	0xdb, 0x8f, 0x8f, 0xce, 0xc2, 0x52, 0xc0, 0xb6, 0x66, 0x3b, 0xf6, 0x5d, 0x33, 0x7c, 0xf4, 0x2a,
+	0xab, 0x71, 0x31, 0x6e, 0xe9, 0x9b, 0x46, 0xf4, 0xd2, 0x35, 0x2e, 0x06, 0xfb, 0x3b, 0x1e, 0xd6,
+	0x0f, 0x5c, 0xd3, 0x3f, 0xba, 0x69, 0x69, 0x87, 0x8e, 0xeb, 0x91, 0x33, 0xf0, 0x96, 0x9a, 0xad,
+	0x0e, 0x00, 0xf5, 0x8e, 0x6c, 0x9d, 0xdc, 0x45, 0x0d, 0x8f, 0xc8, 0x92, 0xf2, 0x64, 0x86, 0xf5,
+	0x71, 0x15, 0x16, 0x33, 0x48, 0x9d, 0xf0, 0x6b, 0xc0, 0x71, 0x96, 0xbf, 0xc7, 0x99, 0xd4, 0xf5,
+	0x72, 0x6b, 0xa7, 0x55, 0xb6, 0x45, 0x8c, 0x91, 0xee, 0xc7, 0x98, 0xc1, 0x05, 0x4a, 0xc5, 0x8f,
+	0x87, 0x8e, 0xeb, 0x07, 0xcf, 0x6c, 0x02, 0x89, 0x3d, 0x72, 0xb0, 0xdb, 0x52, 0xb3, 0xd5, 0x99,
+	0x17, 0x21, 0xb3, 0xb9, 0x17, 0x21, 0xd4, 0x3b, 0x69, 0xea, 0x3c, 0x36, 0x55, 0xc7, 0x30, 0xdb,
+	0xf9, 0xe9, 0x47, 0x9f, 0xcf, 0x24, 0x58, 0xcc, 0x44, 0xc5, 0xb1, 0xd6, 0x81, 0xbd, 0x5c, 0x7a,
+	0x5c, 0x65, 0xc7, 0xda, 0x93, 0x79, 0xd4, 0x98, 0x18, 0x6f, 0x83, 0x32, 0xde, 0xc8, 0x84, 0xec,
+	0x51, 0x10, 0x4a, 0xca, 0x2c, 0x15, 0xb6, 0xd8, 0x2a, 0x3c, 0x95, 0x67, 0x79, 0x7f, 0x97, 0xe0,
+	0x99, 0x8c, 0xed, 0x9e, 0xd2, 0xcb, 0xdd, 0x4c, 0x2e, 0xa9, 0xe5, 0x73, 0xc9, 0xf1, 0x5f, 0xd5,
+	0xdd, 0xa2, 0x8c, 0xa2, 0x45, 0x8c, 0xe2, 0x1a, 0xdb, 0x4f, 0xcb, 0x4c, 0xcf, 0x27, 0x7c, 0x5f,
+	0x24, 0xc1, 0x33, 0x19, 0x4b, 0x14, 0x02, 0x5c, 0x9c, 0x62, 0x8f, 0xbf, 0xf3, 0x71, 0x2b, 0xb7,
+	0xbb, 0x71, 0x8d, 0xed, 0x2b, 0x27, 0x0f, 0xcb, 0xbf, 0x2a, 0x30, 0xdf, 0xc7, 0x36, 0x76, 0x4d,
+	0x5d, 0xc5, 0xde, 0xd0, 0xb1, 0x3d, 0x8c, 0x5e, 0x83, 0xba, 0x8b, 0xbd, 0x03, 0xcb, 0x27, 0x24,
+	0xda, 0xeb, 0xcf, 0x26, 0x07, 0xb8, 0xa9, 0x7e, 0x6b, 0x2a, 0xe9, 0xb4, 0xf5, 0x94, 0x1a, 0x75,
+	0x47, 0xaf, 0x42, 0x0d, 0xbb, 0xae, 0xe3, 0x92, 0x61, 0xda, 0xeb, 0xcb, 0x9c, 0xef, 0x6e, 0x04,
+	0x7d, 0xb6, 0x9e, 0x52, 0xc3, 0xce, 0x9d, 0x2e, 0xd4, 0x43, 0x4a, 0x01, 0x62, 0x03, 0xec, 0x79,
+	0xda, 0x83, 0xf8, 0x79, 0x55, 0x5c, 0xec, 0xbc, 0x09, 0x35, 0xf2, 0x55, 0x60, 0xe3, 0xba, 0x63,
+	0xc4, 0xed, 0xe4, 0x77, 0xd6, 0xc6, 0xa5, 0x9c, 0x8d, 0x5f, 0x6f, 0x40, 0xcd, 0xc5, 0x43, 0xeb,
+	0xa8, 0xfb, 0x71, 0x05, 0xe6, 0xfa, 0xd8, 0xdf, 0xc1, 0xbe, 0x6b, 0xea, 0xe1, 0xc5, 0x84, 0x2b,
+	0xc1, 0x9b, 0x30, 0xcf, 0xd7, 0x6c, 0x3d, 0xd0, 0x73, 0x48, 0x97, 0xaa, 0x09, 0xda, 0x07, 0xa4,
+	0x3b, 0xbd, 0xb7, 0x38, 0xaa, 0x09, 0x76, 0xe7, 0x3d, 0x5f, 0x73, 0xfd, 0x3b, 0x66, 0x62, 0x05,
+	0xa3, 0x8a, 0x40, 0x24, 0x6c, 0x1b, 0xa4, 0x2d, 0x32, 0x82, 0xa8, 0xc8, 0x0f, 0x65, 0xdd, 0x5f,
+	0x54, 0x00, 0x6d, 0x3a, 0x96, 0x85, 0xf5, 0xb1, 0x18, 0x5d, 0x81, 0xf6, 0x88, 0x2d, 0x4f, 0x91,
+	0x48, 0x34, 0xa3, 0xab, 0xe8, 0x21, 0xe5, 0xb4, 0x45, 0x16, 0xc4, 0xdd, 0x2e, 0x40, 0xf3, 0x1d,
+	0x67, 0x57, 0x73, 0xb5, 0x81, 0xb7, 0xfe, 0xef, 0x39, 0x98, 0xdd, 0x75, 0x9d, 0x43, 0xd3, 0x0b,
+	0x96, 0x46, 0x8e, 0xfe, 0x10, 0x6d, 0xc0, 0x0c, 0xbd, 0x2d, 0x8c, 0x2e, 0x73, 0xfe, 0xdb, 0x48,
+	0xe7, 0x12, 0xdb, 0x22, 0xba, 0x4f, 0x05, 0x24, 0xe8, 0x9d, 0xbd, 0x84, 0x44, 0xf6, 0xff, 0x62,
+	0x88, 0x49, 0xd0, 0xff, 0x9c, 0x21, 0x21, 0x91, 0xfd, 0x8f, 0x0d, 0x02, 0x12, 0xef, 0xc2, 0x45,
+	0xd6, 0xbf, 0x24, 0x40, 0x5f, 0x2a, 0xf8, 0x7f, 0x05, 0x62, 0x92, 0xac, 0xa7, 0xfd, 0x09, 0x49,
+	0xde, 0xbb, 0x7f, 0x01, 0xc9, 0xbb, 0xf1, 0x3a, 0x21, 0xfb, 0x36, 0x1a, 0x3d, 0x57, 0xf8, 0x6c,
+	0x5d, 0x4c, 0x96, 0xfd, 0xe4, 0x3a, 0x21, 0xcb, 0x7f, 0x91, 0x2d, 0x20, 0xfb, 0x36, 0x5c, 0xc8,
+	0xbd, 0xea, 0x42, 0xcb, 0xa2, 0xf7, 0x5e, 0x62, 0x62, 0xb9, 0x87, 0x1b, 0x09, 0x31, 0xe6, 0x93,
+	0x0e, 0x31, 0xb1, 0xdc, 0x25, 0xf2, 0x84, 0x18, 0xf3, 0x7a, 0xb9, 0x80, 0xd8, 0x0e, 0xa0, 0xfc,
+	0x6d, 0x56, 0xf4, 0xac, 0xf0, 0xa2, 0xab, 0x80, 0xdc, 0x6d, 0x58, 0x64, 0x5c, 0x79, 0x43, 0x57,
+	0xc4, 0xd7, 0xe1, 0x04, 0x04, 0xf7, 0x60, 0x89, 0x79, 0x5d, 0x00, 0xad, 0x14, 0x5d, 0x26, 0x10,
+	0x1b, 0x37, 0xeb, 0x34, 0x33, 0x31, 0x6e, 0xde, 0x51, 0xa7, 0x98, 0x24, 0xeb, 0xa2, 0x50, 0x42,
+	0x92, 0x77, 0x8b, 0xa8, 0x8c, 0x05, 0x52, 0xa7, 0x06, 0x19, 0x0b, 0xcc, 0x9c, 0x27, 0x88, 0x89,
+	0xe5, 0xce, 0x67, 0x12, 0x62, 0xcc, 0x93, 0x9b, 0x32, 0xe6, 0xcc, 0x22, 0xc6, 0x3c, 0xe9, 0x10,
+	0x9b, 0x0c, 0x63, 0x37, 0x3b, 0x31, 0x19, 0xce, 0x4e, 0xb7, 0x98, 0x20, 0x63, 0x07, 0x35, 0x21,
+	0xc8, 0xd9, 0x5d, 0x15, 0x10, 0xbc, 0x01, 0x73, 0xe9, 0xbc, 0x86, 0x9e, 0x8e, 0x99, 0xcb, 0xa5,
+	0x3b, 0x01, 0x99, 0x37, 0x01, 0x46, 0x39, 0x1c, 0x2d, 0x25, 0xfd, 0x4a, 0x7e, 0xfe, 0x2a, 0x34,
+	0xfa, 0xd8, 0xbf, 0xeb, 0x5a, 0x1e, 0x8a, 0x5f, 0xdf, 0xc7, 0xb9, 0x4d, 0x38, 0xe8, 0x82, 0x8a,
+	0xef, 0xbb, 0xd8, 0xdb, 0xef, 0x99, 0x9e, 0x1e, 0xb8, 0xc9, 0xd1, 0x38, 0x9f, 0xbf, 0x01, 0x33,
+	0x2a, 0xb6, 0x1c, 0xcd, 0xd8, 0x74, 0xec, 0xfb, 0xe6, 0x83, 0x31, 0x3e, 0x5d, 0xff, 0x8f, 0x0c,
+	0xb3, 0xc9, 0x8c, 0x90, 0xe4, 0xdb, 0x3e, 0xcc, 0x67, 0xe6, 0xcf, 0xa8, 0xc3, 0x5f, 0xff, 0x0a,
+	0xb8, 0xea, 0xc3, 0x7c, 0x66, 0xc6, 0x99, 0x10, 0x62, 0xac, 0xda, 0x04, 0x84, 0xde, 0x8f, 0x8f,
+	0x93, 0x72, 0x53, 0x57, 0xd4, 0x2d, 0x9e, 0xf1, 0x8b, 0x09, 0x73, 0xe6, 0xc4, 0x09, 0x61, 0xc1,
+	0x9c, 0x59, 0x1c, 0xaf, 0xf3, 0xbb, 0x47, 0x49, 0xbc, 0x66, 0x6f, 0x2c, 0x15, 0x84, 0xff, 0xdc,
+	0x9e, 0xe2, 0x28, 0xfc, 0x33, 0xb7, 0x1b, 0x05, 0x3a, 0xff, 0x65, 0x05, 0x20, 0xcc, 0xb0, 0xf1,
+	0x04, 0x8b, 0xde, 0x63, 0x4f, 0xa6, 0x36, 0xd9, 0x8d, 0xf7, 0xa2, 0x09, 0x16, 0x83, 0x44, 0x0f,
+	0x97, 0x25, 0x71, 0xaf, 0x4e, 0x1a, 0xbe, 0xf6, 0xff, 0x01, 0x00, 0xc7, 0x4c, 0x88, 0x9d, 0xec,
+	0x4f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConnInterface
+var _ grpc.ClientConn
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+const _ = grpc.SupportPackageIsVersion4
 
 // ProvisionDockClient is the client API for ProvisionDock service.
 //
@@ -3859,25 +4744,39 @@ type ProvisionDockClient interface {
 	DisableReplication(ctx context.Context, in *DisableReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Failover a replication
 	FailoverReplication(ctx context.Context, in *FailoverReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Switch over the roles of a replication without data loss
+	SwitchoverReplication(ctx context.Context, in *SwitchoverReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Reprotect a replication after it is failed over
+	ReprotectReplication(ctx context.Context, in *ReprotectReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Get the status of a replication on the backend
+	GetReplicationStatus(ctx context.Context, in *GetReplicationStatusOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Create a volume group
 	CreateVolumeGroup(ctx context.Context, in *CreateVolumeGroupOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Update volume group
 	UpdateVolumeGroup(ctx context.Context, in *UpdateVolumeGroupOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Delete volume group
 	DeleteVolumeGroup(ctx context.Context, in *DeleteVolumeGroupOpts, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Create a crash-consistent snapshot of all the volumes in a group
+	CreateGroupSnapshot(ctx context.Context, in *CreateGroupSnapshotOpts, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Delete a group snapshot
+	DeleteGroupSnapshot(ctx context.Context, in *DeleteGroupSnapshotOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Collect metrics from metrics driver
 	CollectMetrics(ctx context.Context, in *CollectMetricsOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Get metrics from Prometheus
 	GetMetrics(ctx context.Context, in *GetMetricsOpts, opts ...grpc.CallOption) (*GenericResponse, error)
 	// Get 3rd party re-direct URLs for telemetry
 	GetUrls(ctx context.Context, in *NoParams, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Trigger an immediate discovery of the backends
+	RefreshDiscovery(ctx context.Context, in *NoParams, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Reload the backends from the config file and discover them
+	ReloadConfig(ctx context.Context, in *NoParams, opts ...grpc.CallOption) (*GenericResponse, error)
 }
 
 type provisionDockClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewProvisionDockClient(cc grpc.ClientConnInterface) ProvisionDockClient {
+func NewProvisionDockClient(cc *grpc.ClientConn) ProvisionDockClient {
 	return &provisionDockClient{cc}
 }
 
@@ -3989,6 +4888,33 @@ func (c *provisionDockClient) FailoverReplication(ctx context.Context, in *Failo
 	return out, nil
 }
 
+func (c *provisionDockClient) SwitchoverReplication(ctx context.Context, in *SwitchoverReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/SwitchoverReplication", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisionDockClient) ReprotectReplication(ctx context.Context, in *ReprotectReplicationOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/ReprotectReplication", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisionDockClient) GetReplicationStatus(ctx context.Context, in *GetReplicationStatusOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/GetReplicationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *provisionDockClient) CreateVolumeGroup(ctx context.Context, in *CreateVolumeGroupOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
 	out := new(GenericResponse)
 	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/CreateVolumeGroup", in, out, opts...)
@@ -4016,6 +4942,24 @@ func (c *provisionDockClient) DeleteVolumeGroup(ctx context.Context, in *DeleteV
 	return out, nil
 }
 
+func (c *provisionDockClient) CreateGroupSnapshot(ctx context.Context, in *CreateGroupSnapshotOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/CreateGroupSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisionDockClient) DeleteGroupSnapshot(ctx context.Context, in *DeleteGroupSnapshotOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/DeleteGroupSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *provisionDockClient) CollectMetrics(ctx context.Context, in *CollectMetricsOpts, opts ...grpc.CallOption) (*GenericResponse, error) {
 	out := new(GenericResponse)
 	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/CollectMetrics", in, out, opts...)
@@ -4043,6 +4987,24 @@ func (c *provisionDockClient) GetUrls(ctx context.Context, in *NoParams, opts ..
 	return out, nil
 }
 
+func (c *provisionDockClient) RefreshDiscovery(ctx context.Context, in *NoParams, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/RefreshDiscovery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisionDockClient) ReloadConfig(ctx context.Context, in *NoParams, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/proto.ProvisionDock/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProvisionDockServer is the server API for ProvisionDock service.
 type ProvisionDockServer interface {
 	// Create a volume
@@ -4069,18 +5031,32 @@ type ProvisionDockServer interface {
 	DisableReplication(context.Context, *DisableReplicationOpts) (*GenericResponse, error)
 	// Failover a replication
 	FailoverReplication(context.Context, *FailoverReplicationOpts) (*GenericResponse, error)
+	// Switch over the roles of a replication without data loss
+	SwitchoverReplication(context.Context, *SwitchoverReplicationOpts) (*GenericResponse, error)
+	// Reprotect a replication after it is failed over
+	ReprotectReplication(context.Context, *ReprotectReplicationOpts) (*GenericResponse, error)
+	// Get the status of a replication on the backend
+	GetReplicationStatus(context.Context, *GetReplicationStatusOpts) (*GenericResponse, error)
 	// Create a volume group
 	CreateVolumeGroup(context.Context, *CreateVolumeGroupOpts) (*GenericResponse, error)
 	// Update volume group
 	UpdateVolumeGroup(context.Context, *UpdateVolumeGroupOpts) (*GenericResponse, error)
 	// Delete volume group
 	DeleteVolumeGroup(context.Context, *DeleteVolumeGroupOpts) (*GenericResponse, error)
+	// Create a crash-consistent snapshot of all the volumes in a group
+	CreateGroupSnapshot(context.Context, *CreateGroupSnapshotOpts) (*GenericResponse, error)
+	// Delete a group snapshot
+	DeleteGroupSnapshot(context.Context, *DeleteGroupSnapshotOpts) (*GenericResponse, error)
 	// Collect metrics from metrics driver
 	CollectMetrics(context.Context, *CollectMetricsOpts) (*GenericResponse, error)
 	// Get metrics from Prometheus
 	GetMetrics(context.Context, *GetMetricsOpts) (*GenericResponse, error)
 	// Get 3rd party re-direct URLs for telemetry
 	GetUrls(context.Context, *NoParams) (*GenericResponse, error)
+	// Trigger an immediate discovery of the backends
+	RefreshDiscovery(context.Context, *NoParams) (*GenericResponse, error)
+	// Reload the backends from the config file and discover them
+	ReloadConfig(context.Context, *NoParams) (*GenericResponse, error)
 }
 
 // UnimplementedProvisionDockServer can be embedded to have forward compatible implementations.
@@ -4123,6 +5099,15 @@ func (*UnimplementedProvisionDockServer) DisableReplication(ctx context.Context,
 func (*UnimplementedProvisionDockServer) FailoverReplication(ctx context.Context, req *FailoverReplicationOpts) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FailoverReplication not implemented")
 }
+func (*UnimplementedProvisionDockServer) SwitchoverReplication(ctx context.Context, req *SwitchoverReplicationOpts) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwitchoverReplication not implemented")
+}
+func (*UnimplementedProvisionDockServer) ReprotectReplication(ctx context.Context, req *ReprotectReplicationOpts) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReprotectReplication not implemented")
+}
+func (*UnimplementedProvisionDockServer) GetReplicationStatus(ctx context.Context, req *GetReplicationStatusOpts) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReplicationStatus not implemented")
+}
 func (*UnimplementedProvisionDockServer) CreateVolumeGroup(ctx context.Context, req *CreateVolumeGroupOpts) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateVolumeGroup not implemented")
 }
@@ -4132,6 +5117,12 @@ func (*UnimplementedProvisionDockServer) UpdateVolumeGroup(ctx context.Context,
 func (*UnimplementedProvisionDockServer) DeleteVolumeGroup(ctx context.Context, req *DeleteVolumeGroupOpts) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteVolumeGroup not implemented")
 }
+func (*UnimplementedProvisionDockServer) CreateGroupSnapshot(ctx context.Context, req *CreateGroupSnapshotOpts) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroupSnapshot not implemented")
+}
+func (*UnimplementedProvisionDockServer) DeleteGroupSnapshot(ctx context.Context, req *DeleteGroupSnapshotOpts) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroupSnapshot not implemented")
+}
 func (*UnimplementedProvisionDockServer) CollectMetrics(ctx context.Context, req *CollectMetricsOpts) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CollectMetrics not implemented")
 }
@@ -4141,6 +5132,12 @@ func (*UnimplementedProvisionDockServer) GetMetrics(ctx context.Context, req *Ge
 func (*UnimplementedProvisionDockServer) GetUrls(ctx context.Context, req *NoParams) (*GenericResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUrls not implemented")
 }
+func (*UnimplementedProvisionDockServer) RefreshDiscovery(ctx context.Context, req *NoParams) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshDiscovery not implemented")
+}
+func (*UnimplementedProvisionDockServer) ReloadConfig(ctx context.Context, req *NoParams) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
 
 func RegisterProvisionDockServer(s *grpc.Server, srv ProvisionDockServer) {
 	s.RegisterService(&_ProvisionDock_serviceDesc, srv)
@@ -4362,6 +5359,60 @@ func _ProvisionDock_FailoverReplication_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProvisionDock_SwitchoverReplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwitchoverReplicationOpts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).SwitchoverReplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/SwitchoverReplication",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).SwitchoverReplication(ctx, req.(*SwitchoverReplicationOpts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisionDock_ReprotectReplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReprotectReplicationOpts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).ReprotectReplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/ReprotectReplication",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).ReprotectReplication(ctx, req.(*ReprotectReplicationOpts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisionDock_GetReplicationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReplicationStatusOpts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).GetReplicationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/GetReplicationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).GetReplicationStatus(ctx, req.(*GetReplicationStatusOpts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProvisionDock_CreateVolumeGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateVolumeGroupOpts)
 	if err := dec(in); err != nil {
@@ -4416,6 +5467,42 @@ func _ProvisionDock_DeleteVolumeGroup_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProvisionDock_CreateGroupSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupSnapshotOpts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).CreateGroupSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/CreateGroupSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).CreateGroupSnapshot(ctx, req.(*CreateGroupSnapshotOpts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisionDock_DeleteGroupSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupSnapshotOpts)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).DeleteGroupSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/DeleteGroupSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).DeleteGroupSnapshot(ctx, req.(*DeleteGroupSnapshotOpts))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProvisionDock_CollectMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CollectMetricsOpts)
 	if err := dec(in); err != nil {
@@ -4470,6 +5557,42 @@ func _ProvisionDock_GetUrls_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProvisionDock_RefreshDiscovery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NoParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).RefreshDiscovery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/RefreshDiscovery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).RefreshDiscovery(ctx, req.(*NoParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisionDock_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NoParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisionDockServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ProvisionDock/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisionDockServer).ReloadConfig(ctx, req.(*NoParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ProvisionDock_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.ProvisionDock",
 	HandlerType: (*ProvisionDockServer)(nil),
@@ -4522,6 +5645,18 @@ var _ProvisionDock_serviceDesc = grpc.ServiceDesc{
 			MethodName: "FailoverReplication",
 			Handler:    _ProvisionDock_FailoverReplication_Handler,
 		},
+		{
+			MethodName: "SwitchoverReplication",
+			Handler:    _ProvisionDock_SwitchoverReplication_Handler,
+		},
+		{
+			MethodName: "ReprotectReplication",
+			Handler:    _ProvisionDock_ReprotectReplication_Handler,
+		},
+		{
+			MethodName: "GetReplicationStatus",
+			Handler:    _ProvisionDock_GetReplicationStatus_Handler,
+		},
 		{
 			MethodName: "CreateVolumeGroup",
 			Handler:    _ProvisionDock_CreateVolumeGroup_Handler,
@@ -4534,6 +5669,14 @@ var _ProvisionDock_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteVolumeGroup",
 			Handler:    _ProvisionDock_DeleteVolumeGroup_Handler,
 		},
+		{
+			MethodName: "CreateGroupSnapshot",
+			Handler:    _ProvisionDock_CreateGroupSnapshot_Handler,
+		},
+		{
+			MethodName: "DeleteGroupSnapshot",
+			Handler:    _ProvisionDock_DeleteGroupSnapshot_Handler,
+		},
 		{
 			MethodName: "CollectMetrics",
 			Handler:    _ProvisionDock_CollectMetrics_Handler,
@@ -4546,6 +5689,14 @@ var _ProvisionDock_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetUrls",
 			Handler:    _ProvisionDock_GetUrls_Handler,
 		},
+		{
+			MethodName: "RefreshDiscovery",
+			Handler:    _ProvisionDock_RefreshDiscovery_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _ProvisionDock_ReloadConfig_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "model.proto",
@@ -4570,10 +5721,10 @@ type FileShareDockClient interface {
 }
 
 type fileShareDockClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewFileShareDockClient(cc grpc.ClientConnInterface) FileShareDockClient {
+func NewFileShareDockClient(cc *grpc.ClientConn) FileShareDockClient {
 	return &fileShareDockClient{cc}
 }
 
@@ -4826,10 +5977,10 @@ type AttachDockClient interface {
 }
 
 type attachDockClient struct {
-	cc grpc.ClientConnInterface
+	cc *grpc.ClientConn
 }
 
-func NewAttachDockClient(cc grpc.ClientConnInterface) AttachDockClient {
+func NewAttachDockClient(cc *grpc.ClientConn) AttachDockClient {
 	return &attachDockClient{cc}
 }
 
@@ -79,9 +79,43 @@ type ReplicationSpec struct {
 	Metadata map[string]string `json:"metadata,omitempty"`
 	// volume data list
 	VolumeDataList []*proto.VolumeData `json:"volumeDataList,omitempty"`
+	// The state of the replication pair on the backend, which is refreshed
+	// by the dock of the primary pool periodically.
+	PairStatus *ReplicationPairStatus `json:"pairStatus,omitempty"`
 }
 
 type FailoverReplicationSpec struct {
 	AllowAttachedVolume bool   `json:"allowAttachedVolume,omitempty"`
 	SecondaryBackendId  string `json:"secondaryBackendId,omitempty"`
 }
+
+// The running states of replication pairs.
+const (
+	ReplicationRunningSynced      = "synced"
+	ReplicationRunningSyncing     = "syncing"
+	ReplicationRunningSplit       = "split"
+	ReplicationRunningInterrupted = "interrupted"
+	ReplicationRunningUnknown     = "unknown"
+)
+
+// ReplicationPairStatus is the state of a replication pair on the backend,
+// which is reported by the replication driver.
+type ReplicationPairStatus struct {
+	// The uuid of the replication.
+	Id string `json:"id"`
+	// The role of local site, such as primary or secondary.
+	Role string `json:"role,omitempty"`
+	// Whether the data is replicated to all the peers normally.
+	Healthy bool `json:"healthy"`
+	// The reason why the replication pair is unhealthy.
+	Reason string `json:"reason,omitempty"`
+	// The running state of the replication, such as synced or split.
+	RunningStatus string `json:"runningStatus,omitempty"`
+	// The percentage of the data which is synchronized to the peers.
+	SyncProgress float64 `json:"syncProgress"`
+	// How many seconds the peers are behind the primary site, which is the
+	// data lost if the primary site fails now. -1 means it is unknown.
+	RpoLag int64 `json:"rpoLag"`
+	// The time when the state is reported.
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
@@ -14,6 +14,19 @@
 
 package model
 
+// dock status
+const (
+	DockAvailable   = "available"
+	DockDegraded    = "degraded"
+	DockUnavailable = "unavailable"
+)
+
+// pool status
+const (
+	PoolAvailable   = "available"
+	PoolUnavailable = "unavailable"
+)
+
 // fileshare status
 const (
 	FileShareCreating      = "creating"
@@ -159,5 +159,60 @@ type VolumeGroupSpec struct {
 	// +readOnly
 	PoolId string `json:"poolId,omitempty"`
 
+	// The uuids of the group snapshots of the volume group.
 	GroupSnapshots []string `json:"groupSnapshots,omitempty"`
+
+	// Metadata is the backend information of the volume group returned by
+	// the driver, such as the id of the group on the storage.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// UpdateGroupSnapshots adds the ids in add to the group snapshots of the
+// volume group and removes the ones in remove, the ids already added are not
+// added again.
+func (vg *VolumeGroupSpec) UpdateGroupSnapshots(add, remove []string) {
+	removed := make(map[string]bool)
+	for _, id := range remove {
+		removed[id] = true
+	}
+	var ids []string
+	for _, id := range append(vg.GroupSnapshots, add...) {
+		if !removed[id] {
+			ids = append(ids, id)
+			// Skip the duplicates.
+			removed[id] = true
+		}
+	}
+	vg.GroupSnapshots = ids
+}
+
+// VolumeGroupSnapshotSpec is a crash-consistent point-in-time image of all
+// the volumes in a volume group, which are snapshotted at the same time.
+type VolumeGroupSnapshotSpec struct {
+	*BaseModel
+
+	// The uuid of the project that the group snapshot belongs to.
+	TenantId string `json:"tenantId,omitempty"`
+
+	// The name of the group snapshot.
+	Name string `json:"name,omitempty"`
+
+	// The description of the group snapshot.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The status of the group snapshot.
+	// One of: "available", "error", etc.
+	Status string `json:"status,omitempty"`
+
+	// The uuid of the volume group which the snapshot belongs to.
+	GroupId string `json:"groupId,omitempty"`
+
+	// The snapshots of the volumes in the group.
+	Snapshots []*VolumeSnapshotSpec `json:"snapshots,omitempty"`
+
+	// Metadata is the backend information of the group snapshot.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
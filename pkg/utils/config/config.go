@@ -17,16 +17,19 @@ package config
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-ini/ini"
 	"github.com/sodafoundation/dock/pkg/utils/constants"
+	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -252,6 +255,19 @@ func initConf(confFile string, conf interface{}) {
 	}
 }
 
+// loadConf is like initConf, but returns the errors instead of falling back
+// to the default value or exiting.
+func loadConf(confFile string, conf interface{}) error {
+	cfg, err := ini.Load(confFile)
+	if err != nil {
+		return fmt.Errorf("read configuration %s failed: %v", confFile, err)
+	}
+	if err := parseSections(cfg, reflect.TypeOf(conf), reflect.ValueOf(conf)); err != nil {
+		return fmt.Errorf("parse configuration %s failed: %v", confFile, err)
+	}
+	return nil
+}
+
 // Global Configuration Variable
 var CONF *Config = GetDefaultConfig()
 
@@ -282,9 +298,40 @@ func (c *Config) Load() {
 }
 
 func GetBackendsMap() map[string]BackendProperties {
+	return backendsMap(GetBackends())
+}
+
+// backendsMu guards the backend settings of osdsdock section, which are
+// replaced by ReloadBackends at runtime, so they are read through the getters
+// below rather than CONF.
+var backendsMu sync.RWMutex
+
+// GetBackends returns the settings of the backends.
+func GetBackends() Backends {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return CONF.OsdsDock.Backends
+}
+
+// GetEnabledBackends returns the enabled backends, which must not be
+// modified.
+func GetEnabledBackends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return CONF.OsdsDock.EnabledBackends
+}
+
+// GetHostBasedReplicationDriver returns the host based replication driver.
+func GetHostBasedReplicationDriver() string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return CONF.OsdsDock.HostBasedReplicationDriver
+}
+
+func backendsMap(backends Backends) map[string]BackendProperties {
 	backendsMap := map[string]BackendProperties{}
-	v := reflect.ValueOf(CONF.Backends)
-	t := reflect.TypeOf(CONF.Backends)
+	v := reflect.ValueOf(backends)
+	t := reflect.TypeOf(backends)
 
 	for i := 0; i < t.NumField(); i++ {
 		feild := v.Field(i)
@@ -293,3 +340,57 @@ func GetBackendsMap() map[string]BackendProperties {
 	}
 	return backendsMap
 }
+
+// reloadMu serializes the reloads of CONF.
+var reloadMu sync.Mutex
+
+// ReloadBackends re-reads the config file and replaces the backend settings
+// of osdsdock section under backendsMu, so that the readers see either the
+// old or the new backends. The other settings are only read on start, so
+// they are kept. CONF is left unchanged if the new backends are invalid.
+func ReloadBackends() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	conf := new(Config)
+	if err := loadConf(GetConfigPath(), conf); err != nil {
+		return err
+	}
+	if err := validateBackends(&conf.OsdsDock); err != nil {
+		return err
+	}
+
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	CONF.OsdsDock.EnabledBackends = conf.OsdsDock.EnabledBackends
+	CONF.OsdsDock.HostBasedReplicationDriver = conf.OsdsDock.HostBasedReplicationDriver
+	CONF.OsdsDock.Backends = conf.OsdsDock.Backends
+	return nil
+}
+
+// validateBackends checks that the enabled backends are defined, and that
+// their driver config files can be parsed.
+func validateBackends(dock *OsdsDock) error {
+	bm := backendsMap(dock.Backends)
+	for _, name := range dock.EnabledBackends {
+		b, ok := bm[name]
+		if !ok {
+			return fmt.Errorf("enabled backend %s is not supported", name)
+		}
+		if b.Name == "" || b.DriverName == "" {
+			return fmt.Errorf("backend %s should have name and driver_name", name)
+		}
+		if b.ConfigPath == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(b.ConfigPath)
+		if err != nil {
+			return fmt.Errorf("read config of backend %s failed: %v", name, err)
+		}
+		var m map[string]interface{}
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("parse config of backend %s failed: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -26,6 +26,27 @@ type OsdsDock struct {
 	BindIp                     string        `conf:"bind_ip"` // Just used for attacher dock
 	HostBasedReplicationDriver string        `conf:"host_based_replication_driver,drbd"`
 	LogFlushFrequency          time.Duration `conf:"log_flush_frequency,5s"` // Default value is 5s
+	MetricsEndpoint            string        `conf:"metrics_endpoint"`       // The metrics server is disabled if empty
+	AuditLogFile               string        `conf:"audit_log_file"`         // The audit log file is disabled if empty
+	AuditLogMaxSize            int           `conf:"audit_log_max_size,100"` // Default value is 100 MB
+	AuditLogMaxBackups         int           `conf:"audit_log_max_backups,5"`
+	AuditSyslogEndpoint        string        `conf:"audit_syslog_endpoint"` // Either local or such as udp://host:514
+	TraceExporter              string        `conf:"trace_exporter"`        // Either otlp or file, tracing is disabled if empty
+	TraceEndpoint              string        `conf:"trace_endpoint,http://localhost:4318/v1/traces"`
+	TraceFile                  string        `conf:"trace_file,/var/log/opensds/osdsdock-trace.json"`
+	TraceSampleRatio           float64       `conf:"trace_sample_ratio,1"`  // Ratio of sampled traces from 0 to 1
+	AlertmanagerEndpoint       string        `conf:"alertmanager_endpoint"` // The alerting engine is disabled if empty
+	AlertRulesFile             string        `conf:"alert_rules_file,/etc/opensds/alert_rules.yaml"`
+	AlertEvaluationInterval    time.Duration `conf:"alert_evaluation_interval,60s"`
+	AlertResendInterval        time.Duration `conf:"alert_resend_interval,5m"`
+	DiscoveryInterval          time.Duration `conf:"discovery_interval,60s"`
+	DiscoveryJitter            float64       `conf:"discovery_jitter,0.1"` // Fraction of discovery interval to randomize
+	DiscoveryRetryInterval     time.Duration `conf:"discovery_retry_interval,5s"`
+	DiscoveryMaxBackoff        time.Duration `conf:"discovery_max_backoff,5m"`
+	ShutdownGracePeriod        time.Duration `conf:"shutdown_grace_period,30s"` // Time for the running rpcs to finish on shutdown
+	LeaseTTL                   time.Duration `conf:"lease_ttl,30s"`             // Time after which the docks of a dead host are unavailable
+	LockTimeout                time.Duration `conf:"lock_timeout,30s"`          // Time to wait for the resources locked by other operations
+	DistributedLock            bool          `conf:"distributed_lock,false"`    // Lock the resources across the docks sharing the backends
 	Backends
 }
 
@@ -40,6 +61,8 @@ type Database struct {
 	KeyFile         string `conf:"key_file,/etc/etcd/server.key"`
 	TrustedCAFile   string `conf:"ca_file,/etc/etcd/ca.crt"`
 	AllowClientAuth bool   `conf:"allowClientAuth,false"`
+	PwdEncrypter    string `conf:"password_decrypt_tool,aes"`        // Secret provider of the password
+	Path            string `conf:"path,/var/lib/opensds/opensds.db"` // Database file of the local driver
 }
 
 type BackendProperties struct {
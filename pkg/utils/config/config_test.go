@@ -15,6 +15,9 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -355,3 +358,58 @@ func TestOpensdsConfig(t *testing.T) {
 		t.Error("Test bm[\"lvm\"].Name error")
 	}
 }
+
+func TestReloadBackends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	confPath, lvmPath := filepath.Join(dir, "opensds.conf"), filepath.Join(dir, "lvm.yaml")
+	writeFile := func(path, content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args, conf := os.Args, CONF
+	defer func() { os.Args, CONF = args, conf }()
+	os.Args = []string{"osdsdock", "--config-file", confPath}
+
+	CONF = new(Config)
+	initConf("", CONF)
+	CONF.OsdsDock.ApiEndpoint = "127.0.0.1:50050"
+	CONF.Database.Endpoint = "localhost:2379"
+	writeFile(lvmPath, "tgtBindIp: 127.0.0.1\npool:\n  vg001:\n    storageType: block\n")
+	writeFile(confPath, "[osdsdock]\napi_endpoint = 0.0.0.0:50050\nenabled_backends = lvm\n"+
+		"[lvm]\nname = lvm\ndriver_name = lvm\nconfig_path = "+lvmPath+"\n"+
+		"[database]\nendpoint = localhost:2380\n")
+	if err := ReloadBackends(); err != nil {
+		t.Fatal(err)
+	}
+	if GetBackends().LVM.ConfigPath != lvmPath || !reflect.DeepEqual(GetEnabledBackends(), []string{"lvm"}) {
+		t.Errorf("Expected reloaded backends, got %+v", CONF.OsdsDock)
+	}
+	if CONF.OsdsDock.ApiEndpoint != "127.0.0.1:50050" || CONF.Database.Endpoint != "localhost:2379" {
+		t.Errorf("Expected the settings read on start to be kept, got %+v", CONF)
+	}
+
+	for _, c := range []struct{ conf, lvm string }{
+		{"[osdsdock]\nenabled_backends = unknown\n", ""},
+		{"[osdsdock]\nenabled_backends = lvm\n", ""},
+		{"[osdsdock]\nenabled_backends = lvm\n[lvm]\nname = lvm\ndriver_name = lvm\nconfig_path = " + lvmPath + "\n", "pool: [\n"},
+		{"[osdsdock]\nlog_flush_frequency = often\n", ""},
+	} {
+		writeFile(confPath, c.conf)
+		if c.lvm != "" {
+			writeFile(lvmPath, c.lvm)
+		}
+		if err := ReloadBackends(); err == nil || GetBackends().LVM.ConfigPath != lvmPath ||
+			!reflect.DeepEqual(GetEnabledBackends(), []string{"lvm"}) {
+			t.Errorf("Expected error of config %q and lvm config %q", c.conf, c.lvm)
+		}
+	}
+	os.Remove(confPath)
+	if err := ReloadBackends(); err == nil {
+		t.Error("Expected error of missing config file")
+	}
+}
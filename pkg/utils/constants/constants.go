@@ -26,6 +26,14 @@ const (
 	// configuration file.
 	OpensdsConfigPath = "/etc/opensds/opensds.conf"
 
+	// PwdKeyPath indicates the absolute path of the key file which is used to
+	// encrypt the passwords of backends and database.
+	PwdKeyPath = "/etc/opensds/pwd.key"
+
+	// SecretsDir indicates the directory where an external secret manager
+	// mounts the secret files.
+	SecretsDir = "/etc/opensds/secrets"
+
 	// OpensdsDockBindEndpoint indicates the bind endpoint which the opensds
 	// dock grpc server would listen to.
 	OpensdsDockBindEndpoint = "0.0.0.0:50050"
@@ -15,11 +15,13 @@
 package exec
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
 
 	log "github.com/golang/glog"
+	"github.com/sodafoundation/dock/pkg/utils/trace"
 )
 
 type Executer interface {
@@ -35,9 +37,15 @@ func Run(name string, arg ...string) (string, error) {
 		return "", err
 	}
 
+	// The arguments are not traced since they may contain credentials.
+	_, span := trace.StartSpan(context.Background(), "exec "+name, trace.KindInternal)
+	defer span.End()
+	span.SetAttribute("exec.command", name)
+
 	log.V(5).Infof("Command: %s %s", name, strings.Join(arg, " "))
 	info, err := exec.Command(name, arg...).CombinedOutput()
 	if err != nil {
+		span.SetError(err)
 		log.Errorf("Execute command failed\ninfo:\n%s\nerror: %v", info, err)
 		return string(info), err
 	}
@@ -53,10 +53,11 @@ func (writer GlogWriter) Write(data []byte) (n int, err error) {
 	return len(data), nil
 }
 
-// flush log when be interrupted.
+// flush log when be quitted. SIGINT and SIGTERM are left to the service, which
+// shuts down gracefully and flushes the logs before exiting.
 func handleInterrupt() {
 	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL, syscall.SIGQUIT)
+	signal.Notify(sigs, syscall.SIGQUIT)
 	go func() {
 		sig := <-sigs
 		fmt.Println(sig)
@@ -20,52 +20,129 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
+
+	log "github.com/golang/glog"
+)
+
+// gcmPrefix marks the ciphertexts of AES-GCM, which can not be confused with
+// the hex encoded ones of the deprecated AES-CFB.
+const gcmPrefix = "aes-gcm:"
+
+var (
+	// legacyKey is the deprecated key compiled into the binary. It is only
+	// used to decrypt the existing ciphertexts, so that they can be migrated.
+	legacyKey = []byte("8RcY34!7dce3,cdcaeb*faeC3cd9fQfe")
 )
 
-type AES struct{}
+// AES encrypts the passwords with AES-GCM and the key loaded by LoadKey.
+type AES struct {
+	key []byte
+	err error
+}
 
+// NewAES returns an AES with the per-install key, the errors of loading the
+// key are returned when the key is used.
 func NewAES() *AES {
-	return &AES{}
+	key, err := LoadKey()
+	return &AES{key: key, err: err}
 }
 
-var (
-	key = []byte("8RcY34!7dce3,cdcaeb*faeC3cd9fQfe")
-)
+// NewAESWithKey returns an AES with the given key, which must be 32 bytes.
+func NewAESWithKey(key []byte) *AES {
+	return &AES{key: key}
+}
 
-func (*AES) Encrypter(password string) (string, error) {
-	if len(key) != 32 {
-		return "", errors.New("The length of the key must be 32.")
+func (a *AES) gcm() (cipher.AEAD, error) {
+	if a.err != nil {
+		return nil, a.err
 	}
+	if len(a.key) != KeySize {
+		return nil, fmt.Errorf("the length of the key must be %d", KeySize)
+	}
+	block, err := aes.NewCipher(a.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
 
-	plaintext := []byte(password)
-
-	block, err := aes.NewCipher(key)
+func (a *AES) Encrypter(password string) (string, error) {
+	gcm, err := a.gcm()
 	if err != nil {
 		return "", err
 	}
 
-	// The IV needs to be unique, but not secure. Therefore it's common to
-	// include it at the beginning of the ciphertext.
-	ciphertext := make([]byte, aes.BlockSize+len(plaintext))
-	iv := ciphertext[:aes.BlockSize]
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+	// The nonce must be unique for the key, it is put at the beginning of
+	// the ciphertext.
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(password), nil)
+
+	return gcmPrefix + hex.EncodeToString(ciphertext), nil
+}
+
+func (a *AES) Decrypter(code string) (string, error) {
+	if IsLegacy(code) {
+		log.Warning("Password is encrypted by the deprecated built-in key, please migrate it with pwdEncrypter")
+		return decryptLegacy(code)
+	}
+
+	ciphertext, err := hex.DecodeString(strings.TrimPrefix(code, gcmPrefix))
+	if err != nil {
 		return "", err
 	}
+	gcm, err := a.gcm()
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", errors.New("Ciphertext too short")
+	}
+
+	nonce := ciphertext[:gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt password failed: %v", err)
+	}
+	return string(plaintext), nil
+}
 
-	stream := cipher.NewCFBEncrypter(block, iv)
-	stream.XORKeyStream(ciphertext[aes.BlockSize:], plaintext)
+// Migrate re-encrypts the ciphertext of the deprecated built-in key with
+// AES-GCM, the ciphertexts of AES-GCM are returned as they are.
+func (a *AES) Migrate(code string) (string, error) {
+	if IsLegacy(code) {
+		password, err := decryptLegacy(code)
+		if err != nil {
+			return "", err
+		}
+		return a.Encrypter(password)
+	}
+	// Check that the ciphertext belongs to the key.
+	if _, err := a.Decrypter(code); err != nil {
+		return "", err
+	}
+	return code, nil
+}
 
-	return hex.EncodeToString(ciphertext), nil
+// IsLegacy reports whether the ciphertext is encrypted by the deprecated
+// built-in key.
+func IsLegacy(code string) bool {
+	return !strings.HasPrefix(code, gcmPrefix)
 }
 
-func (*AES) Decrypter(code string) (string, error) {
+// decryptLegacy decrypts the ciphertexts of AES-CFB with the built-in key.
+func decryptLegacy(code string) (string, error) {
 	ciphertext, err := hex.DecodeString(code)
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(key)
+	block, err := aes.NewCipher(legacyKey)
 	if err != nil {
 		return "", err
 	}
@@ -15,20 +15,115 @@
 package pwd
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 var (
 	password = "123456"
-	//ciphertext = "9a3b4d577c86d5c9468123b93382c194399070c3f5e3"
+	// legacyCiphertext is encrypted by AES-CFB with the built-in key.
+	legacyCiphertext = "00070e151c232a31383f464d545b62694f9ed4d02a26"
+	testKey          = []byte("0123456789abcdef0123456789abcdef")
 )
 
 func TestDecrypter(t *testing.T) {
 	var expected = password
-	var aes = &AES{}
-	ciphertext, _ := aes.Encrypter(password)
+	var aes = NewAESWithKey(testKey)
+	ciphertext, err := aes.Encrypter(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ciphertext, gcmPrefix) || IsLegacy(ciphertext) {
+		t.Errorf("Expected AES-GCM ciphertext, got %s", ciphertext)
+	}
 	pwdText, _ := aes.Decrypter(ciphertext)
 	if pwdText != expected {
 		t.Errorf("Expected %s, got %s\n", expected, pwdText)
 	}
+
+	// The ciphertexts can not be decrypted by other keys or be tampered.
+	if _, err := NewAESWithKey([]byte("fedcba9876543210fedcba9876543210")).Decrypter(ciphertext); err == nil {
+		t.Error("Expected error of decrypting with another key")
+	}
+	tampered := ciphertext[:len(ciphertext)-1] + "0"
+	if tampered == ciphertext {
+		tampered = ciphertext[:len(ciphertext)-1] + "1"
+	}
+	if _, err := aes.Decrypter(tampered); err == nil {
+		t.Error("Expected error of decrypting tampered ciphertext")
+	}
+}
+
+func TestLegacyMigration(t *testing.T) {
+	aes := NewAESWithKey(testKey)
+	if pwdText, err := aes.Decrypter(legacyCiphertext); err != nil || pwdText != password {
+		t.Errorf("Expected legacy password %s, got %s and %v", password, pwdText, err)
+	}
+
+	migrated, err := aes.Migrate(legacyCiphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsLegacy(migrated) {
+		t.Errorf("Expected migrated ciphertext, got %s", migrated)
+	}
+	if pwdText, _ := aes.Decrypter(migrated); pwdText != password {
+		t.Errorf("Expected %s, got %s", password, pwdText)
+	}
+	if again, err := aes.Migrate(migrated); err != nil || again != migrated {
+		t.Errorf("Expected migrated ciphertext unchanged, got %s and %v", again, err)
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Unsetenv(KeyEnv)
+	defer os.Unsetenv(KeyFileEnv)
+	path := filepath.Join(dir, "pwd.key")
+	os.Setenv(KeyFileEnv, path)
+
+	if _, err := LoadKey(); err == nil {
+		t.Error("Expected error of missing key file")
+	}
+	if err := GenerateKey(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateKey(path); err == nil {
+		t.Error("Expected existing key file not to be overwritten")
+	}
+	key, err := LoadKey()
+	if err != nil || len(key) != KeySize {
+		t.Fatalf("Expected key of %d bytes, got %v and %v", KeySize, key, err)
+	}
+	ciphertext, err := NewAES().Encrypter(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pwdText, _ := NewAESWithKey(key).Decrypter(ciphertext); pwdText != password {
+		t.Errorf("Expected %s, got %s", password, pwdText)
+	}
+
+	os.Chmod(path, 0644)
+	if _, err := LoadKey(); err == nil {
+		t.Error("Expected error of key file readable by others")
+	}
+	if _, err := NewAES().Encrypter(password); err == nil {
+		t.Error("Expected error of encrypting without key")
+	}
+
+	os.Setenv(KeyEnv, "30313233343536373839616263646566"+"30313233343536373839616263646566")
+	if key, err := LoadKey(); err != nil || string(key) != string(testKey) {
+		t.Errorf("Expected key from environment, got %s and %v", key, err)
+	}
+	os.Setenv(KeyEnv, "0123")
+	if _, err := LoadKey(); err == nil {
+		t.Error("Expected error of short key")
+	}
 }
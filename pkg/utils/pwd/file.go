@@ -0,0 +1,71 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pwd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// SecretsDirEnv is the environment variable of the directory of secret files.
+const SecretsDirEnv = "OPENSDS_SECRETS_DIR"
+
+// FileSecret reads the secrets from the files mounted by an external secret
+// manager, such as the secrets of Kubernetes or the agent of Vault. The
+// password in the config is the name of secret file in the secrets directory,
+// or the absolute path of it.
+type FileSecret struct {
+	Dir string
+}
+
+// NewFileSecret returns a FileSecret of constants.SecretsDir unless the
+// directory is set by the environment.
+func NewFileSecret() *FileSecret {
+	dir := os.Getenv(SecretsDirEnv)
+	if dir == "" {
+		dir = constants.SecretsDir
+	}
+	return &FileSecret{Dir: dir}
+}
+
+// Encrypter is not supported, since the secrets are managed by the external
+// secret manager.
+func (*FileSecret) Encrypter(password string) (string, error) {
+	return "", errors.New("secret files are managed by the external secret manager")
+}
+
+func (f *FileSecret) Decrypter(code string) (string, error) {
+	path := code
+	if !filepath.IsAbs(path) {
+		// The names of secrets should not escape the secrets directory.
+		if code == "" || strings.Contains(code, "..") {
+			return "", fmt.Errorf("invalid secret name %q", code)
+		}
+		path = filepath.Join(f.Dir, code)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file failed: %v", err)
+	}
+	// The secret files often end with a newline, which is not a part of the
+	// secrets.
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pwd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "etcd"), []byte(password+"\n"), 0600)
+	defer os.Unsetenv(SecretsDirEnv)
+	os.Setenv(SecretsDirEnv, dir)
+
+	f := NewPwdEncrypter(FileEncrypter)
+	for _, code := range []string{"etcd", filepath.Join(dir, "etcd")} {
+		if pwdText, err := f.Decrypter(code); err != nil || pwdText != password {
+			t.Errorf("Expected secret %s of %s, got %s and %v", password, code, pwdText, err)
+		}
+	}
+	for _, code := range []string{"", "missing", "../secrets/etcd"} {
+		if _, err := f.Decrypter(code); err == nil {
+			t.Errorf("Expected error of secret %q", code)
+		}
+	}
+	if _, err := f.Encrypter(password); err == nil {
+		t.Error("Expected error of encrypting secret file")
+	}
+}
+
+func TestNewPwdEncrypter(t *testing.T) {
+	if _, ok := NewPwdEncrypter("unknown").(*AES); !ok {
+		t.Error("Expected aes encrypter of unknown name")
+	}
+	RegisterPwdEncrypter("test", func() PwdEncrypter { return NewAESWithKey(testKey) })
+	if a, ok := NewPwdEncrypter("test").(*AES); !ok || string(a.key) != string(testKey) {
+		t.Errorf("Expected registered encrypter, got %v", a)
+	}
+}
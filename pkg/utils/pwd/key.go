@@ -0,0 +1,98 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pwd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/sodafoundation/dock/pkg/utils/constants"
+)
+
+// KeySize is the size of the AES-256 key.
+const KeySize = 32
+
+// Environment variables of the key, the key itself takes precedence over the
+// key file.
+const (
+	KeyEnv     = "OPENSDS_PWD_KEY"
+	KeyFileEnv = "OPENSDS_PWD_KEY_FILE"
+)
+
+// KeyPath returns the path of the key file, which is constants.PwdKeyPath
+// unless it is set by the environment.
+func KeyPath() string {
+	if path := os.Getenv(KeyFileEnv); path != "" {
+		return path
+	}
+	return constants.PwdKeyPath
+}
+
+// LoadKey loads the hex encoded key from the environment or the key file. The
+// key file must not be accessible by group or others.
+func LoadKey() ([]byte, error) {
+	if s := os.Getenv(KeyEnv); s != "" {
+		return decodeKey(s, KeyEnv)
+	}
+
+	path := KeyPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file failed, please generate it with pwdEncrypter: %v", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("key file %s is accessible by group or others, its mode should be 0600", path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file failed: %v", err)
+	}
+	return decodeKey(string(data), path)
+}
+
+func decodeKey(s, source string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("key of %s is not hex encoded: %v", source, err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key of %s should be %d bytes, got %d", source, KeySize, len(key))
+	}
+	return key, nil
+}
+
+// GenerateKey writes a random key into the key file, which is only readable
+// by its owner. An existing key file is never overwritten, since the passwords
+// encrypted by it could no longer be decrypted.
+func GenerateKey(path string) error {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
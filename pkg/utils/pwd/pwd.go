@@ -14,16 +14,56 @@
 
 package pwd
 
+import (
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+// PwdEncrypter is a secret provider, which encrypts the passwords in the
+// configs and decrypts them when they are used.
 type PwdEncrypter interface {
 	Encrypter(password string) (string, error)
 	Decrypter(code string) (string, error)
 }
 
+// Names of the built-in secret providers.
+const (
+	// AESEncrypter encrypts with AES-GCM and a per-install key, and still
+	// decrypts the ciphertexts of the deprecated built-in key.
+	AESEncrypter = "aes"
+	// FileEncrypter reads the secrets from the files mounted by an external
+	// secret manager.
+	FileEncrypter = "file"
+)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]func() PwdEncrypter{
+		AESEncrypter:  func() PwdEncrypter { return NewAES() },
+		FileEncrypter: func() PwdEncrypter { return NewFileSecret() },
+	}
+)
+
+// RegisterPwdEncrypter makes a secret provider available by the name, which
+// replaces the provider of the same name.
+func RegisterPwdEncrypter(name string, newFunc func() PwdEncrypter) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = newFunc
+}
+
+// NewPwdEncrypter returns the secret provider of the name, the aes provider
+// is used if the name is empty or unknown.
 func NewPwdEncrypter(encrypter string) PwdEncrypter {
-	switch encrypter {
-	case "aes":
-		return NewAES()
-	default:
-		return NewAES()
+	providersMu.RLock()
+	newFunc, ok := providers[encrypter]
+	providersMu.RUnlock()
+	if !ok {
+		if encrypter != "" {
+			log.Warningf("Unknown password encrypter %s, use %s instead", encrypter, AESEncrypter)
+		}
+		newFunc = providers[AESEncrypter]
 	}
+	return newFunc()
 }
@@ -0,0 +1,255 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	OTLPExporterType = "otlp"
+	FileExporterType = "file"
+
+	queueSize     = 2048
+	batchSize     = 512
+	flushInterval = 5 * time.Second
+)
+
+// SpanData is the finished span passed to exporters.
+type SpanData struct {
+	TraceId      string            `json:"traceId"`
+	SpanId       string            `json:"spanId"`
+	ParentSpanId string            `json:"parentSpanId,omitempty"`
+	Name         string            `json:"name"`
+	Kind         Kind              `json:"kind"`
+	StartTime    time.Time         `json:"startTime"`
+	EndTime      time.Time         `json:"endTime"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// Exporter sends the finished spans to a tracing backend.
+type Exporter interface {
+	Export(spans []*SpanData) error
+	Shutdown() error
+}
+
+// Config is the config of tracing, which is disabled if Exporter is empty.
+type Config struct {
+	// ServiceName is the service.name resource attribute of spans.
+	ServiceName string
+	// Exporter is the type of registered exporter, such as otlp or file.
+	Exporter string
+	// Endpoint is the url of OTLP/HTTP traces receiver.
+	Endpoint string
+	// File is the path of file which the file exporter writes to.
+	File string
+	// SampleRatio is the ratio of traces to be sampled, from 0 to 1.
+	SampleRatio float64
+}
+
+// ExporterFactory creates an exporter from the config.
+type ExporterFactory func(conf *Config) (Exporter, error)
+
+var exporterFactories = map[string]ExporterFactory{
+	OTLPExporterType: newOTLPExporter,
+	FileExporterType: newFileExporter,
+}
+
+// RegisterExporter registers an exporter type so that it can be selected by
+// the config.
+func RegisterExporter(name string, f ExporterFactory) {
+	exporterFactories[name] = f
+}
+
+type tracer struct {
+	exporter    Exporter
+	sampleRatio float64
+	queue       chan *SpanData
+	done        chan struct{}
+
+	// mu guards the queue from being sent to after it is closed.
+	mu     sync.RWMutex
+	closed bool
+}
+
+var (
+	tracerMu     sync.RWMutex
+	globalTracer *tracer
+)
+
+func getTracer() *tracer {
+	tracerMu.RLock()
+	defer tracerMu.RUnlock()
+	return globalTracer
+}
+
+// Enabled returns whether tracing is initialized.
+func Enabled() bool {
+	return getTracer() != nil
+}
+
+// Init creates the configured exporter and starts exporting spans, it does
+// nothing if no exporter is configured.
+func Init(conf *Config) error {
+	if conf.Exporter == "" {
+		return nil
+	}
+	f, ok := exporterFactories[conf.Exporter]
+	if !ok {
+		return fmt.Errorf("trace exporter %s is not supported", conf.Exporter)
+	}
+	e, err := f(conf)
+	if err != nil {
+		return err
+	}
+	t := &tracer{
+		exporter:    e,
+		sampleRatio: conf.SampleRatio,
+		queue:       make(chan *SpanData, queueSize),
+		done:        make(chan struct{}),
+	}
+	go t.loop()
+
+	tracerMu.Lock()
+	old := globalTracer
+	globalTracer = t
+	tracerMu.Unlock()
+	if old != nil {
+		old.shutdown()
+	}
+	return nil
+}
+
+// Shutdown flushes the pending spans and stops the exporter.
+func Shutdown() error {
+	tracerMu.Lock()
+	t := globalTracer
+	globalTracer = nil
+	tracerMu.Unlock()
+	if t == nil {
+		return nil
+	}
+	return t.shutdown()
+}
+
+// sample decides whether a new trace is sampled by its id, so that the
+// decision is consistent for the same trace.
+func (t *tracer) sample(id TraceID) bool {
+	if t.sampleRatio >= 1 {
+		return true
+	}
+	if t.sampleRatio <= 0 {
+		return false
+	}
+	return float64(binary.BigEndian.Uint64(id[8:])>>1) < t.sampleRatio*float64(uint64(1)<<63)
+}
+
+// enqueue never blocks the traced operation, the span is dropped if the
+// queue is full.
+func (t *tracer) enqueue(s *SpanData) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.queue <- s:
+	default:
+		log.V(5).Infof("Trace queue is full, span %s is dropped", s.Name)
+	}
+}
+
+func (t *tracer) loop() {
+	defer close(t.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []*SpanData
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.exporter.Export(batch); err != nil {
+			log.Errorf("Failed to export %d spans: %v", len(batch), err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case s, ok := <-t.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if batch = append(batch, s); len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (t *tracer) shutdown() error {
+	t.mu.Lock()
+	t.closed = true
+	close(t.queue)
+	t.mu.Unlock()
+	<-t.done
+	return t.exporter.Shutdown()
+}
+
+// fileExporter writes the spans as JSON lines to a local file, which is
+// useful when no tracing backend is reachable.
+type fileExporter struct {
+	file *os.File
+}
+
+func newFileExporter(conf *Config) (Exporter, error) {
+	if conf.File == "" {
+		return nil, fmt.Errorf("trace file is not configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(conf.File), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(conf.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{file: f}, nil
+}
+
+func (e *fileExporter) Export(spans []*SpanData) error {
+	enc := json.NewEncoder(e.file)
+	for _, s := range spans {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *fileExporter) Shutdown() error {
+	return e.file.Close()
+}
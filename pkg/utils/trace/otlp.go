@@ -0,0 +1,155 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+
+	otlpTimeout     = 10 * time.Second
+	otlpStatusError = 2
+	instrumentation = "github.com/sodafoundation/dock"
+)
+
+// The following types are the JSON encoding of OTLP trace export request,
+// in which the ids are hex strings and the 64 bits integers are strings.
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              Kind           `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpExportRequest struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+// otlpExporter posts the spans to an OTLP/HTTP receiver in JSON encoding,
+// such as the OpenTelemetry collector or Jaeger.
+type otlpExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+func newOTLPExporter(conf *Config) (Exporter, error) {
+	e := &otlpExporter{
+		endpoint:    conf.Endpoint,
+		serviceName: conf.ServiceName,
+		client:      &http.Client{Timeout: otlpTimeout},
+	}
+	if e.endpoint == "" {
+		e.endpoint = DefaultOTLPEndpoint
+	}
+	return e, nil
+}
+
+func toKeyValues(attrs map[string]string) []otlpKeyValue {
+	var kvs []otlpKeyValue
+	for k, v := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+func (e *otlpExporter) newRequest(spans []*SpanData) *otlpExportRequest {
+	ss := &otlpScopeSpans{}
+	ss.Scope.Name = instrumentation
+	for _, s := range spans {
+		sp := &otlpSpan{
+			TraceId:           s.TraceId,
+			SpanId:            s.SpanId,
+			ParentSpanId:      s.ParentSpanId,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        toKeyValues(s.Attributes),
+		}
+		if s.Error != "" {
+			sp.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		ss.Spans = append(ss.Spans, sp)
+	}
+	rs := &otlpResourceSpans{ScopeSpans: []*otlpScopeSpans{ss}}
+	rs.Resource.Attributes = toKeyValues(map[string]string{"service.name": e.serviceName})
+	return &otlpExportRequest{ResourceSpans: []*otlpResourceSpans{rs}}
+}
+
+func (e *otlpExporter) Export(spans []*SpanData) error {
+	body, err := json.Marshal(e.newRequest(spans))
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp receiver %s responded %s: %s", e.endpoint, resp.Status, msg)
+	}
+	return nil
+}
+
+func (e *otlpExporter) Shutdown() error {
+	return nil
+}
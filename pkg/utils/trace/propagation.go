@@ -0,0 +1,122 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TraceparentHeader is the W3C trace context header, which is also used as the
+// key of grpc metadata.
+const TraceparentHeader = "traceparent"
+
+// FormatTraceparent formats the span context as version 00 of traceparent.
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses the traceparent header, an invalid span context is
+// returned if the header is malformed.
+func ParseTraceparent(h string) SpanContext {
+	var sc SpanContext
+	segs := strings.Split(strings.TrimSpace(h), "-")
+	if len(segs) < 4 || len(segs[0]) != 2 || segs[0] == "ff" ||
+		len(segs[1]) != 32 || len(segs[2]) != 16 || len(segs[3]) != 2 {
+		return SpanContext{}
+	}
+	flags, err := hex.DecodeString(segs[3])
+	if err != nil {
+		return SpanContext{}
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(segs[1])); err != nil {
+		return SpanContext{}
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(segs[2])); err != nil {
+		return SpanContext{}
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc
+}
+
+// UnaryServerInterceptor starts a server span of each rpc as the child of the
+// trace context in the incoming metadata, and binds it to the goroutine
+// handling the rpc so that the driver operations are traced in it.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if !Enabled() {
+		return handler(ctx, req)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(TraceparentHeader); len(vals) > 0 {
+			if sc := ParseTraceparent(vals[0]); sc.IsValid() {
+				ctx = context.WithValue(ctx, spanKey{}, sc)
+			}
+		}
+	}
+	ctx, span := StartSpan(ctx, strings.TrimPrefix(info.FullMethod, "/"), KindServer)
+	defer span.End()
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.service", strings.TrimPrefix(path.Dir(info.FullMethod), "/"))
+	span.SetAttribute("rpc.method", path.Base(info.FullMethod))
+
+	unbind := Bind(span)
+	defer unbind()
+	resp, err := handler(ctx, req)
+	span.SetError(err)
+	return resp, err
+}
+
+// StartHTTPSpan starts a client span of an outbound http request in the span
+// bound to the calling goroutine, the returned traceparent should be set in
+// the request header. The query of url is not recorded since it may contain
+// credentials.
+func StartHTTPSpan(method, rawurl string) (*Span, string) {
+	_, span := StartSpan(context.Background(), "HTTP "+method, KindClient)
+	if span == nil {
+		return nil, ""
+	}
+	span.SetAttribute("http.method", method)
+	if u, err := url.Parse(rawurl); err == nil {
+		u.RawQuery, u.User = "", nil
+		span.SetAttribute("http.url", u.String())
+	}
+	return span, FormatTraceparent(span.SpanContext())
+}
+
+// SetStatusCode records the status code of http response, the span is marked
+// failed if it is an error status.
+func (s *Span) SetStatusCode(code int) {
+	if s == nil {
+		return
+	}
+	s.SetAttribute("http.status_code", strconv.Itoa(code))
+	if code >= http.StatusBadRequest {
+		s.SetError(fmt.Errorf("%d %s", code, http.StatusText(code)))
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 The OpenSDS Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This module implements a lightweight tracer compatible with OpenTelemetry.
+The trace context is propagated in the W3C traceparent format, and the spans
+are exported by a pluggable exporter such as OTLP over HTTP or a local file.
+
+The storage drivers do not take a context.Context, so a span can be bound to
+the calling goroutine, then the spans of driver methods, shell commands and
+array requests started in the same goroutine become its children.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of span, the values are the same as OpenTelemetry.
+type Kind int
+
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+type TraceID [16]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+type SpanID [8]byte
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext is the part of span which is propagated across processes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Span is an operation in a trace, all the methods are safe to be called on a
+// nil span which is returned when tracing is disabled.
+type Span struct {
+	mu       sync.Mutex
+	name     string
+	kind     Kind
+	sc       SpanContext
+	parentID SpanID
+	start    time.Time
+	attrs    map[string]string
+	err      error
+	ended    bool
+}
+
+type spanKey struct{}
+
+// NewContext returns a copy of ctx which carries the span.
+func NewContext(ctx context.Context, s *Span) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, s.SpanContext())
+}
+
+// SpanContextFromContext returns the span context carried by ctx, or the one
+// of span bound to the calling goroutine if ctx carries nothing.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if sc, ok := ctx.Value(spanKey{}).(SpanContext); ok {
+		return sc
+	}
+	return current()
+}
+
+// StartSpan starts a span as a child of the span carried by ctx, or of the
+// span bound to the calling goroutine if ctx carries nothing. A nil span is
+// returned if tracing is disabled.
+func StartSpan(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil
+	}
+	parent := SpanContextFromContext(ctx)
+	s := &Span{name: name, kind: kind, start: time.Now(), attrs: map[string]string{}}
+	if parent.IsValid() {
+		s.sc.TraceID, s.sc.Sampled, s.parentID = parent.TraceID, parent.Sampled, parent.SpanID
+	} else {
+		rand.Read(s.sc.TraceID[:])
+		s.sc.Sampled = t.sample(s.sc.TraceID)
+	}
+	rand.Read(s.sc.SpanID[:])
+	return NewContext(ctx, s), s
+}
+
+// SpanContext returns the span context, which is invalid for a nil span.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttribute sets a string attribute of the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs[key] = value
+	s.mu.Unlock()
+}
+
+// SetError marks the span failed if err is not nil.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// End finishes the span and sends it to the exporter if it is sampled, only
+// the first call takes effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := &SpanData{
+		TraceId:    s.sc.TraceID.String(),
+		SpanId:     s.sc.SpanID.String(),
+		Name:       s.name,
+		Kind:       s.kind,
+		StartTime:  s.start,
+		EndTime:    end,
+		Attributes: s.attrs,
+	}
+	if s.parentID.IsValid() {
+		data.ParentSpanId = s.parentID.String()
+	}
+	if s.err != nil {
+		data.Error = s.err.Error()
+	}
+	s.mu.Unlock()
+
+	if t := getTracer(); t != nil && s.sc.Sampled {
+		t.enqueue(data)
+	}
+}
+
+// bound holds the spans bound to goroutines, keyed by goroutine id.
+var (
+	boundMu sync.Mutex
+	bound   = map[uint64]SpanContext{}
+)
+
+// goid parses the id of calling goroutine from the header of its stack trace
+// such as "goroutine 18 [running]:".
+func goid() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		id, _ := strconv.ParseUint(string(b[:i]), 10, 64)
+		return id
+	}
+	return 0
+}
+
+func current() SpanContext {
+	if getTracer() == nil {
+		return SpanContext{}
+	}
+	id := goid()
+	boundMu.Lock()
+	defer boundMu.Unlock()
+	return bound[id]
+}
+
+// Bind binds the span to the calling goroutine until the returned function is
+// called, which restores the previously bound one. It must be called in the
+// same goroutine.
+func Bind(s *Span) func() {
+	if s == nil {
+		return func() {}
+	}
+	id := goid()
+	boundMu.Lock()
+	prev, ok := bound[id]
+	bound[id] = s.sc
+	boundMu.Unlock()
+	return func() {
+		boundMu.Lock()
+		defer boundMu.Unlock()
+		if ok {
+			bound[id] = prev
+		} else {
+			delete(bound, id)
+		}
+	}
+}
+
+// StartBoundSpan starts an internal span with the attributes in key value
+// pairs and binds it to the calling goroutine. The returned function records
+// the error, restores the binding and ends the span, which is usually
+// deferred by a function with named error result.
+func StartBoundSpan(name string, attrs ...string) func(err *error) {
+	_, span := StartSpan(context.Background(), name, KindInternal)
+	if span == nil {
+		return func(*error) {}
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		span.SetAttribute(attrs[i], attrs[i+1])
+	}
+	unbind := Bind(span)
+	return func(err *error) {
+		if err != nil {
+			span.SetError(*err)
+		}
+		unbind()
+		span.End()
+	}
+}
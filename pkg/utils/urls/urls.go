@@ -47,6 +47,18 @@ func GenerateDockURL(urlType int, tenantId string, in ...string) string {
 	return generateURL("docks", urlType, tenantId, in...)
 }
 
+// GenerateDockLeaseURL returns the liveness key of the dock, which exists as
+// long as the lease of the dock is kept alive.
+func GenerateDockLeaseURL(urlType int, tenantId string, in ...string) string {
+	return generateURL("leases/docks", urlType, tenantId, in...)
+}
+
+// GenerateLockURL returns the key of the distributed lock of the resource,
+// which exists as long as the lock is held.
+func GenerateLockURL(urlType int, tenantId string, in ...string) string {
+	return generateURL("locks", urlType, tenantId, in...)
+}
+
 func GeneratePoolURL(urlType int, tenantId string, in ...string) string {
 	return generateURL("pools", urlType, tenantId, in...)
 }
@@ -15,9 +15,11 @@
 package utils
 
 import (
+	crand "crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"os"
 	"reflect"
@@ -299,6 +301,22 @@ func RandSeqWithAlnum(n int) string {
 	return RandSeq(n, alnum)
 }
 
+// SecureRandSeqWithAlnum works like RandSeqWithAlnum but reads from a
+// cryptographically secure source, so it can be used to generate secrets.
+func SecureRandSeqWithAlnum(n int) (string, error) {
+	alnum := []rune("1234567890abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	b := make([]rune, n)
+	max := big.NewInt(int64(len(alnum)))
+	for i := range b {
+		idx, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = alnum[idx.Int64()]
+	}
+	return string(b), nil
+}
+
 func RandSeq(n int, chs []rune) string {
 	b := make([]rune, n)
 	for i := range b {
@@ -230,6 +230,11 @@ func (fc *FakeDbClient) UpdateDock(ctx *c.Context, dckID, name, desp string) (*m
 	return nil, nil
 }
 
+// UpdateDockStatus
+func (fc *FakeDbClient) UpdateDockStatus(ctx *c.Context, dckID, status, reason string, check func(*model.DockSpec) (bool, error)) (bool, error) {
+	return true, nil
+}
+
 // DeleteDock
 func (fc *FakeDbClient) DeleteDock(ctx *c.Context, dckID string) error {
 	return nil
@@ -273,6 +278,11 @@ func (fc *FakeDbClient) UpdatePool(ctx *c.Context, polID, name, desp string, use
 	return nil, nil
 }
 
+// UpdatePoolStatus
+func (fc *FakeDbClient) UpdatePoolStatus(ctx *c.Context, polID, status, reason string, check func(*model.StoragePoolSpec) (bool, error)) (bool, error) {
+	return true, nil
+}
+
 // DeletePool
 func (fc *FakeDbClient) DeletePool(ctx *c.Context, polID string) error {
 	return nil
@@ -435,6 +445,10 @@ func (fc *FakeDbClient) UpdateReplication(ctx *c.Context, replicationId string,
 	return nil, nil
 }
 
+func (fc *FakeDbClient) UpdateReplicationPairStatus(ctx *c.Context, replicationId string, status *model.ReplicationPairStatus) error {
+	return nil
+}
+
 // CreateVolumeGroup
 func (fc *FakeDbClient) CreateVolumeGroup(ctx *c.Context, vg *model.VolumeGroupSpec) (*model.VolumeGroupSpec, error) {
 	return &SampleVolumeGroups[0], nil
@@ -448,6 +462,10 @@ func (fc *FakeDbClient) GetVolumeGroup(ctx *c.Context, vgId string) (*model.Volu
 	return &SampleVolumeGroups[0], nil
 }
 
+func (fc *FakeDbClient) UpdateGroupSnapshots(ctx *c.Context, vgId string, add, remove []string) (*model.VolumeGroupSpec, error) {
+	return &SampleVolumeGroups[0], nil
+}
+
 func (fc *FakeDbClient) UpdateStatus(ctx *c.Context, in interface{}, status string) error {
 	return nil
 }
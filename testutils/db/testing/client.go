@@ -1411,6 +1411,27 @@ func (_m *Client) UpdateDock(ctx *context.Context, dckID string, name string, de
 	return r0, r1
 }
 
+// UpdateDockStatus provides a mock function with given fields: ctx, dckID, status, reason, check
+func (_m *Client) UpdateDockStatus(ctx *context.Context, dckID string, status string, reason string, check func(*model.DockSpec) (bool, error)) (bool, error) {
+	ret := _m.Called(ctx, dckID, status, reason, check)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*context.Context, string, string, string, func(*model.DockSpec) (bool, error)) bool); ok {
+		r0 = rf(ctx, dckID, status, reason, check)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*context.Context, string, string, string, func(*model.DockSpec) (bool, error)) error); ok {
+		r1 = rf(ctx, dckID, status, reason, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateFileShare provides a mock function with given fields: ctx, fshare
 func (_m *Client) UpdateFileShare(ctx *context.Context, fshare *model.FileShareSpec) (*model.FileShareSpec, error) {
 	ret := _m.Called(ctx, fshare)
@@ -1526,6 +1547,27 @@ func (_m *Client) UpdatePool(ctx *context.Context, polID string, name string, de
 	return r0, r1
 }
 
+// UpdatePoolStatus provides a mock function with given fields: ctx, polID, status, reason, check
+func (_m *Client) UpdatePoolStatus(ctx *context.Context, polID string, status string, reason string, check func(*model.StoragePoolSpec) (bool, error)) (bool, error) {
+	ret := _m.Called(ctx, polID, status, reason, check)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*context.Context, string, string, string, func(*model.StoragePoolSpec) (bool, error)) bool); ok {
+		r0 = rf(ctx, polID, status, reason, check)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*context.Context, string, string, string, func(*model.StoragePoolSpec) (bool, error)) error); ok {
+		r1 = rf(ctx, polID, status, reason, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateReplication provides a mock function with given fields: ctx, replicationId, input
 func (_m *Client) UpdateReplication(ctx *context.Context, replicationId string, input *model.ReplicationSpec) (*model.ReplicationSpec, error) {
 	ret := _m.Called(ctx, replicationId, input)
@@ -1549,6 +1591,20 @@ func (_m *Client) UpdateReplication(ctx *context.Context, replicationId string,
 	return r0, r1
 }
 
+// UpdateReplicationPairStatus provides a mock function with given fields: ctx, replicationId, status
+func (_m *Client) UpdateReplicationPairStatus(ctx *context.Context, replicationId string, status *model.ReplicationPairStatus) error {
+	ret := _m.Called(ctx, replicationId, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*context.Context, string, *model.ReplicationPairStatus) error); ok {
+		r0 = rf(ctx, replicationId, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateStatus provides a mock function with given fields: ctx, object, status
 func (_m *Client) UpdateStatus(ctx *context.Context, object interface{}, status string) error {
 	ret := _m.Called(ctx, object, status)
@@ -1609,6 +1665,29 @@ func (_m *Client) UpdateVolumeAttachment(ctx *context.Context, attachmentId stri
 	return r0, r1
 }
 
+// UpdateGroupSnapshots provides a mock function with given fields: ctx, vgId, add, remove
+func (_m *Client) UpdateGroupSnapshots(ctx *context.Context, vgId string, add []string, remove []string) (*model.VolumeGroupSpec, error) {
+	ret := _m.Called(ctx, vgId, add, remove)
+
+	var r0 *model.VolumeGroupSpec
+	if rf, ok := ret.Get(0).(func(*context.Context, string, []string, []string) *model.VolumeGroupSpec); ok {
+		r0 = rf(ctx, vgId, add, remove)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.VolumeGroupSpec)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*context.Context, string, []string, []string) error); ok {
+		r1 = rf(ctx, vgId, add, remove)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateVolumeGroup provides a mock function with given fields: ctx, vg
 func (_m *Client) UpdateVolumeGroup(ctx *context.Context, vg *model.VolumeGroupSpec) (*model.VolumeGroupSpec, error) {
 	ret := _m.Called(ctx, vg)
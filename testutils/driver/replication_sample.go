@@ -57,3 +57,21 @@ func (r *ReplicationDriver) DisableReplication(opt *pb.DisableReplicationOpts) e
 func (r *ReplicationDriver) FailoverReplication(opt *pb.FailoverReplicationOpts) error {
 	return nil
 }
+
+func (r *ReplicationDriver) SwitchoverReplication(opt *pb.SwitchoverReplicationOpts) error {
+	return nil
+}
+
+func (r *ReplicationDriver) ReprotectReplication(opt *pb.ReprotectReplicationOpts) error {
+	return nil
+}
+
+func (r *ReplicationDriver) GetReplicationStatus(opt *pb.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	return &model.ReplicationPairStatus{
+		Id:            opt.GetId(),
+		Role:          "primary",
+		Healthy:       true,
+		RunningStatus: model.ReplicationRunningSynced,
+		SyncProgress:  100,
+	}, nil
+}
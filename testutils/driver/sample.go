@@ -122,6 +122,32 @@ func (d *Driver) DeleteVolumeGroup(opt *pb.DeleteVolumeGroupOpts) error {
 	return &model.NotImplementError{"method DeleteVolumeGroup has not been implemented yet"}
 }
 
+// CreateGroupSnapshot
+func (d *Driver) CreateGroupSnapshot(opt *pb.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	var snps []*model.VolumeSnapshotSpec
+	for _, m := range opt.GetSnapshots() {
+		snps = append(snps, &model.VolumeSnapshotSpec{
+			BaseModel: &model.BaseModel{Id: m.GetId()},
+			VolumeId:  m.GetVolumeId(),
+			Size:      m.GetSize(),
+			Status:    model.VolumeSnapAvailable,
+		})
+	}
+	return &model.VolumeGroupSnapshotSpec{
+		BaseModel:   &model.BaseModel{Id: opt.GetId()},
+		Name:        opt.GetName(),
+		Description: opt.GetDescription(),
+		GroupId:     opt.GetGroupId(),
+		Status:      model.VolumeSnapAvailable,
+		Snapshots:   snps,
+	}, nil
+}
+
+// DeleteGroupSnapshot
+func (d *Driver) DeleteGroupSnapshot(opt *pb.DeleteGroupSnapshotOpts) error {
+	return nil
+}
+
 func (d *Driver) CreateFileShare(opt *pb.CreateFileShareOpts) (*model.FileShareSpec, error) {
 	return &SampleFileShares[0], nil
 }
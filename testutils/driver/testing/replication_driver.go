@@ -93,6 +93,43 @@ func (_m *ReplicationDriver) FailoverReplication(opt *proto.FailoverReplicationO
 	return r0
 }
 
+// GetReplicationStatus provides a mock function with given fields: opt
+func (_m *ReplicationDriver) GetReplicationStatus(opt *proto.GetReplicationStatusOpts) (*model.ReplicationPairStatus, error) {
+	ret := _m.Called(opt)
+
+	var r0 *model.ReplicationPairStatus
+	if rf, ok := ret.Get(0).(func(*proto.GetReplicationStatusOpts) *model.ReplicationPairStatus); ok {
+		r0 = rf(opt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReplicationPairStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*proto.GetReplicationStatusOpts) error); ok {
+		r1 = rf(opt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReprotectReplication provides a mock function with given fields: opt
+func (_m *ReplicationDriver) ReprotectReplication(opt *proto.ReprotectReplicationOpts) error {
+	ret := _m.Called(opt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*proto.ReprotectReplicationOpts) error); ok {
+		r0 = rf(opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Setup provides a mock function with given fields:
 func (_m *ReplicationDriver) Setup() error {
 	ret := _m.Called()
@@ -107,6 +144,20 @@ func (_m *ReplicationDriver) Setup() error {
 	return r0
 }
 
+// SwitchoverReplication provides a mock function with given fields: opt
+func (_m *ReplicationDriver) SwitchoverReplication(opt *proto.SwitchoverReplicationOpts) error {
+	ret := _m.Called(opt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*proto.SwitchoverReplicationOpts) error); ok {
+		r0 = rf(opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Unset provides a mock function with given fields:
 func (_m *ReplicationDriver) Unset() error {
 	ret := _m.Called()
@@ -60,6 +60,29 @@ func (_m *VolumeDriver) CreateVolume(opt *proto.CreateVolumeOpts) (*model.Volume
 	return r0, r1
 }
 
+// CreateGroupSnapshot provides a mock function with given fields: opt
+func (_m *VolumeDriver) CreateGroupSnapshot(opt *proto.CreateGroupSnapshotOpts) (*model.VolumeGroupSnapshotSpec, error) {
+	ret := _m.Called(opt)
+
+	var r0 *model.VolumeGroupSnapshotSpec
+	if rf, ok := ret.Get(0).(func(*proto.CreateGroupSnapshotOpts) *model.VolumeGroupSnapshotSpec); ok {
+		r0 = rf(opt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.VolumeGroupSnapshotSpec)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*proto.CreateGroupSnapshotOpts) error); ok {
+		r1 = rf(opt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateVolumeGroup provides a mock function with given fields: opt
 func (_m *VolumeDriver) CreateVolumeGroup(opt *proto.CreateVolumeGroupOpts) (*model.VolumeGroupSpec, error) {
 	ret := _m.Called(opt)
@@ -83,6 +106,20 @@ func (_m *VolumeDriver) CreateVolumeGroup(opt *proto.CreateVolumeGroupOpts) (*mo
 	return r0, r1
 }
 
+// DeleteGroupSnapshot provides a mock function with given fields: opt
+func (_m *VolumeDriver) DeleteGroupSnapshot(opt *proto.DeleteGroupSnapshotOpts) error {
+	ret := _m.Called(opt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*proto.DeleteGroupSnapshotOpts) error); ok {
+		r0 = rf(opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSnapshot provides a mock function with given fields: opt
 func (_m *VolumeDriver) DeleteSnapshot(opt *proto.DeleteVolumeSnapshotOpts) error {
 	ret := _m.Called(opt)